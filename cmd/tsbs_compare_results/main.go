@@ -0,0 +1,94 @@
+// tsbs_compare_results compares the query results captured by two query
+// benchmark runs (see --capture-results of the tsbs_run_queries_* programs),
+// typically of the same queries against two different databases.
+//
+// Queries are matched by their ID, so both runs should use query files
+// generated with the same parameters and seed. Mismatches are reported by
+// query label, and the program exits with a non-zero status if there are any.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Program option vars:
+var (
+	referenceFile string
+	resultsFile   string
+	tolerance     float64
+	maxShown      int
+)
+
+// Parse args:
+func init() {
+	pflag.StringVar(&referenceFile, "reference", "", "File with the captured results of the reference database")
+	pflag.StringVar(&resultsFile, "results", "", "File with the captured results to check against the reference")
+	pflag.Float64Var(&tolerance, "tolerance", 1e-6, "Allowed difference between two values, relative to the larger one for values above 1")
+	pflag.IntVar(&maxShown, "max-mismatches-shown", 5, "Number of mismatching queries to describe per query label")
+	pflag.Parse()
+
+	if referenceFile == "" || resultsFile == "" {
+		log.Fatal("both --reference and --results are required")
+	}
+}
+
+func readResults(fileName string) map[uint64]*query.QueryResult {
+	f, err := os.Open(fileName)
+	if err != nil {
+		log.Fatalf("cannot open file for read %s: %v", fileName, err)
+	}
+	defer f.Close()
+	results, err := query.ReadResults(f)
+	if err != nil {
+		log.Fatalf("cannot read results from %s: %v", fileName, err)
+	}
+	return results
+}
+
+func main() {
+	reference := readResults(referenceFile)
+	results := readResults(resultsFile)
+
+	comparisons := query.CompareResults(reference, results, tolerance)
+	labels := make([]string, 0, len(comparisons))
+	for label := range comparisons {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	failed := false
+	for _, label := range labels {
+		c := comparisons[label]
+		fmt.Printf("%s: %d compared, %d mismatched, %d missing\n", label, c.Compared, len(c.Mismatches), c.Missing)
+		sort.Slice(c.Mismatches, func(i, j int) bool { return c.Mismatches[i].ID < c.Mismatches[j].ID })
+		for i, m := range c.Mismatches {
+			if i == maxShown {
+				fmt.Printf("  ...\n")
+				break
+			}
+			fmt.Printf("  query %d: %s\n", m.ID, m.Reason)
+		}
+		if len(c.Mismatches) > 0 || c.Missing > 0 {
+			failed = true
+		}
+	}
+	extra := 0
+	for id := range results {
+		if _, ok := reference[id]; !ok {
+			extra++
+		}
+	}
+	if extra > 0 {
+		fmt.Printf("%d queries are not in the reference\n", extra)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -5,6 +5,7 @@
 // ClickHouse pseudo-CSV format (the same as for TimescaleDB)
 // InfluxDB bulk load format
 // MongoDB BSON format
+// OpenTelemetry OTLP protobuf format
 // TimescaleDB pseudo-CSV format (the same as for ClickHouse)
 // VictoriaMetrics bulk load format (the same as for InfluxDB)
 
@@ -57,6 +58,9 @@ func init() {
 		panic(fmt.Errorf("unable to decode config: %s", err))
 	}
 
+	config.ScaleGiven = viper.IsSet("scale")
+	config.LogIntervalGiven = viper.IsSet("log-interval")
+
 	profileFile = viper.GetString("profile-file")
 }
 
@@ -0,0 +1,50 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// BaseGenerator contains settings specific for Elasticsearch and OpenSearch.
+type BaseGenerator struct {
+	// DBName is the database the data was loaded into, which prefixes the
+	// names of its indices
+	DBName string
+}
+
+// GenerateEmptyQuery returns an empty query.HTTP.
+func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
+	return query.NewHTTP()
+}
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &Devops{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
+}
+
+// fillInQuery fills the query struct with a search of the daily indices of
+// the given measurement.
+func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc, measurement string, search map[string]interface{}) {
+	body, err := json.Marshal(search)
+	if err != nil {
+		panic(fmt.Sprintf("cannot encode search: %v", err))
+	}
+	q := qi.(*query.HTTP)
+	q.HumanLabel = []byte(humanLabel)
+	q.HumanDescription = []byte(humanDesc)
+	q.Method = []byte("POST")
+	q.Path = []byte(fmt.Sprintf("/%s-%s-*/_search", g.DBName, measurement))
+	q.Body = body
+}
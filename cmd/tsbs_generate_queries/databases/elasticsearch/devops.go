@@ -0,0 +1,222 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	iutils "github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// timeField holds the time of the documents
+	timeField = "@timestamp"
+	// hostField holds the hostname tag of the documents
+	hostField = "labels.hostname"
+)
+
+// Devops produces Elasticsearch-specific queries for all the devops query
+// types, as aggregations of the documents of the cpu indices.
+type Devops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// object is a JSON object of a search request.
+type object = map[string]interface{}
+
+func (d *Devops) getRandomHosts(nHosts int) []string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return hostnames
+}
+
+// timeFilter matches the documents within interval.
+func timeFilter(interval *iutils.TimeInterval) object {
+	return object{"range": object{timeField: object{
+		"gte": interval.StartString(),
+		"lt":  interval.EndString(),
+	}}}
+}
+
+// hostsFilter matches the documents of the given hosts.
+func hostsFilter(hostnames []string) object {
+	return object{"terms": object{hostField: hostnames}}
+}
+
+// filterQuery is a query matching the documents matched by all filters.
+func filterQuery(filters ...object) object {
+	return object{"bool": object{"filter": filters}}
+}
+
+// dateHistogram groups documents into buckets of the given interval, to which
+// aggs are applied.
+func dateHistogram(interval string, aggs object) object {
+	return object{
+		"date_histogram": object{"field": timeField, "fixed_interval": interval},
+		"aggs":           aggs,
+	}
+}
+
+// metricAggs applies agg to every metric, naming the aggregations after the
+// aggregation and the metric, e.g. max_usage_user.
+func metricAggs(agg string, metrics []string) object {
+	aggs := object{}
+	for _, m := range metrics {
+		aggs[agg+"_"+m] = object{agg: object{"field": m}}
+	}
+	return aggs
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	hostnames := d.getRandomHosts(nHosts)
+
+	humanLabel := fmt.Sprintf("Elasticsearch %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := object{
+		"size":  0,
+		"query": filterQuery(hostsFilter(hostnames), timeFilter(interval)),
+		"aggs":  object{"minute": dateHistogram("1m", metricAggs("max", metrics))},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, search)
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	humanLabel := "Elasticsearch max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	aggs := metricAggs("max", []string{"usage_user"})
+	aggs["limit"] = object{"bucket_sort": object{"size": 5}}
+	search := object{
+		"size":  0,
+		"query": object{"range": object{timeField: object{"lt": interval.EndString()}}},
+		"aggs": object{"minute": object{
+			"date_histogram": object{
+				"field":          timeField,
+				"fixed_interval": "1m",
+				"min_doc_count":  1,
+				"order":          object{"_key": "desc"},
+			},
+			"aggs": aggs,
+		}},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, search)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+
+	humanLabel := devops.GetDoubleGroupByLabel("Elasticsearch", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	hosts := object{
+		"terms": object{"field": hostField, "size": d.Scale, "order": object{"_key": "asc"}},
+		"aggs":  metricAggs("avg", metrics),
+	}
+	search := object{
+		"size":  0,
+		"query": filterQuery(timeFilter(interval)),
+		"aggs":  object{"hour": dateHistogram("1h", object{"hostname": hosts})},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, search)
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT MAX(metric1), ..., MAX(metricN)
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour ORDER BY hour
+func (d *Devops) MaxAllCPU(qi query.Query, nHosts int, duration time.Duration) {
+	interval := d.Interval.MustRandWindow(duration)
+	hostnames := d.getRandomHosts(nHosts)
+
+	humanLabel := devops.GetMaxAllLabel("Elasticsearch", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := object{
+		"size":  0,
+		"query": filterQuery(hostsFilter(hostnames), timeFilter(interval)),
+		"aggs":  object{"hour": dateHistogram("1h", metricAggs("max", devops.GetAllCPUMetrics()))},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, search)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	humanLabel := "Elasticsearch last row per host"
+	humanDesc := humanLabel + ": cpu"
+	search := object{
+		"size": 0,
+		"aggs": object{"hostname": object{
+			"terms": object{"field": hostField, "size": d.Scale},
+			"aggs": object{"last": object{"top_hits": object{
+				"size": 1,
+				"sort": []object{{timeField: object{"order": "desc"}}},
+			}}},
+		}},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, search)
+}
+
+// maxHighCPUHits is the number of documents returned by HighCPUForHosts,
+// which is the largest result window of an index by default.
+const maxHighCPUHits = 10000
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	filters := []object{
+		{"range": object{"usage_user": object{"gt": 90.0}}},
+		timeFilter(interval),
+	}
+	if nHosts > 0 {
+		filters = append(filters, hostsFilter(d.getRandomHosts(nHosts)))
+	}
+
+	humanLabel, err := devops.GetHighCPULabel("Elasticsearch", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := object{
+		"size":  maxHighCPUHits,
+		"query": filterQuery(filters...),
+		"sort":  []object{{timeField: object{"order": "asc"}}},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, search)
+}
@@ -0,0 +1,127 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestDevopsQueries(t *testing.T) {
+	cases := []struct {
+		desc              string
+		fn                func(d *Devops, q query.Query)
+		expectedHumanDesc string
+		expectedBody      string
+		fail              bool
+	}{
+		{
+			desc:              "GroupByTime",
+			fn:                func(d *Devops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanDesc: "Elasticsearch 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T20:16:22Z",
+			expectedBody: `{"aggs":{"minute":{"aggs":{"max_usage_system":{"max":{"field":"usage_system"}},"max_usage_user":{"max":{"field":"usage_user"}}},` +
+				`"date_histogram":{"field":"@timestamp","fixed_interval":"1m"}}},` +
+				`"query":{"bool":{"filter":[{"terms":{"labels.hostname":["host_9","host_3"]}},{"range":{"@timestamp":{"gte":"1970-01-01T20:16:22Z","lt":"1970-01-01T21:16:22Z"}}}]}},"size":0}`,
+		},
+		{
+			desc:              "GroupByOrderByLimit",
+			fn:                func(d *Devops, q query.Query) { d.GroupByOrderByLimit(q) },
+			expectedHumanDesc: "Elasticsearch max cpu over last 5 min-intervals (random end): 1970-01-01T20:16:22Z",
+			expectedBody: `{"aggs":{"minute":{"aggs":{"limit":{"bucket_sort":{"size":5}},"max_usage_user":{"max":{"field":"usage_user"}}},` +
+				`"date_histogram":{"field":"@timestamp","fixed_interval":"1m","min_doc_count":1,"order":{"_key":"desc"}}}},` +
+				`"query":{"range":{"@timestamp":{"lt":"1970-01-01T21:16:22Z"}}},"size":0}`,
+		},
+		{
+			desc:              "GroupByTimeAndPrimaryTag",
+			fn:                func(d *Devops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 2) },
+			expectedHumanDesc: "Elasticsearch mean of 2 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedBody: `{"aggs":{"hour":{"aggs":{"hostname":{"aggs":{"avg_usage_system":{"avg":{"field":"usage_system"}},"avg_usage_user":{"avg":{"field":"usage_user"}}},` +
+				`"terms":{"field":"labels.hostname","order":{"_key":"asc"},"size":10}}},"date_histogram":{"field":"@timestamp","fixed_interval":"1h"}}},` +
+				`"query":{"bool":{"filter":[{"range":{"@timestamp":{"gte":"1970-01-01T06:16:22Z","lt":"1970-01-01T18:16:22Z"}}}]}},"size":0}`,
+		},
+		{
+			desc:              "MaxAllCPU",
+			fn:                func(d *Devops, q query.Query) { d.MaxAllCPU(q, 1, 8*time.Hour) },
+			expectedHumanDesc: "Elasticsearch max of all CPU metrics, random    1 hosts, random 8h0m0s by 1h: 1970-01-01T02:16:22Z",
+			expectedBody: `{"aggs":{"hour":{"aggs":{"max_usage_guest":{"max":{"field":"usage_guest"}},"max_usage_guest_nice":{"max":{"field":"usage_guest_nice"}},` +
+				`"max_usage_idle":{"max":{"field":"usage_idle"}},"max_usage_iowait":{"max":{"field":"usage_iowait"}},"max_usage_irq":{"max":{"field":"usage_irq"}},` +
+				`"max_usage_nice":{"max":{"field":"usage_nice"}},"max_usage_softirq":{"max":{"field":"usage_softirq"}},"max_usage_steal":{"max":{"field":"usage_steal"}},` +
+				`"max_usage_system":{"max":{"field":"usage_system"}},"max_usage_user":{"max":{"field":"usage_user"}}},"date_histogram":{"field":"@timestamp","fixed_interval":"1h"}}},` +
+				`"query":{"bool":{"filter":[{"terms":{"labels.hostname":["host_9"]}},{"range":{"@timestamp":{"gte":"1970-01-01T02:16:22Z","lt":"1970-01-01T10:16:22Z"}}}]}},"size":0}`,
+		},
+		{
+			desc:              "LastPointPerHost",
+			fn:                func(d *Devops, q query.Query) { d.LastPointPerHost(q) },
+			expectedHumanDesc: "Elasticsearch last row per host: cpu",
+			expectedBody: `{"aggs":{"hostname":{"aggs":{"last":{"top_hits":{"size":1,"sort":[{"@timestamp":{"order":"desc"}}]}}},` +
+				`"terms":{"field":"labels.hostname","size":10}}},"size":0}`,
+		},
+		{
+			desc:              "HighCPUForHosts all hosts",
+			fn:                func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanDesc: "Elasticsearch CPU over threshold, all hosts: 1970-01-01T06:16:22Z",
+			expectedBody: `{"query":{"bool":{"filter":[{"range":{"usage_user":{"gt":90}}},{"range":{"@timestamp":{"gte":"1970-01-01T06:16:22Z","lt":"1970-01-01T18:16:22Z"}}}]}},` +
+				`"size":10000,"sort":[{"@timestamp":{"order":"asc"}}]}`,
+		},
+		{
+			desc:              "HighCPUForHosts 1 host",
+			fn:                func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 1) },
+			expectedHumanDesc: "Elasticsearch CPU over threshold, 1 host(s): 1970-01-01T06:16:22Z",
+			expectedBody: `{"query":{"bool":{"filter":[{"range":{"usage_user":{"gt":90}}},{"range":{"@timestamp":{"gte":"1970-01-01T06:16:22Z","lt":"1970-01-01T18:16:22Z"}}},` +
+				`{"terms":{"labels.hostname":["host_9"]}}]}},"size":10000,"sort":[{"@timestamp":{"order":"asc"}}]}`,
+		},
+		{
+			desc: "GroupByTime zero metrics",
+			fn:   func(d *Devops, q query.Query) { d.GroupByTime(q, 1, 0, time.Hour) },
+			fail: true,
+		},
+		{
+			desc: "HighCPUForHosts negative hosts",
+			fn:   func(d *Devops, q query.Query) { d.HighCPUForHosts(q, -1) },
+			fail: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := &BaseGenerator{DBName: "benchmark"}
+			s := time.Unix(0, 0)
+			dq, err := b.NewDevops(s, s.Add(24*time.Hour), 10)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+			q := d.GenerateEmptyQuery().(*query.HTTP)
+
+			if c.fail {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("did not panic when should")
+					}
+				}()
+				c.fn(d, q)
+				return
+			}
+			c.fn(d, q)
+
+			if got, want := string(q.HumanDescription), c.expectedHumanDesc; got != want {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, want)
+			}
+			if got := string(q.Method); got != "POST" {
+				t.Errorf("incorrect method: got %s want POST", got)
+			}
+			if got, want := string(q.Path), "/benchmark-cpu-*/_search"; got != want {
+				t.Errorf("incorrect path: got %s want %s", got, want)
+			}
+			if got := string(q.Body); got != c.expectedBody {
+				t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", got, c.expectedBody)
+			}
+			if !json.Valid(q.Body) {
+				t.Errorf("body is not valid JSON")
+			}
+		})
+	}
+}
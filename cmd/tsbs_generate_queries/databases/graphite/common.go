@@ -0,0 +1,63 @@
+package graphite
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	iutils "github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// BaseGenerator contains settings specific for the Graphite render API.
+type BaseGenerator struct {
+	// UseTags selects tagged series of Graphite 1.1 rather than dotted paths
+	UseTags bool
+}
+
+// GenerateEmptyQuery returns an empty query.HTTP.
+func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
+	return query.NewHTTP()
+}
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &Devops{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
+}
+
+type queryInfo struct {
+	// render targets, each a series list
+	targets []string
+	// label to describe type of query
+	label string
+	// time range for query executing
+	interval *iutils.TimeInterval
+}
+
+// fillInQuery fills the query struct with a JSON request of the render API
+func (g *BaseGenerator) fillInQuery(qq query.Query, qi *queryInfo) {
+	q := qq.(*query.HTTP)
+	q.HumanLabel = []byte(qi.label)
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s", qi.label, qi.interval.StartString()))
+	q.Method = []byte("GET")
+
+	v := url.Values{}
+	v["target"] = qi.targets
+	v.Set("from", strconv.FormatInt(qi.interval.Start().Unix(), 10))
+	v.Set("until", strconv.FormatInt(qi.interval.End().Unix(), 10))
+	v.Set("format", "json")
+	q.Path = []byte(fmt.Sprintf("/render?%s", v.Encode()))
+	q.RawQuery = []byte(strings.Join(qi.targets, "\n"))
+	q.Body = nil
+}
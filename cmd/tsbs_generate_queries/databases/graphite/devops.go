@@ -0,0 +1,172 @@
+package graphite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	iutils "github.com/timescale/tsbs/internal/utils"
+	devopsdata "github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// hostNode is the node of the dotted paths of the cpu series that holds the
+// hostname, their first tag.
+const hostNode = 1
+
+// Devops produces render API queries for the devops query types. The
+// Graphite target names every series after its measurement and field, with
+// the tags of the point either as the nodes of a dotted path in between,
+// e.g. cpu.host_0.eu-central-1.<...>.usage_user, or as tags of a tagged
+// series, e.g. cpu.usage_user;hostname=host_0;region=eu-central-1;<...>.
+type Devops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// mustGetRandomHosts is the form of GetRandomHosts that cannot error; if it does error,
+// it causes a panic.
+func (d *Devops) mustGetRandomHosts(nHosts int) []string {
+	hosts, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return hosts
+}
+
+// seriesOf selects the cpu series of metric for the given hosts, or for all
+// hosts if there are none.
+func (d *Devops) seriesOf(metric string, hostnames []string) string {
+	if d.UseTags {
+		exprs := fmt.Sprintf("'name=%s.%s'", devops.TableName, metric)
+		if len(hostnames) == 1 {
+			exprs += fmt.Sprintf(",'hostname=%s'", hostnames[0])
+		} else if len(hostnames) > 1 {
+			exprs += fmt.Sprintf(",'hostname=~^(%s)$'", strings.Join(hostnames, "|"))
+		}
+		return fmt.Sprintf("seriesByTag(%s)", exprs)
+	}
+
+	host := "*"
+	if len(hostnames) == 1 {
+		host = hostnames[0]
+	} else if len(hostnames) > 1 {
+		host = fmt.Sprintf("{%s}", strings.Join(hostnames, ","))
+	}
+	// every machine tag is a node, the hostname first
+	otherTags := strings.Repeat(".*", len(devopsdata.MachineTagKeys)-1)
+	return fmt.Sprintf("%s.%s%s.%s", devops.TableName, host, otherTags, metric)
+}
+
+// groupByHost combines the series of each host with the avg function.
+func (d *Devops) groupByHost(series string) string {
+	if d.UseTags {
+		return fmt.Sprintf("groupByTags(%s,'avg','hostname')", series)
+	}
+	return fmt.Sprintf("groupByNode(%s,%d,'avg')", series, hostNode)
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu'
+// per minute for nhosts hosts,
+// e.g. for each metric:
+//
+//	summarize(maxSeries(cpu.{hostname1,...,hostnameN}.*.<...>.metric),'1min','max')
+func (d *Devops) GroupByTime(qq query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	hosts := d.mustGetRandomHosts(nHosts)
+	targets := make([]string, len(metrics))
+	for i, m := range metrics {
+		targets[i] = fmt.Sprintf("summarize(maxSeries(%s),'1min','max')", d.seriesOf(m, hosts))
+	}
+	qi := &queryInfo{
+		targets:  targets,
+		label:    fmt.Sprintf("Graphite %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange),
+		interval: d.Interval.MustRandWindow(timeRange),
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// GroupByOrderByLimit selects the MAX of usage_user per minute for the last
+// 5 minutes before a random end,
+// e.g.:
+//
+//	summarize(maxSeries(cpu.*.*.<...>.usage_user),'1min','max')
+func (d *Devops) GroupByOrderByLimit(qq query.Query) {
+	end := d.Interval.MustRandWindow(time.Hour).End()
+	interval, err := iutils.NewTimeInterval(end.Add(-5*time.Minute), end)
+	databases.PanicIfErr(err)
+	qi := &queryInfo{
+		targets:  []string{fmt.Sprintf("summarize(maxSeries(%s),'1min','max')", d.seriesOf("usage_user", nil))},
+		label:    "Graphite max cpu over last 5 min-intervals (random end)",
+		interval: interval,
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. for each metric:
+//
+//	summarize(groupByNode(cpu.*.*.<...>.metric,1,'avg'),'1h','avg')
+//
+// Resultsets:
+// double-groupby-1
+// double-groupby-5
+// double-groupby-all
+func (d *Devops) GroupByTimeAndPrimaryTag(qq query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	targets := make([]string, len(metrics))
+	for i, m := range metrics {
+		targets[i] = fmt.Sprintf("summarize(%s,'1h','avg')", d.groupByHost(d.seriesOf(m, nil)))
+	}
+	qi := &queryInfo{
+		targets:  targets,
+		label:    devops.GetDoubleGroupByLabel("Graphite", numMetrics),
+		interval: d.Interval.MustRandWindow(devops.DoubleGroupByDuration),
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. for each metric:
+//
+//	summarize(maxSeries(cpu.{hostname1,...,hostnameN}.*.<...>.metric),'1h','max')
+func (d *Devops) MaxAllCPU(qq query.Query, nHosts int, duration time.Duration) {
+	hosts := d.mustGetRandomHosts(nHosts)
+	metrics := devops.GetAllCPUMetrics()
+	targets := make([]string, len(metrics))
+	for i, m := range metrics {
+		targets[i] = fmt.Sprintf("summarize(maxSeries(%s),'1h','max')", d.seriesOf(m, hosts))
+	}
+	qi := &queryInfo{
+		targets:  targets,
+		label:    devops.GetMaxAllLabel("Graphite", nHosts),
+		interval: d.Interval.MustRandWindow(duration),
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// HighCPUForHosts selects the usage_user of nHosts hosts (if 0, of all
+// hosts) when it is at least 90,
+// e.g.:
+//
+//	removeBelowValue(cpu.{hostname1,...,hostnameN}.*.<...>.usage_user,90)
+func (d *Devops) HighCPUForHosts(qq query.Query, nHosts int) {
+	label, err := devops.GetHighCPULabel("Graphite", nHosts)
+	databases.PanicIfErr(err)
+	var hosts []string
+	if nHosts > 0 {
+		hosts = d.mustGetRandomHosts(nHosts)
+	}
+	qi := &queryInfo{
+		targets:  []string{fmt.Sprintf("removeBelowValue(%s,90)", d.seriesOf("usage_user", hosts))},
+		label:    label,
+		interval: d.Interval.MustRandWindow(devops.HighCPUDuration),
+	}
+	d.fillInQuery(qq, qi)
+}
+
+func (d *Devops) LastPointPerHost(qq query.Query) {
+	panic("LastPointPerHost not supported in the Graphite render API")
+}
@@ -0,0 +1,181 @@
+package graphite
+
+import (
+	"math/rand"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	testOtherTags = ".*.*.*.*.*.*.*.*.*"
+	testAllHosts  = "cpu.*" + testOtherTags
+)
+
+func TestDevopsQueries(t *testing.T) {
+	cases := []struct {
+		desc              string
+		useTags           bool
+		fn                func(d *Devops, q query.Query)
+		expectedHumanDesc string
+		expectedTargets   []string
+		expectedFrom      string
+		expectedUntil     string
+		fail              bool
+	}{
+		{
+			desc:              "GroupByTime",
+			fn:                func(d *Devops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanDesc: "Graphite 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T21:47:30Z",
+			expectedTargets: []string{
+				"summarize(maxSeries(cpu.{host_5,host_9}" + testOtherTags + ".usage_user),'1min','max')",
+				"summarize(maxSeries(cpu.{host_5,host_9}" + testOtherTags + ".usage_system),'1min','max')",
+			},
+			expectedFrom:  "78450",
+			expectedUntil: "82050",
+		},
+		{
+			desc:              "GroupByTime tagged",
+			useTags:           true,
+			fn:                func(d *Devops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanDesc: "Graphite 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T21:47:30Z",
+			expectedTargets: []string{
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_user','hostname=~^(host_5|host_9)$')),'1min','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_system','hostname=~^(host_5|host_9)$')),'1min','max')",
+			},
+			expectedFrom:  "78450",
+			expectedUntil: "82050",
+		},
+		{
+			desc:              "GroupByOrderByLimit",
+			fn:                func(d *Devops, q query.Query) { d.GroupByOrderByLimit(q) },
+			expectedHumanDesc: "Graphite max cpu over last 5 min-intervals (random end): 1970-01-01T21:11:22Z",
+			expectedTargets:   []string{"summarize(maxSeries(" + testAllHosts + ".usage_user),'1min','max')"},
+			expectedFrom:      "76282",
+			expectedUntil:     "76582",
+		},
+		{
+			desc:              "GroupByTimeAndPrimaryTag",
+			fn:                func(d *Devops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 1) },
+			expectedHumanDesc: "Graphite mean of 1 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedTargets:   []string{"summarize(groupByNode(" + testAllHosts + ".usage_user,1,'avg'),'1h','avg')"},
+			expectedFrom:      "22582",
+			expectedUntil:     "65782",
+		},
+		{
+			desc:              "GroupByTimeAndPrimaryTag tagged",
+			useTags:           true,
+			fn:                func(d *Devops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 1) },
+			expectedHumanDesc: "Graphite mean of 1 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedTargets:   []string{"summarize(groupByTags(seriesByTag('name=cpu.usage_user'),'avg','hostname'),'1h','avg')"},
+			expectedFrom:      "22582",
+			expectedUntil:     "65782",
+		},
+		{
+			desc:              "MaxAllCPU tagged",
+			useTags:           true,
+			fn:                func(d *Devops, q query.Query) { d.MaxAllCPU(q, 1, 8*time.Hour) },
+			expectedHumanDesc: "Graphite max of all CPU metrics, random    1 hosts, random 8h0m0s by 1h: 1970-01-01T03:54:10Z",
+			expectedTargets: []string{
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_user','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_system','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_idle','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_nice','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_iowait','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_irq','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_softirq','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_steal','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_guest','hostname=host_5')),'1h','max')",
+				"summarize(maxSeries(seriesByTag('name=cpu.usage_guest_nice','hostname=host_5')),'1h','max')",
+			},
+			expectedFrom:  "14050",
+			expectedUntil: "42850",
+		},
+		{
+			desc:              "HighCPUForHosts all hosts",
+			fn:                func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanDesc: "Graphite CPU over threshold, all hosts: 1970-01-01T06:16:22Z",
+			expectedTargets:   []string{"removeBelowValue(" + testAllHosts + ".usage_user,90)"},
+			expectedFrom:      "22582",
+			expectedUntil:     "65782",
+		},
+		{
+			desc:              "HighCPUForHosts 2 hosts",
+			fn:                func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 2) },
+			expectedHumanDesc: "Graphite CPU over threshold, 2 host(s): 1970-01-01T05:47:30Z",
+			expectedTargets:   []string{"removeBelowValue(cpu.{host_5,host_9}" + testOtherTags + ".usage_user,90)"},
+			expectedFrom:      "20850",
+			expectedUntil:     "64050",
+		},
+		{
+			desc: "GroupByTime zero metrics",
+			fn:   func(d *Devops, q query.Query) { d.GroupByTime(q, 1, 0, time.Hour) },
+			fail: true,
+		},
+		{
+			desc: "HighCPUForHosts negative hosts",
+			fn:   func(d *Devops, q query.Query) { d.HighCPUForHosts(q, -1) },
+			fail: true,
+		},
+		{
+			desc: "LastPointPerHost",
+			fn:   func(d *Devops, q query.Query) { d.LastPointPerHost(q) },
+			fail: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := &BaseGenerator{UseTags: c.useTags}
+			s := time.Unix(0, 0)
+			dq, err := b.NewDevops(s, s.Add(24*time.Hour), 10)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+			q := d.GenerateEmptyQuery().(*query.HTTP)
+
+			if c.fail {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("did not panic when should")
+					}
+				}()
+				c.fn(d, q)
+				return
+			}
+			c.fn(d, q)
+
+			if got, want := string(q.HumanDescription), c.expectedHumanDesc; got != want {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, want)
+			}
+			if got := string(q.Method); got != "GET" {
+				t.Errorf("incorrect method: got %s want GET", got)
+			}
+			path := string(q.Path)
+			if !strings.HasPrefix(path, "/render?") {
+				t.Fatalf("incorrect path: %s", path)
+			}
+			v, err := url.ParseQuery(strings.TrimPrefix(path, "/render?"))
+			if err != nil {
+				t.Fatalf("cannot parse query string: %v", err)
+			}
+			if got := strings.Join(v["target"], "\n"); got != strings.Join(c.expectedTargets, "\n") {
+				t.Errorf("incorrect targets:\ngot\n%s\nwant\n%s", got, strings.Join(c.expectedTargets, "\n"))
+			}
+			if got := v.Get("from"); got != c.expectedFrom {
+				t.Errorf("incorrect from: got %s want %s", got, c.expectedFrom)
+			}
+			if got := v.Get("until"); got != c.expectedUntil {
+				t.Errorf("incorrect until: got %s want %s", got, c.expectedUntil)
+			}
+			if got := v.Get("format"); got != "json" {
+				t.Errorf("incorrect format: got %s want json", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// K8s produces Influx-specific queries for all the k8s query types.
+type K8s struct {
+	*k8s.Core
+	*BaseGenerator
+}
+
+func (k *K8s) getNodeWhereString(nNodes int) string {
+	nodes, err := k.GetRandomNodes(nNodes)
+	databases.PanicIfErr(err)
+
+	nodeClauses := make([]string, len(nodes))
+	for i, n := range nodes {
+		nodeClauses[i] = fmt.Sprintf("\"node\" = '%s'", n)
+	}
+	return "(" + strings.Join(nodeClauses, " or ") + ")"
+}
+
+// TopPodsCPUPerNamespace finds the limit pods of a random namespace with the
+// highest average cpu usage over a random hour.
+func (k *K8s) TopPodsCPUPerNamespace(qi query.Query, limit int) {
+	interval := k.Interval.MustRandWindow(k8s.TopPodsCPUDuration)
+	influxql := fmt.Sprintf(`SELECT top("mean_usage", "pod", %d) 
+		FROM (SELECT mean("usage_percent") AS "mean_usage" 
+		 FROM "container_cpu" 
+		 WHERE "namespace" = '%s' AND time >= '%s' AND time < '%s' 
+		 GROUP BY "pod")`,
+		limit,
+		k.GetRandomNamespace(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := fmt.Sprintf("Influx top %d pods by cpu per namespace", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// NamespaceMemory calculates the average memory working set per namespace
+// per minute over a random hour.
+func (k *K8s) NamespaceMemory(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.NamespaceMemoryDuration)
+	influxql := fmt.Sprintf(`SELECT mean("working_set_bytes") 
+		FROM "container_memory" 
+		WHERE time >= '%s' AND time < '%s' 
+		GROUP BY time(1m), "namespace"`,
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx memory per namespace, random 1h0m0s by 1m"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// NodeCPU calculates the average cpu usage of the pods on nNodes random nodes
+// per 10 minutes over a random 12 hours.
+func (k *K8s) NodeCPU(qi query.Query, nNodes int) {
+	interval := k.Interval.MustRandWindow(k8s.NodeCPUDuration)
+	influxql := fmt.Sprintf(`SELECT mean("usage_percent") 
+		FROM "container_cpu" 
+		WHERE %s AND time >= '%s' AND time < '%s' 
+		GROUP BY time(10m), "node"`,
+		k.getNodeWhereString(nNodes),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := fmt.Sprintf("Influx cpu per node, random %4d nodes, random 12h0m0s by 10m", nNodes)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// PodRestarts finds the pods of a random namespace that restarted during a
+// random hour.
+func (k *K8s) PodRestarts(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.PodRestartsDuration)
+	influxql := fmt.Sprintf(`SELECT "restarts" 
+		FROM (SELECT spread("restarts_total") AS "restarts" 
+		 FROM "container_status" 
+		 WHERE "namespace" = '%s' AND time >= '%s' AND time < '%s' 
+		 GROUP BY "pod") 
+		WHERE "restarts" > 0 
+		GROUP BY "pod"`,
+		k.GetRandomNamespace(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx restarted pods per namespace"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// PodChurn counts the distinct pods reporting per namespace per hour over a
+// random day.
+func (k *K8s) PodChurn(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.PodChurnDuration)
+	influxql := fmt.Sprintf(`SELECT count("max_usage") AS "pods" 
+		FROM (SELECT max("usage_percent") AS "max_usage" 
+		 FROM "container_cpu" 
+		 WHERE time >= '%s' AND time < '%s' 
+		 GROUP BY time(1h), "namespace", "pod") 
+		WHERE time >= '%s' AND time < '%s' 
+		GROUP BY time(1h), "namespace"`,
+		interval.StartString(),
+		interval.EndString(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx pods per namespace, random 24h0m0s by 1h"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
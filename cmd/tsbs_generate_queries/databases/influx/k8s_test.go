@@ -0,0 +1,147 @@
+package influx
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestTopPodsCPUPerNamespace(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:  "top 5 pods",
+			input: 5,
+
+			expectedHumanLabel: "Influx top 5 pods by cpu per namespace",
+			expectedHumanDesc:  "Influx top 5 pods by cpu per namespace: 1970-01-02T02:16:22Z",
+			expectedQuery: `SELECT top("mean_usage", "pod", 5) 
+		FROM (SELECT mean("usage_percent") AS "mean_usage" 
+		 FROM "container_cpu" 
+		 WHERE "namespace" = 'kube-system' AND time >= '1970-01-02T02:16:22Z' AND time < '1970-01-02T03:16:22Z' 
+		 GROUP BY "pod")`,
+		},
+	}
+
+	testFunc := func(k *K8s, c testCase) query.Query {
+		q := k.GenerateEmptyQuery()
+		k.TopPodsCPUPerNamespace(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(48 * time.Hour)
+
+	runK8sTestCases(t, testFunc, start, end, cases)
+}
+
+func TestNodeCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:    "zero nodes",
+			input:   0,
+			fail:    true,
+			failMsg: "number of nodes cannot be < 1; got 0",
+		},
+		{
+			desc:    "more nodes than scale",
+			input:   20,
+			fail:    true,
+			failMsg: "number of nodes (20) larger than total nodes (10). See --scale",
+		},
+		{
+			desc:  "two nodes",
+			input: 2,
+
+			expectedHumanLabel: "Influx cpu per node, random    2 nodes, random 12h0m0s by 10m",
+			expectedHumanDesc:  "Influx cpu per node, random    2 nodes, random 12h0m0s by 10m: 1970-01-02T05:47:30Z",
+			expectedQuery: `SELECT mean("usage_percent") 
+		FROM "container_cpu" 
+		WHERE ("node" = 'node_5' or "node" = 'node_9') AND time >= '1970-01-02T05:47:30Z' AND time < '1970-01-02T17:47:30Z' 
+		GROUP BY time(10m), "node"`,
+		},
+	}
+
+	testFunc := func(k *K8s, c testCase) query.Query {
+		q := k.GenerateEmptyQuery()
+		k.NodeCPU(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(48 * time.Hour)
+
+	runK8sTestCases(t, testFunc, start, end, cases)
+}
+
+func TestPodRestarts(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "restarted pods",
+
+			expectedHumanLabel: "Influx restarted pods per namespace",
+			expectedHumanDesc:  "Influx restarted pods per namespace: 1970-01-02T02:16:22Z",
+			expectedQuery: `SELECT "restarts" 
+		FROM (SELECT spread("restarts_total") AS "restarts" 
+		 FROM "container_status" 
+		 WHERE "namespace" = 'kube-system' AND time >= '1970-01-02T02:16:22Z' AND time < '1970-01-02T03:16:22Z' 
+		 GROUP BY "pod") 
+		WHERE "restarts" > 0 
+		GROUP BY "pod"`,
+		},
+	}
+
+	testFunc := func(k *K8s, c testCase) query.Query {
+		q := k.GenerateEmptyQuery()
+		k.PodRestarts(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(48 * time.Hour)
+
+	runK8sTestCases(t, testFunc, start, end, cases)
+}
+
+func runK8sTestCases(t *testing.T, testFunc func(*K8s, testCase) query.Query, s time.Time, e time.Time, cases []testCase) {
+	rand.Seed(123) // Setting seed for testing purposes.
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			b := BaseGenerator{}
+			kq, err := b.NewK8s(s, e, 100)
+			if err != nil {
+				t.Fatalf("Error while creating k8s generator")
+			}
+			k := kq.(*K8s)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Errorf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(k, c)
+				}()
+			} else {
+				q := testFunc(k, c)
+
+				v := url.Values{}
+				v.Set("q", c.expectedQuery)
+				expectedPath := fmt.Sprintf("/query?%s", v.Encode())
+
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, expectedPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/promql"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// naming is how the Prometheus target names its series: after the field
+// alone, e.g. usage_user, with the tags of the point as labels.
+var naming = promql.Naming{Label: "Prometheus"}
+
+// BaseGenerator contains settings specific for the Prometheus query API.
+type BaseGenerator struct{}
+
+// GenerateEmptyQuery returns an empty query.HTTP.
+func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
+	return query.NewHTTP()
+}
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &Devops{
+		BaseGenerator: g,
+		Devops:        promql.NewDevops(core, naming),
+	}, nil
+}
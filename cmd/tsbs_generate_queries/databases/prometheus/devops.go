@@ -0,0 +1,10 @@
+package prometheus
+
+import "github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/promql"
+
+// Devops produces PromQL queries for the devops query types that can be
+// expressed as range queries.
+type Devops struct {
+	*BaseGenerator
+	*promql.Devops
+}
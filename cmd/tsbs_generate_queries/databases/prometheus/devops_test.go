@@ -0,0 +1,145 @@
+package prometheus
+
+import (
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestDevopsQueries(t *testing.T) {
+	testCases := map[string]struct {
+		fn        func(g *Devops, q *query.HTTP)
+		expQuery  string
+		expStep   string
+		expLabel  string
+		expToFail bool
+	}{
+		"GroupByTime_1_1": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.GroupByTime(q, 1, 1, time.Hour)
+			},
+			expQuery: "max(max_over_time(usage_user{hostname='host_5'}[1m])) by (__name__)",
+			expStep:  "60",
+			expLabel: "Prometheus 1 cpu metric(s), random    1 hosts, random 1h0m0s by 1m",
+		},
+		"GroupByTime_5_1": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.GroupByTime(q, 5, 1, time.Hour)
+			},
+			expQuery: "max(max_over_time(usage_user{hostname=~'host_5|host_9|host_3|host_1|host_7'}[1m])) by (__name__)",
+			expStep:  "60",
+			expLabel: "Prometheus 1 cpu metric(s), random    5 hosts, random 1h0m0s by 1m",
+		},
+		"GroupByTime_5_5": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.GroupByTime(q, 5, 5, time.Hour)
+			},
+			expQuery: "max(max_over_time({__name__=~'(usage_user|usage_system|usage_idle|usage_nice|usage_iowait)', hostname=~'host_5|host_9|host_3|host_1|host_7'}[1m])) by (__name__)",
+			expStep:  "60",
+			expLabel: "Prometheus 5 cpu metric(s), random    5 hosts, random 1h0m0s by 1m",
+		},
+		"GroupByTimeAndPrimaryTag": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.GroupByTimeAndPrimaryTag(q, 5)
+			},
+			expQuery: "avg(avg_over_time({__name__=~'(usage_user|usage_system|usage_idle|usage_nice|usage_iowait)'}[1h])) by (__name__, hostname)",
+			expStep:  "3600",
+			expLabel: "Prometheus mean of 5 metrics, all hosts, random 12h0m0s by 1h",
+		},
+		"MaxAllCPU": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.MaxAllCPU(q, 5, devops.MaxAllDuration)
+			},
+			expQuery: "max(max_over_time({__name__=~'(usage_user|usage_system|usage_idle|usage_nice|usage_iowait|usage_irq|usage_softirq|usage_steal|usage_guest|usage_guest_nice)', hostname=~'host_5|host_9|host_3|host_1|host_7'}[1h])) by (__name__)",
+			expStep:  "3600",
+			expLabel: "Prometheus max of all CPU metrics, random    5 hosts, random 8h0m0s by 1h",
+		},
+		"GroupByOrderByLimit": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.GroupByOrderByLimit(q)
+			},
+			expToFail: true,
+		},
+		"LastPointPerHost": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.LastPointPerHost(q)
+			},
+			expToFail: true,
+		},
+		"HighCPUForHosts": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.HighCPUForHosts(q, 6)
+			},
+			expToFail: true,
+		},
+		"GroupByTime_negative_metrics": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.GroupByTime(q, 1, -1, time.Hour)
+			},
+			expToFail: true,
+		},
+	}
+	g := acquireGenerator(t, time.Hour*24, 10)
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			q := g.GenerateEmptyQuery().(*query.HTTP)
+			if tc.expToFail {
+				func() {
+					defer func() {
+						if recover() == nil {
+							t.Errorf("expected to panic")
+						}
+					}()
+					tc.fn(g, q)
+				}()
+				return
+			}
+
+			tc.fn(g, q)
+			path := string(q.Path)
+			if !strings.HasPrefix(path, "/api/v1/query_range?") {
+				t.Fatalf("incorrect path: got %s", path)
+			}
+			vals, err := url.ParseQuery(strings.TrimPrefix(path, "/api/v1/query_range?"))
+			if err != nil {
+				t.Fatalf("unexpected err while parsing query: %s", err)
+			}
+			checkEqual(t, "query", tc.expQuery, vals.Get("query"))
+			checkEqual(t, "raw query", tc.expQuery, string(q.RawQuery))
+			checkEqual(t, "step", tc.expStep, vals.Get("step"))
+			checkEqual(t, "label", tc.expLabel, string(q.HumanLabel))
+			checkEqual(t, "method", http.MethodGet, string(q.Method))
+			start, _ := strconv.ParseInt(vals.Get("start"), 10, 64)
+			end, _ := strconv.ParseInt(vals.Get("end"), 10, 64)
+			if start >= end {
+				t.Errorf("start %d not before end %d", start, end)
+			}
+		})
+	}
+}
+
+func checkEqual(t *testing.T, name, a, b string) {
+	t.Helper()
+	if a != b {
+		t.Fatalf("values for %q are not equal \na: %q \nb: %q", name, a, b)
+	}
+}
+
+func acquireGenerator(t *testing.T, interval time.Duration, scale int) *Devops {
+	b := &BaseGenerator{}
+	s := time.Unix(0, 0)
+	e := s.Add(interval)
+	g, err := b.NewDevops(s, e, scale)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	return g.(*Devops)
+}
@@ -0,0 +1,121 @@
+package promql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Devops produces PromQL queries for the devops query types that can be
+// expressed as range queries. Every series is named after its field, as set
+// by Naming, with the tags of the point as labels.
+type Devops struct {
+	*devops.Core
+	Naming
+}
+
+// NewDevops creates a devops query generator for series named by naming.
+func NewDevops(core *devops.Core, naming Naming) *Devops {
+	return &Devops{Core: core, Naming: naming}
+}
+
+// mustGetRandomHosts is the form of GetRandomHosts that cannot error; if it does error,
+// it causes a panic.
+func (d *Devops) mustGetRandomHosts(nHosts int) []string {
+	hosts, err := d.GetRandomHosts(nHosts)
+	if err != nil {
+		panic(err.Error())
+	}
+	return hosts
+}
+
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	panic("GroupByOrderByLimit not supported in PromQL")
+}
+
+func (d *Devops) LastPointPerHost(qq query.Query) {
+	panic("LastPointPerHost not supported in PromQL")
+}
+
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	panic("HighCPUForHosts not supported in PromQL")
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu'
+// per minute for nhosts hosts,
+// e.g. in pseudo-PromQL:
+//
+//	max(
+//		max_over_time(
+//			{__name__=~"metric1|metric2...|metricN",hostname=~"hostname1|hostname2...|hostnameN"}[1m]
+//		)
+//	) by (__name__)
+func (d *Devops) GroupByTime(qq query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	metrics := mustGetCPUMetricsSlice(numMetrics)
+	hosts := d.mustGetRandomHosts(nHosts)
+	selectClause := d.SelectClause(metrics, hosts)
+	qi := &QueryInfo{
+		Query:    fmt.Sprintf("max(max_over_time(%s[1m])) by (__name__)", selectClause),
+		Label:    fmt.Sprintf("%s %d cpu metric(s), random %4d hosts, random %s by 1m", d.Label, numMetrics, nHosts, timeRange),
+		Interval: d.Interval.MustRandWindow(timeRange),
+		Step:     "60",
+	}
+	FillInQuery(qq, qi)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-PromQL:
+//
+//	avg(
+//		avg_over_time(
+//			{__name__=~"metric1|metric2...|metricN"}[1h]
+//		)
+//	) by (__name__, hostname)
+//
+// Resultsets:
+// double-groupby-1
+// double-groupby-5
+// double-groupby-all
+func (d *Devops) GroupByTimeAndPrimaryTag(qq query.Query, numMetrics int) {
+	metrics := mustGetCPUMetricsSlice(numMetrics)
+	selectClause := d.SelectClause(metrics, nil)
+	qi := &QueryInfo{
+		Query:    fmt.Sprintf("avg(avg_over_time(%s[1h])) by (__name__, hostname)", selectClause),
+		Label:    devops.GetDoubleGroupByLabel(d.Label, numMetrics),
+		Interval: d.Interval.MustRandWindow(devops.DoubleGroupByDuration),
+		Step:     "3600",
+	}
+	FillInQuery(qq, qi)
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-PromQL:
+//
+//	max(
+//		max_over_time(
+//			{__name__=~"metric1|metric2...|metricN",hostname=~"hostname1|hostname2...|hostnameN"}[1h]
+//		)
+//	) by (__name__)
+func (d *Devops) MaxAllCPU(qq query.Query, nHosts int, duration time.Duration) {
+	hosts := d.mustGetRandomHosts(nHosts)
+	selectClause := d.SelectClause(devops.GetAllCPUMetrics(), hosts)
+	qi := &QueryInfo{
+		Query:    fmt.Sprintf("max(max_over_time(%s[1h])) by (__name__)", selectClause),
+		Label:    devops.GetMaxAllLabel(d.Label, nHosts),
+		Interval: d.Interval.MustRandWindow(duration),
+		Step:     "3600",
+	}
+	FillInQuery(qq, qi)
+}
+
+// mustGetCPUMetricsSlice is the form of GetCPUMetricsSlice that cannot error; if it does error,
+// it causes a panic.
+func mustGetCPUMetricsSlice(numMetrics int) []string {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	if err != nil {
+		panic(err.Error())
+	}
+	return metrics
+}
@@ -0,0 +1,81 @@
+// Package promql builds the PromQL range queries of the databases queried
+// through the Prometheus HTTP API. The databases only differ in how they name
+// their series, which is given as a Naming.
+package promql
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	iutils "github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Naming describes how a database names the series it is queried for.
+type Naming struct {
+	// Label starts the human label of every query, e.g. Prometheus
+	Label string
+	// MetricPrefix goes before the name of a field to make the name of its
+	// metric, e.g. cpu_ when usage_user of cpu is stored as cpu_usage_user
+	MetricPrefix string
+}
+
+// QueryInfo describes a range query.
+type QueryInfo struct {
+	// PromQL query
+	Query string
+	// label to describe type of query
+	Label string
+	// time range for query executing
+	Interval *iutils.TimeInterval
+	// time period to group by in seconds
+	Step string
+}
+
+// FillInQuery fills the query struct with a range query of the HTTP API.
+func FillInQuery(qq query.Query, qi *QueryInfo) {
+	q := qq.(*query.HTTP)
+	q.HumanLabel = []byte(qi.Label)
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s", qi.Label, qi.Interval.StartString()))
+	q.Method = []byte("GET")
+
+	v := url.Values{}
+	v.Set("query", qi.Query)
+	v.Set("start", strconv.FormatInt(qi.Interval.StartUnixNano()/1e9, 10))
+	v.Set("end", strconv.FormatInt(qi.Interval.EndUnixNano()/1e9, 10))
+	v.Set("step", qi.Step)
+	q.Path = []byte(fmt.Sprintf("/api/v1/query_range?%s", v.Encode()))
+	q.RawQuery = []byte(qi.Query)
+	q.Body = nil
+}
+
+// SelectClause selects the series of the given fields for the given hosts,
+// or for all hosts if there are none.
+func (n Naming) SelectClause(metrics, hosts []string) string {
+	if len(metrics) == 0 {
+		panic("BUG: must be at least one metric name in clause")
+	}
+
+	hostsClause := hostClause(hosts)
+	if len(metrics) == 1 {
+		return fmt.Sprintf("%s%s{%s}", n.MetricPrefix, metrics[0], hostsClause)
+	}
+
+	metricsClause := fmt.Sprintf("%s(%s)", n.MetricPrefix, strings.Join(metrics, "|"))
+	if len(hosts) > 0 {
+		return fmt.Sprintf("{__name__=~'%s', %s}", metricsClause, hostsClause)
+	}
+	return fmt.Sprintf("{__name__=~'%s'}", metricsClause)
+}
+
+func hostClause(hostnames []string) string {
+	if len(hostnames) == 0 {
+		return ""
+	}
+	if len(hostnames) == 1 {
+		return fmt.Sprintf("hostname='%s'", hostnames[0])
+	}
+	return fmt.Sprintf("hostname=~'%s'", strings.Join(hostnames, "|"))
+}
@@ -0,0 +1,98 @@
+package questdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Finance produces QuestDB-specific queries for all the finance query types.
+type Finance struct {
+	*BaseGenerator
+	*finance.Core
+}
+
+func (f *Finance) getRandomSymbol() string {
+	symbols, err := f.GetRandomSymbols(1)
+	panicIfErr(err)
+	return symbols[0]
+}
+
+// OHLCV calculates 1-minute open, high, low, close and volume bars of
+// nSymbols random symbols over a random hour.
+//
+// Queries:
+// ohlcv-1
+// ohlcv-10
+func (f *Finance) OHLCV(qi query.Query, nSymbols int) {
+	interval := f.Interval.MustRandWindow(finance.OHLCVDuration)
+	symbols, err := f.GetRandomSymbols(nSymbols)
+	panicIfErr(err)
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp, symbol,
+			first(price) AS open, max(price) AS high, min(price) AS low, last(price) AS close, sum(size) AS volume
+		FROM trades
+		WHERE symbol IN ('%s')
+		  AND timestamp >= '%s'
+		  AND timestamp < '%s'
+		SAMPLE BY 1m`,
+		strings.Join(symbols, "', '"),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := fmt.Sprintf("QuestDB OHLCV bars, random %4d symbols, random 1h0m0s by 1m", nSymbols)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// VWAP calculates the hourly volume weighted average price of a random symbol
+// over a random day.
+//
+// Queries:
+// vwap
+func (f *Finance) VWAP(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.VWAPDuration)
+	sql := fmt.Sprintf(`
+		SELECT timestamp,
+			sum(price * size) / sum(size) AS vwap, sum(size) AS volume
+		FROM trades
+		WHERE symbol = '%s'
+		  AND timestamp >= '%s'
+		  AND timestamp < '%s'
+		SAMPLE BY 1h`,
+		f.getRandomSymbol(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "QuestDB VWAP, random symbol, random 24h0m0s by 1h"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// AsOfQuote finds the last quote before every trade of a random symbol over a
+// random hour.
+//
+// Queries:
+// asof-quote
+func (f *Finance) AsOfQuote(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.AsOfQuoteDuration)
+	symbol := f.getRandomSymbol()
+	sql := fmt.Sprintf(`
+		SELECT t.timestamp, t.price, t.size, q.bid_price, q.ask_price
+		FROM (trades
+		  WHERE symbol = '%s'
+		    AND timestamp >= '%s'
+		    AND timestamp < '%s') t
+		ASOF JOIN (quotes WHERE symbol = '%s') q`,
+		symbol,
+		interval.StartString(),
+		interval.EndString(),
+		symbol)
+
+	humanLabel := "QuestDB last quote before trade, random symbol, random 1h0m0s"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
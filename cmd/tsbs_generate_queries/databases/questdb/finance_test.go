@@ -0,0 +1,74 @@
+package questdb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestFinanceOHLCV(t *testing.T) {
+	expectedHumanLabel := "QuestDB OHLCV bars, random    2 symbols, random 1h0m0s by 1m"
+	expectedHumanDesc := "QuestDB OHLCV bars, random    2 symbols, random 1h0m0s by 1m: 1970-01-02T02:16:22Z"
+	expectedQuery := "SELECT timestamp, symbol, first(price) AS open, max(price) AS high, min(price) AS low, last(price) AS close, sum(size) AS volume FROM trades " +
+		"WHERE symbol IN ('symbol_9', 'symbol_3') AND timestamp >= '1970-01-02T02:16:22Z' AND timestamp < '1970-01-02T03:16:22Z' SAMPLE BY 1m"
+
+	f := newTestFinance(t)
+	q := f.GenerateEmptyQuery()
+	f.OHLCV(q, 2)
+
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedQuery)
+}
+
+func TestFinanceVWAP(t *testing.T) {
+	expectedHumanLabel := "QuestDB VWAP, random symbol, random 24h0m0s by 1h"
+	expectedHumanDesc := "QuestDB VWAP, random symbol, random 24h0m0s by 1h: 1970-01-01T18:16:22Z"
+	expectedQuery := "SELECT timestamp, sum(price * size) / sum(size) AS vwap, sum(size) AS volume FROM trades " +
+		"WHERE symbol = 'symbol_9' AND timestamp >= '1970-01-01T18:16:22Z' AND timestamp < '1970-01-02T18:16:22Z' SAMPLE BY 1h"
+
+	f := newTestFinance(t)
+	q := f.GenerateEmptyQuery()
+	f.VWAP(q)
+
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedQuery)
+}
+
+func TestFinanceAsOfQuote(t *testing.T) {
+	expectedHumanLabel := "QuestDB last quote before trade, random symbol, random 1h0m0s"
+	expectedHumanDesc := "QuestDB last quote before trade, random symbol, random 1h0m0s: 1970-01-02T02:16:22Z"
+	expectedQuery := "SELECT t.timestamp, t.price, t.size, q.bid_price, q.ask_price " +
+		"FROM (trades WHERE symbol = 'symbol_9' AND timestamp >= '1970-01-02T02:16:22Z' AND timestamp < '1970-01-02T03:16:22Z') t " +
+		"ASOF JOIN (quotes WHERE symbol = 'symbol_9') q"
+
+	f := newTestFinance(t)
+	q := f.GenerateEmptyQuery()
+	f.AsOfQuote(q)
+
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedQuery)
+}
+
+func TestFinanceOHLCVTooManySymbols(t *testing.T) {
+	f := newTestFinance(t)
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("did not panic when should")
+		}
+		want := "number of symbols (20) larger than total symbols (10). See --scale"
+		if r != want {
+			t.Fatalf("incorrect fail message: got %s, want %s", r, want)
+		}
+	}()
+	f.OHLCV(f.GenerateEmptyQuery(), 20)
+}
+
+func newTestFinance(t *testing.T) *Finance {
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	b := BaseGenerator{}
+	fq, err := b.NewFinance(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating finance generator")
+	}
+	return fq.(*Finance)
+}
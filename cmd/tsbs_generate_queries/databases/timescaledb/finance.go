@@ -0,0 +1,97 @@
+package timescaledb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Finance produces TimescaleDB-specific queries for all the finance query types.
+type Finance struct {
+	*finance.Core
+	*BaseGenerator
+}
+
+func (f *Finance) getRandomSymbol() string {
+	symbols, err := f.GetRandomSymbols(1)
+	panicIfErr(err)
+	return symbols[0]
+}
+
+// OHLCV calculates 1-minute open, high, low, close and volume bars of
+// nSymbols random symbols over a random hour.
+func (f *Finance) OHLCV(qi query.Query, nSymbols int) {
+	interval := f.Interval.MustRandWindow(finance.OHLCVDuration)
+	symbols, err := f.GetRandomSymbols(nSymbols)
+	panicIfErr(err)
+
+	sql := fmt.Sprintf(`SELECT %s AS minute, t.%s,
+		first(r.price, r.time) AS open, max(r.price) AS high, min(r.price) AS low, last(r.price, r.time) AS close, sum(r.size) AS volume
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE t.%s IN ('%s')
+		AND r.time >= '%s' AND r.time < '%s'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		f.getTimeBucket(oneMinute),
+		f.withAlias("symbol"),
+		f.columnSelect("symbol"),
+		strings.Join(symbols, "','"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := fmt.Sprintf("TimescaleDB OHLCV bars, random %4d symbols, random 1h0m0s by 1m", nSymbols)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradesTableName, sql)
+}
+
+// VWAP calculates the hourly volume weighted average price of a random symbol
+// over a random day.
+func (f *Finance) VWAP(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.VWAPDuration)
+	sql := fmt.Sprintf(`SELECT %s AS hour, sum(r.price * r.size) / sum(r.size) AS vwap, sum(r.size) AS volume
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE t.%s = '%s'
+		AND r.time >= '%s' AND r.time < '%s'
+		GROUP BY 1
+		ORDER BY 1`,
+		f.getTimeBucket(oneHour),
+		f.columnSelect("symbol"),
+		f.getRandomSymbol(),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB VWAP, random symbol, random 24h0m0s by 1h"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradesTableName, sql)
+}
+
+// AsOfQuote finds the last quote before every trade of a random symbol over a
+// random hour.
+func (f *Finance) AsOfQuote(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.AsOfQuoteDuration)
+	sql := fmt.Sprintf(`SELECT r.time, r.price, r.size, q.bid_price, q.ask_price
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		INNER JOIN LATERAL (
+		 SELECT bid_price, ask_price
+		 FROM quotes
+		 WHERE quotes.tags_id = r.tags_id AND quotes.time <= r.time
+		 ORDER BY quotes.time DESC
+		 LIMIT 1
+		) q ON true
+		WHERE t.%s = '%s'
+		AND r.time >= '%s' AND r.time < '%s'
+		ORDER BY r.time`,
+		f.columnSelect("symbol"),
+		f.getRandomSymbol(),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB last quote before trade, random symbol, random 1h0m0s"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradesTableName, sql)
+}
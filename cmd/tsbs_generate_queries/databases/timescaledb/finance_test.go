@@ -0,0 +1,113 @@
+package timescaledb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestFinanceQueries(t *testing.T) {
+	cases := []struct {
+		desc          string
+		useJSON       bool
+		useTimeBucket bool
+		fn            func(*Finance, query.Query)
+
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedHypertable string
+		expectedSQLQuery   string
+	}{
+		{
+			desc: "ohlcv",
+			fn:   func(f *Finance, q query.Query) { f.OHLCV(q, 2) },
+
+			expectedHumanLabel: "TimescaleDB OHLCV bars, random    2 symbols, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TimescaleDB OHLCV bars, random    2 symbols, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedHypertable: finance.TradesTableName,
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/60)*60) AS minute, t.symbol AS symbol,
+		first(r.price, r.time) AS open, max(r.price) AS high, min(r.price) AS low, last(r.price, r.time) AS close, sum(r.size) AS volume
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE t.symbol IN ('symbol_9','symbol_3')
+		AND r.time >= '1970-01-02 02:16:22.646325 +0000' AND r.time < '1970-01-02 03:16:22.646325 +0000'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		},
+		{
+			desc:          "ohlcv use json and time bucket",
+			useJSON:       true,
+			useTimeBucket: true,
+			fn:            func(f *Finance, q query.Query) { f.OHLCV(q, 2) },
+
+			expectedHumanLabel: "TimescaleDB OHLCV bars, random    2 symbols, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TimescaleDB OHLCV bars, random    2 symbols, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedHypertable: finance.TradesTableName,
+			expectedSQLQuery: `SELECT time_bucket('60 seconds', time) AS minute, t.tagset->>'symbol' AS symbol,
+		first(r.price, r.time) AS open, max(r.price) AS high, min(r.price) AS low, last(r.price, r.time) AS close, sum(r.size) AS volume
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE t.tagset->>'symbol' IN ('symbol_9','symbol_3')
+		AND r.time >= '1970-01-02 02:16:22.646325 +0000' AND r.time < '1970-01-02 03:16:22.646325 +0000'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		},
+		{
+			desc: "vwap",
+			fn:   func(f *Finance, q query.Query) { f.VWAP(q) },
+
+			expectedHumanLabel: "TimescaleDB VWAP, random symbol, random 24h0m0s by 1h",
+			expectedHumanDesc:  "TimescaleDB VWAP, random symbol, random 24h0m0s by 1h: 1970-01-01T18:16:22Z",
+			expectedHypertable: finance.TradesTableName,
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/3600)*3600) AS hour, sum(r.price * r.size) / sum(r.size) AS vwap, sum(r.size) AS volume
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE t.symbol = 'symbol_9'
+		AND r.time >= '1970-01-01 18:16:22.646325 +0000' AND r.time < '1970-01-02 18:16:22.646325 +0000'
+		GROUP BY 1
+		ORDER BY 1`,
+		},
+		{
+			desc: "asof quote",
+			fn:   func(f *Finance, q query.Query) { f.AsOfQuote(q) },
+
+			expectedHumanLabel: "TimescaleDB last quote before trade, random symbol, random 1h0m0s",
+			expectedHumanDesc:  "TimescaleDB last quote before trade, random symbol, random 1h0m0s: 1970-01-02T02:16:22Z",
+			expectedHypertable: finance.TradesTableName,
+			expectedSQLQuery: `SELECT r.time, r.price, r.size, q.bid_price, q.ask_price
+		FROM trades r
+		INNER JOIN tags t ON r.tags_id = t.id
+		INNER JOIN LATERAL (
+		 SELECT bid_price, ask_price
+		 FROM quotes
+		 WHERE quotes.tags_id = r.tags_id AND quotes.time <= r.time
+		 ORDER BY quotes.time DESC
+		 LIMIT 1
+		) q ON true
+		WHERE t.symbol = 'symbol_9'
+		AND r.time >= '1970-01-02 02:16:22.646325 +0000' AND r.time < '1970-01-02 03:16:22.646325 +0000'
+		ORDER BY r.time`,
+		},
+	}
+
+	for _, c := range cases {
+		rand.Seed(123)
+		b := BaseGenerator{
+			UseJSON:       c.useJSON,
+			UseTimeBucket: c.useTimeBucket,
+		}
+		s := time.Unix(0, 0)
+		fg, err := b.NewFinance(s, s.Add(48*time.Hour), 10)
+		if err != nil {
+			t.Fatalf("Error while creating finance generator")
+		}
+
+		f := fg.(*Finance)
+		q := f.GenerateEmptyQuery()
+		c.fn(f, q)
+		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	}
+}
@@ -0,0 +1,138 @@
+package timescaledb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const tenMinutes = 10 * oneMinute
+
+// K8s produces TimescaleDB-specific queries for all the k8s query types.
+type K8s struct {
+	*k8s.Core
+	*BaseGenerator
+}
+
+func (k *K8s) getNodeWhereString(nNodes int) string {
+	nodes, err := k.GetRandomNodes(nNodes)
+	panicIfErr(err)
+
+	nodeClauses := make([]string, len(nodes))
+	for i, n := range nodes {
+		nodeClauses[i] = fmt.Sprintf("'%s'", n)
+	}
+	return fmt.Sprintf("t.%s IN (%s)", k.columnSelect("node"), strings.Join(nodeClauses, ","))
+}
+
+// TopPodsCPUPerNamespace finds the limit pods of a random namespace with the
+// highest average cpu usage over a random hour.
+func (k *K8s) TopPodsCPUPerNamespace(qi query.Query, limit int) {
+	interval := k.Interval.MustRandWindow(k8s.TopPodsCPUDuration)
+	sql := fmt.Sprintf(`SELECT t.%s, avg(c.usage_percent) AS avg_usage
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE t.%s = '%s'
+		AND c.time >= '%s' AND c.time < '%s'
+		GROUP BY 1
+		ORDER BY avg_usage DESC
+		LIMIT %d`,
+		k.withAlias("pod"),
+		k.columnSelect("namespace"),
+		k.GetRandomNamespace(),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		limit)
+
+	humanLabel := fmt.Sprintf("TimescaleDB top %d pods by cpu per namespace", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerCPUTableName, sql)
+}
+
+// NamespaceMemory calculates the average memory working set per namespace
+// per minute over a random hour.
+func (k *K8s) NamespaceMemory(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.NamespaceMemoryDuration)
+	sql := fmt.Sprintf(`SELECT %s AS minute, t.%s, avg(m.working_set_bytes) AS avg_working_set
+		FROM container_memory m
+		INNER JOIN tags t ON m.tags_id = t.id
+		WHERE m.time >= '%s' AND m.time < '%s'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		k.getTimeBucket(oneMinute),
+		k.withAlias("namespace"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB memory per namespace, random 1h0m0s by 1m"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerMemoryTableName, sql)
+}
+
+// NodeCPU calculates the average cpu usage of the pods on nNodes random nodes
+// per 10 minutes over a random 12 hours.
+func (k *K8s) NodeCPU(qi query.Query, nNodes int) {
+	interval := k.Interval.MustRandWindow(k8s.NodeCPUDuration)
+	sql := fmt.Sprintf(`SELECT %s AS bucket, t.%s, avg(c.usage_percent) AS avg_usage
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE %s
+		AND c.time >= '%s' AND c.time < '%s'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		k.getTimeBucket(tenMinutes),
+		k.withAlias("node"),
+		k.getNodeWhereString(nNodes),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := fmt.Sprintf("TimescaleDB cpu per node, random %4d nodes, random 12h0m0s by 10m", nNodes)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerCPUTableName, sql)
+}
+
+// PodRestarts finds the pods of a random namespace that restarted during a
+// random hour.
+func (k *K8s) PodRestarts(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.PodRestartsDuration)
+	sql := fmt.Sprintf(`SELECT t.%s, max(s.restarts_total) - min(s.restarts_total) AS restarts
+		FROM container_status s
+		INNER JOIN tags t ON s.tags_id = t.id
+		WHERE t.%s = '%s'
+		AND s.time >= '%s' AND s.time < '%s'
+		GROUP BY 1
+		HAVING max(s.restarts_total) > min(s.restarts_total)
+		ORDER BY restarts DESC`,
+		k.withAlias("pod"),
+		k.columnSelect("namespace"),
+		k.GetRandomNamespace(),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB restarted pods per namespace"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerStatusTableName, sql)
+}
+
+// PodChurn counts the distinct pods reporting per namespace per hour over a
+// random day.
+func (k *K8s) PodChurn(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.PodChurnDuration)
+	sql := fmt.Sprintf(`SELECT %s AS hour, t.%s, count(DISTINCT t.%s) AS pods
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE c.time >= '%s' AND c.time < '%s'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		k.getTimeBucket(oneHour),
+		k.withAlias("namespace"),
+		k.columnSelect("pod"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB pods per namespace, random 24h0m0s by 1h"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerCPUTableName, sql)
+}
@@ -0,0 +1,172 @@
+package timescaledb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestK8sQueries(t *testing.T) {
+	cases := []struct {
+		desc          string
+		useJSON       bool
+		useTimeBucket bool
+		fn            func(*K8s, query.Query)
+		fail          bool
+
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedHypertable string
+		expectedSQLQuery   string
+	}{
+		{
+			desc: "top pods by cpu",
+			fn:   func(k *K8s, q query.Query) { k.TopPodsCPUPerNamespace(q, 5) },
+
+			expectedHumanLabel: "TimescaleDB top 5 pods by cpu per namespace",
+			expectedHumanDesc:  "TimescaleDB top 5 pods by cpu per namespace: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerCPUTableName,
+			expectedSQLQuery: `SELECT t.pod AS pod, avg(c.usage_percent) AS avg_usage
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE t.namespace = 'kube-system'
+		AND c.time >= '1970-01-02 02:16:22.646325 +0000' AND c.time < '1970-01-02 03:16:22.646325 +0000'
+		GROUP BY 1
+		ORDER BY avg_usage DESC
+		LIMIT 5`,
+		},
+		{
+			desc:    "top pods by cpu use json",
+			useJSON: true,
+			fn:      func(k *K8s, q query.Query) { k.TopPodsCPUPerNamespace(q, 10) },
+
+			expectedHumanLabel: "TimescaleDB top 10 pods by cpu per namespace",
+			expectedHumanDesc:  "TimescaleDB top 10 pods by cpu per namespace: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerCPUTableName,
+			expectedSQLQuery: `SELECT t.tagset->>'pod' AS pod, avg(c.usage_percent) AS avg_usage
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE t.tagset->>'namespace' = 'kube-system'
+		AND c.time >= '1970-01-02 02:16:22.646325 +0000' AND c.time < '1970-01-02 03:16:22.646325 +0000'
+		GROUP BY 1
+		ORDER BY avg_usage DESC
+		LIMIT 10`,
+		},
+		{
+			desc: "cpu per node",
+			fn:   func(k *K8s, q query.Query) { k.NodeCPU(q, 2) },
+
+			expectedHumanLabel: "TimescaleDB cpu per node, random    2 nodes, random 12h0m0s by 10m",
+			expectedHumanDesc:  "TimescaleDB cpu per node, random    2 nodes, random 12h0m0s by 10m: 1970-01-01T06:16:22Z",
+			expectedHypertable: k8s.ContainerCPUTableName,
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/600)*600) AS bucket, t.node AS node, avg(c.usage_percent) AS avg_usage
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE t.node IN ('node_9','node_3')
+		AND c.time >= '1970-01-01 06:16:22.646325 +0000' AND c.time < '1970-01-01 18:16:22.646325 +0000'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		},
+		{
+			desc:    "cpu per node use json",
+			useJSON: true,
+			fn:      func(k *K8s, q query.Query) { k.NodeCPU(q, 2) },
+
+			expectedHumanLabel: "TimescaleDB cpu per node, random    2 nodes, random 12h0m0s by 10m",
+			expectedHumanDesc:  "TimescaleDB cpu per node, random    2 nodes, random 12h0m0s by 10m: 1970-01-01T06:16:22Z",
+			expectedHypertable: k8s.ContainerCPUTableName,
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/600)*600) AS bucket, t.tagset->>'node' AS node, avg(c.usage_percent) AS avg_usage
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE t.tagset->>'node' IN ('node_9','node_3')
+		AND c.time >= '1970-01-01 06:16:22.646325 +0000' AND c.time < '1970-01-01 18:16:22.646325 +0000'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		},
+		{
+			desc: "cpu per node more nodes than scale",
+			fn:   func(k *K8s, q query.Query) { k.NodeCPU(q, 20) },
+			fail: true,
+		},
+		{
+			desc:          "restarted pods",
+			useTimeBucket: true,
+			fn:            func(k *K8s, q query.Query) { k.PodRestarts(q) },
+
+			expectedHumanLabel: "TimescaleDB restarted pods per namespace",
+			expectedHumanDesc:  "TimescaleDB restarted pods per namespace: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerStatusTableName,
+			expectedSQLQuery: `SELECT t.pod AS pod, max(s.restarts_total) - min(s.restarts_total) AS restarts
+		FROM container_status s
+		INNER JOIN tags t ON s.tags_id = t.id
+		WHERE t.namespace = 'kube-system'
+		AND s.time >= '1970-01-02 02:16:22.646325 +0000' AND s.time < '1970-01-02 03:16:22.646325 +0000'
+		GROUP BY 1
+		HAVING max(s.restarts_total) > min(s.restarts_total)
+		ORDER BY restarts DESC`,
+		},
+		{
+			desc:          "pod churn",
+			useTimeBucket: true,
+			fn:            func(k *K8s, q query.Query) { k.PodChurn(q) },
+
+			expectedHumanLabel: "TimescaleDB pods per namespace, random 24h0m0s by 1h",
+			expectedHumanDesc:  "TimescaleDB pods per namespace, random 24h0m0s by 1h: 1970-01-01T18:16:22Z",
+			expectedHypertable: k8s.ContainerCPUTableName,
+			expectedSQLQuery: `SELECT time_bucket('3600 seconds', time) AS hour, t.namespace AS namespace, count(DISTINCT t.pod) AS pods
+		FROM container_cpu c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE c.time >= '1970-01-01 18:16:22.646325 +0000' AND c.time < '1970-01-02 18:16:22.646325 +0000'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		},
+		{
+			desc: "namespace memory",
+			fn:   func(k *K8s, q query.Query) { k.NamespaceMemory(q) },
+
+			expectedHumanLabel: "TimescaleDB memory per namespace, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TimescaleDB memory per namespace, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerMemoryTableName,
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/60)*60) AS minute, t.namespace AS namespace, avg(m.working_set_bytes) AS avg_working_set
+		FROM container_memory m
+		INNER JOIN tags t ON m.tags_id = t.id
+		WHERE m.time >= '1970-01-02 02:16:22.646325 +0000' AND m.time < '1970-01-02 03:16:22.646325 +0000'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		},
+	}
+
+	for _, c := range cases {
+		rand.Seed(123)
+		b := BaseGenerator{
+			UseJSON:       c.useJSON,
+			UseTimeBucket: c.useTimeBucket,
+		}
+		s := time.Unix(0, 0)
+		kg, err := b.NewK8s(s, s.Add(48*time.Hour), 100)
+		if err != nil {
+			t.Fatalf("Error while creating k8s generator")
+		}
+
+		g := kg.(*K8s)
+		q := g.GenerateEmptyQuery()
+
+		if c.fail {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s: expected to panic", c.desc)
+					}
+				}()
+				c.fn(g, q)
+			}()
+			continue
+		}
+
+		c.fn(g, q)
+		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	}
+}
@@ -1,17 +1,19 @@
 package victoriametrics
 
 import (
-	"fmt"
-	"net/url"
-	"strconv"
 	"time"
 
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/promql"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
-	iutils "github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/query"
 )
 
+// naming is how VictoriaMetrics names the series written in the InfluxDB line
+// protocol: the measurement and field joined, e.g. cpu_usage_user.
+var naming = promql.Naming{Label: "VictoriaMetrics", MetricPrefix: "cpu_"}
+
 type BaseGenerator struct{}
 
 // GenerateEmptyQuery returns an empty query.HTTP.
@@ -27,37 +29,18 @@ func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryG
 	}
 	return &Devops{
 		BaseGenerator: g,
-		Core:          core,
+		Devops:        promql.NewDevops(core, naming),
 	}, nil
 }
 
-type queryInfo struct {
-	// prometheus query
-	query string
-	// label to describe type of query
-	label string
-	// desc to describe type of query
-	desc string
-	// time range for query executing
-	interval *iutils.TimeInterval
-	// time period to group by in seconds
-	step string
-}
-
-// fill Query fills the query struct with data
-func (g *BaseGenerator) fillInQuery(qq query.Query, qi *queryInfo) {
-	q := qq.(*query.HTTP)
-	q.HumanLabel = []byte(qi.label)
-	if qi.interval != nil {
-		q.HumanDescription = []byte(fmt.Sprintf("%s: %s", qi.label, qi.interval.StartString()))
+// NewK8s creates a new k8s use case query generator.
+func (g *BaseGenerator) NewK8s(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := k8s.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
 	}
-	q.Method = []byte("GET")
-
-	v := url.Values{}
-	v.Set("query", qi.query)
-	v.Set("start", strconv.FormatInt(qi.interval.StartUnixNano()/1e9, 10))
-	v.Set("end", strconv.FormatInt(qi.interval.EndUnixNano()/1e9, 10))
-	v.Set("step", qi.step)
-	q.Path = []byte(fmt.Sprintf("/api/v1/query_range?%s", v.Encode()))
-	q.Body = nil
+	return &K8s{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
 }
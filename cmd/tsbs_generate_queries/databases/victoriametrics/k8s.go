@@ -0,0 +1,90 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/promql"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// K8s produces PromQL queries for all the k8s query types.
+type K8s struct {
+	*BaseGenerator
+	*k8s.Core
+}
+
+// TopPodsCPUPerNamespace finds the limit pods of a random namespace with the
+// highest average cpu usage over a random hour,
+// e.g. in pseudo-PromQL:
+// topk(N, avg_over_time(container_cpu_usage_percent{namespace='ns'}[1h]))
+func (k *K8s) TopPodsCPUPerNamespace(qq query.Query, limit int) {
+	qi := &promql.QueryInfo{
+		Query:    fmt.Sprintf("topk(%d, avg_over_time(container_cpu_usage_percent{namespace='%s'}[1h]))", limit, k.GetRandomNamespace()),
+		Label:    fmt.Sprintf("VictoriaMetrics top %d pods by cpu per namespace", limit),
+		Interval: k.Interval.MustRandWindow(k8s.TopPodsCPUDuration),
+		Step:     "3600",
+	}
+	promql.FillInQuery(qq, qi)
+}
+
+// NamespaceMemory calculates the average memory working set per namespace
+// per minute over a random hour,
+// e.g. in pseudo-PromQL:
+// avg(avg_over_time(container_memory_working_set_bytes[1m])) by (namespace)
+func (k *K8s) NamespaceMemory(qq query.Query) {
+	qi := &promql.QueryInfo{
+		Query:    "avg(avg_over_time(container_memory_working_set_bytes[1m])) by (namespace)",
+		Label:    "VictoriaMetrics memory per namespace, random 1h0m0s by 1m",
+		Interval: k.Interval.MustRandWindow(k8s.NamespaceMemoryDuration),
+		Step:     "60",
+	}
+	promql.FillInQuery(qq, qi)
+}
+
+// NodeCPU calculates the average cpu usage of the pods on nNodes random nodes
+// per 10 minutes over a random 12 hours,
+// e.g. in pseudo-PromQL:
+// avg(avg_over_time(container_cpu_usage_percent{node=~'node1|...|nodeN'}[10m])) by (node)
+func (k *K8s) NodeCPU(qq query.Query, nNodes int) {
+	nodes, err := k.GetRandomNodes(nNodes)
+	if err != nil {
+		panic(err.Error())
+	}
+	qi := &promql.QueryInfo{
+		Query:    fmt.Sprintf("avg(avg_over_time(container_cpu_usage_percent{node=~'%s'}[10m])) by (node)", strings.Join(nodes, "|")),
+		Label:    fmt.Sprintf("VictoriaMetrics cpu per node, random %4d nodes, random 12h0m0s by 10m", nNodes),
+		Interval: k.Interval.MustRandWindow(k8s.NodeCPUDuration),
+		Step:     "600",
+	}
+	promql.FillInQuery(qq, qi)
+}
+
+// PodRestarts finds the pods of a random namespace that restarted during a
+// random hour,
+// e.g. in pseudo-PromQL:
+// increase(container_status_restarts_total{namespace='ns'}[1h]) > 0
+func (k *K8s) PodRestarts(qq query.Query) {
+	qi := &promql.QueryInfo{
+		Query:    fmt.Sprintf("increase(container_status_restarts_total{namespace='%s'}[1h]) > 0", k.GetRandomNamespace()),
+		Label:    "VictoriaMetrics restarted pods per namespace",
+		Interval: k.Interval.MustRandWindow(k8s.PodRestartsDuration),
+		Step:     "3600",
+	}
+	promql.FillInQuery(qq, qi)
+}
+
+// PodChurn counts the distinct pods reporting per namespace per hour over a
+// random day,
+// e.g. in pseudo-PromQL:
+// count(count_over_time(container_cpu_usage_percent[1h])) by (namespace)
+func (k *K8s) PodChurn(qq query.Query) {
+	qi := &promql.QueryInfo{
+		Query:    "count(count_over_time(container_cpu_usage_percent[1h])) by (namespace)",
+		Label:    "VictoriaMetrics pods per namespace, random 24h0m0s by 1h",
+		Interval: k.Interval.MustRandWindow(k8s.PodChurnDuration),
+		Step:     "3600",
+	}
+	promql.FillInQuery(qq, qi)
+}
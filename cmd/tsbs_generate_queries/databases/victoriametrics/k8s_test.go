@@ -0,0 +1,95 @@
+package victoriametrics
+
+import (
+	"math/rand"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestK8sQueries(t *testing.T) {
+	testCases := map[string]struct {
+		fn        func(g *K8s, q *query.HTTP)
+		expQuery  string
+		expStep   string
+		expToFail bool
+	}{
+		"TopPodsCPUPerNamespace": {
+			fn: func(g *K8s, q *query.HTTP) {
+				g.TopPodsCPUPerNamespace(q, 5)
+			},
+			expQuery: "topk(5, avg_over_time(container_cpu_usage_percent{namespace='ingress'}[1h]))",
+			expStep:  "3600",
+		},
+		"NamespaceMemory": {
+			fn: func(g *K8s, q *query.HTTP) {
+				g.NamespaceMemory(q)
+			},
+			expQuery: "avg(avg_over_time(container_memory_working_set_bytes[1m])) by (namespace)",
+			expStep:  "60",
+		},
+		"NodeCPU": {
+			fn: func(g *K8s, q *query.HTTP) {
+				g.NodeCPU(q, 3)
+			},
+			expQuery: "avg(avg_over_time(container_cpu_usage_percent{node=~'node_5|node_9|node_3'}[10m])) by (node)",
+			expStep:  "600",
+		},
+		"NodeCPU_too_many_nodes": {
+			fn: func(g *K8s, q *query.HTTP) {
+				g.NodeCPU(q, 20)
+			},
+			expToFail: true,
+		},
+		"PodRestarts": {
+			fn: func(g *K8s, q *query.HTTP) {
+				g.PodRestarts(q)
+			},
+			expQuery: "increase(container_status_restarts_total{namespace='ingress'}[1h]) > 0",
+			expStep:  "3600",
+		},
+		"PodChurn": {
+			fn: func(g *K8s, q *query.HTTP) {
+				g.PodChurn(q)
+			},
+			expQuery: "count(count_over_time(container_cpu_usage_percent[1h])) by (namespace)",
+			expStep:  "3600",
+		},
+	}
+	b := &BaseGenerator{}
+	s := time.Unix(0, 0)
+	kg, err := b.NewK8s(s, s.Add(48*time.Hour), 100)
+	if err != nil {
+		t.Fatalf("Error while creating k8s generator")
+	}
+	g := kg.(*K8s)
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			q := g.GenerateEmptyQuery().(*query.HTTP)
+			if tc.expToFail {
+				func() {
+					defer func() {
+						if recover() == nil {
+							t.Errorf("expected to panic")
+						}
+					}()
+					tc.fn(g, q)
+				}()
+				return
+			}
+
+			tc.fn(g, q)
+			vals, err := url.ParseQuery(string(q.Path))
+			if err != nil {
+				t.Fatalf("unexpected err while parsing query: %s", err)
+			}
+			checkEqual(t, "query", tc.expQuery, vals.Get("query"))
+			checkEqual(t, "step", tc.expStep, vals.Get("step"))
+			checkEqual(t, "method", http.MethodGet, string(q.Method))
+		})
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	"github.com/timescale/tsbs/internal/inputs"
 	internalUtils "github.com/timescale/tsbs/internal/utils"
@@ -51,6 +53,21 @@ var useCaseMatrix = map[string]map[string]utils.QueryFillerMaker{
 		iot.LabelDailyActivity:                 iot.NewDailyTruckActivity,
 		iot.LabelBreakdownFrequency:            iot.NewTruckBreakdownFrequency,
 	},
+	"k8s": {
+		k8s.LabelTopPodsCPU + "-5":  k8s.NewTopPodsCPU(5),
+		k8s.LabelTopPodsCPU + "-10": k8s.NewTopPodsCPU(10),
+		k8s.LabelNamespaceMemory:    k8s.NewNamespaceMemory,
+		k8s.LabelNodeCPU + "-1":     k8s.NewNodeCPU(1),
+		k8s.LabelNodeCPU + "-8":     k8s.NewNodeCPU(8),
+		k8s.LabelPodRestarts:        k8s.NewPodRestarts,
+		k8s.LabelPodChurn:           k8s.NewPodChurn,
+	},
+	"finance": {
+		finance.LabelOHLCV + "-1":  finance.NewOHLCV(1),
+		finance.LabelOHLCV + "-10": finance.NewOHLCV(10),
+		finance.LabelVWAP:          finance.NewVWAP,
+		finance.LabelAsOfQuote:     finance.NewAsOfQuote,
+	},
 }
 
 var conf = &config.QueryGeneratorConfig{}
@@ -0,0 +1,29 @@
+package finance
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// AsOfQuote contains info for filling in last quote before trade queries.
+type AsOfQuote struct {
+	core utils.QueryGenerator
+}
+
+// NewAsOfQuote creates a new last quote before trade query filler.
+func NewAsOfQuote(core utils.QueryGenerator) utils.QueryFiller {
+	return &AsOfQuote{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *AsOfQuote) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(AsOfQuoteFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.AsOfQuote(q)
+	return q
+}
@@ -0,0 +1,86 @@
+package finance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// TradesTableName is the name of the table where trades are stored.
+	TradesTableName = "trades"
+	// QuotesTableName is the name of the table where quotes are stored.
+	QuotesTableName = "quotes"
+
+	// OHLCVDuration is the time range for the OHLCV bars query.
+	OHLCVDuration = time.Hour
+	// VWAPDuration is the time range for the VWAP query.
+	VWAPDuration = 24 * time.Hour
+	// AsOfQuoteDuration is the time range for the last quote before trade query.
+	AsOfQuoteDuration = time.Hour
+
+	// LabelOHLCV is the label prefix for the OHLCV bars query.
+	LabelOHLCV = "ohlcv"
+	// LabelVWAP is the label for the VWAP query.
+	LabelVWAP = "vwap"
+	// LabelAsOfQuote is the label for the last quote before trade query.
+	LabelAsOfQuote = "asof-quote"
+)
+
+// Core is the common component of all generators for all systems.
+type Core struct {
+	*common.Core
+}
+
+// NewCore returns a new Core for the given time range and cardinality
+// (number of symbols).
+func NewCore(start, end time.Time, scale int) (*Core, error) {
+	c, err := common.NewCore(start, end, scale)
+	return &Core{Core: c}, err
+}
+
+// GetRandomSymbols returns a random set of nSymbols from a given Core.
+func (c *Core) GetRandomSymbols(nSymbols int) ([]string, error) {
+	return getRandomSymbols(nSymbols, c.Scale)
+}
+
+// getRandomSymbols returns a subset of numSymbols names of a permutation of
+// symbol names, numbered from 0 to totalSymbols.
+func getRandomSymbols(numSymbols int, totalSymbols int) ([]string, error) {
+	if numSymbols < 1 {
+		return nil, fmt.Errorf("number of symbols cannot be < 1; got %d", numSymbols)
+	}
+	if numSymbols > totalSymbols {
+		return nil, fmt.Errorf("number of symbols (%d) larger than total symbols (%d). See --scale", numSymbols, totalSymbols)
+	}
+
+	randomNumbers, err := common.GetRandomSubsetPerm(numSymbols, totalSymbols)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]string, len(randomNumbers))
+	for i, n := range randomNumbers {
+		symbols[i] = finance.SymbolName(n)
+	}
+
+	return symbols, nil
+}
+
+// OHLCVFiller is a type that can fill in an OHLCV bars query.
+type OHLCVFiller interface {
+	OHLCV(query.Query, int)
+}
+
+// VWAPFiller is a type that can fill in a VWAP query.
+type VWAPFiller interface {
+	VWAP(query.Query)
+}
+
+// AsOfQuoteFiller is a type that can fill in a last quote before trade query.
+type AsOfQuoteFiller interface {
+	AsOfQuote(query.Query)
+}
@@ -0,0 +1,33 @@
+package finance
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// OHLCV produces a QueryFiller for the finance OHLCV bars cases.
+type OHLCV struct {
+	core     utils.QueryGenerator
+	nSymbols int
+}
+
+// NewOHLCV produces a new function that produces a new OHLCV.
+func NewOHLCV(nSymbols int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &OHLCV{
+			core:     core,
+			nSymbols: nSymbols,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *OHLCV) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(OHLCVFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.OHLCV(q, i.nSymbols)
+	return q
+}
@@ -0,0 +1,29 @@
+package finance
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// VWAP contains info for filling in VWAP queries.
+type VWAP struct {
+	core utils.QueryGenerator
+}
+
+// NewVWAP creates a new VWAP query filler.
+func NewVWAP(core utils.QueryGenerator) utils.QueryFiller {
+	return &VWAP{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *VWAP) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(VWAPFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.VWAP(q)
+	return q
+}
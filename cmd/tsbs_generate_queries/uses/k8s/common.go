@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// ContainerCPUTableName is the name of the table where container cpu usage is stored.
+	ContainerCPUTableName = "container_cpu"
+	// ContainerMemoryTableName is the name of the table where container memory usage is stored.
+	ContainerMemoryTableName = "container_memory"
+	// ContainerStatusTableName is the name of the table where container restarts are stored.
+	ContainerStatusTableName = "container_status"
+
+	// TopPodsCPUDuration is the time range for the top pods by cpu query.
+	TopPodsCPUDuration = time.Hour
+	// NamespaceMemoryDuration is the time range for the namespace memory query.
+	NamespaceMemoryDuration = time.Hour
+	// NodeCPUDuration is the time range for the node cpu query.
+	NodeCPUDuration = 12 * time.Hour
+	// PodRestartsDuration is the time range for the pod restarts query.
+	PodRestartsDuration = time.Hour
+	// PodChurnDuration is the time range for the pod churn query.
+	PodChurnDuration = 24 * time.Hour
+
+	// LabelTopPodsCPU is the label prefix for the top-N pods by cpu per namespace query.
+	LabelTopPodsCPU = "top-pods-cpu"
+	// LabelNamespaceMemory is the label for the memory per namespace query.
+	LabelNamespaceMemory = "namespace-memory"
+	// LabelNodeCPU is the label prefix for the cpu per node query.
+	LabelNodeCPU = "node-cpu"
+	// LabelPodRestarts is the label for the restarted pods query.
+	LabelPodRestarts = "pod-restarts"
+	// LabelPodChurn is the label for the pods created per namespace query.
+	LabelPodChurn = "pod-churn"
+)
+
+// Core is the common component of all generators for all systems.
+type Core struct {
+	*common.Core
+}
+
+// NewCore returns a new Core for the given time range and cardinality
+// (number of pod slots).
+func NewCore(start, end time.Time, scale int) (*Core, error) {
+	c, err := common.NewCore(start, end, scale)
+	return &Core{Core: c}, err
+}
+
+// GetRandomNamespace returns one of the namespace choices by random.
+func (c *Core) GetRandomNamespace() string {
+	return k8s.NamespaceChoices[rand.Intn(len(k8s.NamespaceChoices))]
+}
+
+// GetRandomNodes returns a random set of nNodes from a given Core.
+func (c *Core) GetRandomNodes(nNodes int) ([]string, error) {
+	return getRandomNodes(nNodes, k8s.NodeCount(c.Scale))
+}
+
+// getRandomNodes returns a subset of numNodes names of a permutation of node
+// names, numbered from 0 to totalNodes.
+func getRandomNodes(numNodes int, totalNodes int) ([]string, error) {
+	if numNodes < 1 {
+		return nil, fmt.Errorf("number of nodes cannot be < 1; got %d", numNodes)
+	}
+	if numNodes > totalNodes {
+		return nil, fmt.Errorf("number of nodes (%d) larger than total nodes (%d). See --scale", numNodes, totalNodes)
+	}
+
+	randomNumbers, err := common.GetRandomSubsetPerm(numNodes, totalNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeNames := make([]string, len(randomNumbers))
+	for i, n := range randomNumbers {
+		nodeNames[i] = k8s.NodeName(n * k8s.PodsPerNode)
+	}
+
+	return nodeNames, nil
+}
+
+// TopPodsCPUFiller is a type that can fill in a top-N pods by cpu per namespace query.
+type TopPodsCPUFiller interface {
+	TopPodsCPUPerNamespace(query.Query, int)
+}
+
+// NamespaceMemoryFiller is a type that can fill in a memory per namespace query.
+type NamespaceMemoryFiller interface {
+	NamespaceMemory(query.Query)
+}
+
+// NodeCPUFiller is a type that can fill in a cpu per node query.
+type NodeCPUFiller interface {
+	NodeCPU(query.Query, int)
+}
+
+// PodRestartsFiller is a type that can fill in a restarted pods query.
+type PodRestartsFiller interface {
+	PodRestarts(query.Query)
+}
+
+// PodChurnFiller is a type that can fill in a pods created per namespace query.
+type PodChurnFiller interface {
+	PodChurn(query.Query)
+}
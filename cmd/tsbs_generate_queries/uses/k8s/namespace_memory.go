@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// NamespaceMemory contains info for filling in memory per namespace queries.
+type NamespaceMemory struct {
+	core utils.QueryGenerator
+}
+
+// NewNamespaceMemory creates a new memory per namespace query filler.
+func NewNamespaceMemory(core utils.QueryGenerator) utils.QueryFiller {
+	return &NamespaceMemory{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *NamespaceMemory) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(NamespaceMemoryFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.NamespaceMemory(q)
+	return q
+}
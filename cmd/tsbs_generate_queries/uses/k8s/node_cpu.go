@@ -0,0 +1,33 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// NodeCPU produces a QueryFiller for the k8s cpu per node cases.
+type NodeCPU struct {
+	core  utils.QueryGenerator
+	nodes int
+}
+
+// NewNodeCPU produces a new function that produces a new NodeCPU.
+func NewNodeCPU(nodes int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &NodeCPU{
+			core:  core,
+			nodes: nodes,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *NodeCPU) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(NodeCPUFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.NodeCPU(q, i.nodes)
+	return q
+}
@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// PodRestarts contains info for filling in restarted pods queries.
+type PodRestarts struct {
+	core utils.QueryGenerator
+}
+
+// NewPodRestarts creates a new restarted pods query filler.
+func NewPodRestarts(core utils.QueryGenerator) utils.QueryFiller {
+	return &PodRestarts{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *PodRestarts) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(PodRestartsFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.PodRestarts(q)
+	return q
+}
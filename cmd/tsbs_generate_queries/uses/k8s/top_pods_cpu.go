@@ -0,0 +1,33 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// TopPodsCPU produces a QueryFiller for the k8s top-N pods by cpu per namespace cases.
+type TopPodsCPU struct {
+	core  utils.QueryGenerator
+	limit int
+}
+
+// NewTopPodsCPU produces a new function that produces a new TopPodsCPU.
+func NewTopPodsCPU(limit int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &TopPodsCPU{
+			core:  core,
+			limit: limit,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *TopPodsCPU) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(TopPodsCPUFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.TopPodsCPUPerNamespace(q, i.limit)
+	return q
+}
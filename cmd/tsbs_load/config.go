@@ -51,4 +51,30 @@ type SimulatorDataSourceConfig struct {
 	Limit                 uint64        `yaml:"max-data-points" mapstructure:"max-data-points"`
 	LogInterval           time.Duration `yaml:"log-interval" mapstructure:"log-interval"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	CustomSchema          string        `yaml:"custom-schema,omitempty" mapstructure:"custom-schema"`
+	PodMeanLifetime       time.Duration `yaml:"pod-mean-lifetime" mapstructure:"pod-mean-lifetime"`
+	LateRate              float64       `yaml:"late-rate" mapstructure:"late-rate"`
+	MaxLateness           time.Duration `yaml:"max-lateness" mapstructure:"max-lateness"`
+	DuplicateRate         float64       `yaml:"duplicate-rate" mapstructure:"duplicate-rate"`
+	MissingRate           float64       `yaml:"missing-rate" mapstructure:"missing-rate"`
+	SeasonalityAmplitude  float64       `yaml:"seasonality-amplitude" mapstructure:"seasonality-amplitude"`
+	SeasonalityPeriod     time.Duration `yaml:"seasonality-period" mapstructure:"seasonality-period"`
+	Trend                 float64       `yaml:"trend" mapstructure:"trend"`
+	StepRate              float64       `yaml:"step-rate" mapstructure:"step-rate"`
+	StepSize              float64       `yaml:"step-size" mapstructure:"step-size"`
+	SpikeRate             float64       `yaml:"spike-rate" mapstructure:"spike-rate"`
+	SpikeMagnitude        float64       `yaml:"spike-magnitude" mapstructure:"spike-magnitude"`
+	SpikeDuration         time.Duration `yaml:"spike-duration" mapstructure:"spike-duration"`
+	FlatLineRate          float64       `yaml:"flat-line-rate" mapstructure:"flat-line-rate"`
+	FlatLineDuration      time.Duration `yaml:"flat-line-duration" mapstructure:"flat-line-duration"`
+	TimestampPrecision    string        `yaml:"timestamp-precision" mapstructure:"timestamp-precision"`
+	MaxPhaseOffset        time.Duration `yaml:"max-phase-offset" mapstructure:"max-phase-offset"`
+	Jitter                time.Duration `yaml:"jitter" mapstructure:"jitter"`
+	SparseRate            float64       `yaml:"sparse-rate" mapstructure:"sparse-rate"`
+	SparseMode            string        `yaml:"sparse-mode" mapstructure:"sparse-mode"`
+	UseCases              string        `yaml:"use-cases,omitempty" mapstructure:"use-cases"`
+	OutageRate            float64       `yaml:"outage-rate" mapstructure:"outage-rate"`
+	DatacenterOutageRate  float64       `yaml:"datacenter-outage-rate" mapstructure:"datacenter-outage-rate"`
+	OutageDuration        time.Duration `yaml:"outage-duration" mapstructure:"outage-duration"`
+	OutageReset           bool          `yaml:"outage-reset" mapstructure:"outage-reset"`
 }
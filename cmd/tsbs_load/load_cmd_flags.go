@@ -14,6 +14,17 @@ const (
 	defaultTimeEnd     = "2020-01-02T00:00:00Z"
 	defaultLogInterval = 10 * time.Second
 	defaultScale       = 1
+
+	defaultPodMeanLifetime = 30 * time.Minute
+	defaultMaxLateness     = time.Minute
+
+	defaultSeasonalityPeriod = 24 * time.Hour
+	defaultStepSize          = 0.2
+	defaultSpikeMagnitude    = 1.0
+	defaultSpikeDuration     = 5 * time.Minute
+	defaultFlatLineDuration  = 10 * time.Minute
+	defaultSparseMode        = "null"
+	defaultOutageDuration    = 10 * time.Minute
 )
 
 func addLoaderRunnerFlags(fs *pflag.FlagSet) {
@@ -96,6 +107,88 @@ func addDataSourceFlags(fs *pflag.FlagSet) {
 		100,
 		"Max number of metric fields to generate per host. Used only in devops-generic use-case",
 	)
+	fs.Duration(
+		"data-source.simulator.pod-mean-lifetime",
+		defaultPodMeanLifetime,
+		"Mean time a pod lives before it is replaced, 0 disables churn. Used only in k8s use-case",
+	)
+	fs.String(
+		"data-source.simulator.custom-schema",
+		"",
+		"Path to the YAML schema describing entities, tags and measurements. Used only in custom use-case",
+	)
+	fs.Float64("data-source.simulator.late-rate", 0, "Fraction (0-1) of points delivered late, after newer points")
+	fs.Duration(
+		"data-source.simulator.max-lateness",
+		defaultMaxLateness,
+		"Maximum time a late point is delivered behind its timestamp",
+	)
+	fs.Float64("data-source.simulator.duplicate-rate", 0, "Fraction (0-1) of points delivered twice")
+	fs.Float64("data-source.simulator.missing-rate", 0, "Fraction (0-1) of points dropped")
+	fs.Float64(
+		"data-source.simulator.seasonality-amplitude",
+		0,
+		"Fraction (0-1) by which values swing either way over a seasonal cycle. Used only in devops, cpu-only, cpu-single and iot use-cases",
+	)
+	fs.Duration(
+		"data-source.simulator.seasonality-period",
+		defaultSeasonalityPeriod,
+		"Length of a seasonal cycle, which is lowest at the start of each period",
+	)
+	fs.Float64("data-source.simulator.trend", 0, "Fraction by which values grow each day, negative to shrink")
+	fs.Float64("data-source.simulator.step-rate", 0, "Chance (0-1) per reading of an abrupt change of level")
+	fs.Float64(
+		"data-source.simulator.step-size",
+		defaultStepSize,
+		"Maximum fraction (0-1) by which a level change moves values either way",
+	)
+	fs.Float64("data-source.simulator.spike-rate", 0, "Chance (0-1) per reading of a spike starting")
+	fs.Float64("data-source.simulator.spike-magnitude", defaultSpikeMagnitude, "Fraction by which values increase during a spike")
+	fs.Duration("data-source.simulator.spike-duration", defaultSpikeDuration, "How long a spike lasts")
+	fs.Float64("data-source.simulator.flat-line-rate", 0, "Chance (0-1) per reading of values getting stuck")
+	fs.Duration("data-source.simulator.flat-line-duration", defaultFlatLineDuration, "How long values stay stuck")
+	fs.String(
+		"data-source.simulator.timestamp-precision",
+		"",
+		"Precision timestamps are truncated to, the same for every format. Valid values: s, ms, us, ns, but no finer than the format writes (ms for prometheus, victoriametrics and elasticsearch, s for graphite). Empty for the precision of the format",
+	)
+	fs.Duration(
+		"data-source.simulator.max-phase-offset",
+		0,
+		"Maximum fixed offset from the log interval that each entity reports at, e.g. each host in devops",
+	)
+	fs.Duration("data-source.simulator.jitter", 0, "Maximum random shift of each timestamp, either way")
+	fs.Float64(
+		"data-source.simulator.sparse-rate",
+		0,
+		"Fraction (0-1) of fields left out of each point; at least one field is always kept",
+	)
+	fs.String(
+		"data-source.simulator.sparse-mode",
+		defaultSparseMode,
+		"How fields are left out. Valid values: null (written as missing values), absent (removed from the point)",
+	)
+	fs.Float64(
+		"data-source.simulator.outage-rate",
+		0,
+		"Chance (0-1) per log interval of each host going silent. Used only in devops, cpu-only, cpu-single, devops-generic and devops-status use-cases",
+	)
+	fs.Float64(
+		"data-source.simulator.datacenter-outage-rate",
+		0,
+		"Chance (0-1) per log interval of each datacenter going silent along with all its hosts",
+	)
+	fs.Duration("data-source.simulator.outage-duration", defaultOutageDuration, "Maximum time an outage lasts")
+	fs.Bool(
+		"data-source.simulator.outage-reset",
+		false,
+		"Whether hosts come back from an outage as if rebooted, with their counters starting over",
+	)
+	fs.String(
+		"data-source.simulator.use-cases",
+		"",
+		"Comma separated use cases to combine, each with an optional scale, e.g. devops=100,iot=50; use cases without one get scale. Used only in multi use-case",
+	)
 	fs.Uint64(
 		"data-source.simulator.scale",
 		defaultScale,
@@ -20,6 +20,11 @@ func parseConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benc
 		return nil, nil, err
 	}
 	dataSourceInternal := convertDataSourceConfigToInternalRepresentation(target.TargetName(), dataSource)
+	if sim := dataSourceInternal.Simulator; sim != nil {
+		// a scale and log interval given override those of a custom schema
+		sim.ScaleGiven = dataSourceViper.IsSet("simulator.scale")
+		sim.LogIntervalGiven = dataSourceViper.IsSet("simulator.log-interval")
+	}
 
 	loaderViper := v.Sub("loader")
 	if loaderViper == nil {
@@ -137,6 +142,32 @@ func convertDataSourceConfigToInternalRepresentation(format string, d *DataSourc
 			Limit:                 d.Simulator.Limit,
 			LogInterval:           d.Simulator.LogInterval,
 			MaxMetricCountPerHost: d.Simulator.MaxMetricCountPerHost,
+			CustomSchema:          d.Simulator.CustomSchema,
+			PodMeanLifetime:       d.Simulator.PodMeanLifetime,
+			LateRate:              d.Simulator.LateRate,
+			MaxLateness:           d.Simulator.MaxLateness,
+			DuplicateRate:         d.Simulator.DuplicateRate,
+			MissingRate:           d.Simulator.MissingRate,
+			SeasonalityAmplitude:  d.Simulator.SeasonalityAmplitude,
+			SeasonalityPeriod:     d.Simulator.SeasonalityPeriod,
+			Trend:                 d.Simulator.Trend,
+			StepRate:              d.Simulator.StepRate,
+			StepSize:              d.Simulator.StepSize,
+			SpikeRate:             d.Simulator.SpikeRate,
+			SpikeMagnitude:        d.Simulator.SpikeMagnitude,
+			SpikeDuration:         d.Simulator.SpikeDuration,
+			FlatLineRate:          d.Simulator.FlatLineRate,
+			FlatLineDuration:      d.Simulator.FlatLineDuration,
+			TimestampPrecision:    d.Simulator.TimestampPrecision,
+			MaxPhaseOffset:        d.Simulator.MaxPhaseOffset,
+			Jitter:                d.Simulator.Jitter,
+			SparseRate:            d.Simulator.SparseRate,
+			SparseMode:            d.Simulator.SparseMode,
+			UseCases:              d.Simulator.UseCases,
+			OutageRate:            d.Simulator.OutageRate,
+			DatacenterOutageRate:  d.Simulator.DatacenterOutageRate,
+			OutageDuration:        d.Simulator.OutageDuration,
+			OutageReset:           d.Simulator.OutageReset,
 			InterleavedNumGroups:  1,
 		}
 	}
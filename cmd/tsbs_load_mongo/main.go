@@ -5,45 +5,21 @@ package main
 
 import (
 	"fmt"
-	"time"
+	"log"
 
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/load"
-	"github.com/timescale/tsbs/pkg/targets"
-	"github.com/timescale/tsbs/pkg/targets/constants"
-	"github.com/timescale/tsbs/pkg/targets/initializers"
-)
-
-const (
-	collectionName     = "point_data"
-	aggDocID           = "doc_id"
-	aggDateFmt         = "20060102_15" // see Go docs for how we arrive at this time format
-	aggKeyID           = "key_id"
-	aggInsertBatchSize = 500 // found via trial-and-error
-	timestampField     = "timestamp_ns"
-)
-
-// Program option vars:
-var (
-	daemonURL    string
-	documentPer  bool
-	writeTimeout time.Duration
-)
-
-// Global vars
-var (
-	loader load.BenchmarkRunner
-	config load.BenchmarkRunnerConfig
-	target targets.ImplementedTarget
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets/mongo"
 )
 
 // Parse args:
-func init() {
-	target = initializers.GetTarget(constants.FormatMongo)
-	config = load.BenchmarkRunnerConfig{}
-	config.AddToFlagSet(pflag.CommandLine)
+func initProgramOptions() (*mongo.SpecificConfig, load.BenchmarkRunner, *load.BenchmarkRunnerConfig) {
+	target := mongo.NewTarget()
+	loaderConf := load.BenchmarkRunnerConfig{}
+	loaderConf.AddToFlagSet(pflag.CommandLine)
 	target.TargetSpecificFlags("", pflag.CommandLine)
 
 	pflag.Parse()
@@ -54,29 +30,34 @@ func init() {
 		panic(fmt.Errorf("fatal error config file: %s", err))
 	}
 
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := viper.Unmarshal(&loaderConf); err != nil {
 		panic(fmt.Errorf("unable to decode config: %s", err))
 	}
 
-	daemonURL = viper.GetString("url")
-	writeTimeout = viper.GetDuration("write-timeout")
-	documentPer = viper.GetBool("document-per-event")
-	if documentPer {
-		config.HashWorkers = false
+	mongoConf := &mongo.SpecificConfig{
+		URL:          viper.GetString("url"),
+		WriteTimeout: viper.GetDuration("write-timeout"),
+		DocumentPer:  viper.GetBool("document-per-event"),
+	}
+	if mongoConf.DocumentPer {
+		loaderConf.HashWorkers = false
 	} else {
-		config.HashWorkers = true
+		loaderConf.HashWorkers = true
 	}
 
-	loader = load.GetBenchmarkRunner(config)
+	loader := load.GetBenchmarkRunner(loaderConf)
+	return mongoConf, loader, &loaderConf
 }
 
 func main() {
-	var benchmark targets.Benchmark
-	if documentPer {
-		benchmark = newNaiveBenchmark(loader, &config)
-	} else {
-		benchmark = newAggBenchmark(loader, &config)
-	}
+	mongoConf, loader, loaderConf := initProgramOptions()
 
+	benchmark, err := mongo.NewBenchmark(loaderConf.DBName, mongoConf, &source.DataSourceConfig{
+		Type: source.FileDataSourceType,
+		File: &source.FileDataSourceConfig{Location: loaderConf.FileName},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 	loader.RunBenchmark(benchmark)
 }
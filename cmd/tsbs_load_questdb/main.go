@@ -5,44 +5,22 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/load"
-	"github.com/timescale/tsbs/pkg/targets"
-	"github.com/timescale/tsbs/pkg/targets/constants"
-	"github.com/timescale/tsbs/pkg/targets/initializers"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets/questdb"
 )
 
-// Program option vars:
-var (
-	questdbRESTEndPoint string
-	questdbILPBindTo    string
-	doAbortOnExist      bool
-)
-
-// Global vars
-var (
-	loader  load.BenchmarkRunner
-	config  load.BenchmarkRunnerConfig
-	bufPool sync.Pool
-	target  targets.ImplementedTarget
-)
-
-// allows for testing
-var fatal = log.Fatalf
-
 // Parse args:
-func init() {
-	target = initializers.GetTarget(constants.FormatQuestDB)
-	config = load.BenchmarkRunnerConfig{}
+func initProgramOptions() (*questdb.SpecificConfig, load.BenchmarkRunner, *load.BenchmarkRunnerConfig) {
+	target := questdb.NewTarget()
+	config := load.BenchmarkRunnerConfig{}
 	// Not all the default flags apply to QuestDB
 	// config.AddToFlagSet(pflag.CommandLine)
 	pflag.CommandLine.Uint("batch-size", 10000, "Number of items to batch together in a single insert")
@@ -67,40 +45,24 @@ func init() {
 		panic(fmt.Errorf("unable to decode config: %s", err))
 	}
 
-	questdbRESTEndPoint = viper.GetString("url")
-	questdbILPBindTo = viper.GetString("ilp-bind-to")
+	questdbConf := &questdb.SpecificConfig{
+		RESTEndPoint: viper.GetString("url"),
+		ILPBindTo:    viper.GetString("ilp-bind-to"),
+	}
 	config.HashWorkers = false
-	loader = load.GetBenchmarkRunner(config)
-}
-
-type benchmark struct{}
-
-func (b *benchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{scanner: bufio.NewScanner(load.GetBufferedReader(config.FileName))}
-}
-
-func (b *benchmark) GetBatchFactory() targets.BatchFactory {
-	return &factory{}
-}
-
-func (b *benchmark) GetPointIndexer(_ uint) targets.PointIndexer {
-	return &targets.ConstantIndexer{}
-}
-
-func (b *benchmark) GetProcessor() targets.Processor {
-	return &processor{}
-}
-
-func (b *benchmark) GetDBCreator() targets.DBCreator {
-	return &dbCreator{}
+	loader := load.GetBenchmarkRunner(config)
+	return questdbConf, loader, &config
 }
 
 func main() {
-	bufPool = sync.Pool{
-		New: func() interface{} {
-			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
-		},
-	}
+	questdbConf, loader, loaderConf := initProgramOptions()
 
-	loader.RunBenchmark(&benchmark{})
+	benchmark, err := questdb.NewBenchmark(questdbConf, &source.DataSourceConfig{
+		Type: source.FileDataSourceType,
+		File: &source.FileDataSourceConfig{Location: loaderConf.FileName},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	loader.RunBenchmark(benchmark)
 }
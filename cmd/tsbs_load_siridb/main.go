@@ -3,40 +3,20 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/load"
-	"github.com/timescale/tsbs/pkg/targets"
-	"github.com/timescale/tsbs/pkg/targets/constants"
-	"github.com/timescale/tsbs/pkg/targets/initializers"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets/siridb"
 )
 
-// Program option vars:
-var (
-	hosts        string
-	writeTimeout int
-	dbUser       string
-	dbPass       string
-	logBatches   bool
-	replica      bool
-)
-
-// Global vars
-var (
-	loader load.BenchmarkRunner
-	config load.BenchmarkRunnerConfig
-	target targets.ImplementedTarget
-)
-
-// allows for testing
-var fatal = log.Fatal
-
 // Parse args:
-func init() {
-	target = initializers.GetTarget(constants.FormatSiriDB)
-	config = load.BenchmarkRunnerConfig{}
+func initProgramOptions() (*siridb.SpecificConfig, load.BenchmarkRunner, *load.BenchmarkRunnerConfig) {
+	target := siridb.NewTarget()
+	config := load.BenchmarkRunnerConfig{}
 	config.AddToFlagSet(pflag.CommandLine)
 	target.TargetSpecificFlags("", pflag.CommandLine)
 
@@ -52,42 +32,28 @@ func init() {
 		panic(fmt.Errorf("unable to decode config: %s", err))
 	}
 
-	dbUser = viper.GetString("dbuser")
-	dbPass = viper.GetString("dbpass")
-	hosts = viper.GetString("hosts")
-	replica = viper.GetBool("replica")
-	logBatches = viper.GetBool("log-batches")
-	writeTimeout = viper.GetInt("write-timeout")
+	siriConf := &siridb.SpecificConfig{
+		DBUser:       viper.GetString("dbuser"),
+		DBPass:       viper.GetString("dbpass"),
+		Hosts:        strings.Split(viper.GetString("hosts"), ","),
+		Replica:      viper.GetBool("replica"),
+		LogBatches:   viper.GetBool("log-batches"),
+		WriteTimeout: viper.GetInt("write-timeout"),
+	}
 	config.HashWorkers = false
-	loader = load.GetBenchmarkRunner(config)
+	loader := load.GetBenchmarkRunner(config)
+	return siriConf, loader, &config
 }
 
-type benchmark struct{}
+func main() {
+	siriConf, loader, loaderConf := initProgramOptions()
 
-func (b *benchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{
-		buf: make([]byte, 0),
-		len: 0,
-		br:  load.GetBufferedReader(config.FileName),
+	benchmark, err := siridb.NewBenchmark(loaderConf.DBName, siriConf, &source.DataSourceConfig{
+		Type: source.FileDataSourceType,
+		File: &source.FileDataSourceConfig{Location: loaderConf.FileName},
+	})
+	if err != nil {
+		log.Fatal(err)
 	}
-}
-
-func (b *benchmark) GetBatchFactory() targets.BatchFactory {
-	return &factory{}
-}
-
-func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
-	return &targets.ConstantIndexer{}
-}
-
-func (b *benchmark) GetProcessor() targets.Processor {
-	return &processor{}
-}
-
-func (b *benchmark) GetDBCreator() targets.DBCreator {
-	return &dbCreator{}
-}
-
-func main() {
-	loader.RunBenchmark(&benchmark{})
+	loader.RunBenchmark(benchmark)
 }
@@ -0,0 +1,209 @@
+// tsbs_run_mixed loads data into a target database while querying it at the
+// same time, and reports ingest throughput and query latencies for the same
+// reporting periods.
+//
+// The target is chosen with --target. Loader flags are prefixed with "load.",
+// query runner flags with "query.", e.g. --load.insert-intervals throttles the
+// writes and --query.max-rps or --query.arrival-rate the queries.
+//
+// Only targets whose query processors are part of pkg/targets are supported;
+// the query runners of the other targets live in their own commands.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/mixed"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/elasticsearch"
+	"github.com/timescale/tsbs/pkg/targets/graphite"
+	"github.com/timescale/tsbs/pkg/targets/initializers"
+	"github.com/timescale/tsbs/pkg/targets/prometheus"
+)
+
+const (
+	loadPrefix  = "load."
+	queryPrefix = "query."
+)
+
+// queryTarget is the query side of a target: its flags, other than those of
+// the query benchmark runner, and the query processors configured by them.
+type queryTarget struct {
+	addFlags        func(fs *pflag.FlagSet)
+	processorCreate func(v *viper.Viper, urls []string, runner *query.BenchmarkRunner) query.ProcessorCreate
+}
+
+var queryTargets = map[string]queryTarget{
+	constants.FormatVictoriaMetrics: {
+		addFlags: func(fs *pflag.FlagSet) {
+			fs.String("urls", "http://localhost:8428",
+				"Comma-separated list of VictoriaMetrics query URLs(single-node or VMSelect)")
+		},
+		processorCreate: func(_ *viper.Viper, urls []string, runner *query.BenchmarkRunner) query.ProcessorCreate {
+			// VictoriaMetrics serves the Prometheus query API
+			return prometheus.NewQueryProcessorCreate(&prometheus.QueryProcessorOptions{
+				URLs:                 urls,
+				Debug:                runner.DebugLevel(),
+				PrettyPrintResponses: runner.DoPrintResponses(),
+			})
+		},
+	},
+	constants.FormatPrometheus: {
+		addFlags: func(fs *pflag.FlagSet) {
+			fs.String("urls", "http://localhost:9090",
+				"Comma-separated list of Prometheus query API URLs (Prometheus or a remote storage with a Prometheus compatible API)")
+		},
+		processorCreate: func(_ *viper.Viper, urls []string, runner *query.BenchmarkRunner) query.ProcessorCreate {
+			return prometheus.NewQueryProcessorCreate(&prometheus.QueryProcessorOptions{
+				URLs:                 urls,
+				Debug:                runner.DebugLevel(),
+				PrettyPrintResponses: runner.DoPrintResponses(),
+			})
+		},
+	},
+	constants.FormatElasticsearch: {
+		addFlags: func(fs *pflag.FlagSet) {
+			fs.String("urls", "http://localhost:9200", "Comma-separated list of Elasticsearch or OpenSearch node URLs")
+			fs.String("username", "", "Username for basic authentication, none if empty")
+			fs.String("password", "", "Password for basic authentication")
+		},
+		processorCreate: func(v *viper.Viper, urls []string, runner *query.BenchmarkRunner) query.ProcessorCreate {
+			return elasticsearch.NewQueryProcessorCreate(&elasticsearch.QueryProcessorOptions{
+				URLs:                 urls,
+				Username:             v.GetString("username"),
+				Password:             v.GetString("password"),
+				Debug:                runner.DebugLevel(),
+				PrettyPrintResponses: runner.DoPrintResponses(),
+			})
+		},
+	},
+	constants.FormatGraphite: {
+		addFlags: func(fs *pflag.FlagSet) {
+			fs.String("urls", "http://localhost:8080",
+				"Comma-separated list of Graphite render API URLs (graphite-web or a Graphite compatible API such as carbonapi)")
+		},
+		processorCreate: func(_ *viper.Viper, urls []string, runner *query.BenchmarkRunner) query.ProcessorCreate {
+			return graphite.NewQueryProcessorCreate(&graphite.QueryProcessorOptions{
+				URLs:                 urls,
+				Debug:                runner.DebugLevel(),
+				PrettyPrintResponses: runner.DoPrintResponses(),
+			})
+		},
+	},
+}
+
+// supportedTargets returns the names of the targets that can be run mixed.
+func supportedTargets() []string {
+	names := make([]string, 0, len(queryTargets))
+	for name := range queryTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addPrefixedFlags adds all the flags of `from` to `to` under a prefix. The
+// flags share their values, so after `to` is parsed `from` can be read as if
+// it was parsed itself.
+func addPrefixedFlags(to, from *pflag.FlagSet, prefix string) {
+	from.VisitAll(func(f *pflag.Flag) {
+		to.AddFlag(&pflag.Flag{
+			Name:     prefix + f.Name,
+			Usage:    f.Usage,
+			Value:    f.Value,
+			DefValue: f.DefValue,
+		})
+	})
+}
+
+// newFlagViper returns a viper holding the values of a flag set.
+func newFlagViper(fs *pflag.FlagSet) *viper.Viper {
+	v := viper.New()
+	if err := v.BindPFlags(fs); err != nil {
+		panic(fmt.Errorf("could not bind flags: %s", err))
+	}
+	return v
+}
+
+// unmarshalFlags decodes the values of a flag set into `out`.
+func unmarshalFlags(fs *pflag.FlagSet, out interface{}) {
+	if err := newFlagViper(fs).Unmarshal(out); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+}
+
+// parseTarget returns the value of the --target flag, which decides the other
+// flags, so it is parsed before them.
+func parseTarget() string {
+	fs := pflag.NewFlagSet("target", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.Usage = func() {}
+	target := fs.String("target", "", "")
+	_ = fs.Parse(os.Args[1:])
+	return *target
+}
+
+func main() {
+	targetName := parseTarget()
+	pflag.String("target", "", fmt.Sprintf("Target database, one of %v", supportedTargets()))
+	qt, ok := queryTargets[targetName]
+	if !ok {
+		pflag.Parse()
+		log.Fatalf("invalid target '%s'; allowed: %v", targetName, supportedTargets())
+	}
+	target := initializers.GetTarget(targetName)
+
+	var mixedConf mixed.Config
+	mixedConf.AddToFlagSet(pflag.CommandLine)
+
+	loadFlags := pflag.NewFlagSet(loadPrefix, pflag.ExitOnError)
+	loaderConf := load.BenchmarkRunnerConfig{}
+	loaderConf.AddToFlagSet(loadFlags)
+	target.TargetSpecificFlags("", loadFlags)
+	addPrefixedFlags(pflag.CommandLine, loadFlags, loadPrefix)
+
+	queryFlags := pflag.NewFlagSet(queryPrefix, pflag.ExitOnError)
+	queryConf := query.BenchmarkRunnerConfig{}
+	queryConf.AddToFlagSet(queryFlags)
+	qt.addFlags(queryFlags)
+	addPrefixedFlags(pflag.CommandLine, queryFlags, queryPrefix)
+
+	pflag.Parse()
+
+	unmarshalFlags(pflag.CommandLine, &mixedConf)
+	unmarshalFlags(loadFlags, &loaderConf)
+	unmarshalFlags(queryFlags, &queryConf)
+
+	queryViper := newFlagViper(queryFlags)
+	queryURLs := queryViper.GetString("urls")
+	if len(queryURLs) == 0 {
+		log.Fatalf("missing `%surls` flag", queryPrefix)
+	}
+
+	// the mixed runner does the periodic reporting for both
+	loaderConf.ReportingPeriod = 0
+	queryConf.PrintInterval = 0
+
+	benchmark, err := target.Benchmark(loaderConf.DBName,
+		&source.DataSourceConfig{
+			Type: source.FileDataSourceType,
+			File: &source.FileDataSourceConfig{Location: loaderConf.FileName},
+		}, newFlagViper(loadFlags))
+	if err != nil {
+		panic(err)
+	}
+	loader := load.GetBenchmarkRunner(loaderConf)
+	queries := query.NewBenchmarkRunner(queryConf)
+
+	mixed.NewRunner(mixedConf).Run(loader, benchmark, queries, &query.HTTPPool,
+		qt.processorCreate(queryViper, strings.Split(queryURLs, ","), queries))
+}
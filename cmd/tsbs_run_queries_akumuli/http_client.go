@@ -48,18 +48,18 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 	// populate a request with data from the Query:
 	req, err := http.NewRequest(string(q.Method), string(w.uri), bytes.NewReader(q.Body))
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 
 	// Perform the request while tracking latency:
 	start := time.Now()
 	resp, err := w.client.Do(req)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		panic("http request did not return status 200 OK")
+		return 0, fmt.Errorf("http request did not return status 200 OK: %s", resp.Status)
 	}
 
 	reader := bufio.NewReader(resp.Body)
@@ -70,7 +70,7 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 			err = nil
 			break
 		} else if err != nil {
-			panic(err)
+			return 0, err
 		}
 	}
 	lag = float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
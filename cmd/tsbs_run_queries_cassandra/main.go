@@ -28,6 +28,7 @@ const (
 var (
 	BlessedTables = []string{
 		"series_bigint",
+		"series_varint",
 		"series_float",
 		"series_double",
 		"series_boolean",
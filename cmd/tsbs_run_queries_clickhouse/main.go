@@ -96,7 +96,7 @@ func getConnectString(workerNumber int) string {
 // prettyPrintResponse prints a Query and its response in JSON format with two
 // keys: 'query' which has a value of the SQL used to generate the second key
 // 'results' which is an array of each row in the return set.
-func prettyPrintResponse(rows *sqlx.Rows, q *query.ClickHouse) {
+func prettyPrintResponse(rows *sqlx.Rows, q *query.ClickHouse) error {
 	resp := make(map[string]interface{})
 	resp["query"] = string(q.SqlQuery)
 
@@ -104,7 +104,7 @@ func prettyPrintResponse(rows *sqlx.Rows, q *query.ClickHouse) {
 	for rows.Next() {
 		r := make(map[string]interface{})
 		if err := rows.MapScan(r); err != nil {
-			panic(err)
+			return err
 		}
 		results = append(results, r)
 		resp["results"] = results
@@ -112,16 +112,18 @@ func prettyPrintResponse(rows *sqlx.Rows, q *query.ClickHouse) {
 
 	line, err := json.MarshalIndent(resp, "", "  ")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	fmt.Println(string(line) + "\n")
+	return nil
 }
 
 type queryExecutorOptions struct {
-	showExplain   bool
-	debug         bool
-	printResponse bool
+	showExplain    bool
+	debug          bool
+	printResponse  bool
+	captureResults bool
 }
 
 // query.Processor interface implementation
@@ -140,9 +142,10 @@ func (p *processor) Init(workerNumber int) {
 	p.db = sqlx.MustConnect("clickhouse", getConnectString(workerNumber))
 	p.opts = &queryExecutorOptions{
 		// ClickHouse could not do EXPLAIN
-		showExplain:   false,
-		debug:         runner.DebugLevel() > 0,
-		printResponse: runner.DoPrintResponses(),
+		showExplain:    false,
+		debug:          runner.DebugLevel() > 0,
+		printResponse:  runner.DoPrintResponses(),
+		captureResults: runner.DoCaptureResults(),
 	}
 }
 
@@ -166,21 +169,37 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	// Print some extra info if needed
 	if p.opts.debug {
 		fmt.Println(sql)
 	}
+	capture := false
+	var values [][]interface{}
 	if p.opts.printResponse {
-		prettyPrintResponse(rows, chQuery)
+		if err := prettyPrintResponse(rows, chQuery); err != nil {
+			return nil, err
+		}
+	} else if p.opts.captureResults && !isWarm {
+		capture = true
+		if values, err = query.ScanRows(rows); err != nil {
+			return nil, err
+		}
 	}
 
 	// Finalize the query
 	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	took := float64(time.Since(start).Nanoseconds()) / 1e6
+	if capture {
+		runner.CaptureResult(q, query.NormalizeRows(values))
+	}
 
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
-	return []*query.Stat{stat}, err
+	return []*query.Stat{stat}, nil
 }
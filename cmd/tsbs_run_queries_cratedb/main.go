@@ -79,9 +79,10 @@ type processor struct {
 }
 
 type executorOptions struct {
-	showExplain   bool
-	debug         bool
-	printResponse bool
+	showExplain    bool
+	debug          bool
+	printResponse  bool
+	captureResults bool
 }
 
 func newProcessor() (query.Processor, error) {
@@ -93,19 +94,19 @@ func newProcessor() (query.Processor, error) {
 	return &processor{
 		connCfg: connConfig,
 		opts: &executorOptions{
-			showExplain:   showExplain,
-			debug:         runner.DebugLevel() > 0,
-			printResponse: runner.DoPrintResponses(),
+			showExplain:    showExplain,
+			debug:          runner.DebugLevel() > 0,
+			printResponse:  runner.DoPrintResponses(),
+			captureResults: runner.DoCaptureResults(),
 		},
 	}, nil
 }
 
+// Init connects to the database. A failed connection is not fatal: it is
+// attempted again by the next query, which fails as configured by the error
+// policy otherwise.
 func (p *processor) Init(workerNumber int) {
-	conn, err := pgx.ConnectConfig(context.Background(), p.connCfg)
-	if err != nil {
-		panic(err)
-	}
-	p.conn = conn
+	p.conn, _ = pgx.ConnectConfig(context.Background(), p.connCfg)
 }
 
 func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
@@ -114,6 +115,13 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 		return nil, nil
 	}
 	tq := q.(*query.CrateDB)
+	if p.conn == nil {
+		conn, err := pgx.ConnectConfig(context.Background(), p.connCfg)
+		if err != nil {
+			return nil, err
+		}
+		p.conn = conn
+	}
 
 	start := time.Now()
 	qry := string(tq.SqlQuery)
@@ -124,61 +132,72 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	if p.opts.debug {
 		fmt.Println(qry)
 	}
+	capture := false
+	var values [][]interface{}
 	if showExplain {
 		fmt.Printf("Explian Query:\n")
-		prettyPrintResponse(rows, tq)
+		if err := prettyPrintResponse(rows, tq); err != nil {
+			return nil, err
+		}
 		fmt.Printf("\n-----------\n\n")
 	} else if p.opts.printResponse {
-		prettyPrintResponse(rows, tq)
+		if err := prettyPrintResponse(rows, tq); err != nil {
+			return nil, err
+		}
+	} else if p.opts.captureResults && !isWarm {
+		capture = true
+		if values, err = query.ScanRows(pgxRows{rows}); err != nil {
+			return nil, err
+		}
 	}
-	defer rows.Close()
 
 	took := float64(time.Since(start).Nanoseconds()) / 1e6
+	if capture {
+		runner.CaptureResult(q, query.NormalizeRows(values))
+	}
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
-	return []*query.Stat{stat}, err
+	return []*query.Stat{stat}, nil
 }
 
 // prettyPrintResponse prints a Query and its response in JSON format with two
 // keys: 'query' which has a value of the SQL used to generate the second key
 // 'results' which is an array of each row in the return set.
-func prettyPrintResponse(rows pgx.Rows, q *query.CrateDB) {
+func prettyPrintResponse(rows pgx.Rows, q *query.CrateDB) error {
+	results, err := query.MapRows(pgxRows{rows})
+	if err != nil {
+		return err
+	}
 	resp := make(map[string]interface{})
 	resp["query"] = string(q.SqlQuery)
-	resp["results"] = mapRows(rows)
+	resp["results"] = results
 
 	line, err := json.MarshalIndent(resp, "", "  ")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	fmt.Println(string(line) + "\n")
+	return nil
 }
 
-func mapRows(r pgx.Rows) []map[string]interface{} {
-	var rows []map[string]interface{}
-	cols := r.FieldDescriptions()
-	for r.Next() {
-		row := make(map[string]interface{})
-		values := make([]interface{}, len(cols))
-		for i := range values {
-			values[i] = new(interface{})
-		}
-
-		err := r.Scan(values...)
-		if err != nil {
-			panic(errors.Wrap(err, "error while reading values"))
-		}
+// pgxRows adapts the rows of pgx to query.SQLRows.
+type pgxRows struct {
+	pgx.Rows
+}
 
-		for i, column := range cols {
-			row[string(column.Name)] = *values[i].(*interface{})
-		}
-		rows = append(rows, row)
+// Columns returns the names of the columns of the rows.
+func (r pgxRows) Columns() ([]string, error) {
+	fields := r.FieldDescriptions()
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		cols[i] = string(f.Name)
 	}
-	return rows
+	return cols, nil
 }
@@ -0,0 +1,69 @@
+// tsbs_run_queries_elasticsearch speed tests Elasticsearch or OpenSearch
+// using requests from stdin or file.
+//
+// It reads encoded Query objects from stdin, and makes concurrent search
+// requests to the provided cluster nodes, checking that every search
+// completed on all shards.
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/elasticsearch"
+)
+
+// Program option vars:
+var (
+	esURLs   []string
+	username string
+	password string
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("urls", "http://localhost:9200", "Comma-separated list of Elasticsearch or OpenSearch node URLs")
+	pflag.String("username", "", "Username for basic authentication, none if empty")
+	pflag.String("password", "", "Password for basic authentication")
+
+	pflag.Parse()
+
+	if err := utils.SetupConfigFile(); err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	urls := viper.GetString("urls")
+	if len(urls) == 0 {
+		log.Fatalf("missing `urls` flag")
+	}
+	esURLs = strings.Split(urls, ",")
+	username = viper.GetString("username")
+	password = viper.GetString("password")
+	runner = query.NewBenchmarkRunner(config)
+}
+
+func main() {
+	runner.Run(&query.HTTPPool, elasticsearch.NewQueryProcessorCreate(&elasticsearch.QueryProcessorOptions{
+		URLs:                 esURLs,
+		Username:             username,
+		Password:             password,
+		Debug:                runner.DebugLevel(),
+		PrettyPrintResponses: runner.DoPrintResponses(),
+	}))
+}
@@ -32,6 +32,8 @@ type HTTPClientDoOptions struct {
 	PrettyPrintResponses bool
 	chunkSize            uint64
 	database             string
+	// captureResponse, when set, is called with the response body
+	captureResponse func(body []byte) error
 }
 
 var httpClientOnce = sync.Once{}
@@ -79,7 +81,7 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 	// populate a request with data from the Query:
 	req, err := http.NewRequest(string(q.Method), string(w.uri), nil)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 	if w.authToken != "" {
 		req.Header.Add(headerAuthorization, w.authToken)
@@ -88,22 +90,28 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 	start := time.Now()
 	resp, err := w.client.Do(req)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		panic("http request did not return status 200 OK")
+		return 0, fmt.Errorf("http request did not return status 200 OK: %s", resp.Status)
 	}
 
 	var body []byte
 	body, err = ioutil.ReadAll(resp.Body)
 
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 
 	lag = float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
 
+	if opts != nil && opts.captureResponse != nil {
+		if err = opts.captureResponse(body); err != nil {
+			return 0, err
+		}
+	}
+
 	if opts != nil {
 		// Print debug messages, if applicable:
 		switch opts.Debug {
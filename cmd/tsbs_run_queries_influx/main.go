@@ -88,8 +88,19 @@ func (p *processor) Init(workerNumber int) {
 	p.w = NewHTTPClient(url, authToken)
 }
 
-func (p *processor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
+func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
 	hq := q.(*query.HTTP)
+	p.opts.captureResponse = nil
+	if runner.DoCaptureResults() && !isWarm {
+		p.opts.captureResponse = func(body []byte) error {
+			rows, err := normalizeResponse(body)
+			if err != nil {
+				return err
+			}
+			runner.CaptureResult(q, rows)
+			return nil
+		}
+	}
 	lag, err := p.w.Do(hq, p.opts)
 	if err != nil {
 		return nil, err
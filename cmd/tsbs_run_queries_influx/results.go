@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// response is the part of an InfluxQL query response needed to capture the
+// results. Chunked responses are a stream of these.
+type response struct {
+	Results []struct {
+		Series []struct {
+			Tags    map[string]string `json:"tags"`
+			Columns []string          `json:"columns"`
+			Values  [][]interface{}   `json:"values"`
+		} `json:"series"`
+		Error string `json:"error"`
+	} `json:"results"`
+}
+
+// normalizeResponse converts the series of a query response into rows of
+// time bucket, group keys and values. Tags of a series become its group keys,
+// ordered by tag name.
+func normalizeResponse(body []byte) ([]query.ResultRow, error) {
+	var rows []query.ResultRow
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	for {
+		var resp response
+		err := dec.Decode(&resp)
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode response: %v", err)
+		}
+		for _, result := range resp.Results {
+			for _, series := range result.Series {
+				tagKeys := make([]string, 0, len(series.Tags))
+				for k := range series.Tags {
+					tagKeys = append(tagKeys, k)
+				}
+				sort.Strings(tagKeys)
+
+				for _, values := range series.Values {
+					columns := make([]interface{}, 0, len(tagKeys)+len(values))
+					for _, k := range tagKeys {
+						columns = append(columns, series.Tags[k])
+					}
+					for i, v := range values {
+						if i < len(series.Columns) && series.Columns[i] == "time" {
+							if t, err := parseTime(v); err == nil {
+								v = t
+							}
+						}
+						columns = append(columns, v)
+					}
+					rows = append(rows, query.NormalizeRow(columns))
+				}
+			}
+		}
+	}
+}
+
+// parseTime parses a time column, which is RFC3339 by default or an epoch
+// in nanoseconds when the query asked for it.
+func parseTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case string:
+		return time.Parse(time.RFC3339Nano, t)
+	case json.Number:
+		ns, err := t.Int64()
+		return time.Unix(0, ns), err
+	}
+	return time.Time{}, fmt.Errorf("unexpected time value %v", v)
+}
@@ -0,0 +1,61 @@
+// tsbs_run_queries_prometheus speed tests Prometheus using requests from stdin or file.
+//
+// It reads encoded Query objects from stdin, and makes concurrent range
+// queries to the provided Prometheus compatible query APIs, counting the
+// series and samples of every result.
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/prometheus"
+)
+
+// Program option vars:
+var (
+	promURLs []string
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("urls", "http://localhost:9090",
+		"Comma-separated list of Prometheus query API URLs (Prometheus or a remote storage with a Prometheus compatible API)")
+
+	pflag.Parse()
+
+	if err := utils.SetupConfigFile(); err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	urls := viper.GetString("urls")
+	if len(urls) == 0 {
+		log.Fatalf("missing `urls` flag")
+	}
+	promURLs = strings.Split(urls, ",")
+	runner = query.NewBenchmarkRunner(config)
+}
+
+func main() {
+	runner.Run(&query.HTTPPool, prometheus.NewQueryProcessorCreate(&prometheus.QueryProcessorOptions{
+		URLs:                 promURLs,
+		Debug:                runner.DebugLevel(),
+		PrettyPrintResponses: runner.DoPrintResponses(),
+	}))
+}
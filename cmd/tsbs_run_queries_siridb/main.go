@@ -186,15 +186,12 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	start := time.Now()
 	qry := string(tq.SqlQuery)
 
-	var res interface{}
-	var err error
-
-	if siridbConnector.IsConnected() {
-		if res, err = siridbConnector.Query(qry, uint16(writeTimeout)); err != nil {
-			log.Fatal(err)
-		}
-	} else {
-		log.Fatal("not even a single server is connected...")
+	if !siridbConnector.IsConnected() {
+		return nil, fmt.Errorf("not even a single server is connected")
+	}
+	res, err := siridbConnector.Query(qry, uint16(writeTimeout))
+	if err != nil {
+		return nil, err
 	}
 
 	if p.opts.debug {
@@ -209,5 +206,5 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
-	return []*query.Stat{stat}, err
+	return []*query.Stat{stat}, nil
 }
@@ -16,7 +16,6 @@ import (
 	"github.com/blagojts/viper"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	_ "github.com/lib/pq"
-	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/query"
@@ -131,46 +130,29 @@ func getConnectString(workerNumber int) string {
 // prettyPrintResponse prints a Query and its response in JSON format with two
 // keys: 'query' which has a value of the SQL used to generate the second key
 // 'results' which is an array of each row in the return set.
-func prettyPrintResponse(rows *sql.Rows, q *query.TimescaleDB) {
+func prettyPrintResponse(rows *sql.Rows, q *query.TimescaleDB) error {
+	results, err := query.MapRows(rows)
+	if err != nil {
+		return err
+	}
 	resp := make(map[string]interface{})
 	resp["query"] = string(q.SqlQuery)
-	resp["results"] = mapRows(rows)
+	resp["results"] = results
 
 	line, err := json.MarshalIndent(resp, "", "  ")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	fmt.Println(string(line) + "\n")
-}
-
-func mapRows(r *sql.Rows) []map[string]interface{} {
-	rows := []map[string]interface{}{}
-	cols, _ := r.Columns()
-	for r.Next() {
-		row := make(map[string]interface{})
-		values := make([]interface{}, len(cols))
-		for i := range values {
-			values[i] = new(interface{})
-		}
-
-		err := r.Scan(values...)
-		if err != nil {
-			panic(errors.Wrap(err, "error while reading values"))
-		}
-
-		for i, column := range cols {
-			row[column] = *values[i].(*interface{})
-		}
-		rows = append(rows, row)
-	}
-	return rows
+	return nil
 }
 
 type queryExecutorOptions struct {
-	showExplain   bool
-	debug         bool
-	printResponse bool
+	showExplain    bool
+	debug          bool
+	printResponse  bool
+	captureResults bool
 }
 
 type processor struct {
@@ -187,9 +169,10 @@ func (p *processor) Init(workerNumber int) {
 	}
 	p.db = db
 	p.opts = &queryExecutorOptions{
-		showExplain:   showExplain,
-		debug:         runner.DebugLevel() > 0,
-		printResponse: runner.DoPrintResponses(),
+		showExplain:    showExplain,
+		debug:          runner.DebugLevel() > 0,
+		printResponse:  runner.DoPrintResponses(),
+		captureResults: runner.DoCaptureResults(),
 	}
 }
 
@@ -209,22 +192,32 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	if p.opts.debug {
 		fmt.Println(qry)
 	}
+	capture := false
+	var values [][]interface{}
 	if showExplain {
 		text := ""
 		for rows.Next() {
 			var s string
-			if err2 := rows.Scan(&s); err2 != nil {
-				panic(err2)
+			if err := rows.Scan(&s); err != nil {
+				return nil, err
 			}
 			text += s + "\n"
 		}
 		fmt.Printf("%s\n\n%s\n-----\n\n", qry, text)
 	} else if p.opts.printResponse {
-		prettyPrintResponse(rows, tq)
+		if err := prettyPrintResponse(rows, tq); err != nil {
+			return nil, err
+		}
+	} else if p.opts.captureResults && !isWarm {
+		capture = true
+		if values, err = query.ScanRows(rows); err != nil {
+			return nil, err
+		}
 	}
 	// Fetching all the rows to confirm that the query is fully completed.
 	for rows.Next() {
@@ -234,8 +227,11 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 		return nil, err
 	}
 	took := float64(time.Since(start).Nanoseconds()) / 1e6
+	if capture {
+		runner.CaptureResult(q, query.NormalizeRows(values))
+	}
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
-	return []*query.Stat{stat}, err
+	return []*query.Stat{stat}, nil
 }
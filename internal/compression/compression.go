@@ -0,0 +1,102 @@
+// Package compression lets generated data and query files be written and read
+// compressed with gzip or zstd.
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported compressions
+const (
+	None = "none"
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// Choices are the compressions that can be asked for.
+var Choices = []string{None, Gzip, Zstd}
+
+const errUnknownCompressionFmt = "unknown compression: '%s'"
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// FromFileName returns the compression that the extension of the file name
+// calls for, i.e. Gzip for .gz, Zstd for .zst or .zstd and None otherwise.
+func FromFileName(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".gz", ".gzip":
+		return Gzip
+	case ".zst", ".zstd":
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// NewWriter returns a writer that compresses to w. It must be closed to
+// complete the compressed stream, which leaves w open. The returned writer is
+// nil for None.
+func NewWriter(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case None, "":
+		return nil, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf(errUnknownCompressionFmt, compression)
+	}
+}
+
+// Detect tells the compression of what r reads from its magic bytes, without
+// consuming them.
+func Detect(r *bufio.Reader) string {
+	// a short read just means the input is too small to be compressed
+	magic, _ := r.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(magic, zstdMagic):
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// NewReader returns a buffered reader of the given size over r, which
+// decompresses r if its magic bytes tell it is compressed. Decompression runs
+// in its own goroutine, ahead of the reads, so it does not slow down the
+// reader.
+func NewReader(r io.Reader, size int) (*bufio.Reader, error) {
+	br := bufio.NewReaderSize(r, size)
+	var dr io.Reader
+	switch Detect(br) {
+	case Gzip:
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		dr = gr
+	case Zstd:
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		dr = zr
+	default:
+		return br, nil
+	}
+	return bufio.NewReaderSize(newReadAhead(dr, size), size), nil
+}
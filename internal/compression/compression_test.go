@@ -0,0 +1,113 @@
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestFromFileName(t *testing.T) {
+	cases := map[string]string{
+		"":                     None,
+		"/tmp/data":            None,
+		"/tmp/data.txt":        None,
+		"/tmp/data.gz":         Gzip,
+		"/tmp/data.GZ":         Gzip,
+		"/tmp/queries.gzip":    Gzip,
+		"/tmp/data.zst":        Zstd,
+		"/tmp/queries.zstd":    Zstd,
+		"/tmp/data.gz/nothing": None,
+	}
+	for fileName, want := range cases {
+		if got := FromFileName(fileName); got != want {
+			t.Errorf("%s: incorrect compression: got %s want %s", fileName, got, want)
+		}
+	}
+}
+
+func TestNewWriterUnknown(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "rar"); err == nil {
+		t.Errorf("unexpected lack of error for unknown compression")
+	}
+	w, err := NewWriter(&bytes.Buffer{}, None)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if w != nil {
+		t.Errorf("unexpected writer for no compression")
+	}
+}
+
+func compress(t *testing.T, compression string, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, compression)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w == nil {
+		return data
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat("cpu,hostname=host_0 usage_user=58i 1451606400000000000\n", 10000))
+	for _, c := range Choices {
+		compressed := compress(t, c, data)
+		if got := Detect(bufio.NewReader(bytes.NewReader(compressed))); got != c {
+			t.Errorf("%s: incorrect compression detected: got %s", c, got)
+		}
+		if c != None && len(compressed) >= len(data) {
+			t.Errorf("%s: data not compressed: %d bytes of %d", c, len(compressed), len(data))
+		}
+
+		// a small read size makes the decompressed data span many chunks
+		r, err := NewReader(bytes.NewReader(compressed), 4096)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c, err)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: unexpected error reading: %v", c, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("%s: incorrect data read: got %d bytes want %d", c, len(got), len(data))
+		}
+	}
+}
+
+func TestNewReaderShortInput(t *testing.T) {
+	for _, in := range []string{"", "a", "\x1f"} {
+		r, err := NewReader(strings.NewReader(in), 4096)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Errorf("%q: unexpected error reading: %v", in, err)
+		}
+		if string(got) != in {
+			t.Errorf("%q: incorrect data read: got %q", in, got)
+		}
+	}
+}
+
+func TestNewReaderCorrupt(t *testing.T) {
+	compressed := compress(t, Gzip, []byte(strings.Repeat("data\n", 1000)))
+	compressed = compressed[:len(compressed)/2]
+	r, err := NewReader(bytes.NewReader(compressed), 4096)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ioutil.ReadAll(r); err == nil {
+		t.Errorf("unexpected lack of error for truncated input")
+	}
+}
@@ -0,0 +1,75 @@
+package compression
+
+import "io"
+
+// readAheadChunks is how many chunks a readAhead keeps ready to be read.
+const readAheadChunks = 4
+
+type chunk struct {
+	buf []byte
+	err error
+}
+
+// readAhead reads chunks of the underlying reader in its own goroutine, so
+// producing the data, e.g. decompressing it, overlaps with consuming it.
+type readAhead struct {
+	chunks chan chunk
+	free   chan []byte
+
+	cur chunk
+	off int
+}
+
+func newReadAhead(r io.Reader, size int) *readAhead {
+	ra := &readAhead{
+		chunks: make(chan chunk, readAheadChunks),
+		free:   make(chan []byte, readAheadChunks+1),
+	}
+	for i := 0; i < cap(ra.free); i++ {
+		ra.free <- make([]byte, size)
+	}
+	go ra.fill(r)
+	return ra
+}
+
+// fill reads r into the free buffers until it fails, which ends the chunks.
+func (ra *readAhead) fill(r io.Reader) {
+	for {
+		buf := <-ra.free
+		n, err := readFull(r, buf)
+		ra.chunks <- chunk{buf: buf[:n], err: err}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (ra *readAhead) Read(p []byte) (int, error) {
+	for ra.off == len(ra.cur.buf) {
+		if ra.cur.err != nil {
+			return 0, ra.cur.err
+		}
+		if ra.cur.buf != nil {
+			ra.free <- ra.cur.buf[:cap(ra.cur.buf)]
+		}
+		ra.cur = <-ra.chunks
+		ra.off = 0
+	}
+	n := copy(p, ra.cur.buf[ra.off:])
+	ra.off += n
+	return n, nil
+}
+
+// readFull is like io.ReadFull, except that it returns io.EOF however much it
+// read before, and passes on io.ErrUnexpectedEOF of r, e.g. of truncated input.
+func readFull(r io.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -36,6 +36,9 @@ type DataGenerator struct {
 	// bufOut represents the buffered writer that should actually be passed to
 	// any operations that write out data.
 	bufOut *bufio.Writer
+	// closer completes the output once bufOut is flushed, e.g. ends the
+	// compressed stream.
+	closer io.Closer
 }
 
 func (g *DataGenerator) init(config common.GeneratorConfig) error {
@@ -57,7 +60,7 @@ func (g *DataGenerator) init(config common.GeneratorConfig) error {
 	if g.Out == nil {
 		g.Out = os.Stdout
 	}
-	g.bufOut, err = getBufferedWriter(g.config.File, g.Out)
+	g.bufOut, g.closer, err = getBufferedWriter(g.config.File, g.config.Compression, g.Out)
 	if err != nil {
 		return err
 	}
@@ -71,20 +74,41 @@ func (g *DataGenerator) Generate(config common.GeneratorConfig, target targets.I
 		return err
 	}
 
-	rand.Seed(g.config.Seed)
+	sim, err := g.seededSimulator()
+	if err != nil {
+		return err
+	}
 
-	scfg, err := usecases.GetSimulatorConfig(g.config)
+	headers := sim.Headers()
+	if ds, ok := sim.(*common.DisorderSimulator); ok && writesHeader(target) {
+		// the header reports the points actually disordered, which are only
+		// known once the simulator is done, so a first run of the same
+		// simulator counts them
+		counts := countDisorder(ds)
+		headers.DisorderCounts = &counts
+		if sim, err = g.seededSimulator(); err != nil {
+			return err
+		}
+	}
+	serializer, err := g.getSerializer(sim, headers, target)
 	if err != nil {
 		return err
 	}
 
-	sim := scfg.NewSimulator(g.config.LogInterval, g.config.Limit)
-	serializer, err := g.getSerializer(sim, target)
+	err = g.runSimulator(sim, serializer, g.config)
 	if err != nil {
 		return err
 	}
+	if err := closeOutput(g.bufOut, g.closer); err != nil {
+		return fmt.Errorf("cannot complete output: %v", err)
+	}
 
-	return g.runSimulator(sim, serializer, g.config)
+	if ds, ok := sim.(*common.DisorderSimulator); ok {
+		counts := ds.Counts()
+		fmt.Fprintf(os.Stderr, "injected disorder: %d late, %d duplicate, %d missing points\n",
+			counts.Late, counts.Duplicate, counts.Missing)
+	}
+	return nil
 }
 
 func (g *DataGenerator) CreateSimulator(config *common.DataGeneratorConfig) (common.Simulator, error) {
@@ -92,13 +116,46 @@ func (g *DataGenerator) CreateSimulator(config *common.DataGeneratorConfig) (com
 	if err != nil {
 		return nil, err
 	}
+	return g.seededSimulator()
+}
+
+// seededSimulator seeds the random number generator and creates the simulator
+// for the use case, so every simulator created with the same config makes the
+// same points.
+func (g *DataGenerator) seededSimulator() (common.Simulator, error) {
 	rand.Seed(g.config.Seed)
 	scfg, err := usecases.GetSimulatorConfig(g.config)
 	if err != nil {
 		return nil, err
 	}
+	return g.newSimulator(scfg), nil
+}
 
-	return scfg.NewSimulator(g.config.LogInterval, g.config.Limit), nil
+// countDisorder runs a disorder simulator to its end without writing its
+// points, and returns the number of points it disordered.
+func countDisorder(ds *common.DisorderSimulator) common.DisorderCounts {
+	point := data.NewPoint()
+	for !ds.Finished() {
+		ds.Next(point)
+		point.Reset()
+	}
+	return ds.Counts()
+}
+
+// newSimulator creates the simulator for the use case, wrapped so it moves
+// timestamps, leaves fields out and injects disorder when any is configured.
+func (g *DataGenerator) newSimulator(scfg common.SimulatorConfig) common.Simulator {
+	sim := scfg.NewSimulator(g.config.LogInterval, g.config.Limit)
+	if timing := g.config.Timing(); timing.Enabled() {
+		sim = common.NewTimingSimulator(sim, timing)
+	}
+	if sparse := g.config.Sparse(); sparse.Enabled() {
+		sim = common.NewSparseSimulator(sim, sparse)
+	}
+	if disorder := g.config.Disorder(); disorder.Enabled() {
+		return common.NewDisorderSimulator(sim, disorder)
+	}
+	return sim
 }
 
 func (g *DataGenerator) runSimulator(sim common.Simulator, serializer serialize.PointSerializer, dgc *common.DataGeneratorConfig) error {
@@ -127,16 +184,28 @@ func (g *DataGenerator) runSimulator(sim common.Simulator, serializer serialize.
 	return nil
 }
 
-func (g *DataGenerator) getSerializer(sim common.Simulator, target targets.ImplementedTarget) (serialize.PointSerializer, error) {
+// writesHeader tells whether the data of the target starts with a header
+// describing its tables.
+func writesHeader(target targets.ImplementedTarget) bool {
 	switch target.TargetName() {
-	case constants.FormatCrateDB:
-		fallthrough
-	case constants.FormatClickhouse:
-		fallthrough
-	case constants.FormatTimescaleDB:
-		g.writeHeader(sim.Headers())
-	}
-	return target.Serializer(), nil
+	case constants.FormatCrateDB, constants.FormatClickhouse, constants.FormatTimescaleDB, constants.FormatTimestream:
+		return true
+	}
+	return false
+}
+
+func (g *DataGenerator) getSerializer(sim common.Simulator, headers *common.GeneratedDataHeaders, target targets.ImplementedTarget) (serialize.PointSerializer, error) {
+	if writesHeader(target) {
+		g.writeHeader(headers)
+	}
+	serializer := target.Serializer()
+	if cs, ok := serializer.(serialize.ColumnarSerializer); ok {
+		cs.SetColumns(headers.FieldKeys)
+	}
+	if ts, ok := serializer.(serialize.TagKeysSerializer); ok {
+		ts.SetTagKeys(sim.TagKeys())
+	}
+	return serializer, nil
 }
 
 //TODO should be implemented in targets package
@@ -160,9 +229,28 @@ func (g *DataGenerator) writeHeader(headers *common.GeneratedDataHeaders) {
 	sort.Strings(keys)
 	for _, measurementName := range keys {
 		g.bufOut.WriteString(measurementName)
-		for _, field := range fields[measurementName] {
+		for i, field := range fields[measurementName] {
+			g.bufOut.WriteString(",")
+			g.bufOut.WriteString(common.FormatFieldColumn(field, headers.FieldType(measurementName, i)))
+		}
+		g.bufOut.WriteString("\n")
+	}
+	if headers.Timing != nil {
+		g.bufOut.WriteString(common.HeaderCommentPrefix + "timing,")
+		g.bufOut.WriteString(headers.Timing.String())
+		g.bufOut.WriteString("\n")
+	}
+	if headers.Sparse != nil {
+		g.bufOut.WriteString(common.HeaderCommentPrefix + "sparse,")
+		g.bufOut.WriteString(headers.Sparse.String())
+		g.bufOut.WriteString("\n")
+	}
+	if headers.Disorder != nil {
+		g.bufOut.WriteString(common.HeaderCommentPrefix + "disorder,")
+		g.bufOut.WriteString(headers.Disorder.String())
+		if headers.DisorderCounts != nil {
 			g.bufOut.WriteString(",")
-			g.bufOut.Write([]byte(field))
+			g.bufOut.WriteString(headers.DisorderCounts.String())
 		}
 		g.bufOut.WriteString("\n")
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -116,6 +117,53 @@ func TestDataGeneratorGenerate(t *testing.T) {
 	} else if len(mockSerializer.sentPoints) != int(c.Limit) {
 		t.Errorf("unexpected number of points sent to serializer. expected %d, got %d", c.Limit, len(mockSerializer.sentPoints))
 	}
+
+	// Test that disorder is injected and reported in the header
+	c.DuplicateRate = 1
+	buf.Reset()
+	mockSerializer.sentPoints = nil
+	err = dg.Generate(c, mockTarget)
+	if err != nil {
+		t.Errorf("unexpected error when generating with disorder: got %v", err)
+	} else if len(mockSerializer.sentPoints) != 2*int(c.Limit) {
+		t.Errorf("unexpected number of points sent to serializer with duplicates. expected %d, got %d", 2*c.Limit, len(mockSerializer.sentPoints))
+	}
+	want := "#disorder,late-rate=0,max-lateness=0s,duplicate-rate=1,missing-rate=0,late=0,duplicate=3,missing=0\n"
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("header does not report disorder: got\n%s\nwant it to contain\n%s", got, want)
+	}
+
+	// Test that the header reports the injected counts, not the rates
+	c.Limit = 100
+	c.LateRate = 0.2
+	c.MaxLateness = time.Minute
+	c.DuplicateRate = 0.2
+	c.MissingRate = 0.2
+	buf.Reset()
+	mockSerializer.sentPoints = nil
+	err = dg.Generate(c, mockTarget)
+	if err != nil {
+		t.Fatalf("unexpected error when generating with disorder: got %v", err)
+	}
+	var counts common.DisorderCounts
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.HasPrefix(line, "#disorder,") {
+			continue
+		}
+		i := strings.Index(line, ",late=")
+		if i < 0 {
+			t.Fatalf("header does not report disorder counts: got\n%s", line)
+		}
+		if _, err := fmt.Sscanf(line[i:], ",late=%d,duplicate=%d,missing=%d", &counts.Late, &counts.Duplicate, &counts.Missing); err != nil {
+			t.Fatalf("could not parse disorder counts of %s: %v", line, err)
+		}
+	}
+	if counts.Late == 0 || counts.Duplicate == 0 || counts.Missing == 0 {
+		t.Errorf("expected every kind of disorder to be counted, got %+v", counts)
+	}
+	if got, want := uint64(len(mockSerializer.sentPoints)), c.Limit-counts.Missing+counts.Duplicate; got != want {
+		t.Errorf("header counts do not match the points sent: got %d points, want %d", got, want)
+	}
 }
 
 var keyIteration = []byte("iteration")
@@ -315,7 +363,7 @@ func TestGetSerializer(t *testing.T) {
 			name:       format,
 			serializer: serializer,
 		}
-		s, err := g.getSerializer(sim, target)
+		s, err := g.getSerializer(sim, sim.Headers(), target)
 		if err != nil {
 			t.Errorf("unexpected error making serializer: %v", err)
 		}
@@ -340,6 +388,10 @@ func TestGetSerializer(t *testing.T) {
 	checkWriteHeader(constants.FormatTimescaleDB, true)
 	checkWriteHeader(constants.FormatVictoriaMetrics, false)
 	checkWriteHeader(constants.FormatQuestDB, false)
+	checkWriteHeader(constants.FormatOTLP, false)
+	checkWriteHeader(constants.FormatElasticsearch, false)
+	checkWriteHeader(constants.FormatGraphite, false)
+	checkWriteHeader(constants.FormatTimestream, true)
 }
 
 type mockSerializer struct {
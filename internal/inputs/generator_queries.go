@@ -40,6 +40,16 @@ type IoTGeneratorMaker interface {
 	NewIoT(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
 }
 
+// K8sGeneratorMaker creates a query generator for k8s use case
+type K8sGeneratorMaker interface {
+	NewK8s(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
+}
+
+// FinanceGeneratorMaker creates a query generator for finance use case
+type FinanceGeneratorMaker interface {
+	NewFinance(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
+}
+
 // QueryGenerator is a type of Generator for creating queries to test against a
 // database. The output is specific to the type of database (due to each using
 // different querying techniques, e.g. SQL or REST), but is consumed by TSBS
@@ -64,6 +74,9 @@ type QueryGenerator struct {
 	// bufOut represents the buffered writer that should actually be passed to
 	// any operations that write out data.
 	bufOut *bufio.Writer
+	// closer completes the output once bufOut is flushed, e.g. ends the
+	// compressed stream.
+	closer io.Closer
 }
 
 // NewQueryGenerator returns a QueryGenerator that is set up to work with a given
@@ -89,7 +102,14 @@ func (g *QueryGenerator) Generate(config common.GeneratorConfig) error {
 
 	filler := g.useCaseMatrix[g.conf.Use][g.conf.QueryType](useGen)
 
-	return g.runQueryGeneration(useGen, filler, g.conf)
+	err = g.runQueryGeneration(useGen, filler, g.conf)
+	if err != nil {
+		return err
+	}
+	if err := closeOutput(g.bufOut, g.closer); err != nil {
+		return fmt.Errorf("cannot complete output: %v", err)
+	}
+	return nil
 }
 
 func (g *QueryGenerator) init(conf common.GeneratorConfig) error {
@@ -132,7 +152,7 @@ func (g *QueryGenerator) init(conf common.GeneratorConfig) error {
 	if g.Out == nil {
 		g.Out = os.Stdout
 	}
-	g.bufOut, err = getBufferedWriter(g.conf.File, g.Out)
+	g.bufOut, g.closer, err = getBufferedWriter(g.conf.File, g.conf.Compression, g.Out)
 	if err != nil {
 		return err
 	}
@@ -158,7 +178,7 @@ func (g *QueryGenerator) addFactory(database string, factory interface{}) error
 	validFactory := false
 
 	switch factory.(type) {
-	case DevopsGeneratorMaker, IoTGeneratorMaker:
+	case DevopsGeneratorMaker, IoTGeneratorMaker, K8sGeneratorMaker, FinanceGeneratorMaker:
 		validFactory = true
 	}
 
@@ -196,6 +216,20 @@ func (g *QueryGenerator) getUseCaseGenerator(c *config.QueryGeneratorConfig) (qu
 		}
 
 		return devopsFactory.NewDevops(g.tsStart, g.tsEnd, scale)
+	case common.UseCaseK8s:
+		k8sFactory, ok := factory.(K8sGeneratorMaker)
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+
+		return k8sFactory.NewK8s(g.tsStart, g.tsEnd, scale)
+	case common.UseCaseFinance:
+		financeFactory, ok := factory.(FinanceGeneratorMaker)
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+
+		return financeFactory.NewFinance(g.tsStart, g.tsEnd, scale)
 	default:
 		return nil, fmt.Errorf(errUnknownUseCaseFmt, c.Use)
 	}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/timescale/tsbs/internal/compression"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets/constants"
 )
 
 const (
-	errBadFormatFmt = "invalid format specified: '%v'"
+	errBadFormatFmt      = "invalid format specified: '%v'"
+	errBadCompressionFmt = "invalid compression specified: '%v'"
 )
 
 func TestBaseConfigValidate(t *testing.T) {
@@ -90,4 +92,23 @@ func TestBaseConfigValidate(t *testing.T) {
 		}
 	}
 	c.Use = common.UseCaseDevops
+
+	// Test Compression validation
+	c.Compression = compression.Zstd
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error with Compression '%s': %v", compression.Zstd, err)
+	}
+
+	c.Compression = "rar"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for incorrect compression")
+	} else {
+		want := fmt.Sprintf(errBadCompressionFmt, "rar")
+		if got := err.Error(); got != want {
+			t.Errorf("incorrect error for incorrect compression: got\n%v\nwant\n%v", got, want)
+		}
+	}
+	c.Compression = ""
 }
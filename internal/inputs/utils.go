@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+
+	"github.com/timescale/tsbs/internal/compression"
 )
 
 const (
@@ -13,15 +15,67 @@ const (
 
 const defaultWriteSize = 4 << 20 // 4 MB
 
-func getBufferedWriter(filename string, fallback io.Writer) (*bufio.Writer, error) {
+// getBufferedWriter returns the buffered writer to the file, or to fallback if
+// no file name is given, along with the closer that completes the output once
+// the writer is flushed. Output is compressed as asked for, or as the extension
+// of the file name calls for if compression is empty.
+func getBufferedWriter(filename, compress string, fallback io.Writer) (*bufio.Writer, io.Closer, error) {
+	if len(compress) == 0 {
+		compress = compression.FromFileName(filename)
+	}
+
+	out := fallback
+	var file *os.File
 	// If filename is given, output should go to a file
 	if len(filename) > 0 {
-		file, err := os.Create(filename)
+		var err error
+		file, err = os.Create(filename)
 		if err != nil {
-			return nil, fmt.Errorf("cannot open file for write %s: %v", filename, err)
+			return nil, nil, fmt.Errorf("cannot open file for write %s: %v", filename, err)
 		}
-		return bufio.NewWriterSize(file, defaultWriteSize), nil
+		out = file
 	}
 
-	return bufio.NewWriterSize(fallback, defaultWriteSize), nil
+	cw, err := compression.NewWriter(out, compress)
+	if err != nil {
+		return nil, nil, err
+	}
+	closer := &outputCloser{compressor: cw}
+	if file != nil {
+		closer.file = file
+	}
+	if cw != nil {
+		out = cw
+	}
+	return bufio.NewWriterSize(out, defaultWriteSize), closer, nil
+}
+
+// outputCloser completes the output of a generator: it ends the compressed
+// stream, if any, and closes the file, if any.
+type outputCloser struct {
+	compressor io.Closer
+	file       io.Closer
+}
+
+func (c *outputCloser) Close() error {
+	if c.compressor != nil {
+		if err := c.compressor.Close(); err != nil {
+			return err
+		}
+	}
+	if c.file != nil {
+		return c.file.Close()
+	}
+	return nil
+}
+
+// closeOutput flushes bufOut and closes closer, if any.
+func closeOutput(bufOut *bufio.Writer, closer io.Closer) error {
+	if err := bufOut.Flush(); err != nil {
+		return err
+	}
+	if closer == nil {
+		return nil
+	}
+	return closer.Close()
 }
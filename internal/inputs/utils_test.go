@@ -1,9 +1,15 @@
 package inputs
 
 import (
-	"github.com/timescale/tsbs/internal/utils"
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/timescale/tsbs/internal/compression"
+	"github.com/timescale/tsbs/internal/utils"
 )
 
 func TestIsIn(t *testing.T) {
@@ -56,3 +62,56 @@ func TestParseUTCTime(t *testing.T) {
 		t.Errorf("unexpected lack of error")
 	}
 }
+
+func TestGetBufferedWriterCompression(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tsbs")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	const want = "some generated data\n"
+	cases := []struct {
+		file     string
+		compress string
+		want     string
+	}{
+		{file: "data", want: compression.None},
+		{file: "data.gz", want: compression.Gzip},
+		{file: "data.zst", want: compression.Zstd},
+		{file: "data.gz", compress: compression.None, want: compression.None},
+		{file: "data", compress: compression.Zstd, want: compression.Zstd},
+	}
+	for _, c := range cases {
+		fileName := filepath.Join(dir, c.file)
+		w, closer, err := getBufferedWriter(fileName, c.compress, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.file, err)
+		}
+		w.WriteString(want)
+		if err := closeOutput(w, closer); err != nil {
+			t.Fatalf("%s: unexpected error closing: %v", c.file, err)
+		}
+
+		f, err := os.Open(fileName)
+		if err != nil {
+			t.Fatalf("%s: could not open output: %v", c.file, err)
+		}
+		r := bufio.NewReader(f)
+		if got := compression.Detect(r); got != c.want {
+			t.Errorf("%s: incorrect compression with '%s': got %s want %s", c.file, c.compress, got, c.want)
+		}
+		dr, err := compression.NewReader(r, 4096)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.file, err)
+		}
+		if got, _ := ioutil.ReadAll(dr); string(got) != want {
+			t.Errorf("%s: incorrect output: got %q want %q", c.file, got, want)
+		}
+		f.Close()
+	}
+
+	if _, _, err := getBufferedWriter(filepath.Join(dir, "data"), "rar", nil); err == nil {
+		t.Errorf("unexpected lack of error for unknown compression")
+	}
+}
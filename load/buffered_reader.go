@@ -2,7 +2,10 @@ package load
 
 import (
 	"bufio"
+	"io"
 	"os"
+
+	"github.com/timescale/tsbs/internal/compression"
 )
 
 const (
@@ -10,17 +13,23 @@ const (
 )
 
 // GetBufferedReader returns the buffered Reader that should be used by the file loader
-// if no file name is specified a buffer for STDIN is returned
+// if no file name is specified a buffer for STDIN is returned. Input compressed with
+// gzip or zstd is decompressed ahead of the reads.
 func GetBufferedReader(fileName string) *bufio.Reader {
-	if len(fileName) == 0 {
-		// Read from STDIN
-		return bufio.NewReaderSize(os.Stdin, defaultReadSize)
+	var in io.Reader = os.Stdin
+	if len(fileName) > 0 {
+		// Read from specified file
+		file, err := os.Open(fileName)
+		if err != nil {
+			fatal("cannot open file for read %s: %v", fileName, err)
+			return nil
+		}
+		in = file
 	}
-	// Read from specified file
-	file, err := os.Open(fileName)
+	br, err := compression.NewReader(in, defaultReadSize)
 	if err != nil {
-		fatal("cannot open file for read %s: %v", fileName, err)
+		fatal("cannot decompress input %s: %v", fileName, err)
 		return nil
 	}
-	return bufio.NewReaderSize(file, defaultReadSize)
+	return br
 }
@@ -119,6 +119,21 @@ func (p *Point) ClearFieldValue(key []byte) {
 	}
 }
 
+// RemoveField removes the field with a given key, keeping the order of the remaining fields.
+// This will panic if the internal state has been altered to not have the same number of field keys as field values.
+func (p *Point) RemoveField(key []byte) {
+	if len(p.fieldKeys) != len(p.fieldValues) {
+		panic("field keys and field values are out of sync")
+	}
+	for i, v := range p.fieldKeys {
+		if bytes.Equal(v, key) {
+			p.fieldKeys = append(p.fieldKeys[:i], p.fieldKeys[i+1:]...)
+			p.fieldValues = append(p.fieldValues[:i], p.fieldValues[i+1:]...)
+			return
+		}
+	}
+}
+
 // TagKeys returns the Point's tag keys
 func (p *Point) TagKeys() [][]byte {
 	return p.tagKeys
@@ -236,6 +236,29 @@ func TestFields(t *testing.T) {
 	}
 }
 
+func TestRemoveField(t *testing.T) {
+	p := NewPoint()
+	p.AppendField([]byte("foo"), 1)
+	p.AppendField([]byte("bar"), 2)
+	p.AppendField([]byte("baz"), 3)
+
+	p.RemoveField([]byte("bar"))
+	if got := len(p.FieldKeys()); got != 2 {
+		t.Fatalf("incorrect len after remove: got %d want %d", got, 2)
+	}
+	if got := string(p.FieldKeys()[1]); got != "baz" {
+		t.Errorf("incorrect order after remove: got %s want baz", got)
+	}
+	if got := p.FieldValues()[1]; got != 3 {
+		t.Errorf("incorrect value after remove: got %v want 3", got)
+	}
+
+	p.RemoveField([]byte("qux"))
+	if got := len(p.FieldKeys()); got != 2 {
+		t.Errorf("removing an unknown key changed the len: got %d want %d", got, 2)
+	}
+}
+
 func TestFieldsPanic(t *testing.T) {
 	testPanic := func(p *Point) {
 		defer func() {
@@ -253,12 +276,21 @@ func TestFieldsPanic(t *testing.T) {
 		}()
 		p.ClearFieldValue([]byte("foo"))
 	}
+	testRemovePanic := func(p *Point) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("did not panic when should")
+			}
+		}()
+		p.RemoveField([]byte("foo"))
+	}
 	p := NewPoint()
 	p.AppendField([]byte("foo"), []byte("bar"))
 	p.fieldKeys = p.fieldKeys[:0]
 	testPanic(p)
 	p.AppendField([]byte("foo"), []byte("bar"))
 	testClearPanic(p)
+	testRemovePanic(p)
 }
 
 func TestTags(t *testing.T) {
@@ -9,3 +9,49 @@ import (
 type PointSerializer interface {
 	Serialize(p *data.Point, w io.Writer) error
 }
+
+// ColumnarSerializer is a PointSerializer for a format that writes a value for
+// every column of a measurement, in order, rather than a key with every value.
+// It has to be told the columns so that fields a point leaves out are written
+// as missing values instead of shifting the columns that follow.
+type ColumnarSerializer interface {
+	PointSerializer
+	// SetColumns sets the field keys of each measurement, in column order.
+	SetColumns(columns map[string][]string)
+}
+
+// TagKeysSerializer is a PointSerializer for a format that treats the tags
+// every point carries apart from the tags of single measurements, e.g. the tags
+// of a host apart from the device of a disk. It has to be told the former,
+// i.e. the tag keys of the simulator.
+type TagKeysSerializer interface {
+	PointSerializer
+	// SetTagKeys sets the keys of the tags every point carries.
+	SetTagKeys(keys []string)
+}
+
+// ColumnValues returns the field values of p in the order of the given
+// columns, with nil for every column p has no field for. Fields of p that are
+// not a column are left out. When p has a field for every column the values
+// of p are returned as they are.
+func ColumnValues(p *data.Point, columns []string) []interface{} {
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	if len(fieldKeys) == len(columns) || columns == nil {
+		return fieldValues
+	}
+
+	values := make([]interface{}, len(columns))
+	j := 0
+	for i, column := range columns {
+		// fields keep the column order, so only look ahead of the last match
+		for k := j; k < len(fieldKeys); k++ {
+			if string(fieldKeys[k]) == column {
+				values[i] = fieldValues[k]
+				j = k + 1
+				break
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,58 @@
+package serialize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnValues(t *testing.T) {
+	columns := []string{string(TestColInt64), string(TestColInt), string(TestColFloat)}
+	cases := []struct {
+		desc    string
+		columns []string
+		keys    [][]byte
+		values  []interface{}
+		want    []interface{}
+	}{
+		{
+			desc:    "all columns",
+			columns: columns,
+			keys:    [][]byte{TestColInt64, TestColInt, TestColFloat},
+			values:  []interface{}{TestInt64, TestInt, TestFloat},
+			want:    []interface{}{TestInt64, TestInt, TestFloat},
+		},
+		{
+			desc:    "middle column left out",
+			columns: columns,
+			keys:    [][]byte{TestColInt64, TestColFloat},
+			values:  []interface{}{TestInt64, TestFloat},
+			want:    []interface{}{TestInt64, nil, TestFloat},
+		},
+		{
+			desc:    "only last column",
+			columns: columns,
+			keys:    [][]byte{TestColFloat},
+			values:  []interface{}{TestFloat},
+			want:    []interface{}{nil, nil, TestFloat},
+		},
+		{
+			desc:    "unknown field",
+			columns: columns,
+			keys:    [][]byte{TestColInt, []byte("unknown")},
+			values:  []interface{}{TestInt, TestFloat},
+			want:    []interface{}{nil, TestInt, nil},
+		},
+		{
+			desc:   "no columns",
+			keys:   [][]byte{TestColFloat},
+			values: []interface{}{TestFloat},
+			want:   []interface{}{TestFloat},
+		},
+	}
+	for _, c := range cases {
+		p := generateTestPoint(TestMeasurement, TestTagKeys, TestTagVals, &TestNow, c.keys, c.values)
+		if got := ColumnValues(p, c.columns); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v want %v", c.desc, got, c.want)
+		}
+	}
+}
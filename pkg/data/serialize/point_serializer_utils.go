@@ -16,12 +16,18 @@ var (
 	TestColFloat    = []byte("usage_guest_nice")
 	TestColInt      = []byte("usage_guest")
 	TestColInt64    = []byte("big_usage_guest")
+	TestColString   = []byte("status")
+	TestColBool     = []byte("reachable")
+	TestColUint64   = []byte("requests_total")
 )
 
 const (
 	TestFloat             = float64(38.24311829)
 	TestInt               = 38
 	TestInt64             = int64(5000000000)
+	TestString            = "ok"
+	TestBool              = true
+	TestUint64            = uint64(18446744073709551615)
 	ErrWriterAlwaysErr    = "bad write: I always error"
 	ErrWriterSometimesErr = "bad write: I sometimes error"
 )
@@ -86,6 +92,11 @@ func TestPointWithNilField() *data.Point {
 		[][]byte{TestColInt64, TestColFloat}, []interface{}{nil, TestFloat})
 }
 
+func TestPointTypedFields() *data.Point {
+	return generateTestPoint(TestMeasurement, TestTagKeys, TestTagVals, &TestNow,
+		[][]byte{TestColString, TestColBool, TestColUint64}, []interface{}{TestString, TestBool, TestUint64})
+}
+
 type SerializeCase struct {
 	Desc       string
 	InputPoint *data.Point
@@ -12,6 +12,8 @@ func FastFormatAppend(v interface{}, buf []byte) []byte {
 		return strconv.AppendInt(buf, int64(v.(int)), 10)
 	case int64:
 		return strconv.AppendInt(buf, v.(int64), 10)
+	case uint64:
+		return strconv.AppendUint(buf, v.(uint64), 10)
 	case float64:
 		// Why -1 ?
 		// From Golang source on genericFtoa (called by AppendFloat): 'Negative precision means "only as much as needed to be exact."'
@@ -12,6 +12,18 @@ const (
 	errInvalidGroupsFmt = "incorrect interleaved groups configuration: id %d >= total groups %d"
 	errTotalGroupsZero  = "incorrect interleaved groups configuration: total groups = 0"
 	errLogIntervalZero  = "cannot have log interval of 0"
+
+	errRateOutOfRangeFmt = "%s must be between 0 and 1; got %g"
+	errMaxLatenessNotSet = "late points require a positive max lateness (--max-lateness)"
+
+	errShapeUnsupportedFmt = "seasonality, trend and anomalies are not supported by use case '%s'"
+	errSeasonalityPeriod   = "seasonality requires a positive period (--seasonality-period)"
+
+	errUseCasesMissing  = "multi use case requires a list of use cases (--use-cases)"
+	errUseCasesNotMulti = "a list of use cases (--use-cases) requires the multi use case"
+
+	errOutageUnsupportedFmt = "outages are not supported by use case '%s'"
+	errOutageDuration       = "outages require a positive duration (--outage-duration)"
 )
 
 func TestDataGeneratorConfigValidate(t *testing.T) {
@@ -90,4 +102,204 @@ func TestDataGeneratorConfigValidate(t *testing.T) {
 			t.Errorf("incorrect error for group id > num groups: got\n%s\nwant\n%s", got, want)
 		}
 	}
+	c.InterleavedGroupID = 0
+
+	// Test disorder validation
+	c.DuplicateRate = 1.5
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for duplicate rate > 1")
+	} else {
+		want := fmt.Sprintf(errRateOutOfRangeFmt, "duplicate rate", 1.5)
+		if got := err.Error(); got != want {
+			t.Errorf("incorrect error for duplicate rate > 1: got\n%s\nwant\n%s", got, want)
+		}
+	}
+	c.DuplicateRate = 0
+
+	c.LateRate = 0.1
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for late rate without max lateness")
+	} else if got := err.Error(); got != errMaxLatenessNotSet {
+		t.Errorf("incorrect error for late rate without max lateness: got\n%s\nwant\n%s", got, errMaxLatenessNotSet)
+	}
+
+	c.MaxLateness = time.Minute
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for late rate with max lateness: %v", err)
+	}
+
+	// Test shape validation
+	c.SeasonalityAmplitude = 0.3
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for seasonality without period")
+	} else if got := err.Error(); got != errSeasonalityPeriod {
+		t.Errorf("incorrect error for seasonality without period: got\n%s\nwant\n%s", got, errSeasonalityPeriod)
+	}
+
+	c.SeasonalityPeriod = 24 * time.Hour
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for seasonality with period: %v", err)
+	}
+
+	c.SpikeRate = 2
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for spike rate > 1")
+	} else {
+		want := fmt.Sprintf(errRateOutOfRangeFmt, "spike rate", 2.0)
+		if got := err.Error(); got != want {
+			t.Errorf("incorrect error for spike rate > 1: got\n%s\nwant\n%s", got, want)
+		}
+	}
+	c.SpikeRate = 0
+
+	c.Use = common.UseCaseK8s
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for shape in unsupported use case")
+	} else {
+		want := fmt.Sprintf(errShapeUnsupportedFmt, common.UseCaseK8s)
+		if got := err.Error(); got != want {
+			t.Errorf("incorrect error for shape in unsupported use case: got\n%s\nwant\n%s", got, want)
+		}
+	}
+	c.Use = common.UseCaseDevops
+
+	// Test timing validation
+	c.TimestampPrecision = "m"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for unknown timestamp precision")
+	}
+	c.TimestampPrecision = "ms"
+	c.Format = constants.FormatGraphite
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for timestamp precision finer than the format")
+	} else if got, want := err.Error(), "timestamp precision 'ms' is finer than format 'graphite' writes; choose 's' or coarser"; got != want {
+		t.Errorf("incorrect error for timestamp precision finer than the format: got\n%s\nwant\n%s", got, want)
+	}
+	c.Format = constants.FormatTimescaleDB
+
+	c.Jitter = -time.Second
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for negative jitter")
+	} else if got, want := err.Error(), "jitter cannot be negative"; got != want {
+		t.Errorf("incorrect error for negative jitter: got\n%s\nwant\n%s", got, want)
+	}
+	c.Jitter = time.Second
+
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for valid timing: %v", err)
+	}
+	if got, want := c.Timing(), (common.TimingConfig{Precision: time.Millisecond, Jitter: time.Second}); got != want {
+		t.Errorf("incorrect timing: got %v want %v", got, want)
+	}
+
+	// Test sparse validation
+	c.SparseRate = 0.5
+	c.SparseMode = "empty"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for unknown sparse mode")
+	} else if got, want := err.Error(), "unknown sparse mode 'empty'; choose from: null, absent"; got != want {
+		t.Errorf("incorrect error for unknown sparse mode: got\n%s\nwant\n%s", got, want)
+	}
+	c.SparseMode = ""
+
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for valid sparse rate: %v", err)
+	}
+	if got, want := c.Sparse(), (common.SparseConfig{Rate: 0.5, Mode: common.SparseModeNull}); got != want {
+		t.Errorf("incorrect sparse config: got %v want %v", got, want)
+	}
+
+	// Test multi use case validation
+	c.UseCases = "devops=5,iot=2"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for use cases without multi use case")
+	} else if got, want := err.Error(), errUseCasesNotMulti; got != want {
+		t.Errorf("incorrect error for use cases without multi use case: got\n%s\nwant\n%s", got, want)
+	}
+
+	c.Use = common.UseCaseMulti
+	c.UseCases = ""
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for multi use case without use cases")
+	} else if got, want := err.Error(), errUseCasesMissing; got != want {
+		t.Errorf("incorrect error for multi use case without use cases: got\n%s\nwant\n%s", got, want)
+	}
+
+	for _, useCases := range []string{"devops=5,bogus", "devops=0", "iot,iot", "multi", "devops,devops-status"} {
+		c.UseCases = useCases
+		if err = c.Validate(); err == nil {
+			t.Errorf("unexpected lack of error for use cases %s", useCases)
+		}
+	}
+
+	c.UseCases = "devops=5,iot"
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for valid use cases: %v", err)
+	}
+
+	c.UseCases = "iot,k8s"
+	c.Trend = 0.1
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for shape with unsupported use case")
+	} else if got, want := err.Error(), fmt.Sprintf(errShapeUnsupportedFmt, common.UseCaseK8s); got != want {
+		t.Errorf("incorrect error for shape with unsupported use case: got\n%s\nwant\n%s", got, want)
+	}
+	c.Trend = 0
+
+	// Test outage validation
+	c.Use = common.UseCaseDevops
+	c.UseCases = ""
+	c.OutageRate = 1.5
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for outage rate out of range")
+	} else if got, want := err.Error(), fmt.Sprintf(errRateOutOfRangeFmt, "outage rate", 1.5); got != want {
+		t.Errorf("incorrect error for outage rate out of range: got\n%s\nwant\n%s", got, want)
+	}
+
+	c.OutageRate = 0.01
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for outages without duration")
+	} else if got, want := err.Error(), errOutageDuration; got != want {
+		t.Errorf("incorrect error for outages without duration: got\n%s\nwant\n%s", got, want)
+	}
+
+	c.OutageDuration = time.Minute
+	c.Use = common.UseCaseIoT
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for outages with unsupported use case")
+	} else if got, want := err.Error(), fmt.Sprintf(errOutageUnsupportedFmt, common.UseCaseIoT); got != want {
+		t.Errorf("incorrect error for outages with unsupported use case: got\n%s\nwant\n%s", got, want)
+	}
+
+	c.Use = common.UseCaseCPUOnly
+	c.DatacenterOutageRate = 0.001
+	c.OutageReset = true
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for valid outages: %v", err)
+	}
+	want := common.OutageConfig{HostRate: 0.01, DatacenterRate: 0.001, MaxDuration: time.Minute, Reset: true}
+	if got := c.Outage(); got != want {
+		t.Errorf("incorrect outage config: got %v want %v", got, want)
+	}
 }
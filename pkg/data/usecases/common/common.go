@@ -24,6 +24,12 @@ const (
 	UseCaseDevops        = "devops"
 	UseCaseIoT           = "iot"
 	UseCaseDevopsGeneric = "devops-generic"
+	UseCaseCustom        = "custom"
+	UseCaseK8s           = "k8s"
+	UseCaseFinance       = "finance"
+	UseCaseDevopsStatus  = "devops-status"
+	// UseCaseMulti combines the use cases listed in DataGeneratorConfig.UseCases
+	UseCaseMulti = "multi"
 )
 
 var UseCaseChoices = []string{
@@ -32,4 +38,9 @@ var UseCaseChoices = []string{
 	UseCaseDevops,
 	UseCaseIoT,
 	UseCaseDevopsGeneric,
+	UseCaseCustom,
+	UseCaseK8s,
+	UseCaseFinance,
+	UseCaseDevopsStatus,
+	UseCaseMulti,
 }
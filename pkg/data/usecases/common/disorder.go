@@ -0,0 +1,220 @@
+package common
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// HeaderCommentPrefix starts header lines that describe the generated data
+// rather than a table; readers of the header skip them.
+const HeaderCommentPrefix = "#"
+
+// DisorderConfig describes how the points of a Simulator are disordered
+// before they are written. Rates are fractions of the simulated points.
+type DisorderConfig struct {
+	// LateRate is the fraction of points delivered late
+	LateRate float64
+	// MaxLateness is the upper bound of how far behind a late point is delivered
+	MaxLateness time.Duration
+	// DuplicateRate is the fraction of points delivered twice
+	DuplicateRate float64
+	// MissingRate is the fraction of points dropped
+	MissingRate float64
+}
+
+// Enabled tells whether any disorder is injected.
+func (c *DisorderConfig) Enabled() bool {
+	return c.LateRate > 0 || c.DuplicateRate > 0 || c.MissingRate > 0
+}
+
+// String formats the config as the comma separated key=value pairs that are
+// reported in the header of the generated data.
+func (c *DisorderConfig) String() string {
+	return fmt.Sprintf("late-rate=%g,max-lateness=%s,duplicate-rate=%g,missing-rate=%g",
+		c.LateRate, c.MaxLateness, c.DuplicateRate, c.MissingRate)
+}
+
+// DisorderCounts is the number of points a DisorderSimulator disordered.
+type DisorderCounts struct {
+	Late      uint64
+	Duplicate uint64
+	Missing   uint64
+}
+
+// String formats the counts as the comma separated key=value pairs that are
+// reported in the header of the generated data.
+func (c *DisorderCounts) String() string {
+	return fmt.Sprintf("late=%d,duplicate=%d,missing=%d", c.Late, c.Duplicate, c.Missing)
+}
+
+// DisorderSimulator wraps any Simulator and injects late, duplicate and
+// missing points. A late point is held back and delivered once the wrapped
+// simulator has moved past its timestamp plus a random lateness of up to
+// MaxLateness, so it arrives after points that are newer than it.
+type DisorderSimulator struct {
+	base   Simulator
+	config DisorderConfig
+	counts DisorderCounts
+
+	// ready contains the points to deliver before asking base for more
+	ready []*data.Point
+	// held contains late points, ordered by the time they are released
+	held latePoints
+}
+
+// NewDisorderSimulator wraps base so its points are disordered according to c.
+func NewDisorderSimulator(base Simulator, c DisorderConfig) *DisorderSimulator {
+	return &DisorderSimulator{
+		base:   base,
+		config: c,
+	}
+}
+
+// Finished tells whether the wrapped simulator is done and every held point
+// has been delivered.
+func (s *DisorderSimulator) Finished() bool {
+	return s.base.Finished() && len(s.ready) == 0 && s.held.Len() == 0
+}
+
+// Next populates p with the next point to deliver. It returns false when the
+// point drawn from the wrapped simulator should not be written: because the
+// wrapped simulator said so, or because it was dropped or held back.
+func (s *DisorderSimulator) Next(p *data.Point) bool {
+	if len(s.ready) == 0 {
+		if s.base.Finished() {
+			s.releaseUntil(nil)
+		} else if !s.simulateNext() {
+			return false
+		}
+	}
+	if len(s.ready) == 0 {
+		return false
+	}
+
+	p.Copy(s.ready[0])
+	s.ready[0] = nil
+	s.ready = s.ready[1:]
+	return true
+}
+
+// simulateNext draws one point from the wrapped simulator and decides its fate.
+func (s *DisorderSimulator) simulateNext() bool {
+	point := data.NewPoint()
+	if !s.base.Next(point) {
+		return false
+	}
+	// measurements reuse their timestamp between ticks, so keep a copy
+	ts := *point.Timestamp()
+	point.SetTimestamp(&ts)
+
+	s.releaseUntil(&ts)
+
+	if rand.Float64() < s.config.MissingRate {
+		s.counts.Missing++
+		return false
+	}
+
+	points := []*data.Point{point}
+	if rand.Float64() < s.config.DuplicateRate {
+		s.counts.Duplicate++
+		points = append(points, clonePoint(point))
+	}
+
+	if rand.Float64() < s.config.LateRate {
+		s.counts.Late++
+		release := ts.Add(time.Duration(rand.Int63n(int64(s.config.MaxLateness)) + 1))
+		for _, lp := range points {
+			heap.Push(&s.held, &latePoint{point: lp, release: release})
+		}
+		return true
+	}
+
+	s.ready = append(s.ready, points...)
+	return true
+}
+
+// releaseUntil moves the held points due at now to the ready queue. A nil now
+// releases all of them.
+func (s *DisorderSimulator) releaseUntil(now *time.Time) {
+	for s.held.Len() > 0 {
+		if now != nil && s.held[0].release.After(*now) {
+			return
+		}
+		s.ready = append(s.ready, heap.Pop(&s.held).(*latePoint).point)
+	}
+}
+
+// Counts returns the number of points disordered so far.
+func (s *DisorderSimulator) Counts() DisorderCounts {
+	return s.counts
+}
+
+// Fields returns the fields of the wrapped simulator.
+func (s *DisorderSimulator) Fields() map[string][]string {
+	return s.base.Fields()
+}
+
+// TagKeys returns the tag keys of the wrapped simulator.
+func (s *DisorderSimulator) TagKeys() []string {
+	return s.base.TagKeys()
+}
+
+// TagTypes returns the tag types of the wrapped simulator.
+func (s *DisorderSimulator) TagTypes() []string {
+	return s.base.TagTypes()
+}
+
+// Headers returns the headers of the wrapped simulator along with the
+// disorder config.
+func (s *DisorderSimulator) Headers() *GeneratedDataHeaders {
+	h := s.base.Headers()
+	h.Disorder = &s.config
+	return h
+}
+
+// clonePoint returns a copy of p that shares no slices with it, so both can
+// be handed out and reset independently.
+func clonePoint(p *data.Point) *data.Point {
+	c := data.NewPoint()
+	c.SetMeasurementName(p.MeasurementName())
+	tagValues := p.TagValues()
+	for i, k := range p.TagKeys() {
+		c.AppendTag(k, tagValues[i])
+	}
+	fieldValues := p.FieldValues()
+	for i, k := range p.FieldKeys() {
+		c.AppendField(k, fieldValues[i])
+	}
+	ts := *p.Timestamp()
+	c.SetTimestamp(&ts)
+	return c
+}
+
+type latePoint struct {
+	point   *data.Point
+	release time.Time
+}
+
+// latePoints is a min-heap of late points ordered by release time.
+type latePoints []*latePoint
+
+func (h latePoints) Len() int           { return len(h) }
+func (h latePoints) Less(i, j int) bool { return h[i].release.Before(h[j].release) }
+func (h latePoints) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *latePoints) Push(x interface{}) {
+	*h = append(*h, x.(*latePoint))
+}
+
+func (h *latePoints) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
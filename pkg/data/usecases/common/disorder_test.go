@@ -0,0 +1,180 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+var (
+	orderedMeasurementName = []byte("ordered")
+	orderedFieldKey        = []byte("seq")
+	orderedTagKey          = []byte("name")
+)
+
+// orderedSimulator emits count points one second apart, reusing a single
+// timestamp like measurements do. The points take turns among entities.
+type orderedSimulator struct {
+	start    time.Time
+	ts       time.Time
+	made     int
+	count    int
+	entities int
+}
+
+func newOrderedSimulator(count int) *orderedSimulator {
+	return &orderedSimulator{start: time.Unix(0, 0), count: count, entities: 1}
+}
+
+func (s *orderedSimulator) Finished() bool { return s.made >= s.count }
+
+func (s *orderedSimulator) Next(p *data.Point) bool {
+	s.ts = s.start.Add(time.Duration(s.made) * time.Second)
+	p.SetMeasurementName(orderedMeasurementName)
+	p.AppendTag(orderedTagKey, fmt.Sprintf("entity_%d", s.made%s.entities))
+	p.AppendField(orderedFieldKey, int64(s.made))
+	p.SetTimestamp(&s.ts)
+	s.made++
+	return true
+}
+
+func (s *orderedSimulator) Fields() map[string][]string {
+	return map[string][]string{string(orderedMeasurementName): {string(orderedFieldKey)}}
+}
+
+func (s *orderedSimulator) TagKeys() []string { return []string{string(orderedTagKey)} }
+
+func (s *orderedSimulator) TagTypes() []string { return []string{"string"} }
+
+func (s *orderedSimulator) Headers() *GeneratedDataHeaders {
+	return &GeneratedDataHeaders{
+		TagTypes:  s.TagTypes(),
+		TagKeys:   s.TagKeys(),
+		FieldKeys: s.Fields(),
+	}
+}
+
+// drain runs sim to completion and returns the sequence numbers and
+// timestamps of the written points.
+func drain(sim Simulator) ([]int64, []time.Time) {
+	var seqs []int64
+	var times []time.Time
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			seqs = append(seqs, p.GetFieldValue(orderedFieldKey).(int64))
+			times = append(times, *p.Timestamp())
+		}
+		p.Reset()
+	}
+	return seqs, times
+}
+
+func TestDisorderConfigEnabled(t *testing.T) {
+	c := &DisorderConfig{MaxLateness: time.Minute}
+	if c.Enabled() {
+		t.Errorf("config without rates should not be enabled")
+	}
+	c.MissingRate = 0.1
+	if !c.Enabled() {
+		t.Errorf("config with missing rate should be enabled")
+	}
+}
+
+func TestDisorderConfigString(t *testing.T) {
+	c := &DisorderConfig{LateRate: 0.05, MaxLateness: time.Minute, DuplicateRate: 0.01}
+	want := "late-rate=0.05,max-lateness=1m0s,duplicate-rate=0.01,missing-rate=0"
+	if got := c.String(); got != want {
+		t.Errorf("incorrect string: got %s want %s", got, want)
+	}
+}
+
+func TestDisorderSimulatorPassThrough(t *testing.T) {
+	sim := NewDisorderSimulator(newOrderedSimulator(100), DisorderConfig{})
+	seqs, times := drain(sim)
+	if len(seqs) != 100 {
+		t.Fatalf("incorrect point count: got %d want %d", len(seqs), 100)
+	}
+	for i, seq := range seqs {
+		if seq != int64(i) {
+			t.Fatalf("point %d out of order: got seq %d", i, seq)
+		}
+		if want := time.Unix(int64(i), 0); !times[i].Equal(want) {
+			t.Fatalf("point %d has wrong timestamp: got %v want %v", i, times[i], want)
+		}
+	}
+}
+
+func TestDisorderSimulatorMissing(t *testing.T) {
+	sim := NewDisorderSimulator(newOrderedSimulator(100), DisorderConfig{MissingRate: 1})
+	seqs, _ := drain(sim)
+	if len(seqs) != 0 {
+		t.Errorf("incorrect point count: got %d want %d", len(seqs), 0)
+	}
+	if got := sim.Counts().Missing; got != 100 {
+		t.Errorf("incorrect missing count: got %d want %d", got, 100)
+	}
+}
+
+func TestDisorderSimulatorDuplicate(t *testing.T) {
+	sim := NewDisorderSimulator(newOrderedSimulator(100), DisorderConfig{DuplicateRate: 1})
+	seqs, times := drain(sim)
+	if len(seqs) != 200 {
+		t.Fatalf("incorrect point count: got %d want %d", len(seqs), 200)
+	}
+	for i := 0; i < len(seqs); i += 2 {
+		if seqs[i] != seqs[i+1] || !times[i].Equal(times[i+1]) {
+			t.Fatalf("point %d not followed by its duplicate: got %d@%v and %d@%v", i, seqs[i], times[i], seqs[i+1], times[i+1])
+		}
+	}
+	if got := sim.Counts().Duplicate; got != 100 {
+		t.Errorf("incorrect duplicate count: got %d want %d", got, 100)
+	}
+}
+
+func TestDisorderSimulatorLate(t *testing.T) {
+	maxLateness := 10 * time.Second
+	sim := NewDisorderSimulator(newOrderedSimulator(1000), DisorderConfig{LateRate: 0.2, MaxLateness: maxLateness})
+	seqs, times := drain(sim)
+	if len(seqs) != 1000 {
+		t.Fatalf("incorrect point count: got %d want %d", len(seqs), 1000)
+	}
+
+	seen := make(map[int64]bool)
+	outOfOrder := 0
+	newest := time.Unix(0, 0)
+	for i, seq := range seqs {
+		if seen[seq] {
+			t.Fatalf("point %d delivered twice", seq)
+		}
+		seen[seq] = true
+		if times[i].Before(newest) {
+			outOfOrder++
+			if lateness := newest.Sub(times[i]); lateness >= maxLateness {
+				t.Errorf("point %d delivered %v late, max is %v", seq, lateness, maxLateness)
+			}
+		} else {
+			newest = times[i]
+		}
+	}
+	if outOfOrder == 0 {
+		t.Errorf("no point was delivered out of order")
+	}
+	if got := sim.Counts().Late; got == 0 || got > 1000 {
+		t.Errorf("incorrect late count: got %d", got)
+	}
+}
+
+func TestDisorderSimulatorHeaders(t *testing.T) {
+	c := DisorderConfig{DuplicateRate: 0.5}
+	sim := NewDisorderSimulator(newOrderedSimulator(1), c)
+	h := sim.Headers()
+	if h.Disorder == nil || *h.Disorder != c {
+		t.Errorf("incorrect disorder in headers: got %v want %v", h.Disorder, c)
+	}
+	if got := h.TagKeys; len(got) != 1 || got[0] != string(orderedTagKey) {
+		t.Errorf("incorrect tag keys: got %v", got)
+	}
+}
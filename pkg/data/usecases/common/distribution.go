@@ -239,3 +239,248 @@ func (d *LazyDistribution) Advance() {
 func (d *LazyDistribution) Get() float64 {
 	return d.step.Get()
 }
+
+// SeasonalDistribution scales an underlying distribution by a sinusoidal
+// cycle that is Period steps long. The value is scaled down by Amplitude at
+// the start of each cycle and up by Amplitude halfway through it. Offset is
+// the number of steps into the cycle that the distribution starts at.
+type SeasonalDistribution struct {
+	Base      Distribution
+	Amplitude float64
+	Period    int
+	Offset    int
+
+	step int
+}
+
+// SD creates a new SeasonalDistribution over a given distribution with the
+// given amplitude, period and offset.
+func SD(base Distribution, amplitude float64, period, offset int) *SeasonalDistribution {
+	return &SeasonalDistribution{
+		Base:      base,
+		Amplitude: amplitude,
+		Period:    period,
+		Offset:    offset,
+	}
+}
+
+// Advance advances the underlying distribution and moves one step along the cycle.
+func (d *SeasonalDistribution) Advance() {
+	d.Base.Advance()
+	d.step++
+}
+
+// Get returns the value of the underlying distribution scaled by the cycle.
+func (d *SeasonalDistribution) Get() float64 {
+	phase := 2 * math.Pi * float64(d.step+d.Offset) / float64(d.Period)
+	return d.Base.Get() * (1 - d.Amplitude*math.Cos(phase))
+}
+
+// TrendDistribution scales an underlying distribution by a linear trend,
+// growing (or shrinking, for a negative Slope) by Slope of its value with
+// every step. The scale never drops below zero.
+type TrendDistribution struct {
+	Base  Distribution
+	Slope float64
+
+	step int
+}
+
+// TD creates a new TrendDistribution over a given distribution with the given slope.
+func TD(base Distribution, slope float64) *TrendDistribution {
+	return &TrendDistribution{
+		Base:  base,
+		Slope: slope,
+	}
+}
+
+// Advance advances the underlying distribution and the trend.
+func (d *TrendDistribution) Advance() {
+	d.Base.Advance()
+	d.step++
+}
+
+// Get returns the value of the underlying distribution scaled by the trend.
+func (d *TrendDistribution) Get() float64 {
+	return d.Base.Get() * math.Max(0, 1+d.Slope*float64(d.step))
+}
+
+// StepChangeDistribution scales an underlying distribution by a level that
+// changes abruptly. With every step, there is a Rate chance that the level is
+// changed by a random fraction of up to Size of its value, either way.
+type StepChangeDistribution struct {
+	Base Distribution
+	Rate float64
+	Size float64
+
+	level float64
+}
+
+// SCD creates a new StepChangeDistribution over a given distribution with the
+// given rate and maximum size of changes.
+func SCD(base Distribution, rate, size float64) *StepChangeDistribution {
+	return &StepChangeDistribution{
+		Base:  base,
+		Rate:  rate,
+		Size:  size,
+		level: 1,
+	}
+}
+
+// Advance advances the underlying distribution and possibly changes the level.
+func (d *StepChangeDistribution) Advance() {
+	d.Base.Advance()
+	if rand.Float64() < d.Rate {
+		d.level *= 1 + (2*rand.Float64()-1)*d.Size
+	}
+}
+
+// Get returns the value of the underlying distribution scaled by the current level.
+func (d *StepChangeDistribution) Get() float64 {
+	return d.Base.Get() * d.level
+}
+
+// SpikeDistribution injects spikes into an underlying distribution. With
+// every step outside of a spike, there is a Rate chance that a spike starts.
+// For the Duration steps of a spike the value is increased by Magnitude of
+// itself.
+type SpikeDistribution struct {
+	Base      Distribution
+	Rate      float64
+	Magnitude float64
+	Duration  int
+
+	remaining int
+}
+
+// SPD creates a new SpikeDistribution over a given distribution with the
+// given rate, magnitude and duration of spikes.
+func SPD(base Distribution, rate, magnitude float64, duration int) *SpikeDistribution {
+	return &SpikeDistribution{
+		Base:      base,
+		Rate:      rate,
+		Magnitude: magnitude,
+		Duration:  duration,
+	}
+}
+
+// Advance advances the underlying distribution and ends or starts a spike.
+func (d *SpikeDistribution) Advance() {
+	d.Base.Advance()
+	if d.remaining > 0 {
+		d.remaining--
+	}
+	if d.remaining == 0 && rand.Float64() < d.Rate {
+		d.remaining = d.Duration
+	}
+}
+
+// Get returns the value of the underlying distribution, increased during a spike.
+func (d *SpikeDistribution) Get() float64 {
+	if d.remaining > 0 {
+		return d.Base.Get() * (1 + d.Magnitude)
+	}
+	return d.Base.Get()
+}
+
+// FlatLineDistribution injects flat-lines into an underlying distribution,
+// like a sensor or exporter that got stuck. With every step outside of a
+// flat-line, there is a Rate chance that the value stops changing for the
+// next Duration steps. The underlying distribution keeps advancing meanwhile.
+type FlatLineDistribution struct {
+	Base     Distribution
+	Rate     float64
+	Duration int
+
+	remaining int
+	value     float64
+}
+
+// FLD creates a new FlatLineDistribution over a given distribution with the
+// given rate and duration of flat-lines.
+func FLD(base Distribution, rate float64, duration int) *FlatLineDistribution {
+	return &FlatLineDistribution{
+		Base:     base,
+		Rate:     rate,
+		Duration: duration,
+		value:    base.Get(),
+	}
+}
+
+// Advance advances the underlying distribution and updates the value unless flat-lining.
+func (d *FlatLineDistribution) Advance() {
+	d.Base.Advance()
+	if d.remaining > 0 {
+		d.remaining--
+		return
+	}
+	if rand.Float64() < d.Rate {
+		d.remaining = d.Duration - 1
+		return
+	}
+	d.value = d.Base.Get()
+}
+
+// Get returns the last value taken from the underlying distribution.
+func (d *FlatLineDistribution) Get() float64 {
+	return d.value
+}
+
+// ClampedDistribution keeps the values of an underlying distribution within
+// Min and Max.
+type ClampedDistribution struct {
+	Base Distribution
+	Min  float64
+	Max  float64
+}
+
+// CD creates a new ClampedDistribution over a given distribution with the given bounds.
+func CD(base Distribution, min, max float64) *ClampedDistribution {
+	return &ClampedDistribution{
+		Base: base,
+		Min:  min,
+		Max:  max,
+	}
+}
+
+// Advance calls the underlying distribution Advance method.
+func (d *ClampedDistribution) Advance() {
+	d.Base.Advance()
+}
+
+// Get returns the value of the underlying distribution clamped to the bounds.
+func (d *ClampedDistribution) Get() float64 {
+	return math.Min(math.Max(d.Base.Get(), d.Min), d.Max)
+}
+
+// MarkovStateDistribution moves between a fixed number of discrete states,
+// such as the statuses of a service. With every step, there is a Rate chance
+// that it leaves its state for a random other one. Its values are the
+// indices of the states, starting in state 0.
+type MarkovStateDistribution struct {
+	States int
+	Rate   float64
+
+	state int
+}
+
+// MSD creates a new MarkovStateDistribution with the given number of states
+// and rate of changing state.
+func MSD(states int, rate float64) *MarkovStateDistribution {
+	return &MarkovStateDistribution{
+		States: states,
+		Rate:   rate,
+	}
+}
+
+// Advance possibly moves to a random other state.
+func (d *MarkovStateDistribution) Advance() {
+	if d.States > 1 && rand.Float64() < d.Rate {
+		d.state = (d.state + 1 + rand.Intn(d.States-1)) % d.States
+	}
+}
+
+// Get returns the index of the current state.
+func (d *MarkovStateDistribution) Get() float64 {
+	return float64(d.state)
+}
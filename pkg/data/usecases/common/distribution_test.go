@@ -158,3 +158,143 @@ func TestLazyDistribution(t *testing.T) {
 		})
 	}
 }
+
+func TestSeasonalDistribution(t *testing.T) {
+	sd := SD(&mockDistribution{ReturnValue: 10}, 0.5, 4, 0)
+	// the cycle is lowest at the start, back at base a quarter through and peaks halfway through
+	for i, want := range []float64{5, 10, 15, 10, 5} {
+		if got := sd.Get(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("step %d: got %f want %f", i, got, want)
+		}
+		sd.Advance()
+	}
+	if !sd.Base.(*mockDistribution).AdvanceCalled {
+		t.Errorf("advance not called on base distribution")
+	}
+
+	sd = SD(&mockDistribution{ReturnValue: 10}, 0.5, 4, 2)
+	if got := sd.Get(); math.Abs(got-15) > 1e-9 {
+		t.Errorf("offset not applied: got %f want 15", got)
+	}
+}
+
+func TestTrendDistribution(t *testing.T) {
+	td := TD(&mockDistribution{ReturnValue: 10}, 0.1)
+	for i, want := range []float64{10, 11, 12} {
+		if got := td.Get(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("step %d: got %f want %f", i, got, want)
+		}
+		td.Advance()
+	}
+
+	td = TD(&mockDistribution{ReturnValue: 10}, -0.6)
+	td.Advance()
+	td.Advance()
+	if got := td.Get(); got != 0 {
+		t.Errorf("shrinking trend went below zero: got %f", got)
+	}
+}
+
+func TestStepChangeDistribution(t *testing.T) {
+	scd := SCD(&mockDistribution{ReturnValue: 10}, 0, 0.5)
+	scd.Advance()
+	if got := scd.Get(); got != 10 {
+		t.Errorf("level changed with zero rate: got %f", got)
+	}
+
+	scd.Rate = 1
+	prev := scd.Get()
+	for i := 0; i < 100; i++ {
+		scd.Advance()
+		got := scd.Get()
+		if got < prev*0.5 || got > prev*1.5 {
+			t.Fatalf("level changed by more than size: from %f to %f", prev, got)
+		}
+		prev = got
+	}
+}
+
+func TestSpikeDistribution(t *testing.T) {
+	spd := SPD(&mockDistribution{ReturnValue: 10}, 1, 2, 3)
+	if got := spd.Get(); got != 10 {
+		t.Errorf("spiked before advancing: got %f", got)
+	}
+	for i := 0; i < 3; i++ {
+		spd.Advance()
+		if got := spd.Get(); got != 30 {
+			t.Errorf("step %d: got %f want 30", i, got)
+		}
+	}
+
+	spd.Rate = 0
+	spd.Advance()
+	if got := spd.Get(); got != 10 {
+		t.Errorf("spike did not end after its duration: got %f", got)
+	}
+}
+
+func TestFlatLineDistribution(t *testing.T) {
+	base := WD(&mockDistribution{ReturnValue: 1}, 0)
+	fld := FLD(base, 0, 2)
+	fld.Advance()
+	if got := fld.Get(); got != 1 {
+		t.Errorf("value not updated without flat-line: got %f", got)
+	}
+
+	fld.Rate = 1
+	fld.Advance()
+	fld.Advance()
+	if got := fld.Get(); got != 1 {
+		t.Errorf("value changed during flat-line: got %f", got)
+	}
+
+	fld.Rate = 0
+	fld.Advance()
+	if got, want := fld.Get(), base.Get(); got != want || got != 4 {
+		t.Errorf("value not updated after flat-line: got %f want %f", got, want)
+	}
+}
+
+func TestClampedDistribution(t *testing.T) {
+	m := &mockDistribution{ReturnValue: 10}
+	cd := CD(m, 0, 5)
+	cd.Advance()
+	if !m.AdvanceCalled {
+		t.Errorf("advance not called on base distribution")
+	}
+	if got := cd.Get(); got != 5 {
+		t.Errorf("value not clamped to max: got %f", got)
+	}
+	m.ReturnValue = -1
+	if got := cd.Get(); got != 0 {
+		t.Errorf("value not clamped to min: got %f", got)
+	}
+}
+
+func TestMarkovStateDistribution(t *testing.T) {
+	msd := MSD(3, 0)
+	msd.Advance()
+	if got := msd.Get(); got != 0 {
+		t.Errorf("state changed with zero rate: got %f", got)
+	}
+
+	msd.Rate = 1
+	prev := msd.Get()
+	for i := 0; i < 100; i++ {
+		msd.Advance()
+		got := msd.Get()
+		if got == prev {
+			t.Fatalf("state not changed with a rate of 1: stayed %f", got)
+		}
+		if got < 0 || got >= 3 || got != math.Trunc(got) {
+			t.Fatalf("not a state index: got %f", got)
+		}
+		prev = got
+	}
+
+	single := MSD(1, 1)
+	single.Advance()
+	if got := single.Get(); got != 0 {
+		t.Errorf("single state changed: got %f", got)
+	}
+}
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// Types of field values that are reported in the header of the generated data,
+// named after the Go types of the values. Fields without a type are numbers,
+// which targets store as floats. Int64 fields are only typed by use cases that
+// say so in their headers, as other use cases write int64 values for numbers
+// that are stored as floats.
+const (
+	FieldTypeString = "string"
+	FieldTypeBool   = "bool"
+	FieldTypeUint64 = "uint64"
+	FieldTypeInt64  = "int64"
+)
+
+// FieldType returns the type of the given field value, or "" if it is a
+// number that can be stored as a float.
+func FieldType(v interface{}) string {
+	switch v.(type) {
+	case string, []byte:
+		return FieldTypeString
+	case bool:
+		return FieldTypeBool
+	case uint64:
+		return FieldTypeUint64
+	}
+	return ""
+}
+
+// FieldTypes returns the types of the fields of the given measurements, keyed
+// by measurement name like their field keys. It returns nil if every field is
+// a number that can be stored as a float.
+func FieldTypes(measurements []SimulatedMeasurement) map[string][]string {
+	types := make(map[string][]string, len(measurements))
+	typed := false
+	for _, sm := range measurements {
+		point := data.NewPoint()
+		sm.ToPoint(point)
+		values := point.FieldValues()
+		fieldTypes := make([]string, len(values))
+		for i, v := range values {
+			fieldTypes[i] = FieldType(v)
+			typed = typed || fieldTypes[i] != ""
+		}
+		types[string(point.MeasurementName())] = fieldTypes
+	}
+	if !typed {
+		return nil
+	}
+	return types
+}
+
+// FormatFieldColumn formats a field column of the header of the generated
+// data: the field key, followed by its type if it has one.
+func FormatFieldColumn(key, fieldType string) string {
+	if fieldType == "" {
+		return key
+	}
+	return key + " " + fieldType
+}
+
+// ParseFieldColumns splits the field columns of a header line of the
+// generated data into the field keys and their types. The types are nil if no
+// column has one.
+func ParseFieldColumns(columns []string) (keys, types []string) {
+	keys = make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column
+		if sep := strings.IndexByte(column, ' '); sep >= 0 {
+			if types == nil {
+				types = make([]string, len(columns))
+			}
+			keys[i], types[i] = column[:sep], column[sep+1:]
+		}
+	}
+	return keys, types
+}
+
+// ParseFieldValue parses a field value that was formatted as text back into a
+// value of the given type; values of fields without a type are parsed as
+// float64.
+func ParseFieldValue(s, fieldType string) (interface{}, error) {
+	switch fieldType {
+	case FieldTypeString:
+		return s, nil
+	case FieldTypeBool:
+		return strconv.ParseBool(s)
+	case FieldTypeUint64:
+		return strconv.ParseUint(s, 10, 64)
+	case FieldTypeInt64:
+		return strconv.ParseInt(s, 10, 64)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// FieldType returns the type of the i-th field of the given measurement, or ""
+// if it is a number that can be stored as a float.
+func (h *GeneratedDataHeaders) FieldType(measurement string, i int) string {
+	types := h.FieldTypes[measurement]
+	if i >= len(types) {
+		return ""
+	}
+	return types[i]
+}
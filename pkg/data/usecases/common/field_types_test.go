@@ -0,0 +1,124 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestFieldType(t *testing.T) {
+	cases := []struct {
+		v    interface{}
+		want string
+	}{
+		{v: 1.5, want: ""},
+		{v: int64(1), want: ""},
+		{v: nil, want: ""},
+		{v: "ok", want: FieldTypeString},
+		{v: []byte("ok"), want: FieldTypeString},
+		{v: true, want: FieldTypeBool},
+		{v: uint64(1), want: FieldTypeUint64},
+	}
+	for _, c := range cases {
+		if got := FieldType(c.v); got != c.want {
+			t.Errorf("incorrect type for %#v: got %q want %q", c.v, got, c.want)
+		}
+	}
+}
+
+type typedMeasurement struct {
+	name   []byte
+	values []interface{}
+}
+
+func (m *typedMeasurement) Tick(time.Duration) {}
+
+func (m *typedMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(m.name)
+	for i, v := range m.values {
+		p.AppendField([]byte{byte('a' + i)}, v)
+	}
+}
+
+func TestFieldTypes(t *testing.T) {
+	numbers := &typedMeasurement{name: []byte("numbers"), values: []interface{}{1.5, int64(2)}}
+	if got := FieldTypes([]SimulatedMeasurement{numbers}); got != nil {
+		t.Errorf("types returned for numbers only: got %v", got)
+	}
+
+	typed := &typedMeasurement{name: []byte("typed"), values: []interface{}{"ok", 1.5, true, uint64(3)}}
+	got := FieldTypes([]SimulatedMeasurement{numbers, typed})
+	want := map[string][]string{
+		"numbers": {"", ""},
+		"typed":   {FieldTypeString, "", FieldTypeBool, FieldTypeUint64},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect types: got %v want %v", got, want)
+	}
+
+	h := &GeneratedDataHeaders{FieldTypes: got}
+	if got := h.FieldType("typed", 2); got != FieldTypeBool {
+		t.Errorf("incorrect header field type: got %q want %q", got, FieldTypeBool)
+	}
+	if got := h.FieldType("missing", 0); got != "" {
+		t.Errorf("incorrect header field type of missing measurement: got %q", got)
+	}
+}
+
+func TestFieldColumns(t *testing.T) {
+	columns := []string{
+		FormatFieldColumn("usage", ""),
+		FormatFieldColumn("status", FieldTypeString),
+		FormatFieldColumn("total", FieldTypeUint64),
+	}
+	if want := []string{"usage", "status string", "total uint64"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("incorrect columns: got %v want %v", columns, want)
+	}
+
+	keys, types := ParseFieldColumns(columns)
+	if want := []string{"usage", "status", "total"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("incorrect keys: got %v want %v", keys, want)
+	}
+	if want := []string{"", FieldTypeString, FieldTypeUint64}; !reflect.DeepEqual(types, want) {
+		t.Errorf("incorrect types: got %v want %v", types, want)
+	}
+
+	if _, types := ParseFieldColumns([]string{"usage", "idle"}); types != nil {
+		t.Errorf("types returned for untyped columns: got %v", types)
+	}
+}
+
+func TestParseFieldValue(t *testing.T) {
+	cases := []struct {
+		s         string
+		fieldType string
+		want      interface{}
+		wantErr   bool
+	}{
+		{s: "1.5", want: 1.5},
+		{s: "2", want: 2.0},
+		{s: "ok", fieldType: FieldTypeString, want: "ok"},
+		{s: "true", fieldType: FieldTypeBool, want: true},
+		{s: "18446744073709551615", fieldType: FieldTypeUint64, want: uint64(18446744073709551615)},
+		{s: "ok", wantErr: true},
+		{s: "-1", fieldType: FieldTypeUint64, wantErr: true},
+		{s: "-12345", fieldType: FieldTypeInt64, want: int64(-12345)},
+		{s: "1.5", fieldType: FieldTypeInt64, wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := ParseFieldValue(c.s, c.fieldType)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("no error parsing %q as %q", c.s, c.fieldType)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing %q as %q: %v", c.s, c.fieldType, err)
+		} else if got != c.want {
+			t.Errorf("incorrect value parsing %q as %q: got %#v want %#v", c.s, c.fieldType, got, c.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/compression"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/targets/constants"
 	"strings"
@@ -23,9 +24,31 @@ type Tag struct {
 }
 
 const (
-	errMaxMetricCountValue = "max metric count per host has to be greater than 0"
-	errLogIntervalZero     = "cannot have log interval of 0"
-	defaultLogInterval     = 10 * time.Second
+	errMaxMetricCountValue  = "max metric count per host has to be greater than 0"
+	errLogIntervalZero      = "cannot have log interval of 0"
+	errCustomSchemaMissing  = "custom use case requires a schema file (--custom-schema)"
+	errPodLifetimeNegative  = "pod mean lifetime cannot be negative"
+	errRateOutOfRangeFmt    = "%s must be between 0 and 1; got %g"
+	errMaxLatenessNotSet    = "late points require a positive max lateness (--max-lateness)"
+	errShapeUnsupportedFmt  = "seasonality, trend and anomalies are not supported by use case '%s'"
+	errShapeNegativeFmt     = "%s cannot be negative"
+	errSeasonalityPeriod    = "seasonality requires a positive period (--seasonality-period)"
+	errTimingNegativeFmt    = "%s cannot be negative"
+	errUseCasesMissing      = "multi use case requires a list of use cases (--use-cases)"
+	errUseCasesNotMulti     = "a list of use cases (--use-cases) requires the multi use case"
+	errOutageUnsupportedFmt = "outages are not supported by use case '%s'"
+	errOutageDuration       = "outages require a positive duration (--outage-duration)"
+	defaultPodMeanLifetime  = 30 * time.Minute
+	defaultMaxLateness      = time.Minute
+	defaultLogInterval      = 10 * time.Second
+	defaultSparseMode       = SparseModeNull
+	defaultOutageDuration   = 10 * time.Minute
+
+	defaultSeasonalityPeriod = 24 * time.Hour
+	defaultStepSize          = 0.2
+	defaultSpikeMagnitude    = 1.0
+	defaultSpikeDuration     = 5 * time.Minute
+	defaultFlatLineDuration  = 10 * time.Minute
 )
 
 // DataGeneratorConfig is the GeneratorConfig that should be used with a
@@ -40,6 +63,105 @@ type DataGeneratorConfig struct {
 	InterleavedGroupID    uint          `yaml:"interleaved-generation-group-id" mapstructure:"interleaved-generation-group-id"`
 	InterleavedNumGroups  uint          `yaml:"interleaved-generation-groups" mapstructure:"interleaved-generation-groups"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	CustomSchema          string        `yaml:"custom-schema,omitempty" mapstructure:"custom-schema"`
+	PodMeanLifetime       time.Duration `yaml:"pod-mean-lifetime" mapstructure:"pod-mean-lifetime"`
+	LateRate              float64       `yaml:"late-rate" mapstructure:"late-rate"`
+	MaxLateness           time.Duration `yaml:"max-lateness" mapstructure:"max-lateness"`
+	DuplicateRate         float64       `yaml:"duplicate-rate" mapstructure:"duplicate-rate"`
+	MissingRate           float64       `yaml:"missing-rate" mapstructure:"missing-rate"`
+
+	SeasonalityAmplitude float64       `yaml:"seasonality-amplitude" mapstructure:"seasonality-amplitude"`
+	SeasonalityPeriod    time.Duration `yaml:"seasonality-period" mapstructure:"seasonality-period"`
+	Trend                float64       `yaml:"trend" mapstructure:"trend"`
+	StepRate             float64       `yaml:"step-rate" mapstructure:"step-rate"`
+	StepSize             float64       `yaml:"step-size" mapstructure:"step-size"`
+	SpikeRate            float64       `yaml:"spike-rate" mapstructure:"spike-rate"`
+	SpikeMagnitude       float64       `yaml:"spike-magnitude" mapstructure:"spike-magnitude"`
+	SpikeDuration        time.Duration `yaml:"spike-duration" mapstructure:"spike-duration"`
+	FlatLineRate         float64       `yaml:"flat-line-rate" mapstructure:"flat-line-rate"`
+	FlatLineDuration     time.Duration `yaml:"flat-line-duration" mapstructure:"flat-line-duration"`
+
+	TimestampPrecision string        `yaml:"timestamp-precision" mapstructure:"timestamp-precision"`
+	MaxPhaseOffset     time.Duration `yaml:"max-phase-offset" mapstructure:"max-phase-offset"`
+	Jitter             time.Duration `yaml:"jitter" mapstructure:"jitter"`
+
+	SparseRate float64 `yaml:"sparse-rate" mapstructure:"sparse-rate"`
+	SparseMode string  `yaml:"sparse-mode" mapstructure:"sparse-mode"`
+
+	UseCases string `yaml:"use-cases,omitempty" mapstructure:"use-cases"`
+
+	OutageRate           float64       `yaml:"outage-rate" mapstructure:"outage-rate"`
+	DatacenterOutageRate float64       `yaml:"datacenter-outage-rate" mapstructure:"datacenter-outage-rate"`
+	OutageDuration       time.Duration `yaml:"outage-duration" mapstructure:"outage-duration"`
+	OutageReset          bool          `yaml:"outage-reset" mapstructure:"outage-reset"`
+
+	// ScaleGiven and LogIntervalGiven tell whether the scale and log interval
+	// were set rather than left at their defaults. The entity count and
+	// interval a custom schema declares only stand in for the ones that were not.
+	ScaleGiven       bool `yaml:"-" mapstructure:"-"`
+	LogIntervalGiven bool `yaml:"-" mapstructure:"-"`
+}
+
+// Disorder returns the disorder to inject into the simulated data.
+func (c *DataGeneratorConfig) Disorder() DisorderConfig {
+	return DisorderConfig{
+		LateRate:      c.LateRate,
+		MaxLateness:   c.MaxLateness,
+		DuplicateRate: c.DuplicateRate,
+		MissingRate:   c.MissingRate,
+	}
+}
+
+// Shape returns the seasonality, trend and anomalies to lay over the simulated values.
+func (c *DataGeneratorConfig) Shape() ShapeConfig {
+	return ShapeConfig{
+		SeasonalityAmplitude: c.SeasonalityAmplitude,
+		SeasonalityPeriod:    c.SeasonalityPeriod,
+		Trend:                c.Trend,
+		StepRate:             c.StepRate,
+		StepSize:             c.StepSize,
+		SpikeRate:            c.SpikeRate,
+		SpikeMagnitude:       c.SpikeMagnitude,
+		SpikeDuration:        c.SpikeDuration,
+		FlatLineRate:         c.FlatLineRate,
+		FlatLineDuration:     c.FlatLineDuration,
+	}
+}
+
+// Timing returns how to move and truncate the simulated timestamps. The
+// timestamp precision must have been validated already.
+func (c *DataGeneratorConfig) Timing() TimingConfig {
+	precision := time.Nanosecond
+	if c.TimestampPrecision != "" {
+		precision, _ = ParseTimestampPrecision(c.TimestampPrecision)
+	}
+	return TimingConfig{
+		Precision:      precision,
+		MaxPhaseOffset: c.MaxPhaseOffset,
+		Jitter:         c.Jitter,
+	}
+}
+
+// Sparse returns how to leave fields out of the simulated points.
+func (c *DataGeneratorConfig) Sparse() SparseConfig {
+	mode := c.SparseMode
+	if mode == "" {
+		mode = defaultSparseMode
+	}
+	return SparseConfig{
+		Rate: c.SparseRate,
+		Mode: mode,
+	}
+}
+
+// Outage returns how the simulated hosts go silent.
+func (c *DataGeneratorConfig) Outage() OutageConfig {
+	return OutageConfig{
+		HostRate:       c.OutageRate,
+		DatacenterRate: c.DatacenterOutageRate,
+		MaxDuration:    c.OutageDuration,
+		Reset:          c.OutageReset,
+	}
 }
 
 // Validate checks that the values of the DataGeneratorConfig are reasonable.
@@ -59,13 +181,142 @@ func (c *DataGeneratorConfig) Validate() error {
 
 	err = utils.ValidateGroups(c.InterleavedGroupID, c.InterleavedNumGroups)
 
-	if c.Use == UseCaseDevopsGeneric && c.MaxMetricCountPerHost < 1 {
+	uses, useErr := c.uses()
+	if useErr != nil {
+		return useErr
+	}
+
+	if utils.IsIn(UseCaseDevopsGeneric, uses) && c.MaxMetricCountPerHost < 1 {
 		return fmt.Errorf(errMaxMetricCountValue)
 	}
 
+	if utils.IsIn(UseCaseCustom, uses) && c.CustomSchema == "" {
+		return fmt.Errorf(errCustomSchemaMissing)
+	}
+
+	if c.PodMeanLifetime < 0 {
+		return fmt.Errorf(errPodLifetimeNegative)
+	}
+
+	rates := []struct {
+		name  string
+		value float64
+	}{
+		{"late rate", c.LateRate},
+		{"duplicate rate", c.DuplicateRate},
+		{"missing rate", c.MissingRate},
+		{"seasonality amplitude", c.SeasonalityAmplitude},
+		{"step rate", c.StepRate},
+		{"step size", c.StepSize},
+		{"spike rate", c.SpikeRate},
+		{"flat-line rate", c.FlatLineRate},
+		{"sparse rate", c.SparseRate},
+		{"outage rate", c.OutageRate},
+		{"datacenter outage rate", c.DatacenterOutageRate},
+	}
+	for _, r := range rates {
+		if r.value < 0 || r.value > 1 {
+			return fmt.Errorf(errRateOutOfRangeFmt, r.name, r.value)
+		}
+	}
+
+	if c.LateRate > 0 && c.MaxLateness <= 0 {
+		return fmt.Errorf(errMaxLatenessNotSet)
+	}
+
+	if err := c.validateShape(uses); err != nil {
+		return err
+	}
+
+	if c.Outage().Enabled() {
+		for _, use := range uses {
+			if !utils.IsIn(use, hostUseCases) {
+				return fmt.Errorf(errOutageUnsupportedFmt, use)
+			}
+		}
+		if c.OutageDuration <= 0 {
+			return fmt.Errorf(errOutageDuration)
+		}
+	}
+
+	if c.TimestampPrecision != "" {
+		if err := ValidateTimestampPrecision(c.TimestampPrecision, c.Format); err != nil {
+			return err
+		}
+	}
+	if c.MaxPhaseOffset < 0 {
+		return fmt.Errorf(errTimingNegativeFmt, "max phase offset")
+	}
+	if c.Jitter < 0 {
+		return fmt.Errorf(errTimingNegativeFmt, "jitter")
+	}
+
+	if c.SparseMode != "" {
+		if err := ValidateSparseMode(c.SparseMode); err != nil {
+			return err
+		}
+	}
+
 	return err
 }
 
+// uses returns the names of the use cases to simulate: the ones listed in
+// UseCases for the multi use case, or just Use.
+func (c *DataGeneratorConfig) uses() ([]string, error) {
+	if c.Use != UseCaseMulti {
+		if c.UseCases != "" {
+			return nil, fmt.Errorf(errUseCasesNotMulti)
+		}
+		return []string{c.Use}, nil
+	}
+	if c.UseCases == "" {
+		return nil, fmt.Errorf(errUseCasesMissing)
+	}
+	cases, err := ParseUseCases(c.UseCases, c.Scale)
+	if err != nil {
+		return nil, err
+	}
+	uses := make([]string, len(cases))
+	for i, uc := range cases {
+		uses[i] = uc.Use
+	}
+	return uses, nil
+}
+
+func (c *DataGeneratorConfig) validateShape(uses []string) error {
+	if !c.Shape().Enabled() {
+		return nil
+	}
+	for _, use := range uses {
+		switch use {
+		case UseCaseDevops, UseCaseCPUOnly, UseCaseCPUSingle, UseCaseIoT:
+		default:
+			return fmt.Errorf(errShapeUnsupportedFmt, use)
+		}
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"seasonality period", c.SeasonalityPeriod},
+		{"spike duration", c.SpikeDuration},
+		{"flat-line duration", c.FlatLineDuration},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			return fmt.Errorf(errShapeNegativeFmt, d.name)
+		}
+	}
+	if c.SpikeMagnitude < 0 {
+		return fmt.Errorf(errShapeNegativeFmt, "spike magnitude")
+	}
+	if c.SeasonalityAmplitude > 0 && c.SeasonalityPeriod == 0 {
+		return fmt.Errorf(errSeasonalityPeriod)
+	}
+	return nil
+}
+
 func (c *DataGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	c.BaseConfig.AddToFlagSet(fs)
 	fs.Uint64("max-data-points", 0, "Limit the number of data points to generate, 0 = no limit")
@@ -77,6 +328,37 @@ func (c *DataGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Uint("interleaved-generation-groups", 1,
 		"The number of round-robin serialization groups. Use this to scale up data generation to multiple processes.")
 	fs.Uint64("max-metric-count", 100, "Max number of metric fields to generate per host. Used only in devops-generic use-case")
+	fs.Duration("pod-mean-lifetime", defaultPodMeanLifetime, "Mean time a pod lives before it is replaced, 0 disables churn. Used only in k8s use-case")
+	fs.String("custom-schema", "", "Path to the YAML schema describing entities, tags and measurements. Used only in custom use-case")
+	fs.Float64("late-rate", 0, "Fraction (0-1) of points delivered late, after newer points")
+	fs.Duration("max-lateness", defaultMaxLateness, "Maximum time a late point is delivered behind its timestamp")
+	fs.Float64("duplicate-rate", 0, "Fraction (0-1) of points delivered twice")
+	fs.Float64("missing-rate", 0, "Fraction (0-1) of points dropped")
+
+	fs.Float64("seasonality-amplitude", 0, "Fraction (0-1) by which values swing either way over a seasonal cycle. Used only in devops, cpu-only, cpu-single and iot use-cases")
+	fs.Duration("seasonality-period", defaultSeasonalityPeriod, "Length of a seasonal cycle, which is lowest at the start of each period")
+	fs.Float64("trend", 0, "Fraction by which values grow each day, negative to shrink")
+	fs.Float64("step-rate", 0, "Chance (0-1) per reading of an abrupt change of level")
+	fs.Float64("step-size", defaultStepSize, "Maximum fraction (0-1) by which a level change moves values either way")
+	fs.Float64("spike-rate", 0, "Chance (0-1) per reading of a spike starting")
+	fs.Float64("spike-magnitude", defaultSpikeMagnitude, "Fraction by which values increase during a spike")
+	fs.Duration("spike-duration", defaultSpikeDuration, "How long a spike lasts")
+	fs.Float64("flat-line-rate", 0, "Chance (0-1) per reading of values getting stuck")
+	fs.Duration("flat-line-duration", defaultFlatLineDuration, "How long values stay stuck")
+
+	fs.String("timestamp-precision", "", "Precision timestamps are truncated to, the same for every format. Valid values: s, ms, us, ns, but no finer than the format writes (ms for prometheus, victoriametrics and elasticsearch, s for graphite). Empty for the precision of the format")
+	fs.Duration("max-phase-offset", 0, "Maximum fixed offset from the log interval that each entity reports at, e.g. each host in devops")
+	fs.Duration("jitter", 0, "Maximum random shift of each timestamp, either way")
+
+	fs.Float64("sparse-rate", 0, "Fraction (0-1) of fields left out of each point; at least one field is always kept")
+	fs.String("sparse-mode", defaultSparseMode, "How fields are left out. Valid values: null (written as missing values), absent (removed from the point)")
+
+	fs.Float64("outage-rate", 0, "Chance (0-1) per log interval of each host going silent. Used only in devops, cpu-only, cpu-single, devops-generic and devops-status use-cases")
+	fs.Float64("datacenter-outage-rate", 0, "Chance (0-1) per log interval of each datacenter going silent along with all its hosts")
+	fs.Duration("outage-duration", defaultOutageDuration, "Maximum time an outage lasts")
+	fs.Bool("outage-reset", false, "Whether hosts come back from an outage as if rebooted, with their counters starting over")
+
+	fs.String("use-cases", "", "Comma separated use cases to combine, each with an optional scale, e.g. devops=100,iot=50; use cases without one get -scale. Used only in multi use-case")
 }
 
 const defaultTimeStart = "2016-01-01T00:00:00Z"
@@ -87,6 +369,8 @@ const errBadFormatFmt = "invalid format specified: '%v'"
 
 const errBadUseFmt = "invalid use case specified: '%v'"
 
+const errBadCompressionFmt = "invalid compression specified: '%v'"
+
 // GeneratorConfig is an interface that defines a configuration that is used
 // by Generators to govern their behavior. The interface methods provide a way
 // to use the GeneratorConfig with the command-line via flag.FlagSet and
@@ -113,6 +397,8 @@ type BaseConfig struct {
 	Seed  int64
 	Debug int    `yaml:"debug,omitempty" mapstructure:"debug,omitempty"`
 	File  string `yaml:"file,omitempty" mapstructure:"file,omitempty"`
+	// Compression of the output; empty means by the extension of File
+	Compression string `yaml:"compression,omitempty" mapstructure:"compression,omitempty"`
 }
 
 func (c *BaseConfig) AddToFlagSet(fs *pflag.FlagSet) {
@@ -127,6 +413,7 @@ func (c *BaseConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Int64("seed", 0, "PRNG seed (default: 0, which uses the current timestamp)")
 	fs.Int("debug", 0, "Control level of debug output")
 	fs.String("file", "", "Write the output to this path")
+	fs.String("compression", "", fmt.Sprintf("Compress the output. (choices: %s; default: by the extension of --file, .gz or .zst)", strings.Join(compression.Choices, ", ")))
 }
 
 func (c *BaseConfig) Validate() error {
@@ -146,6 +433,10 @@ func (c *BaseConfig) Validate() error {
 		return fmt.Errorf(errBadUseFmt, c.Use)
 	}
 
+	if c.Compression != "" && !utils.IsIn(c.Compression, compression.Choices) {
+		return fmt.Errorf(errBadCompressionFmt, c.Compression)
+	}
+
 	return nil
 }
 
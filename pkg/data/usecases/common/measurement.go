@@ -37,6 +37,20 @@ func (m *SubsystemMeasurement) Tick(d time.Duration) {
 	}
 }
 
+// ApplyShape lays the shape described by c over the distributions at the given
+// indices, or over all of them if no indices are given, keeping their values
+// within [min, max].
+func (m *SubsystemMeasurement) ApplyShape(c ShapeConfig, interval time.Duration, min, max float64, indices ...int) {
+	if len(indices) == 0 {
+		for i := range m.Distributions {
+			indices = append(indices, i)
+		}
+	}
+	for _, i := range indices {
+		m.Distributions[i] = CD(c.Wrap(m.Distributions[i], m.Timestamp, interval), min, max)
+	}
+}
+
 // ToPoint fills the provided serialize.Point with measurements from the SubsystemMeasurement.
 func (m *SubsystemMeasurement) ToPoint(p *data.Point, measurementName []byte, labels []LabeledDistributionMaker) {
 	p.SetMeasurementName(measurementName)
@@ -65,3 +79,22 @@ type LabeledDistributionMaker struct {
 	Label             []byte
 	DistributionMaker func() Distribution
 }
+
+// Labels returns the labels of the makers, which are the field keys of the
+// measurements made from them, without making any distribution.
+func Labels(makers []LabeledDistributionMaker) []string {
+	labels := make([]string, len(makers))
+	for i, m := range makers {
+		labels[i] = string(m.Label)
+	}
+	return labels
+}
+
+// KeysToStrings converts field keys to strings.
+func KeysToStrings(keys [][]byte) []string {
+	ret := make([]string, len(keys))
+	for i, k := range keys {
+		ret[i] = string(k)
+	}
+	return ret
+}
@@ -0,0 +1,345 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const (
+	errUseCaseFormatFmt      = "invalid use case '%s'; expected <use-case>[=<scale>]"
+	errUseCaseScaleFmt       = "invalid scale of use case '%s': %v"
+	errUseCaseNotInMultiFmt  = "use case '%s' cannot be combined with others"
+	errUseCaseRepeatedFmt    = "use case '%s' is listed more than once"
+	errUseCasesShareHostsFmt = "use cases '%s' and '%s' simulate the same hosts and cannot be combined"
+	errMeasurementClashFmt   = "use cases simulate measurement '%s' with different fields"
+)
+
+// hostUseCases are the use cases that simulate the same hosts, host_0 and up,
+// so combining two of them would report each host twice.
+var hostUseCases = []string{
+	UseCaseDevops,
+	UseCaseCPUOnly,
+	UseCaseCPUSingle,
+	UseCaseDevopsGeneric,
+	UseCaseDevopsStatus,
+}
+
+// UseCaseScale is one of the use cases combined by UseCaseMulti, along with
+// its own scale.
+type UseCaseScale struct {
+	Use   string
+	Scale uint64
+	// ScaleGiven tells whether the scale was listed with the use case
+	ScaleGiven bool
+}
+
+// ParseUseCases parses the comma separated list of use cases combined by
+// UseCaseMulti, each optionally followed by =<scale>, e.g. devops=100,iot=50.
+// Use cases without a scale get defaultScale.
+func ParseUseCases(s string, defaultScale uint64) ([]UseCaseScale, error) {
+	var ret []UseCaseScale
+	var hostUseCase string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		parts := strings.SplitN(item, "=", 2)
+		uc := UseCaseScale{Use: parts[0], Scale: defaultScale}
+		if uc.Use == "" {
+			return nil, fmt.Errorf(errUseCaseFormatFmt, item)
+		}
+		if len(parts) == 2 {
+			scale, err := strconv.ParseUint(parts[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf(errUseCaseScaleFmt, uc.Use, err)
+			}
+			uc.Scale = scale
+			uc.ScaleGiven = true
+		}
+		if uc.Scale == 0 {
+			return nil, fmt.Errorf(errUseCaseScaleFmt, uc.Use, ErrScaleIsZero)
+		}
+
+		if !utils.IsIn(uc.Use, UseCaseChoices) {
+			return nil, fmt.Errorf(errBadUseFmt, uc.Use)
+		}
+		if uc.Use == UseCaseMulti {
+			return nil, fmt.Errorf(errUseCaseNotInMultiFmt, uc.Use)
+		}
+		if seen[uc.Use] {
+			return nil, fmt.Errorf(errUseCaseRepeatedFmt, uc.Use)
+		}
+		seen[uc.Use] = true
+		if utils.IsIn(uc.Use, hostUseCases) {
+			if hostUseCase != "" {
+				return nil, fmt.Errorf(errUseCasesShareHostsFmt, hostUseCase, uc.Use)
+			}
+			hostUseCase = uc.Use
+		}
+		ret = append(ret, uc)
+	}
+	return ret, nil
+}
+
+// MultiSimulatorCase is one of the simulators combined by a MultiSimulator.
+type MultiSimulatorCase struct {
+	// Use is the name of the use case
+	Use string
+	// Config creates the simulator of the use case
+	Config SimulatorConfig
+	// Fields are the fields of the simulator, known without creating it
+	Fields map[string][]string
+}
+
+// MultiSimulatorConfig is used to create a MultiSimulator.
+type MultiSimulatorConfig struct {
+	Cases []MultiSimulatorCase
+}
+
+// Validate checks that the cases can be combined: a measurement that more than
+// one of them simulates must have the same fields in each. It checks the
+// fields of the cases without creating their simulators, which would consume
+// random numbers and so change the data generated afterwards.
+func (c *MultiSimulatorConfig) Validate() error {
+	fields := make(map[string][]string)
+	for _, mc := range c.Cases {
+		for measurement, keys := range mc.Fields {
+			if existing, ok := fields[measurement]; ok {
+				if strings.Join(existing, ",") != strings.Join(keys, ",") {
+					return fmt.Errorf(errMeasurementClashFmt, measurement)
+				}
+				continue
+			}
+			fields[measurement] = keys
+		}
+	}
+	return nil
+}
+
+// NewSimulator produces a MultiSimulator that combines the simulators of the
+// cases, each running over the given interval unless its config overrides it,
+// like a custom schema declaring its own interval. The limit applies to the
+// combined points. It panics if the cases do not pass Validate.
+func (c *MultiSimulatorConfig) NewSimulator(interval time.Duration, limit uint64) Simulator {
+	sim, err := c.newSimulator(interval, limit)
+	if err != nil {
+		panic(err.Error())
+	}
+	return sim
+}
+
+func (c *MultiSimulatorConfig) newSimulator(interval time.Duration, limit uint64) (*MultiSimulator, error) {
+	sim := &MultiSimulator{
+		limit:   limit,
+		tagKeys: make(map[string]int),
+		headers: &GeneratedDataHeaders{FieldKeys: make(map[string][]string)},
+	}
+	for _, mc := range c.Cases {
+		// each simulator runs to its end, the combined limit is enforced here
+		sim.addCase(mc.Config.NewSimulator(interval, 0))
+	}
+	if err := sim.mergeFields(); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
+// multiCase is a simulator combined by a MultiSimulator, along with the point
+// it made next.
+type multiCase struct {
+	sim Simulator
+
+	pending *data.Point
+	ts      time.Time
+	done    bool
+
+	// tagIndexes maps the tags of the simulator to their position among the
+	// tags of the combined simulator
+	tagIndexes []int
+}
+
+// fill makes the next point of the simulator pending, or marks the case done
+// if it has no more points.
+func (c *multiCase) fill() {
+	for !c.sim.Finished() {
+		c.pending.Reset()
+		if c.sim.Next(c.pending) {
+			// measurements reuse their timestamp between ticks, so keep a copy
+			c.ts = *c.pending.Timestamp()
+			return
+		}
+	}
+	c.done = true
+}
+
+// MultiSimulator combines the simulators of several use cases into one stream
+// of points, ordered by timestamp, as if the use cases ran on one database.
+// Use cases that deliver some points out of order themselves, like iot, still
+// do so.
+// Points of every use case carry the tags of all of them, in the same order;
+// the tags of the other use cases are nil. Tags a point has beyond the tags of
+// its simulator, like the measurement-specific ones, follow after them.
+type MultiSimulator struct {
+	cases  []*multiCase
+	primed bool
+
+	limit      uint64
+	madePoints uint64
+
+	// tagKeys maps the tag keys of the combined simulator to their position
+	tagKeys    map[string]int
+	tagKeyList [][]byte
+	tagValues  []interface{}
+	ts         time.Time
+
+	headers *GeneratedDataHeaders
+}
+
+func (s *MultiSimulator) addCase(sim Simulator) {
+	c := &multiCase{sim: sim, pending: data.NewPoint()}
+	types := sim.TagTypes()
+	for i, key := range sim.TagKeys() {
+		idx, ok := s.tagKeys[key]
+		if !ok {
+			idx = len(s.tagKeyList)
+			s.tagKeys[key] = idx
+			s.tagKeyList = append(s.tagKeyList, []byte(key))
+			s.headers.TagKeys = append(s.headers.TagKeys, key)
+			s.headers.TagTypes = append(s.headers.TagTypes, types[i])
+		}
+		c.tagIndexes = append(c.tagIndexes, idx)
+	}
+	s.cases = append(s.cases, c)
+}
+
+// mergeFields combines the fields of the simulators. A measurement that more
+// than one simulator makes must have the same fields in each.
+func (s *MultiSimulator) mergeFields() error {
+	h := s.headers
+
+	types := make(map[string][]string)
+	typed := false
+	for _, c := range s.cases {
+		ch := c.sim.Headers()
+		for measurement, fields := range ch.FieldKeys {
+			if existing, ok := h.FieldKeys[measurement]; ok {
+				if strings.Join(existing, ",") != strings.Join(fields, ",") {
+					return fmt.Errorf(errMeasurementClashFmt, measurement)
+				}
+				continue
+			}
+			h.FieldKeys[measurement] = fields
+			types[measurement] = ch.FieldTypes[measurement]
+			typed = typed || ch.FieldTypes[measurement] != nil
+		}
+	}
+	if typed {
+		h.FieldTypes = types
+	}
+	return nil
+}
+
+// Finished tells whether the limit of points was reached or every simulator
+// is done.
+func (s *MultiSimulator) Finished() bool {
+	if s.limit > 0 && s.madePoints >= s.limit {
+		return true
+	}
+	s.prime()
+	for _, c := range s.cases {
+		if !c.done {
+			return false
+		}
+	}
+	return true
+}
+
+// prime makes the first point of every simulator pending.
+func (s *MultiSimulator) prime() {
+	if s.primed {
+		return
+	}
+	for _, c := range s.cases {
+		c.fill()
+	}
+	s.primed = true
+}
+
+// Next populates p with the pending point that has the earliest timestamp,
+// taking the use cases in order on a tie, and makes the next point of its
+// simulator pending.
+func (s *MultiSimulator) Next(p *data.Point) bool {
+	s.prime()
+	var next *multiCase
+	for _, c := range s.cases {
+		if !c.done && (next == nil || c.ts.Before(next.ts)) {
+			next = c
+		}
+	}
+	if next == nil {
+		return false
+	}
+
+	s.toPoint(next, p)
+	next.fill()
+	s.madePoints++
+	return true
+}
+
+// toPoint copies the pending point of c into p, with the tags of the combined
+// simulator.
+func (s *MultiSimulator) toPoint(c *multiCase, p *data.Point) {
+	pending := c.pending
+	p.SetMeasurementName(pending.MeasurementName())
+
+	s.tagValues = s.tagValues[:0]
+	for range s.tagKeyList {
+		s.tagValues = append(s.tagValues, nil)
+	}
+	tagKeys := pending.TagKeys()
+	tagValues := pending.TagValues()
+	for i, idx := range c.tagIndexes {
+		if i < len(tagValues) {
+			s.tagValues[idx] = tagValues[i]
+		}
+	}
+	for i, key := range s.tagKeyList {
+		p.AppendTag(key, s.tagValues[i])
+	}
+	for i := len(c.tagIndexes); i < len(tagKeys); i++ {
+		p.AppendTag(tagKeys[i], tagValues[i])
+	}
+
+	fieldValues := pending.FieldValues()
+	for i, key := range pending.FieldKeys() {
+		p.AppendField(key, fieldValues[i])
+	}
+	s.ts = c.ts
+	p.SetTimestamp(&s.ts)
+}
+
+// Fields returns the fields of all the simulators.
+func (s *MultiSimulator) Fields() map[string][]string {
+	return s.headers.FieldKeys
+}
+
+// TagKeys returns the tag keys of all the simulators, in the order they are
+// first seen.
+func (s *MultiSimulator) TagKeys() []string {
+	return s.headers.TagKeys
+}
+
+// TagTypes returns the types of the tags in TagKeys.
+func (s *MultiSimulator) TagTypes() []string {
+	return s.headers.TagTypes
+}
+
+// Headers returns the combined headers of the simulators.
+func (s *MultiSimulator) Headers() *GeneratedDataHeaders {
+	h := *s.headers
+	return &h
+}
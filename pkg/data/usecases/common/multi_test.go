@@ -0,0 +1,236 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// taggedSimulator emits count points step apart with the given tags, followed
+// by an extra measurement-specific tag.
+type taggedSimulator struct {
+	measurement string
+	tagKeys     []string
+	step        time.Duration
+	count       int
+
+	made int
+	ts   time.Time
+}
+
+func (s *taggedSimulator) Finished() bool { return s.made >= s.count }
+
+func (s *taggedSimulator) Next(p *data.Point) bool {
+	s.ts = time.Unix(0, 0).Add(time.Duration(s.made) * s.step)
+	p.SetMeasurementName([]byte(s.measurement))
+	for _, key := range s.tagKeys {
+		p.AppendTag([]byte(key), s.measurement+"_"+key)
+	}
+	p.AppendTag([]byte("extra"), "x")
+	p.AppendField([]byte("value"), int64(s.made))
+	p.SetTimestamp(&s.ts)
+	s.made++
+	return true
+}
+
+func (s *taggedSimulator) Fields() map[string][]string {
+	return map[string][]string{s.measurement: {"value"}}
+}
+
+func (s *taggedSimulator) TagKeys() []string { return s.tagKeys }
+
+func (s *taggedSimulator) TagTypes() []string {
+	types := make([]string, len(s.tagKeys))
+	for i := range types {
+		types[i] = "string"
+	}
+	return types
+}
+
+func (s *taggedSimulator) Headers() *GeneratedDataHeaders {
+	return &GeneratedDataHeaders{
+		TagTypes:  s.TagTypes(),
+		TagKeys:   s.TagKeys(),
+		FieldKeys: s.Fields(),
+	}
+}
+
+// fixedSimulatorConfig creates the given simulator, ignoring the interval and
+// limit.
+type fixedSimulatorConfig struct {
+	sim Simulator
+}
+
+func (c *fixedSimulatorConfig) NewSimulator(time.Duration, uint64) Simulator {
+	return c.sim
+}
+
+func newTestMultiSimulator(limit uint64, sims ...Simulator) Simulator {
+	c := &MultiSimulatorConfig{}
+	for _, sim := range sims {
+		c.Cases = append(c.Cases, MultiSimulatorCase{Config: &fixedSimulatorConfig{sim}})
+	}
+	return c.NewSimulator(time.Second, limit)
+}
+
+func TestParseUseCases(t *testing.T) {
+	cases := []struct {
+		desc      string
+		input     string
+		want      []UseCaseScale
+		shouldErr bool
+	}{
+		{
+			desc:  "scales",
+			input: "devops=100,iot=50",
+			want:  []UseCaseScale{{UseCaseDevops, 100, true}, {UseCaseIoT, 50, true}},
+		},
+		{
+			desc:  "default scale",
+			input: "k8s, finance=3",
+			want:  []UseCaseScale{{UseCaseK8s, 7, false}, {UseCaseFinance, 3, true}},
+		},
+		{desc: "unknown use case", input: "devops,bogus", shouldErr: true},
+		{desc: "empty use case", input: "devops,", shouldErr: true},
+		{desc: "bad scale", input: "devops=many", shouldErr: true},
+		{desc: "zero scale", input: "devops=0", shouldErr: true},
+		{desc: "multi", input: "iot,multi", shouldErr: true},
+		{desc: "repeated", input: "iot=1,iot=2", shouldErr: true},
+		{desc: "same hosts", input: "cpu-only,iot,devops-generic", shouldErr: true},
+	}
+	for _, c := range cases {
+		got, err := ParseUseCases(c.input, 7)
+		if c.shouldErr {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: incorrect use cases: got %v want %v", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestMultiSimulatorHeaders(t *testing.T) {
+	sim := newTestMultiSimulator(0,
+		&taggedSimulator{measurement: "a", tagKeys: []string{"host", "region"}},
+		&taggedSimulator{measurement: "b", tagKeys: []string{"truck", "region"}},
+	)
+	h := sim.Headers()
+	if got, want := h.TagKeys, []string{"host", "region", "truck"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect tag keys: got %v want %v", got, want)
+	}
+	if got, want := h.TagTypes, []string{"string", "string", "string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect tag types: got %v want %v", got, want)
+	}
+	if got, want := h.FieldKeys, map[string][]string{"a": {"value"}, "b": {"value"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect field keys: got %v want %v", got, want)
+	}
+	if h.FieldTypes != nil {
+		t.Errorf("field types should be nil for numbers: got %v", h.FieldTypes)
+	}
+
+	// wrappers set their config on the headers, which must not leak back
+	h.Sparse = &SparseConfig{Rate: 0.5}
+	if sim.Headers().Sparse != nil {
+		t.Errorf("headers are shared between calls")
+	}
+}
+
+func TestMultiSimulatorMeasurementClash(t *testing.T) {
+	a := &taggedSimulator{measurement: "a", tagKeys: []string{"host"}}
+	b := &taggedSimulator{measurement: "a", tagKeys: []string{"host"}}
+	// the same fields are fine
+	c := &MultiSimulatorConfig{Cases: []MultiSimulatorCase{
+		{Config: &fixedSimulatorConfig{a}, Fields: a.Fields()},
+		{Config: &fixedSimulatorConfig{b}, Fields: b.Fields()},
+	}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error for measurement with the same fields: %v", err)
+	}
+
+	c.Cases[1].Fields = map[string][]string{"a": {"value", "other"}}
+	if err := c.Validate(); err == nil {
+		t.Errorf("unexpected lack of error for measurement with different fields")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("unexpected lack of panic for measurement with different fields")
+		}
+	}()
+	newTestMultiSimulator(0, a, &clashingSimulator{b})
+}
+
+// clashingSimulator reports an extra field for the measurement of the wrapped
+// taggedSimulator.
+type clashingSimulator struct {
+	*taggedSimulator
+}
+
+func (s *clashingSimulator) Headers() *GeneratedDataHeaders {
+	h := s.taggedSimulator.Headers()
+	h.FieldKeys[s.measurement] = append(h.FieldKeys[s.measurement], "other")
+	return h
+}
+
+func TestMultiSimulatorNext(t *testing.T) {
+	sim := newTestMultiSimulator(0,
+		&taggedSimulator{measurement: "a", tagKeys: []string{"host", "region"}, step: 2 * time.Second, count: 3},
+		&taggedSimulator{measurement: "b", tagKeys: []string{"truck", "region"}, step: 3 * time.Second, count: 3},
+	)
+	type point struct {
+		measurement string
+		seconds     int64
+		tags        []interface{}
+	}
+	aTags := []interface{}{"a_host", "a_region", nil, "x"}
+	bTags := []interface{}{nil, "b_region", "b_truck", "x"}
+	want := []point{
+		{"a", 0, aTags},
+		{"b", 0, bTags},
+		{"a", 2, aTags},
+		{"b", 3, bTags},
+		{"a", 4, aTags},
+		{"b", 6, bTags},
+	}
+
+	var got []point
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			tags := append([]interface{}{}, p.TagValues()...)
+			got = append(got, point{string(p.MeasurementName()), p.Timestamp().Unix(), tags})
+			if key := string(p.TagKeys()[3]); key != "extra" {
+				t.Errorf("extra tag not kept last: got %s", key)
+			}
+		}
+		p.Reset()
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect points:\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestMultiSimulatorLimit(t *testing.T) {
+	sim := newTestMultiSimulator(5, newOrderedSimulator(10), newOrderedSimulator(10))
+	seqs, times := drain(sim)
+	if got := len(seqs); got != 5 {
+		t.Fatalf("incorrect number of points: got %d want 5", got)
+	}
+	// the cases take turns, as their timestamps tie
+	if got, want := seqs, []int64{0, 0, 1, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect points: got %v want %v", got, want)
+	}
+	for i := 1; i < len(times); i++ {
+		if times[i].Before(times[i-1]) {
+			t.Errorf("point %d out of order: %v before %v", i, times[i], times[i-1])
+		}
+	}
+}
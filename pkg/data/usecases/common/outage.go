@@ -0,0 +1,32 @@
+package common
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// OutageConfig describes how the hosts of a simulated use case go silent for
+// a while. Rates are chances per epoch, i.e. per log interval.
+type OutageConfig struct {
+	// HostRate is the chance of each host going silent
+	HostRate float64
+	// DatacenterRate is the chance of each datacenter going silent, along with all its hosts
+	DatacenterRate float64
+	// MaxDuration is the upper bound of how long an outage lasts
+	MaxDuration time.Duration
+	// Reset restarts the measurements of a host when it comes back, so its counters start over
+	Reset bool
+}
+
+// Enabled tells whether any host goes silent.
+func (c OutageConfig) Enabled() bool {
+	return c.HostRate > 0 || c.DatacenterRate > 0
+}
+
+// Epochs draws how many epochs of the given interval an outage lasts: at
+// least one, and up to MaxDuration.
+func (c OutageConfig) Epochs(interval time.Duration) uint64 {
+	max := int64(math.Max(1, float64(c.MaxDuration/interval)))
+	return uint64(rand.Int63n(max)) + 1
+}
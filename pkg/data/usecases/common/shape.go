@@ -0,0 +1,97 @@
+package common
+
+import (
+	"math"
+	"time"
+)
+
+const trendPeriod = 24 * time.Hour
+
+// ShapeConfig describes the seasonality, trend and anomalies to lay over the
+// random values of simulated measurements. Rates are chances per reading.
+type ShapeConfig struct {
+	// SeasonalityAmplitude is the fraction by which values swing either way over a SeasonalityPeriod
+	SeasonalityAmplitude float64
+	// SeasonalityPeriod is the length of a seasonal cycle, which is lowest at the start of each period
+	SeasonalityPeriod time.Duration
+	// Trend is the fraction by which values grow each day, negative to shrink
+	Trend float64
+	// StepRate is the chance of an abrupt change of level
+	StepRate float64
+	// StepSize is the maximum fraction by which a level change moves values either way
+	StepSize float64
+	// SpikeRate is the chance of a spike starting
+	SpikeRate float64
+	// SpikeMagnitude is the fraction by which values increase during a spike
+	SpikeMagnitude float64
+	// SpikeDuration is how long a spike lasts
+	SpikeDuration time.Duration
+	// FlatLineRate is the chance of values getting stuck
+	FlatLineRate float64
+	// FlatLineDuration is how long values stay stuck
+	FlatLineDuration time.Duration
+}
+
+// Enabled tells whether any shape is laid over the values.
+func (c ShapeConfig) Enabled() bool {
+	return c.SeasonalityAmplitude > 0 || c.Trend != 0 || c.StepRate > 0 || c.SpikeRate > 0 || c.FlatLineRate > 0
+}
+
+// Wrap lays the shape over the given distribution of a measurement that
+// starts at start and advances every interval.
+//
+// Monotonic distributions are counters, so the shape is laid over their
+// increments, which keeps them monotonic. Float precision wrappers stay the
+// outermost distribution so the precision of the values is kept.
+func (c ShapeConfig) Wrap(d Distribution, start time.Time, interval time.Duration) Distribution {
+	switch t := d.(type) {
+	case *FloatPrecision:
+		t.step = c.Wrap(t.step, start, interval)
+		return t
+	case *MonotonicRandomWalkDistribution:
+		t.Step = c.Wrap(t.Step, start, interval)
+		return t
+	}
+
+	steps := func(dur time.Duration) int {
+		return int(math.Max(1, float64(dur/interval)))
+	}
+
+	if c.Trend != 0 {
+		d = TD(d, c.Trend*float64(interval)/float64(trendPeriod))
+	}
+	if c.SeasonalityAmplitude > 0 {
+		offset := int(start.Sub(start.Truncate(c.SeasonalityPeriod)) / interval)
+		d = SD(d, c.SeasonalityAmplitude, steps(c.SeasonalityPeriod), offset)
+	}
+	if c.StepRate > 0 {
+		d = SCD(d, c.StepRate, c.StepSize)
+	}
+	if c.SpikeRate > 0 {
+		d = SPD(d, c.SpikeRate, c.SpikeMagnitude, steps(c.SpikeDuration))
+	}
+	if c.FlatLineRate > 0 {
+		d = FLD(d, c.FlatLineRate, steps(c.FlatLineDuration))
+	}
+	return d
+}
+
+// ShapedMeasurement is a SimulatedMeasurement that opts in to having a shape
+// laid over its values.
+type ShapedMeasurement interface {
+	SimulatedMeasurement
+	Shape(c ShapeConfig, interval time.Duration)
+}
+
+// ShapeMeasurements lays the shape over those of the given measurements that
+// opt in to it. It does nothing if the shape is not enabled.
+func ShapeMeasurements(measurements []SimulatedMeasurement, c ShapeConfig, interval time.Duration) {
+	if !c.Enabled() {
+		return
+	}
+	for _, m := range measurements {
+		if sm, ok := m.(ShapedMeasurement); ok {
+			sm.Shape(c, interval)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestShapeConfigEnabled(t *testing.T) {
+	cases := []struct {
+		desc string
+		c    ShapeConfig
+		want bool
+	}{
+		{desc: "zero value", want: false},
+		{desc: "durations only", c: ShapeConfig{SeasonalityPeriod: time.Hour, SpikeDuration: time.Minute, StepSize: 0.2}, want: false},
+		{desc: "seasonality", c: ShapeConfig{SeasonalityAmplitude: 0.1}, want: true},
+		{desc: "negative trend", c: ShapeConfig{Trend: -0.1}, want: true},
+		{desc: "steps", c: ShapeConfig{StepRate: 0.1}, want: true},
+		{desc: "spikes", c: ShapeConfig{SpikeRate: 0.1}, want: true},
+		{desc: "flat-lines", c: ShapeConfig{FlatLineRate: 0.1}, want: true},
+	}
+	for _, c := range cases {
+		if got := c.c.Enabled(); got != c.want {
+			t.Errorf("%s: got %v want %v", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestShapeConfigWrap(t *testing.T) {
+	start := time.Date(2016, 1, 1, 6, 0, 0, 0, time.UTC)
+	c := ShapeConfig{
+		SeasonalityAmplitude: 0.5,
+		SeasonalityPeriod:    24 * time.Hour,
+		Trend:                0.1,
+		StepRate:             0.1,
+		SpikeRate:            0.1,
+		SpikeDuration:        time.Second,
+		FlatLineRate:         0.1,
+		FlatLineDuration:     time.Minute,
+	}
+	d := c.Wrap(&ConstantDistribution{State: 10}, start, time.Hour)
+
+	fld, ok := d.(*FlatLineDistribution)
+	if !ok {
+		t.Fatalf("flat-line is not the outermost shape: %T", d)
+	}
+	if fld.Duration != 1 {
+		t.Errorf("durations shorter than the interval should last a step: got %d", fld.Duration)
+	}
+	spd := fld.Base.(*SpikeDistribution)
+	scd := spd.Base.(*StepChangeDistribution)
+	sd := scd.Base.(*SeasonalDistribution)
+	if sd.Period != 24 || sd.Offset != 6 {
+		t.Errorf("seasonality not aligned to the time of day: got period %d offset %d", sd.Period, sd.Offset)
+	}
+	td := sd.Base.(*TrendDistribution)
+	if want := 0.1 / 24; math.Abs(td.Slope-want) > 1e-12 {
+		t.Errorf("incorrect trend slope per step: got %f want %f", td.Slope, want)
+	}
+}
+
+func TestShapeConfigWrapKeepsOuterDistributions(t *testing.T) {
+	c := ShapeConfig{SeasonalityAmplitude: 0.5, SeasonalityPeriod: time.Hour}
+
+	fp := FP(&ConstantDistribution{State: 1}, 1)
+	if got := c.Wrap(fp, time.Time{}, time.Minute); got != fp {
+		t.Errorf("float precision is not the outermost distribution: %T", got)
+	}
+	if _, ok := fp.step.(*SeasonalDistribution); !ok {
+		t.Errorf("shape not laid inside float precision: %T", fp.step)
+	}
+
+	mwd := MWD(&ConstantDistribution{State: 1}, 0)
+	if got := c.Wrap(mwd, time.Time{}, time.Minute); got != mwd {
+		t.Errorf("monotonic distribution is not the outermost distribution: %T", got)
+	}
+	prev := mwd.Get()
+	for i := 0; i < 120; i++ {
+		mwd.Advance()
+		got := mwd.Get()
+		if got < prev {
+			t.Fatalf("shaped counter decreased from %f to %f", prev, got)
+		}
+		prev = got
+	}
+}
+
+type shapedMeasurement struct {
+	*SubsystemMeasurement
+	shaped bool
+}
+
+func (m *shapedMeasurement) ToPoint(p *data.Point) {}
+
+func (m *shapedMeasurement) Shape(c ShapeConfig, interval time.Duration) {
+	m.shaped = true
+	m.ApplyShape(c, interval, 0, 15, 1)
+}
+
+func TestShapeMeasurements(t *testing.T) {
+	newMeasurement := func() *shapedMeasurement {
+		sm := NewSubsystemMeasurement(time.Time{}, 2)
+		sm.Distributions[0] = &ConstantDistribution{State: 10}
+		sm.Distributions[1] = &ConstantDistribution{State: 10}
+		return &shapedMeasurement{SubsystemMeasurement: sm}
+	}
+	c := ShapeConfig{SeasonalityAmplitude: 1, SeasonalityPeriod: 4 * time.Second}
+
+	m := newMeasurement()
+	ShapeMeasurements([]SimulatedMeasurement{m}, ShapeConfig{}, time.Second)
+	if m.shaped {
+		t.Errorf("measurement shaped with a disabled shape")
+	}
+
+	ShapeMeasurements([]SimulatedMeasurement{m}, c, time.Second)
+	if !m.shaped {
+		t.Fatalf("measurement not shaped")
+	}
+	if _, ok := m.Distributions[0].(*ConstantDistribution); !ok {
+		t.Errorf("distribution not in indices was shaped: %T", m.Distributions[0])
+	}
+	for i, want := range []float64{0, 10, 15, 10} {
+		if got := m.Distributions[1].Get(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("step %d: got %f want %f", i, got, want)
+		}
+		m.Tick(time.Second)
+	}
+}
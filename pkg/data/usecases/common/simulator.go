@@ -23,6 +23,8 @@ type BaseSimulatorConfig struct {
 	GeneratorScale uint64
 	// GeneratorConstructor is the function used to create a new Generator given an id number and start time
 	GeneratorConstructor func(i int, start time.Time) Generator
+	// Shape is laid over the values of the measurements that opt in to it
+	Shape ShapeConfig
 }
 
 func calculateEpochs(duration time.Duration, interval time.Duration) uint64 {
@@ -34,6 +36,7 @@ func (sc *BaseSimulatorConfig) NewSimulator(interval time.Duration, limit uint64
 	generators := make([]Generator, sc.GeneratorScale)
 	for i := 0; i < len(generators); i++ {
 		generators[i] = sc.GeneratorConstructor(i, sc.Start)
+		ShapeMeasurements(generators[i].Measurements(), sc.Shape, interval)
 	}
 
 	epochs := calculateEpochs(sc.End.Sub(sc.Start), interval)
@@ -67,6 +70,17 @@ type GeneratedDataHeaders struct {
 	TagTypes  []string
 	TagKeys   []string
 	FieldKeys map[string][]string
+	// FieldTypes holds the type of each field in FieldKeys, nil if they are all
+	// numbers that can be stored as floats
+	FieldTypes map[string][]string
+	// Disorder is the disorder injected into the data, nil if none was
+	Disorder *DisorderConfig
+	// DisorderCounts is the number of points disordered, nil if not known
+	DisorderCounts *DisorderCounts
+	// Timing is how the timestamps were moved and truncated, nil if they were not
+	Timing *TimingConfig
+	// Sparse is how fields were left out of the data, nil if none were
+	Sparse *SparseConfig
 }
 
 // Simulator simulates a use case.
@@ -190,9 +204,10 @@ func (s *BaseSimulator) TagTypes() []string {
 
 func (s *BaseSimulator) Headers() *GeneratedDataHeaders {
 	return &GeneratedDataHeaders{
-		TagTypes:  s.TagTypes(),
-		TagKeys:   s.TagKeys(),
-		FieldKeys: s.Fields(),
+		TagTypes:   s.TagTypes(),
+		TagKeys:    s.TagKeys(),
+		FieldKeys:  s.Fields(),
+		FieldTypes: FieldTypes(s.generators[0].Measurements()),
 	}
 }
 
@@ -0,0 +1,128 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const (
+	// SparseModeNull keeps left out fields in the point with a nil value
+	SparseModeNull = "null"
+	// SparseModeAbsent removes left out fields from the point
+	SparseModeAbsent = "absent"
+
+	errUnknownSparseModeFmt = "unknown sparse mode '%s'; choose from: null, absent"
+)
+
+// SparseConfig describes how fields are left out of the points of a Simulator.
+type SparseConfig struct {
+	// Rate is the fraction of fields left out of each point
+	Rate float64
+	// Mode is how a field is left out, either SparseModeNull or SparseModeAbsent
+	Mode string
+}
+
+// Enabled tells whether any fields are left out.
+func (c *SparseConfig) Enabled() bool {
+	return c.Rate > 0
+}
+
+// String formats the config as the comma separated key=value pairs that are
+// reported in the header of the generated data.
+func (c *SparseConfig) String() string {
+	return fmt.Sprintf("rate=%g,mode=%s", c.Rate, c.Mode)
+}
+
+// ValidateSparseMode checks that mode is one of the supported sparse modes.
+func ValidateSparseMode(mode string) error {
+	switch mode {
+	case SparseModeNull, SparseModeAbsent:
+		return nil
+	}
+	return fmt.Errorf(errUnknownSparseModeFmt, mode)
+}
+
+// SparseSimulator wraps any Simulator and leaves each field of its points out
+// with a chance of Rate, either by setting its value to nil or by removing it
+// from the point. At least one field of every point is kept, so no point ends
+// up without values.
+type SparseSimulator struct {
+	base   Simulator
+	config SparseConfig
+
+	// left contains the keys of the fields left out of the current point
+	left [][]byte
+}
+
+// NewSparseSimulator wraps base so fields are left out according to c.
+func NewSparseSimulator(base Simulator, c SparseConfig) *SparseSimulator {
+	return &SparseSimulator{
+		base:   base,
+		config: c,
+	}
+}
+
+// Finished tells whether the wrapped simulator is done.
+func (s *SparseSimulator) Finished() bool {
+	return s.base.Finished()
+}
+
+// Next populates p with the next point of the wrapped simulator and leaves
+// some of its fields out.
+func (s *SparseSimulator) Next(p *data.Point) bool {
+	write := s.base.Next(p)
+	if !write {
+		return write
+	}
+
+	s.left = s.left[:0]
+	kept := -1
+	keys := p.FieldKeys()
+	for i, key := range keys {
+		if rand.Float64() < s.config.Rate {
+			s.left = append(s.left, key)
+		} else if kept < 0 {
+			kept = i
+		}
+	}
+	if kept < 0 && len(s.left) > 0 {
+		// every field was drawn, so keep a random one
+		i := rand.Intn(len(s.left))
+		s.left = append(s.left[:i], s.left[i+1:]...)
+	}
+
+	for _, key := range s.left {
+		if s.config.Mode == SparseModeAbsent {
+			p.RemoveField(key)
+		} else {
+			p.ClearFieldValue(key)
+		}
+	}
+	return write
+}
+
+// Fields returns the fields of the wrapped simulator.
+func (s *SparseSimulator) Fields() map[string][]string {
+	return s.base.Fields()
+}
+
+// TagKeys returns the tag keys of the wrapped simulator.
+func (s *SparseSimulator) TagKeys() []string {
+	return s.base.TagKeys()
+}
+
+// TagTypes returns the tag types of the wrapped simulator.
+func (s *SparseSimulator) TagTypes() []string {
+	return s.base.TagTypes()
+}
+
+// Headers returns the headers of the wrapped simulator, along with how fields
+// are left out of its points.
+func (s *SparseSimulator) Headers() *GeneratedDataHeaders {
+	h := s.base.Headers()
+	c := s.config
+	h.Sparse = &c
+	return h
+}
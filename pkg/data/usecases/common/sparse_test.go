@@ -0,0 +1,104 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// wideSimulator is an orderedSimulator whose points have width extra fields.
+type wideSimulator struct {
+	*orderedSimulator
+	width int
+}
+
+func (s *wideSimulator) Next(p *data.Point) bool {
+	write := s.orderedSimulator.Next(p)
+	for i := 0; i < s.width; i++ {
+		p.AppendField([]byte(fmt.Sprintf("f%d", i)), float64(i))
+	}
+	return write
+}
+
+// drainFields runs sim to completion and returns the number of fields and of
+// non-nil field values of each written point.
+func drainFields(sim Simulator) (fields, values []int) {
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			present := 0
+			for _, v := range p.FieldValues() {
+				if v != nil {
+					present++
+				}
+			}
+			fields = append(fields, len(p.FieldKeys()))
+			values = append(values, present)
+		}
+		p.Reset()
+	}
+	return fields, values
+}
+
+func TestSparseConfig(t *testing.T) {
+	c := &SparseConfig{Mode: SparseModeNull}
+	if c.Enabled() {
+		t.Errorf("config without rate should not be enabled")
+	}
+	c.Rate = 0.25
+	if !c.Enabled() {
+		t.Errorf("config with rate should be enabled")
+	}
+	if got, want := c.String(), "rate=0.25,mode=null"; got != want {
+		t.Errorf("incorrect string: got %s want %s", got, want)
+	}
+
+	for _, mode := range []string{SparseModeNull, SparseModeAbsent} {
+		if err := ValidateSparseMode(mode); err != nil {
+			t.Errorf("unexpected error for mode %s: %v", mode, err)
+		}
+	}
+	if err := ValidateSparseMode("zero"); err == nil {
+		t.Errorf("unexpected lack of error for unknown mode")
+	}
+}
+
+func TestSparseSimulatorNull(t *testing.T) {
+	base := &wideSimulator{orderedSimulator: newOrderedSimulator(1000), width: 9}
+	fields, values := drainFields(NewSparseSimulator(base, SparseConfig{Rate: 0.5, Mode: SparseModeNull}))
+	if len(fields) != 1000 {
+		t.Fatalf("incorrect number of points: got %d want %d", len(fields), 1000)
+	}
+	total := 0
+	for i := range fields {
+		if fields[i] != 10 {
+			t.Fatalf("point %d: null mode should keep every field: got %d", i, fields[i])
+		}
+		if values[i] < 1 {
+			t.Fatalf("point %d: no field values kept", i)
+		}
+		total += values[i]
+	}
+	if total < 4500 || total > 5500 {
+		t.Errorf("incorrect number of kept values for a rate of 0.5: got %d of %d", total, 10000)
+	}
+}
+
+func TestSparseSimulatorAbsent(t *testing.T) {
+	base := &wideSimulator{orderedSimulator: newOrderedSimulator(100), width: 3}
+	fields, values := drainFields(NewSparseSimulator(base, SparseConfig{Rate: 1, Mode: SparseModeAbsent}))
+	for i := range fields {
+		if fields[i] != 1 || values[i] != 1 {
+			t.Errorf("point %d: exactly one field should be kept: got %d fields, %d values", i, fields[i], values[i])
+		}
+	}
+}
+
+func TestSparseSimulatorHeaders(t *testing.T) {
+	c := SparseConfig{Rate: 0.1, Mode: SparseModeAbsent}
+	h := NewSparseSimulator(newOrderedSimulator(1), c).Headers()
+	if h.Sparse == nil || *h.Sparse != c {
+		t.Errorf("incorrect sparse header: got %v want %v", h.Sparse, c)
+	}
+}
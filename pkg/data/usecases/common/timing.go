@@ -0,0 +1,193 @@
+package common
+
+import (
+	"fmt"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"math/rand"
+	"time"
+)
+
+const (
+	errUnknownPrecisionFmt = "unknown timestamp precision '%s'; choose from: s, ms, us, ns"
+	errPrecisionTooFineFmt = "timestamp precision '%s' is finer than format '%s' writes; choose '%s' or coarser"
+	defaultFormatPrecision = "ns"
+)
+
+// TimestampPrecisions maps the names of the supported timestamp precisions to
+// their durations.
+var TimestampPrecisions = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// ParseTimestampPrecision returns the duration of the named timestamp precision.
+func ParseTimestampPrecision(name string) (time.Duration, error) {
+	precision, ok := TimestampPrecisions[name]
+	if !ok {
+		return 0, fmt.Errorf(errUnknownPrecisionFmt, name)
+	}
+	return precision, nil
+}
+
+// formatPrecisions is the precision of the timestamps written by the formats
+// that don't write nanoseconds.
+var formatPrecisions = map[string]string{
+	constants.FormatPrometheus:      "ms",
+	constants.FormatVictoriaMetrics: "ms",
+	constants.FormatElasticsearch:   "ms",
+	constants.FormatGraphite:        "s",
+}
+
+// FormatTimestampPrecision returns the name of the finest timestamp precision
+// the format can write.
+func FormatTimestampPrecision(format string) string {
+	if precision, ok := formatPrecisions[format]; ok {
+		return precision
+	}
+	return defaultFormatPrecision
+}
+
+// ValidateTimestampPrecision checks that the named timestamp precision is
+// known and can be written by the format.
+func ValidateTimestampPrecision(name, format string) error {
+	precision, err := ParseTimestampPrecision(name)
+	if err != nil {
+		return err
+	}
+	finest := FormatTimestampPrecision(format)
+	if precision < TimestampPrecisions[finest] {
+		return fmt.Errorf(errPrecisionTooFineFmt, name, format, finest)
+	}
+	return nil
+}
+
+// TimingConfig describes how the timestamps of a Simulator are moved off the
+// log interval and truncated before they are written.
+type TimingConfig struct {
+	// Precision is the unit timestamps are truncated to
+	Precision time.Duration
+	// MaxPhaseOffset is the upper bound of the fixed offset each entity reports at
+	MaxPhaseOffset time.Duration
+	// Jitter is the upper bound of the random shift of each timestamp, either way
+	Jitter time.Duration
+}
+
+// Enabled tells whether timestamps are changed at all.
+func (c *TimingConfig) Enabled() bool {
+	return c.Precision > time.Nanosecond || c.MaxPhaseOffset > 0 || c.Jitter > 0
+}
+
+// String formats the config as the comma separated key=value pairs that are
+// reported in the header of the generated data.
+func (c *TimingConfig) String() string {
+	return fmt.Sprintf("precision=%s,max-phase-offset=%s,jitter=%s", c.Precision, c.MaxPhaseOffset, c.Jitter)
+}
+
+// TimingSimulator wraps any Simulator and moves the timestamps of its points.
+// Each entity, told apart by the values of the simulator's tags, reports at a random but fixed
+// phase offset of up to MaxPhaseOffset, and each timestamp is shifted by a
+// random jitter of up to Jitter either way. The result is then truncated to
+// Precision, so every serializer writes the same timestamps.
+type TimingSimulator struct {
+	base   Simulator
+	config TimingConfig
+
+	// entityTags is the number of leading tags of a point that tell its entity
+	// apart from the measurement-specific tags that may follow
+	entityTags int
+	offsets    map[string]time.Duration
+	key        []byte
+}
+
+// NewTimingSimulator wraps base so its timestamps are moved according to c.
+func NewTimingSimulator(base Simulator, c TimingConfig) *TimingSimulator {
+	return &TimingSimulator{
+		base:       base,
+		config:     c,
+		entityTags: len(base.TagKeys()),
+		offsets:    make(map[string]time.Duration),
+	}
+}
+
+// Finished tells whether the wrapped simulator is done.
+func (s *TimingSimulator) Finished() bool {
+	return s.base.Finished()
+}
+
+// Next populates p with the next point of the wrapped simulator and moves its
+// timestamp.
+func (s *TimingSimulator) Next(p *data.Point) bool {
+	write := s.base.Next(p)
+	if p.Timestamp() == nil {
+		return write
+	}
+
+	ts := *p.Timestamp()
+	if s.config.MaxPhaseOffset > 0 {
+		ts = ts.Add(s.offset(p))
+	}
+	if s.config.Jitter > 0 {
+		ts = ts.Add(time.Duration(rand.Int63n(2*int64(s.config.Jitter)+1)) - s.config.Jitter)
+	}
+	if s.config.Precision > time.Nanosecond {
+		ts = ts.Truncate(s.config.Precision)
+	}
+	// measurements reuse their timestamp between ticks, so never change it in place
+	p.SetTimestamp(&ts)
+	return write
+}
+
+// offset returns the phase offset of the entity p belongs to, drawing it the
+// first time the entity is seen.
+func (s *TimingSimulator) offset(p *data.Point) time.Duration {
+	s.key = s.key[:0]
+	values := p.TagValues()
+	if len(values) > s.entityTags {
+		values = values[:s.entityTags]
+	}
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			s.key = append(s.key, v...)
+		case []byte:
+			s.key = append(s.key, v...)
+		default:
+			s.key = append(s.key, fmt.Sprint(v)...)
+		}
+		s.key = append(s.key, 0)
+	}
+
+	offset, ok := s.offsets[string(s.key)]
+	if !ok {
+		offset = time.Duration(rand.Int63n(int64(s.config.MaxPhaseOffset)))
+		s.offsets[string(s.key)] = offset
+	}
+	return offset
+}
+
+// Fields returns the fields of the wrapped simulator.
+func (s *TimingSimulator) Fields() map[string][]string {
+	return s.base.Fields()
+}
+
+// TagKeys returns the tag keys of the wrapped simulator.
+func (s *TimingSimulator) TagKeys() []string {
+	return s.base.TagKeys()
+}
+
+// TagTypes returns the tag types of the wrapped simulator.
+func (s *TimingSimulator) TagTypes() []string {
+	return s.base.TagTypes()
+}
+
+// Headers returns the headers of the wrapped simulator, along with the timing
+// of its timestamps.
+func (s *TimingSimulator) Headers() *GeneratedDataHeaders {
+	h := s.base.Headers()
+	c := s.config
+	h.Timing = &c
+	return h
+}
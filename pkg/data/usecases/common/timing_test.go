@@ -0,0 +1,151 @@
+package common
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"testing"
+	"time"
+)
+
+func TestParseTimestampPrecision(t *testing.T) {
+	for name, want := range TimestampPrecisions {
+		got, err := ParseTimestampPrecision(name)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", name, err)
+		} else if got != want {
+			t.Errorf("incorrect precision for %s: got %s want %s", name, got, want)
+		}
+	}
+
+	_, err := ParseTimestampPrecision("m")
+	if err == nil {
+		t.Errorf("unexpected lack of error for unknown precision")
+	} else if got, want := err.Error(), "unknown timestamp precision 'm'; choose from: s, ms, us, ns"; got != want {
+		t.Errorf("incorrect error: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestValidateTimestampPrecision(t *testing.T) {
+	cases := []struct {
+		precision string
+		format    string
+		wantErr   bool
+	}{
+		{precision: "ns", format: constants.FormatInflux},
+		{precision: "us", format: constants.FormatTimescaleDB},
+		{precision: "ms", format: constants.FormatPrometheus},
+		{precision: "us", format: constants.FormatPrometheus, wantErr: true},
+		{precision: "ns", format: constants.FormatVictoriaMetrics, wantErr: true},
+		{precision: "s", format: constants.FormatElasticsearch},
+		{precision: "us", format: constants.FormatElasticsearch, wantErr: true},
+		{precision: "s", format: constants.FormatGraphite},
+		{precision: "ms", format: constants.FormatGraphite, wantErr: true},
+		{precision: "m", format: constants.FormatInflux, wantErr: true},
+	}
+	for _, c := range cases {
+		err := ValidateTimestampPrecision(c.precision, c.format)
+		if c.wantErr && err == nil {
+			t.Errorf("%s for %s: unexpected lack of error", c.precision, c.format)
+		} else if !c.wantErr && err != nil {
+			t.Errorf("%s for %s: unexpected error: %v", c.precision, c.format, err)
+		}
+	}
+}
+
+func TestTimingConfigEnabled(t *testing.T) {
+	c := &TimingConfig{Precision: time.Nanosecond}
+	if c.Enabled() {
+		t.Errorf("config with nanosecond precision only should not be enabled")
+	}
+	c.Precision = time.Millisecond
+	if !c.Enabled() {
+		t.Errorf("config with millisecond precision should be enabled")
+	}
+	c = &TimingConfig{Jitter: time.Second}
+	if !c.Enabled() {
+		t.Errorf("config with jitter should be enabled")
+	}
+}
+
+func TestTimingSimulatorPrecision(t *testing.T) {
+	base := newOrderedSimulator(10)
+	base.start = time.Unix(0, 1999999999)
+	_, times := drain(NewTimingSimulator(base, TimingConfig{Precision: time.Second}))
+	for i, ts := range times {
+		if want := time.Unix(int64(i+1), 0); !ts.Equal(want) {
+			t.Errorf("point %d: got %s want %s", i, ts, want)
+		}
+	}
+	if want := time.Unix(10, 999999999); !base.ts.Equal(want) {
+		t.Errorf("timestamp of the wrapped simulator changed in place: got %s want %s", base.ts, want)
+	}
+}
+
+func TestTimingSimulatorPhaseOffset(t *testing.T) {
+	base := newOrderedSimulator(300)
+	base.entities = 3
+	_, times := drain(NewTimingSimulator(base, TimingConfig{MaxPhaseOffset: time.Second}))
+
+	offsets := make(map[int]time.Duration)
+	for i, ts := range times {
+		offset := ts.Sub(time.Unix(int64(i), 0))
+		if offset < 0 || offset >= time.Second {
+			t.Fatalf("point %d: offset %s out of range", i, offset)
+		}
+		entity := i % base.entities
+		if want, ok := offsets[entity]; !ok {
+			offsets[entity] = offset
+		} else if offset != want {
+			t.Fatalf("point %d: entity %d changed offset from %s to %s", i, entity, want, offset)
+		}
+	}
+	if offsets[0] == offsets[1] && offsets[1] == offsets[2] {
+		t.Errorf("all entities got the same offset %s", offsets[0])
+	}
+}
+
+func TestTimingSimulatorJitter(t *testing.T) {
+	_, times := drain(NewTimingSimulator(newOrderedSimulator(1000), TimingConfig{Jitter: 100 * time.Millisecond}))
+	moved := 0
+	for i, ts := range times {
+		jitter := ts.Sub(time.Unix(int64(i), 0))
+		if jitter < -100*time.Millisecond || jitter > 100*time.Millisecond {
+			t.Fatalf("point %d: jitter %s out of range", i, jitter)
+		}
+		if jitter != 0 {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Errorf("no point was moved")
+	}
+}
+
+func TestTimingSimulatorPassesThroughUnwritten(t *testing.T) {
+	s := NewTimingSimulator(&unwrittenSimulator{orderedSimulator: newOrderedSimulator(1)}, TimingConfig{Jitter: time.Second})
+	p := data.NewPoint()
+	if s.Next(p) {
+		t.Errorf("point the wrapped simulator did not write was written")
+	}
+}
+
+// unwrittenSimulator is an orderedSimulator whose points should never be written.
+type unwrittenSimulator struct {
+	*orderedSimulator
+}
+
+func (s *unwrittenSimulator) Next(p *data.Point) bool {
+	s.orderedSimulator.Next(p)
+	return false
+}
+
+func TestTimingSimulatorHeaders(t *testing.T) {
+	c := TimingConfig{Precision: time.Millisecond, Jitter: time.Second}
+	h := NewTimingSimulator(newOrderedSimulator(1), c).Headers()
+	if h.Timing == nil || *h.Timing != c {
+		t.Errorf("incorrect timing in headers: got %v want %v", h.Timing, c)
+	}
+	if got, want := h.Timing.String(), "precision=1ms,max-phase-offset=0s,jitter=1s"; got != want {
+		t.Errorf("incorrect timing string: got %s want %s", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package custom
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// Entity is a single simulated item of a custom use case. It fulfills the
+// common.Generator interface.
+type Entity struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+}
+
+// TickAll advances all Distributions of an Entity.
+func (e *Entity) TickAll(d time.Duration) {
+	for i := range e.simulatedMeasurements {
+		e.simulatedMeasurements[i].Tick(d)
+	}
+}
+
+// Measurements returns the entity measurements.
+func (e *Entity) Measurements() []common.SimulatedMeasurement {
+	return e.simulatedMeasurements
+}
+
+// Tags returns the entity tags.
+func (e *Entity) Tags() []common.Tag {
+	return e.tags
+}
+
+// NewEntity creates the i-th entity of the schema. Tag values are picked at
+// random from each tag's value pool.
+func (s *Schema) NewEntity(i int, start time.Time) common.Generator {
+	tags := make([]common.Tag, 0, len(s.Tags)+1)
+	tags = append(tags, common.Tag{Key: []byte(s.Entities.Tag), Value: fmt.Sprintf(s.Entities.Format, i)})
+	for _, t := range s.Tags {
+		tags = append(tags, common.Tag{Key: []byte(t.Key), Value: common.RandomStringSliceChoice(t.Values)})
+	}
+
+	sm := make([]common.SimulatedMeasurement, len(s.Measurements))
+	for j := range s.Measurements {
+		sm[j] = newMeasurement(start, &s.Measurements[j])
+	}
+
+	return &Entity{
+		simulatedMeasurements: sm,
+		tags:                  tags,
+	}
+}
+
+// measurement simulates one measurement declared in the schema.
+type measurement struct {
+	*common.SubsystemMeasurement
+	name   []byte
+	fields []FieldSpec
+	labels [][]byte
+}
+
+func newMeasurement(start time.Time, spec *MeasurementSpec) *measurement {
+	sub := common.NewSubsystemMeasurement(start, len(spec.Fields))
+	labels := make([][]byte, len(spec.Fields))
+	for i := range spec.Fields {
+		sub.Distributions[i] = spec.Fields[i].Distribution.newDistribution()
+		labels[i] = []byte(spec.Fields[i].Name)
+	}
+	return &measurement{
+		SubsystemMeasurement: sub,
+		name:                 []byte(spec.Name),
+		fields:               spec.Fields,
+		labels:               labels,
+	}
+}
+
+// ToPoint serializes the measurement to the supplied point.
+func (m *measurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(m.name)
+	p.SetTimestamp(&m.Timestamp)
+
+	for i, d := range m.Distributions {
+		if m.fields[i].Int {
+			p.AppendField(m.labels[i], int64(d.Get()))
+		} else {
+			p.AppendField(m.labels[i], d.Get())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package custom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func TestNewEntity(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now()
+	e := s.NewEntity(3, start).(*Entity)
+
+	if got := len(e.Measurements()); got != 2 {
+		t.Errorf("incorrect measurement count: got %d want %d", got, 2)
+	}
+	tags := e.Tags()
+	if got := len(tags); got != 2 {
+		t.Fatalf("incorrect tag count: got %d want %d", got, 2)
+	}
+	if string(tags[0].Key) != "sensor_id" || tags[0].Value != "sensor_3" {
+		t.Errorf("incorrect entity tag: got %s=%v", tags[0].Key, tags[0].Value)
+	}
+	if v := tags[1].Value; v != "berlin" && v != "oslo" {
+		t.Errorf("tag value not from pool: %v", v)
+	}
+
+	e.TickAll(time.Second)
+	for _, m := range e.Measurements() {
+		if got := m.(*measurement).Timestamp; got != start.Add(time.Second) {
+			t.Errorf("incorrect timestamp after tick: got %v want %v", got, start.Add(time.Second))
+		}
+	}
+}
+
+func TestMeasurementToPoint(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := s.NewEntity(0, time.Now())
+
+	p := data.NewPoint()
+	e.Measurements()[0].ToPoint(p)
+	if got := string(p.MeasurementName()); got != "environment" {
+		t.Errorf("incorrect measurement name: got %s want %s", got, "environment")
+	}
+	if got := len(p.FieldKeys()); got != 2 {
+		t.Errorf("incorrect field count: got %d want %d", got, 2)
+	}
+	if _, ok := p.GetFieldValue([]byte("temperature")).(float64); !ok {
+		t.Errorf("temperature should be a float64 field")
+	}
+
+	p.Reset()
+	e.Measurements()[1].ToPoint(p)
+	if got, ok := p.GetFieldValue([]byte("battery_mv")).(int64); !ok || got != 4200 {
+		t.Errorf("incorrect battery_mv: got %v", p.GetFieldValue([]byte("battery_mv")))
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now()
+	sc := &SimulatorConfig{BaseSimulatorConfig: common.BaseSimulatorConfig{
+		Start:                start,
+		End:                  start.Add(time.Minute),
+		InitGeneratorScale:   2,
+		GeneratorScale:       2,
+		GeneratorConstructor: s.NewEntity,
+	}}
+	sim := sc.NewSimulator(30*time.Second, 0)
+
+	fields := sim.Fields()
+	if got := len(fields["environment"]); got != 2 {
+		t.Errorf("incorrect environment fields: got %v", fields["environment"])
+	}
+	if got := sim.TagKeys(); len(got) != 2 || got[0] != "sensor_id" || got[1] != "site" {
+		t.Errorf("incorrect tag keys: got %v", got)
+	}
+
+	// 2 epochs * 2 entities * 2 measurements
+	count := 0
+	p := data.NewPoint()
+	for !sim.Finished() {
+		sim.Next(p)
+		p.Reset()
+		count++
+	}
+	if count != 8 {
+		t.Errorf("incorrect point count: got %d want %d", count, 8)
+	}
+}
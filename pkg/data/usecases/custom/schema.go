@@ -0,0 +1,246 @@
+package custom
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"gopkg.in/yaml.v2"
+)
+
+// Distribution type names accepted in a schema file.
+const (
+	DistributionNormal        = "ND"
+	DistributionUniform       = "UD"
+	DistributionRandomWalk    = "WD"
+	DistributionClampedWalk   = "CWD"
+	DistributionMonotonicWalk = "MWD"
+	DistributionConstant      = "CD"
+	defaultEntityTagKey       = "name"
+	defaultEntityNameFmt      = "entity_%d"
+	errNoMeasurements         = "schema must declare at least one measurement"
+	errNoFields               = "measurement '%s' must declare at least one field"
+	errEmptyName              = "%s name cannot be empty"
+	errDuplicateName          = "duplicate %s name '%s'"
+	errNoTagValues            = "tag '%s' must declare at least one value"
+	errUnknownDistribution    = "unknown distribution type '%s' (choices: ND, UD, WD, CWD, MWD, CD)"
+	errMissingStep            = "distribution '%s' requires a step distribution"
+	errNegativeStdDev         = "distribution ND requires a non-negative stddev"
+	errBadUniformRange        = "distribution UD requires low < high"
+	errBadClampedRange        = "distribution CWD requires min < max"
+	errNegativeInterval       = "interval cannot be negative"
+	errCannotReadSchemaFmt    = "cannot read custom schema '%s': %v"
+	errCannotParseSchemaFmt   = "cannot parse custom schema: %v"
+	errInvalidFieldFmt        = "field '%s.%s': %v"
+)
+
+// Schema describes a custom use case: the entities being simulated, the tags
+// attached to each of them and the measurements they report.
+type Schema struct {
+	Entities     EntitiesSpec      `yaml:"entities"`
+	Interval     time.Duration     `yaml:"interval"`
+	Tags         []TagSpec         `yaml:"tags"`
+	Measurements []MeasurementSpec `yaml:"measurements"`
+}
+
+// EntitiesSpec describes how entities are named and how many of them there are.
+// Count is the number of entities unless --scale is given; a zero Count leaves
+// the entity count to the --scale flag.
+type EntitiesSpec struct {
+	Tag    string `yaml:"tag"`
+	Format string `yaml:"format"`
+	Count  uint64 `yaml:"count"`
+}
+
+// TagSpec is a tag key and the pool of values an entity picks its value from.
+type TagSpec struct {
+	Key    string   `yaml:"key"`
+	Values []string `yaml:"values"`
+}
+
+// MeasurementSpec is a named measurement and the fields it reports.
+type MeasurementSpec struct {
+	Name   string      `yaml:"name"`
+	Fields []FieldSpec `yaml:"fields"`
+}
+
+// FieldSpec is a single field of a measurement. Int fields are truncated to
+// int64 when written to a point.
+type FieldSpec struct {
+	Name         string           `yaml:"name"`
+	Int          bool             `yaml:"int"`
+	Distribution DistributionSpec `yaml:"distribution"`
+}
+
+// DistributionSpec declares one of the distributions from the common package
+// together with its parameters. Walk distributions (WD, CWD, MWD) take a nested
+// Step distribution; a missing State starts the walk at a random value (within
+// [Min, Max] for CWD).
+type DistributionSpec struct {
+	Type   string            `yaml:"type"`
+	Mean   float64           `yaml:"mean"`
+	StdDev float64           `yaml:"stddev"`
+	Low    float64           `yaml:"low"`
+	High   float64           `yaml:"high"`
+	Min    float64           `yaml:"min"`
+	Max    float64           `yaml:"max"`
+	State  *float64          `yaml:"state"`
+	Value  float64           `yaml:"value"`
+	Step   *DistributionSpec `yaml:"step"`
+}
+
+// Fields returns the field keys of the measurements of the schema.
+func (s *Schema) Fields() map[string][]string {
+	fields := make(map[string][]string, len(s.Measurements))
+	for _, m := range s.Measurements {
+		keys := make([]string, len(m.Fields))
+		for i, f := range m.Fields {
+			keys[i] = f.Name
+		}
+		fields[m.Name] = keys
+	}
+	return fields
+}
+
+// LoadSchema reads and validates the schema stored in the YAML file at path.
+func LoadSchema(path string) (*Schema, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errCannotReadSchemaFmt, path, err)
+	}
+	return ParseSchema(b)
+}
+
+// ParseSchema parses and validates a YAML encoded schema, filling in defaults
+// for the entity tag key and name format.
+func ParseSchema(b []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, fmt.Errorf(errCannotParseSchemaFmt, err)
+	}
+	if s.Entities.Tag == "" {
+		s.Entities.Tag = defaultEntityTagKey
+	}
+	if s.Entities.Format == "" {
+		s.Entities.Format = defaultEntityNameFmt
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks that the schema is complete and that every distribution
+// can be constructed.
+func (s *Schema) Validate() error {
+	if s.Interval < 0 {
+		return fmt.Errorf(errNegativeInterval)
+	}
+
+	tagKeys := map[string]bool{s.Entities.Tag: true}
+	for _, t := range s.Tags {
+		if t.Key == "" {
+			return fmt.Errorf(errEmptyName, "tag")
+		}
+		if tagKeys[t.Key] {
+			return fmt.Errorf(errDuplicateName, "tag", t.Key)
+		}
+		tagKeys[t.Key] = true
+		if len(t.Values) == 0 {
+			return fmt.Errorf(errNoTagValues, t.Key)
+		}
+	}
+
+	if len(s.Measurements) == 0 {
+		return fmt.Errorf(errNoMeasurements)
+	}
+	measurementNames := map[string]bool{}
+	for _, m := range s.Measurements {
+		if m.Name == "" {
+			return fmt.Errorf(errEmptyName, "measurement")
+		}
+		if measurementNames[m.Name] {
+			return fmt.Errorf(errDuplicateName, "measurement", m.Name)
+		}
+		measurementNames[m.Name] = true
+		if len(m.Fields) == 0 {
+			return fmt.Errorf(errNoFields, m.Name)
+		}
+
+		fieldNames := map[string]bool{}
+		for _, f := range m.Fields {
+			if f.Name == "" {
+				return fmt.Errorf(errEmptyName, "field")
+			}
+			if fieldNames[f.Name] {
+				return fmt.Errorf(errDuplicateName, "field", m.Name+"."+f.Name)
+			}
+			fieldNames[f.Name] = true
+			if err := f.Distribution.validate(); err != nil {
+				return fmt.Errorf(errInvalidFieldFmt, m.Name, f.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *DistributionSpec) validate() error {
+	switch d.Type {
+	case DistributionNormal:
+		if d.StdDev < 0 {
+			return fmt.Errorf(errNegativeStdDev)
+		}
+	case DistributionUniform:
+		if d.Low >= d.High {
+			return fmt.Errorf(errBadUniformRange)
+		}
+	case DistributionClampedWalk:
+		if d.Min >= d.Max {
+			return fmt.Errorf(errBadClampedRange)
+		}
+		fallthrough
+	case DistributionRandomWalk, DistributionMonotonicWalk:
+		if d.Step == nil {
+			return fmt.Errorf(errMissingStep, d.Type)
+		}
+		return d.Step.validate()
+	case DistributionConstant:
+	default:
+		return fmt.Errorf(errUnknownDistribution, d.Type)
+	}
+	return nil
+}
+
+// newDistribution creates a fresh distribution from the spec. Each entity gets
+// its own instances so stateful walks evolve independently. Stateless
+// distributions are advanced once so the first point already holds a sample.
+func (d *DistributionSpec) newDistribution() common.Distribution {
+	switch d.Type {
+	case DistributionNormal:
+		nd := common.ND(d.Mean, d.StdDev)
+		nd.Advance()
+		return nd
+	case DistributionUniform:
+		ud := common.UD(d.Low, d.High)
+		ud.Advance()
+		return ud
+	case DistributionRandomWalk:
+		return common.WD(d.Step.newDistribution(), d.initialState(rand.Float64()*100))
+	case DistributionClampedWalk:
+		return common.CWD(d.Step.newDistribution(), d.Min, d.Max, d.initialState(d.Min+rand.Float64()*(d.Max-d.Min)))
+	case DistributionMonotonicWalk:
+		return common.MWD(d.Step.newDistribution(), d.initialState(0))
+	case DistributionConstant:
+		return &common.ConstantDistribution{State: d.Value}
+	}
+	panic(fmt.Sprintf(errUnknownDistribution, d.Type))
+}
+
+func (d *DistributionSpec) initialState(fallback float64) float64 {
+	if d.State != nil {
+		return *d.State
+	}
+	return fallback
+}
@@ -0,0 +1,233 @@
+package custom
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const testSchema = `
+entities:
+  tag: sensor_id
+  format: "sensor_%d"
+  count: 10
+interval: 30s
+tags:
+  - key: site
+    values: [berlin, oslo]
+measurements:
+  - name: environment
+    fields:
+      - name: temperature
+        distribution:
+          type: CWD
+          step: {type: ND, mean: 0, stddev: 1}
+          min: -30
+          max: 50
+          state: 20
+      - name: pressure
+        distribution: {type: UD, low: 900, high: 1100}
+  - name: power
+    fields:
+      - name: battery_mv
+        int: true
+        distribution: {type: CD, value: 4200}
+`
+
+func TestParseSchema(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.Entities.Count; got != 10 {
+		t.Errorf("incorrect entity count: got %d want %d", got, 10)
+	}
+	if got := s.Interval; got != 30*time.Second {
+		t.Errorf("incorrect interval: got %v want %v", got, 30*time.Second)
+	}
+	if got := len(s.Measurements); got != 2 {
+		t.Fatalf("incorrect measurement count: got %d want %d", got, 2)
+	}
+	step := s.Measurements[0].Fields[0].Distribution.Step
+	if step == nil || step.Type != DistributionNormal || step.StdDev != 1 {
+		t.Errorf("incorrect step distribution: got %+v", step)
+	}
+	if !s.Measurements[1].Fields[0].Int {
+		t.Errorf("battery_mv should be an int field")
+	}
+}
+
+func TestParseSchemaDefaults(t *testing.T) {
+	s, err := ParseSchema([]byte(`
+measurements:
+  - name: m
+    fields:
+      - name: f
+        distribution: {type: ND, mean: 1, stddev: 0}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Entities.Tag != defaultEntityTagKey {
+		t.Errorf("incorrect default entity tag: got %s want %s", s.Entities.Tag, defaultEntityTagKey)
+	}
+	if s.Entities.Format != defaultEntityNameFmt {
+		t.Errorf("incorrect default entity format: got %s want %s", s.Entities.Format, defaultEntityNameFmt)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	cases := []struct {
+		desc   string
+		schema string
+		errMsg string
+	}{
+		{
+			desc:   "no measurements",
+			schema: "interval: 10s",
+			errMsg: errNoMeasurements,
+		},
+		{
+			desc:   "unknown key",
+			schema: "bogus: 1",
+			errMsg: "cannot parse custom schema",
+		},
+		{
+			desc:   "no fields",
+			schema: "measurements: [{name: m}]",
+			errMsg: "must declare at least one field",
+		},
+		{
+			desc:   "duplicate measurement",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: CD}}]}, {name: m, fields: [{name: f, distribution: {type: CD}}]}]",
+			errMsg: "duplicate measurement name 'm'",
+		},
+		{
+			desc:   "duplicate field",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: CD}}, {name: f, distribution: {type: CD}}]}]",
+			errMsg: "duplicate field name 'm.f'",
+		},
+		{
+			desc:   "tag clashes with entity tag",
+			schema: "tags: [{key: name, values: [a]}]\nmeasurements: [{name: m, fields: [{name: f, distribution: {type: CD}}]}]",
+			errMsg: "duplicate tag name 'name'",
+		},
+		{
+			desc:   "tag without values",
+			schema: "tags: [{key: site}]\nmeasurements: [{name: m, fields: [{name: f, distribution: {type: CD}}]}]",
+			errMsg: "tag 'site' must declare at least one value",
+		},
+		{
+			desc:   "unknown distribution",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: XD}}]}]",
+			errMsg: "unknown distribution type 'XD'",
+		},
+		{
+			desc:   "walk without step",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: WD}}]}]",
+			errMsg: "distribution 'WD' requires a step distribution",
+		},
+		{
+			desc:   "bad uniform range",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: UD, low: 2, high: 1}}]}]",
+			errMsg: errBadUniformRange,
+		},
+		{
+			desc:   "bad clamped range",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: CWD, min: 1, max: 1, step: {type: ND}}}]}]",
+			errMsg: errBadClampedRange,
+		},
+		{
+			desc:   "invalid nested step",
+			schema: "measurements: [{name: m, fields: [{name: f, distribution: {type: MWD, step: {type: ND, stddev: -1}}}]}]",
+			errMsg: errNegativeStdDev,
+		},
+	}
+
+	for _, c := range cases {
+		_, err := ParseSchema([]byte(c.schema))
+		if err == nil {
+			t.Errorf("%s: expected error, got none", c.desc)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.errMsg) {
+			t.Errorf("%s: incorrect error: got %q want it to contain %q", c.desc, err.Error(), c.errMsg)
+		}
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "custom-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "schema.yaml")
+	if err := ioutil.WriteFile(path, []byte(testSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSchema(path); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := LoadSchema(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+func TestNewDistribution(t *testing.T) {
+	state := 5.0
+	cases := []struct {
+		spec DistributionSpec
+		want interface{}
+	}{
+		{DistributionSpec{Type: DistributionNormal}, &common.NormalDistribution{}},
+		{DistributionSpec{Type: DistributionUniform, High: 1}, &common.UniformDistribution{}},
+		{DistributionSpec{Type: DistributionRandomWalk, Step: &DistributionSpec{Type: DistributionNormal}}, &common.RandomWalkDistribution{}},
+		{DistributionSpec{Type: DistributionClampedWalk, Max: 1, Step: &DistributionSpec{Type: DistributionNormal}}, &common.ClampedRandomWalkDistribution{}},
+		{DistributionSpec{Type: DistributionMonotonicWalk, State: &state, Step: &DistributionSpec{Type: DistributionNormal}}, &common.MonotonicRandomWalkDistribution{}},
+		{DistributionSpec{Type: DistributionConstant, Value: 3}, &common.ConstantDistribution{}},
+	}
+	for _, c := range cases {
+		d := c.spec.newDistribution()
+		switch c.want.(type) {
+		case *common.NormalDistribution:
+			_, ok := d.(*common.NormalDistribution)
+			checkOK(t, c.spec.Type, ok)
+		case *common.UniformDistribution:
+			_, ok := d.(*common.UniformDistribution)
+			checkOK(t, c.spec.Type, ok)
+		case *common.RandomWalkDistribution:
+			_, ok := d.(*common.RandomWalkDistribution)
+			checkOK(t, c.spec.Type, ok)
+		case *common.ClampedRandomWalkDistribution:
+			cwd, ok := d.(*common.ClampedRandomWalkDistribution)
+			checkOK(t, c.spec.Type, ok)
+			if ok && (cwd.State < 0 || cwd.State > 1) {
+				t.Errorf("CWD initial state out of range: %f", cwd.State)
+			}
+		case *common.MonotonicRandomWalkDistribution:
+			mwd, ok := d.(*common.MonotonicRandomWalkDistribution)
+			checkOK(t, c.spec.Type, ok)
+			if ok && mwd.State != state {
+				t.Errorf("MWD initial state: got %f want %f", mwd.State, state)
+			}
+		case *common.ConstantDistribution:
+			if got := d.Get(); got != 3 {
+				t.Errorf("CD value: got %f want %f", got, 3.0)
+			}
+		}
+	}
+}
+
+func checkOK(t *testing.T, typ string, ok bool) {
+	t.Helper()
+	if !ok {
+		t.Errorf("distribution %s created wrong type", typ)
+	}
+}
@@ -0,0 +1,26 @@
+package custom
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a custom use case Simulator.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	common.BaseSimulatorConfig
+	// Interval is the interval declared by the schema, which the simulator
+	// runs over instead of the one it is created with; 0 keeps that one
+	Interval time.Duration
+}
+
+// NewSimulator produces a Simulator with the given config over the specified
+// interval and points limit. Entities are generated by the schema, so every
+// serializer sees the same points as it would from a built-in use case.
+func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	if sc.Interval > 0 {
+		interval = sc.Interval
+	}
+	return sc.BaseSimulatorConfig.NewSimulator(interval, limit)
+}
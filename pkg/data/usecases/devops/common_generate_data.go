@@ -28,6 +28,10 @@ type commonDevopsSimulatorConfig struct {
 	HostConstructor func(ctx *HostContext) Host
 	// MaxMetricCount is the max number of metrics per host to create when using generic-devops use-case
 	MaxMetricCount uint64
+	// Shape is laid over the values of the measurements that opt in to it
+	Shape common.ShapeConfig
+	// Outage is how hosts go silent for a while
+	Outage common.OutageConfig
 }
 
 func NewHostCtx(id int, start time.Time) *HostContext {
@@ -57,6 +61,9 @@ type commonDevopsSimulator struct {
 	timestampStart time.Time
 	timestampEnd   time.Time
 	interval       time.Duration
+
+	// outages is nil if hosts never go silent
+	outages *hostOutages
 }
 
 // Finished tells whether we have simulated all the necessary points
@@ -89,9 +96,10 @@ func (s *commonDevopsSimulator) TagTypes() []string {
 
 func (d *commonDevopsSimulator) Headers() *common.GeneratedDataHeaders {
 	return &common.GeneratedDataHeaders{
-		TagTypes:  d.TagTypes(),
-		TagKeys:   d.TagKeys(),
-		FieldKeys: d.Fields(),
+		TagTypes:   d.TagTypes(),
+		TagKeys:    d.TagKeys(),
+		FieldKeys:  d.Fields(),
+		FieldTypes: common.FieldTypes(d.hosts[0].SimulatedMeasurements),
 	}
 }
 func (s *commonDevopsSimulator) fields(measurements []common.SimulatedMeasurement) map[string][]string {
@@ -128,12 +136,18 @@ func (s *commonDevopsSimulator) populatePoint(p *data.Point, measureIdx int) boo
 	// Populate measurement-specific tags and fields:
 	host.SimulatedMeasurements[measureIdx].ToPoint(p)
 
-	ret := s.hostIndex < s.epochHosts
+	ret := s.hostIndex < s.epochHosts && !s.outages.silent(s.hostIndex)
 	s.madePoints++
 	s.hostIndex++
 	return ret
 }
 
+// nextEpoch moves on to the next epoch, once all hosts have been ticked.
+func (s *commonDevopsSimulator) nextEpoch() {
+	s.adjustNumHostsForEpoch()
+	s.outages.advance(s.hosts, s.epoch, s.timestampStart.Add(time.Duration(s.epoch)*s.interval))
+}
+
 // TODO(rrk) - Can probably turn this logic into a separate interface and implement other
 // types of scale up, e.g., exponential
 //
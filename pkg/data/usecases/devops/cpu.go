@@ -48,3 +48,8 @@ func newCPUMeasurementNumDistributions(start time.Time, numDistributions int) *C
 func (m *CPUMeasurement) ToPoint(p *data.Point) {
 	m.ToPointAllInt64(p, labelCPU, cpuFields)
 }
+
+// Shape lays the shape over all cpu usage values, which stay percentages.
+func (m *CPUMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, 100)
+}
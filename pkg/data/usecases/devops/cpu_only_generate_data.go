@@ -35,7 +35,7 @@ func (d *CPUOnlySimulator) Next(p *data.Point) bool {
 			d.hosts[i].TickAll(d.interval)
 		}
 
-		d.adjustNumHostsForEpoch()
+		d.nextEpoch()
 	}
 
 	return d.populatePoint(p, 0)
@@ -49,6 +49,7 @@ func (c *CPUOnlySimulatorConfig) NewSimulator(interval time.Duration, limit uint
 	hostInfos := make([]Host, c.HostCount)
 	for i := 0; i < len(hostInfos); i++ {
 		hostInfos[i] = c.HostConstructor(NewHostCtx(i, c.Start))
+		common.ShapeMeasurements(hostInfos[i].SimulatedMeasurements, c.Shape, interval)
 	}
 
 	epochs := calculateEpochs(commonDevopsSimulatorConfig(*c), interval)
@@ -71,6 +72,7 @@ func (c *CPUOnlySimulatorConfig) NewSimulator(interval time.Duration, limit uint
 		timestampStart: c.Start,
 		timestampEnd:   c.End,
 		interval:       interval,
+		outages:        newHostOutages(hostInfos, c.Outage, c.Shape, interval),
 	}}
 
 	return sim
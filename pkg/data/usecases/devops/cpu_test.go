@@ -122,3 +122,27 @@ func TestSingleCPUMeasurementToPoint(t *testing.T) {
 		}
 	}
 }
+
+func TestCPUMeasurementShape(t *testing.T) {
+	now := time.Now()
+	m := NewCPUMeasurement(now)
+	m.Shape(common.ShapeConfig{
+		SeasonalityAmplitude: 1,
+		SeasonalityPeriod:    time.Minute,
+		SpikeRate:            0.5,
+		SpikeMagnitude:       10,
+		SpikeDuration:        time.Second,
+	}, time.Second)
+
+	p := data.NewPoint()
+	for i := 0; i < 1000; i++ {
+		p.Reset()
+		m.ToPoint(p)
+		for j, v := range p.FieldValues() {
+			if got := v.(int64); got < 0 || got > 100 {
+				t.Fatalf("shaped %s out of range: %d", p.FieldKeys()[j], got)
+			}
+		}
+		m.Tick(time.Second)
+	}
+}
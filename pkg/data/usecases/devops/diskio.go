@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
 	"math/rand"
 	"time"
 )
@@ -52,3 +53,8 @@ func (m *DiskIOMeasurement) ToPoint(p *data.Point) {
 	m.ToPointAllInt64(p, labelDiskIO, diskIOFields)
 	p.AppendTag(labelDiskIOSerial, m.serial)
 }
+
+// Shape lays the shape over the increments of all diskio counters.
+func (m *DiskIOMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, math.MaxFloat64)
+}
@@ -28,7 +28,7 @@ func (d *DevopsSimulator) Next(p *data.Point) bool {
 			d.hosts[i].TickAll(d.interval)
 		}
 
-		d.adjustNumHostsForEpoch()
+		d.nextEpoch()
 	}
 
 	return d.populatePoint(p, d.simulatedMeasurementIndex)
@@ -52,9 +52,10 @@ func (s *DevopsSimulator) TagTypes() []string {
 
 func (d *DevopsSimulator) Headers() *common.GeneratedDataHeaders {
 	return &common.GeneratedDataHeaders{
-		TagTypes:  d.TagTypes(),
-		TagKeys:   d.TagKeys(),
-		FieldKeys: d.Fields(),
+		TagTypes:   d.TagTypes(),
+		TagKeys:    d.TagKeys(),
+		FieldKeys:  d.Fields(),
+		FieldTypes: common.FieldTypes(d.hosts[0].SimulatedMeasurements),
 	}
 }
 
@@ -66,6 +67,7 @@ func (d *DevopsSimulatorConfig) NewSimulator(interval time.Duration, limit uint6
 	hostInfos := make([]Host, d.HostCount)
 	for i := 0; i < len(hostInfos); i++ {
 		hostInfos[i] = d.HostConstructor(NewHostCtx(i, d.Start))
+		common.ShapeMeasurements(hostInfos[i].SimulatedMeasurements, d.Shape, interval)
 	}
 
 	epochs := calculateEpochs(commonDevopsSimulatorConfig(*d), interval)
@@ -89,6 +91,7 @@ func (d *DevopsSimulatorConfig) NewSimulator(interval time.Duration, limit uint6
 			timestampStart: d.Start,
 			timestampEnd:   d.End,
 			interval:       interval,
+			outages:        newHostOutages(hostInfos, d.Outage, d.Shape, interval),
 		},
 		simulatedMeasurementIndex: 0,
 	}
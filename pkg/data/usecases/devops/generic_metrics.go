@@ -16,6 +16,8 @@ var (
 	zipfRandSeed                                          = int64(1234)
 )
 
+const genericMetricFieldFmt = "metric_%d"
+
 // GenericMeasurements represents measurements generated for generic metric fields
 type GenericMeasurements struct {
 	*common.SubsystemMeasurement
@@ -25,7 +27,7 @@ func initGenericMetricFields(size uint64) {
 	if genericMetricFields == nil {
 		genericMetricFields = make([]common.LabeledDistributionMaker, size)
 		for i := range genericMetricFields {
-			genericMetricFields[i] = common.LabeledDistributionMaker{Label: []byte(fmt.Sprintf("metric_%d", i)), DistributionMaker: func() common.Distribution { return common.CWD(metricND, 0.0, 1000, rand.Float64()*1000) }}
+			genericMetricFields[i] = common.LabeledDistributionMaker{Label: []byte(fmt.Sprintf(genericMetricFieldFmt, i)), DistributionMaker: func() common.Distribution { return common.CWD(metricND, 0.0, 1000, rand.Float64()*1000) }}
 		}
 	}
 }
@@ -51,6 +51,7 @@ func (c *GenericMetricsSimulatorConfig) NewSimulator(interval time.Duration, lim
 			timestampStart: c.Start,
 			timestampEnd:   c.End,
 			interval:       interval,
+			outages:        newHostOutages(hostInfos, c.Outage, c.Shape, interval),
 		},
 	}
 
@@ -90,7 +91,7 @@ func (gms *GenericMetricsSimulator) Next(p *data.Point) bool {
 			h.TickAll(gms.interval)
 		}
 		// increment epoch and adjust epoch hosts
-		gms.adjustNumHostsForEpoch()
+		gms.nextEpoch()
 	}
 
 	if gms.hostIndex < gms.epochHosts {
@@ -155,6 +155,10 @@ type Host struct {
 	GenericMetricCount uint64 // number of metrics generated
 	StartEpoch         uint64
 	EpochsToLive       uint64 // 0 means forever
+
+	// needed to restart the measurements after an outage
+	gen generator
+	ctx HostContext
 }
 
 type generator func(ctx *HostContext) []common.SimulatedMeasurement
@@ -185,10 +189,76 @@ func newCPUSingleHostMeasurements(ctx *HostContext) []common.SimulatedMeasuremen
 	}
 }
 
+func newStatusHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewNginxStatusMeasurement(ctx.start),
+		NewSystemdMeasurement(ctx.start),
+	}
+}
+
 func newGenericHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{NewGenericMeasurements(ctx.start, ctx.metricCount)}
 }
 
+// The fields of the measurements of each kind of host, keyed by measurement
+// name like the Fields of the simulators. They are known without creating a
+// host, which would consume random numbers.
+
+// HostFields returns the fields of the hosts created by NewHost.
+func HostFields() map[string][]string {
+	return map[string][]string{
+		string(labelCPU):        common.Labels(cpuFields),
+		string(labelDiskIO):     common.Labels(diskIOFields),
+		string(labelDisk):       common.KeysToStrings(diskFields),
+		string(labelKernel):     append([]string{string(labelKernelBootTime)}, common.Labels(kernelFields)...),
+		string(labelMem):        common.KeysToStrings(memoryFieldKeys),
+		string(labelNet):        common.Labels(netFields),
+		string(labelNginx):      common.Labels(nginxFields),
+		string(labelPostgresql): common.Labels(postgresqlFields),
+		string(labelRedis):      append([]string{string(labelRedisFieldUptime)}, common.Labels(redisFields)...),
+	}
+}
+
+// HostCPUOnlyFields returns the fields of the hosts created by NewHostCPUOnly.
+func HostCPUOnlyFields() map[string][]string {
+	return map[string][]string{string(labelCPU): common.Labels(cpuFields)}
+}
+
+// HostCPUSingleFields returns the fields of the hosts created by
+// NewHostCPUSingle.
+func HostCPUSingleFields() map[string][]string {
+	return map[string][]string{string(labelCPU): common.Labels(cpuFields[:1])}
+}
+
+// HostStatusFields returns the fields of the hosts created by NewHostStatus.
+func HostStatusFields() map[string][]string {
+	return map[string][]string{
+		string(labelNginxStatus): common.KeysToStrings([][]byte{
+			labelNginxStatusStatus,
+			labelNginxStatusReachable,
+			labelNginxStatusRequests,
+			labelNginxStatusBytesSent,
+		}),
+		string(labelSystemdUnit): common.KeysToStrings([][]byte{
+			labelSystemdActiveState,
+			labelSystemdSubState,
+			labelSystemdEnabled,
+			labelSystemdRestarts,
+			labelSystemdMemory,
+		}),
+	}
+}
+
+// HostGenericMetricsFields returns the fields of the generic metrics use case,
+// which are those of the host with the most metrics, maxMetricCount.
+func HostGenericMetricsFields(maxMetricCount uint64) map[string][]string {
+	keys := make([]string, maxMetricCount)
+	for i := range keys {
+		keys[i] = fmt.Sprintf(genericMetricFieldFmt, i)
+	}
+	return map[string][]string{string(labelGenericMetrics): keys}
+}
+
 // NewHost creates a new host in a simulated devops use case
 func NewHost(ctx *HostContext) Host {
 	return newHostWithMeasurementGenerator(newHostMeasurements, ctx)
@@ -206,6 +276,12 @@ func NewHostCPUSingle(ctx *HostContext) Host {
 	return newHostWithMeasurementGenerator(newCPUSingleHostMeasurements, ctx)
 }
 
+// NewHostStatus creates a new host in a simulated devops-status use case, whose
+// measurements report strings, booleans and uint64 counters
+func NewHostStatus(ctx *HostContext) Host {
+	return newHostWithMeasurementGenerator(newStatusHostMeasurements, ctx)
+}
+
 // NewHostGenericMetrics creates a new host in simulated generic metrics use case. Useful for testing with
 // high cardinality metrics
 func NewHostGenericMetrics(ctx *HostContext) Host {
@@ -234,11 +310,22 @@ func newHostWithMeasurementGenerator(gen generator, ctx *HostContext) Host {
 		GenericMetricCount:    ctx.metricCount,
 		StartEpoch:            math.MaxUint64,
 		EpochsToLive:          ctx.epochsToLive,
+
+		gen: gen,
+		ctx: *ctx,
 	}
 
 	return h
 }
 
+// Restart replaces the measurements of a Host with new ones starting at start,
+// as if it was rebooted, so its counters start over.
+func (h *Host) Restart(start time.Time) {
+	ctx := h.ctx
+	ctx.start = start
+	h.SimulatedMeasurements = h.gen(&ctx)
+}
+
 // TickAll advances all Distributions of a Host.
 func (h *Host) TickAll(d time.Duration) {
 	for i := range h.SimulatedMeasurements {
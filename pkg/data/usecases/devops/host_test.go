@@ -89,6 +89,23 @@ func TestNewCPUSingleHostMeasurements(t *testing.T) {
 	}
 }
 
+func TestNewStatusHostMeasurements(t *testing.T) {
+	start := time.Now()
+	measurements := newStatusHostMeasurements(NewHostCtxTime(start))
+	if got := len(measurements); got != 2 {
+		t.Errorf("incorrect number of measurements: got %d want %d", got, 2)
+	}
+	// Cast each measurement to its type; will panic if wrong types
+	nginx := measurements[0].(*NginxStatusMeasurement)
+	if got := nginx.Timestamp; got != start {
+		t.Errorf("incorrect nginx status measurement timestamp: got %v want %v", got, start)
+	}
+	systemd := measurements[1].(*SystemdMeasurement)
+	if got := systemd.Timestamp; got != start {
+		t.Errorf("incorrect systemd measurement timestamp: got %v want %v", got, start)
+	}
+}
+
 func TestNewHost(t *testing.T) {
 	now := time.Now()
 	// test 1000 times to get diversity of results
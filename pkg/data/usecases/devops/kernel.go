@@ -3,6 +3,7 @@ package devops
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
 	"math/rand"
 	"time"
 )
@@ -43,3 +44,8 @@ func (m *KernelMeasurement) ToPoint(p *data.Point) {
 	p.AppendField(labelKernelBootTime, m.bootTime)
 	m.ToPointAllInt64(p, labelKernel, kernelFields)
 }
+
+// Shape lays the shape over the increments of all kernel counters.
+func (m *KernelMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, math.MaxFloat64)
+}
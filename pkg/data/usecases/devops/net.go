@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
 	"math/rand"
 	"time"
 )
@@ -48,3 +49,8 @@ func (m *NetMeasurement) ToPoint(p *data.Point) {
 	m.ToPointAllInt64(p, labelNet, netFields)
 	p.AppendTag(labelNetTagInterface, m.interfaceName)
 }
+
+// Shape lays the shape over the increments of all net counters.
+func (m *NetMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, math.MaxFloat64)
+}
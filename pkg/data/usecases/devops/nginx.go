@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
 	"math/rand"
 	"strconv"
 	"time"
@@ -51,3 +52,10 @@ func (m *NginxMeasurement) ToPoint(p *data.Point) {
 	p.AppendTag(labelNginxTagPort, m.port)
 	p.AppendTag(labelNginxTagServer, m.serverName)
 }
+
+// Shape lays the shape over the increments of the nginx counters and over
+// the connection gauges.
+func (m *NginxMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, math.MaxFloat64, 0, 2, 4)
+	m.ApplyShape(c, interval, 0, 100, 1, 3, 5, 6)
+}
@@ -0,0 +1,82 @@
+package devops
+
+import (
+	"fmt"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	nginxStatusCritical = 2
+	// nginxCounterHeadroom is the most a counter starts below the uint64 limit,
+	// so counters wrap around within a run
+	nginxCounterHeadroom = 1 << 20
+)
+
+var (
+	labelNginxStatus = []byte("nginx_status") // heap optimization
+
+	labelNginxStatusStatus    = []byte("status")
+	labelNginxStatusReachable = []byte("reachable")
+	labelNginxStatusRequests  = []byte("requests_total")
+	labelNginxStatusBytesSent = []byte("bytes_sent_total")
+
+	// nginxStatuses are the states of the status field, indexed by the state
+	// of its distribution; the first is the healthy one
+	nginxStatuses = []string{"ok", "warning", "critical"}
+
+	nginxStatusFields = []common.LabeledDistributionMaker{
+		{Label: labelNginxStatusStatus, DistributionMaker: func() common.Distribution { return common.MSD(len(nginxStatuses), 0.01) }},
+		{Label: labelNginxStatusRequests, DistributionMaker: func() common.Distribution { return common.UD(0, 1000) }},
+		{Label: labelNginxStatusBytesSent, DistributionMaker: func() common.Distribution { return common.UD(0, 1e6) }},
+	}
+)
+
+// NginxStatusMeasurement is the status of an nginx server: a status string,
+// whether it is reachable, and uint64 counters that start close to their
+// limit, so they wrap around like long running counters do.
+type NginxStatusMeasurement struct {
+	*common.SubsystemMeasurement
+	port, serverName string
+
+	requests, bytesSent uint64
+}
+
+func NewNginxStatusMeasurement(start time.Time) *NginxStatusMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, nginxStatusFields)
+	serverName := fmt.Sprintf("nginx_%d", rand.Intn(100000))
+	port := strconv.FormatInt(rand.Int63n(20000)+1024, 10)
+	return &NginxStatusMeasurement{
+		SubsystemMeasurement: sub,
+		port:                 port,
+		serverName:           serverName,
+		requests:             math.MaxUint64 - uint64(rand.Int63n(nginxCounterHeadroom)),
+		bytesSent:            math.MaxUint64 - uint64(rand.Int63n(nginxCounterHeadroom)),
+	}
+}
+
+// Tick advances the status and adds the increments to the counters, which
+// wrap around at the uint64 limit.
+func (m *NginxStatusMeasurement) Tick(d time.Duration) {
+	m.SubsystemMeasurement.Tick(d)
+	m.requests += uint64(m.Distributions[1].Get())
+	m.bytesSent += uint64(m.Distributions[2].Get())
+}
+
+func (m *NginxStatusMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelNginxStatus)
+	p.SetTimestamp(&m.Timestamp)
+
+	status := int(m.Distributions[0].Get())
+	p.AppendField(labelNginxStatusStatus, nginxStatuses[status])
+	p.AppendField(labelNginxStatusReachable, status != nginxStatusCritical)
+	p.AppendField(labelNginxStatusRequests, m.requests)
+	p.AppendField(labelNginxStatusBytesSent, m.bytesSent)
+
+	p.AppendTag(labelNginxTagPort, m.port)
+	p.AppendTag(labelNginxTagServer, m.serverName)
+}
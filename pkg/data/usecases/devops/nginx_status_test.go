@@ -0,0 +1,60 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNginxStatusMeasurementTick(t *testing.T) {
+	now := time.Now()
+	m := NewNginxStatusMeasurement(now)
+	if m.requests < math.MaxUint64-nginxCounterHeadroom {
+		t.Errorf("requests counter does not start close to the limit: got %d", m.requests)
+	}
+
+	m.requests = math.MaxUint64
+	m.Distributions[1] = &oneDistribution{}
+	m.Tick(time.Second)
+	if got := m.requests; got != 0 {
+		t.Errorf("requests counter did not wrap around: got %d want 0", got)
+	}
+	if got := m.Timestamp; got != now.Add(time.Second) {
+		t.Errorf("incorrect timestamp after tick: got %v want %v", got, now.Add(time.Second))
+	}
+}
+
+func TestNginxStatusMeasurementToPoint(t *testing.T) {
+	now := time.Now()
+	m := NewNginxStatusMeasurement(now)
+	m.Tick(time.Second)
+
+	p := data.NewPoint()
+	m.ToPoint(p)
+	if got := string(p.MeasurementName()); got != string(labelNginxStatus) {
+		t.Errorf("incorrect measurement name: got %s want %s", got, labelNginxStatus)
+	}
+	if got := p.GetTagValue(labelNginxTagServer).(string); got != m.serverName {
+		t.Errorf("incorrect tag value for server name: got %s want %s", got, m.serverName)
+	}
+
+	status := p.GetFieldValue(labelNginxStatusStatus).(string)
+	reachable := p.GetFieldValue(labelNginxStatusReachable).(bool)
+	if reachable != (status != nginxStatuses[nginxStatusCritical]) {
+		t.Errorf("reachable %v does not match status %s", reachable, status)
+	}
+	if got := p.GetFieldValue(labelNginxStatusRequests).(uint64); got != m.requests {
+		t.Errorf("incorrect requests: got %d want %d", got, m.requests)
+	}
+	if got := p.GetFieldValue(labelNginxStatusBytesSent).(uint64); got != m.bytesSent {
+		t.Errorf("incorrect bytes sent: got %d want %d", got, m.bytesSent)
+	}
+}
+
+// oneDistribution always returns one.
+type oneDistribution struct{}
+
+func (d *oneDistribution) Advance() {}
+
+func (d *oneDistribution) Get() float64 { return 1 }
@@ -0,0 +1,94 @@
+package devops
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// hostOutages keeps track of the hosts that are silent. Each epoch every host
+// that is up may go silent on its own, and every datacenter may go silent
+// along with all its hosts. A host that comes back is restarted if the config
+// says so.
+type hostOutages struct {
+	config   common.OutageConfig
+	shape    common.ShapeConfig
+	interval time.Duration
+
+	// until is the epoch each host comes back at, 0 while it is up
+	until []uint64
+	// datacenters holds the indices of the hosts of each datacenter, in the
+	// order the datacenters are first seen so draws are deterministic
+	datacenters [][]int
+}
+
+// newHostOutages creates the outages of the given hosts, or returns nil if c
+// is not enabled. Restarted hosts get shape laid over their measurements.
+func newHostOutages(hosts []Host, c common.OutageConfig, shape common.ShapeConfig, interval time.Duration) *hostOutages {
+	if !c.Enabled() {
+		return nil
+	}
+	o := &hostOutages{
+		config:   c,
+		shape:    shape,
+		interval: interval,
+		until:    make([]uint64, len(hosts)),
+	}
+	datacenterIndex := make(map[string]int)
+	for i := range hosts {
+		j, ok := datacenterIndex[hosts[i].Datacenter]
+		if !ok {
+			j = len(o.datacenters)
+			datacenterIndex[hosts[i].Datacenter] = j
+			o.datacenters = append(o.datacenters, nil)
+		}
+		o.datacenters[j] = append(o.datacenters[j], i)
+	}
+	return o
+}
+
+// silent tells whether the host with the given index is silent. There are no
+// silent hosts without outages, i.e. when o is nil.
+func (o *hostOutages) silent(host uint64) bool {
+	return o != nil && o.until[host] > 0
+}
+
+// advance moves the outages to the given epoch, which starts at start: hosts
+// whose outage is over come back, and new outages begin.
+func (o *hostOutages) advance(hosts []Host, epoch uint64, start time.Time) {
+	if o == nil {
+		return
+	}
+	for i := range hosts {
+		if o.until[i] == 0 || o.until[i] > epoch {
+			continue
+		}
+		o.until[i] = 0
+		if o.config.Reset {
+			hosts[i].Restart(start)
+			common.ShapeMeasurements(hosts[i].SimulatedMeasurements, o.shape, o.interval)
+		}
+	}
+
+	if o.config.DatacenterRate > 0 {
+		for _, datacenter := range o.datacenters {
+			if rand.Float64() >= o.config.DatacenterRate {
+				continue
+			}
+			until := epoch + o.config.Epochs(o.interval)
+			for _, i := range datacenter {
+				if o.until[i] < until {
+					o.until[i] = until
+				}
+			}
+		}
+	}
+	if o.config.HostRate > 0 {
+		for i := range hosts {
+			if o.until[i] == 0 && rand.Float64() < o.config.HostRate {
+				o.until[i] = epoch + o.config.Epochs(o.interval)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package devops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func newTestOutageHosts(count int) []Host {
+	hosts := make([]Host, count)
+	for i := range hosts {
+		hosts[i] = NewHostCPUOnly(NewHostCtx(i, testTime))
+	}
+	return hosts
+}
+
+func TestHostOutagesDisabled(t *testing.T) {
+	o := newHostOutages(newTestOutageHosts(2), common.OutageConfig{MaxDuration: time.Minute}, common.ShapeConfig{}, time.Second)
+	if o != nil {
+		t.Fatalf("outages created without rates")
+	}
+	// no outages means no silent hosts
+	o.advance(nil, 1, testTime)
+	if o.silent(0) {
+		t.Errorf("host silent without outages")
+	}
+}
+
+func TestHostOutagesHost(t *testing.T) {
+	hosts := newTestOutageHosts(20)
+	c := common.OutageConfig{HostRate: 1, MaxDuration: 3 * time.Second}
+	o := newHostOutages(hosts, c, common.ShapeConfig{}, time.Second)
+
+	o.advance(hosts, 1, testTime.Add(time.Second))
+	for i := range hosts {
+		if !o.silent(uint64(i)) {
+			t.Fatalf("host %d not silent", i)
+		}
+		if until := o.until[i]; until < 2 || until > 4 {
+			t.Errorf("host %d: outage ends out of range: got epoch %d", i, until)
+		}
+	}
+
+	// stop new outages, so all hosts are back after the longest one
+	o.config.HostRate = 0
+	o.advance(hosts, 4, testTime.Add(4*time.Second))
+	for i := range hosts {
+		if o.silent(uint64(i)) {
+			t.Errorf("host %d still silent after its outage", i)
+		}
+	}
+}
+
+func TestHostOutagesDatacenter(t *testing.T) {
+	hosts := newTestOutageHosts(50)
+	c := common.OutageConfig{DatacenterRate: 1, MaxDuration: time.Minute}
+	o := newHostOutages(hosts, c, common.ShapeConfig{}, time.Second)
+
+	o.advance(hosts, 1, testTime.Add(time.Second))
+	until := make(map[string]uint64)
+	for i, h := range hosts {
+		if !o.silent(uint64(i)) {
+			t.Fatalf("host %d not silent", i)
+		}
+		if want, ok := until[h.Datacenter]; ok && o.until[i] != want {
+			t.Errorf("host %d: outage of datacenter %s ends at different epochs: got %d want %d", i, h.Datacenter, o.until[i], want)
+		}
+		until[h.Datacenter] = o.until[i]
+	}
+}
+
+func TestHostOutagesReset(t *testing.T) {
+	for _, reset := range []bool{false, true} {
+		hosts := newTestOutageHosts(5)
+		c := common.OutageConfig{HostRate: 1, MaxDuration: time.Second, Reset: reset}
+		o := newHostOutages(hosts, c, common.ShapeConfig{}, time.Second)
+		before := hosts[0].SimulatedMeasurements[0]
+
+		o.advance(hosts, 1, testTime.Add(time.Second))
+		o.config.HostRate = 0
+		back := testTime.Add(2 * time.Second)
+		o.advance(hosts, 2, back)
+
+		after := hosts[0].SimulatedMeasurements[0]
+		if restarted := after != before; restarted != reset {
+			t.Errorf("reset %v: incorrect restart of measurements: got %v", reset, restarted)
+		}
+		if !reset {
+			continue
+		}
+		if got := after.(*CPUMeasurement).Timestamp; !got.Equal(back) {
+			t.Errorf("restarted measurements start at wrong time: got %v want %v", got, back)
+		}
+	}
+}
+
+func TestDevopsSimulatorOutages(t *testing.T) {
+	const hostCount, epochs = 10, 100
+	conf := &CPUOnlySimulatorConfig{
+		Start:           testTime,
+		End:             testTime.Add(epochs * time.Second),
+		InitHostCount:   hostCount,
+		HostCount:       hostCount,
+		HostConstructor: NewHostCPUOnly,
+		Outage:          common.OutageConfig{HostRate: 0.1, MaxDuration: 5 * time.Second},
+	}
+	sim := conf.NewSimulator(time.Second, 0)
+	written := 0
+	made := 0
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			written++
+		}
+		made++
+		p.Reset()
+	}
+	if made != hostCount*epochs {
+		t.Errorf("incorrect number of points made: got %d want %d", made, hostCount*epochs)
+	}
+	if written == 0 || written == made {
+		t.Errorf("outages should leave some but not all points out: wrote %d of %d", written, made)
+	}
+}
@@ -0,0 +1,102 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	systemdStateActive     = 0
+	systemdStateReloading  = 1
+	systemdStateActivating = 4
+	// systemdMemoryNotSet is what systemd reports as the memory of a unit
+	// that is not running
+	systemdMemoryNotSet = math.MaxUint64
+)
+
+var (
+	labelSystemdUnit    = []byte("systemd_unit") // heap optimization
+	labelSystemdTagUnit = []byte("unit")
+
+	labelSystemdActiveState = []byte("active_state")
+	labelSystemdSubState    = []byte("sub_state")
+	labelSystemdEnabled     = []byte("enabled")
+	labelSystemdRestarts    = []byte("restarts")
+	labelSystemdMemory      = []byte("memory_current")
+
+	systemdUnitChoices = []string{
+		"cron.service",
+		"docker.service",
+		"nginx.service",
+		"postgresql.service",
+		"redis.service",
+		"sshd.service",
+		"systemd-journald.service",
+	}
+
+	// systemdActiveStates and systemdSubStates are the states of the state
+	// fields, indexed by the state of its distribution; the first is the
+	// healthy one
+	systemdActiveStates = []string{"active", "reloading", "inactive", "failed", "activating", "deactivating"}
+	systemdSubStates    = []string{"running", "reload", "dead", "failed", "start", "stop-sigterm"}
+
+	systemdFields = []common.LabeledDistributionMaker{
+		{Label: labelSystemdActiveState, DistributionMaker: func() common.Distribution { return common.MSD(len(systemdActiveStates), 0.02) }},
+		{Label: labelSystemdMemory, DistributionMaker: func() common.Distribution { return common.CWD(common.ND(0, 1<<20), 1<<20, 1<<32, 1<<26) }},
+	}
+)
+
+// SystemdMeasurement is the state of a systemd unit, reported as strings, and
+// whether it is enabled. Its memory is a uint64 that is the uint64 limit while
+// the unit is not running, like systemd reports it.
+type SystemdMeasurement struct {
+	*common.SubsystemMeasurement
+	unit    string
+	enabled bool
+
+	restarts int64
+}
+
+func NewSystemdMeasurement(start time.Time) *SystemdMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, systemdFields)
+	return &SystemdMeasurement{
+		SubsystemMeasurement: sub,
+		unit:                 common.RandomStringSliceChoice(systemdUnitChoices),
+		enabled:              rand.Intn(4) > 0,
+	}
+}
+
+// Tick advances the state of the unit, counting a restart whenever it starts
+// activating.
+func (m *SystemdMeasurement) Tick(d time.Duration) {
+	prev := m.state()
+	m.SubsystemMeasurement.Tick(d)
+	if state := m.state(); state != prev && state == systemdStateActivating {
+		m.restarts++
+	}
+}
+
+func (m *SystemdMeasurement) state() int {
+	return int(m.Distributions[0].Get())
+}
+
+func (m *SystemdMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelSystemdUnit)
+	p.SetTimestamp(&m.Timestamp)
+
+	state := m.state()
+	memory := uint64(systemdMemoryNotSet)
+	if state == systemdStateActive || state == systemdStateReloading {
+		memory = uint64(m.Distributions[1].Get())
+	}
+	p.AppendField(labelSystemdActiveState, systemdActiveStates[state])
+	p.AppendField(labelSystemdSubState, systemdSubStates[state])
+	p.AppendField(labelSystemdEnabled, m.enabled)
+	p.AppendField(labelSystemdRestarts, m.restarts)
+	p.AppendField(labelSystemdMemory, memory)
+
+	p.AppendTag(labelSystemdTagUnit, m.unit)
+}
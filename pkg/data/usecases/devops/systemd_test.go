@@ -0,0 +1,56 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSystemdMeasurementTick(t *testing.T) {
+	m := NewSystemdMeasurement(time.Now())
+	msd := m.Distributions[0].(*common.MarkovStateDistribution)
+	msd.Rate = 1
+
+	restarts := int64(0)
+	for i := 0; i < 100; i++ {
+		m.Tick(time.Second)
+		if m.state() == systemdStateActivating {
+			restarts++
+		}
+	}
+	if got := m.restarts; got != restarts {
+		t.Errorf("incorrect restarts: got %d want %d", got, restarts)
+	}
+}
+
+func TestSystemdMeasurementToPoint(t *testing.T) {
+	m := NewSystemdMeasurement(time.Now())
+	msd := m.Distributions[0].(*common.MarkovStateDistribution)
+	msd.Rate = 1
+
+	for i := 0; i < 20; i++ {
+		m.Tick(time.Second)
+		p := data.NewPoint()
+		m.ToPoint(p)
+		if got := string(p.MeasurementName()); got != string(labelSystemdUnit) {
+			t.Errorf("incorrect measurement name: got %s want %s", got, labelSystemdUnit)
+		}
+		if got := p.GetTagValue(labelSystemdTagUnit).(string); got != m.unit {
+			t.Errorf("incorrect tag value for unit: got %s want %s", got, m.unit)
+		}
+		if got := p.GetFieldValue(labelSystemdEnabled).(bool); got != m.enabled {
+			t.Errorf("incorrect enabled: got %v want %v", got, m.enabled)
+		}
+
+		state := p.GetFieldValue(labelSystemdActiveState).(string)
+		memory := p.GetFieldValue(labelSystemdMemory).(uint64)
+		running := state == "active" || state == "reloading"
+		if running && memory == math.MaxUint64 {
+			t.Errorf("memory not set for %s unit", state)
+		} else if !running && memory != math.MaxUint64 {
+			t.Errorf("memory set for %s unit: got %d", state, memory)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package finance
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// QuoteProbability is the share of events that are quotes; the rest are trades.
+	QuoteProbability = 0.8
+	// ZipfExponent skews popularity across symbols: the symbol of rank r gets
+	// events proportionally to 1/(r+1)^ZipfExponent.
+	ZipfExponent = 1.0
+)
+
+// SimulatorConfig is used to create a finance Simulator.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	Start time.Time
+	End   time.Time
+
+	// SymbolCount is the size of the symbol universe
+	SymbolCount uint64
+}
+
+// NewSimulator produces a finance Simulator with the given config and points
+// limit. Unlike other use cases events are not on a fixed grid: interval is
+// the mean time between two events of the same symbol, were all symbols
+// equally popular, so the whole universe produces SymbolCount events per
+// interval on average.
+func (c *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	symbols := make([]*Symbol, c.SymbolCount)
+	for i := range symbols {
+		symbols[i] = NewSymbol(i)
+	}
+
+	s := &Simulator{
+		maxPoints:   limit,
+		symbols:     symbols,
+		popularity:  zipfCDF(len(symbols), ZipfExponent),
+		meanArrival: float64(interval.Nanoseconds()) / float64(c.SymbolCount),
+		timestamp:   c.Start,
+		end:         c.End,
+	}
+	s.advance()
+	return s
+}
+
+// Simulator generates trades and quotes with Poisson distributed arrivals:
+// inter-arrival times are exponentially distributed, and every event is
+// assigned to a symbol drawn from a Zipf distribution.
+type Simulator struct {
+	madePoints uint64
+	maxPoints  uint64
+
+	symbols     []*Symbol
+	popularity  []float64
+	meanArrival float64
+
+	// timestamp is the time of the next event
+	timestamp time.Time
+	end       time.Time
+}
+
+// Finished tells whether we have simulated all the necessary points.
+func (s *Simulator) Finished() bool {
+	if s.maxPoints > 0 && s.madePoints >= s.maxPoints {
+		return true
+	}
+	return !s.timestamp.Before(s.end)
+}
+
+// Next populates p with the next event. Every event is written.
+func (s *Simulator) Next(p *data.Point) bool {
+	sym := s.symbols[s.pickSymbol()]
+	if rand.Float64() < QuoteProbability {
+		sym.QuoteToPoint(p, s.timestamp)
+	} else {
+		sym.TradeToPoint(p, s.timestamp)
+	}
+
+	s.madePoints++
+	s.advance()
+	return true
+}
+
+// Fields returns the field keys of the quotes and trades measurements.
+func Fields() map[string][]string {
+	return map[string][]string{
+		string(labelQuotes): common.KeysToStrings(quoteFieldKeys),
+		string(labelTrades): common.KeysToStrings(tradeFieldKeys),
+	}
+}
+
+// Fields returns the field keys of the quotes and trades measurements.
+func (s *Simulator) Fields() map[string][]string {
+	return Fields()
+}
+
+// TagKeys returns all the tag keys of a symbol.
+func (s *Simulator) TagKeys() []string {
+	return []string{string(labelSymbol), string(labelExchange)}
+}
+
+// TagTypes returns the type of each tag; all tags are strings.
+func (s *Simulator) TagTypes() []string {
+	return []string{"string", "string"}
+}
+
+// FieldTypes returns the types of the fields of the quotes and trades
+// measurements; prices in cents and sizes are all integers.
+func (s *Simulator) FieldTypes() map[string][]string {
+	return map[string][]string{
+		string(labelQuotes): int64Types(len(quoteFieldKeys)),
+		string(labelTrades): int64Types(len(tradeFieldKeys)),
+	}
+}
+
+// Headers returns the headers of the generated data.
+func (s *Simulator) Headers() *common.GeneratedDataHeaders {
+	return &common.GeneratedDataHeaders{
+		TagTypes:   s.TagTypes(),
+		TagKeys:    s.TagKeys(),
+		FieldKeys:  s.Fields(),
+		FieldTypes: s.FieldTypes(),
+	}
+}
+
+func (s *Simulator) advance() {
+	s.timestamp = s.timestamp.Add(time.Duration(rand.ExpFloat64() * s.meanArrival))
+}
+
+func (s *Simulator) pickSymbol() int {
+	return sort.SearchFloat64s(s.popularity, rand.Float64()*s.popularity[len(s.popularity)-1])
+}
+
+// zipfCDF returns the cumulative, unnormalized Zipf weights of n ranks.
+func zipfCDF(n int, exponent float64) []float64 {
+	cdf := make([]float64, n)
+	sum := 0.0
+	for i := range cdf {
+		sum += 1 / math.Pow(float64(i+1), exponent)
+		cdf[i] = sum
+	}
+	return cdf
+}
+
+func int64Types(n int) []string {
+	types := make([]string, n)
+	for i := range types {
+		types[i] = common.FieldTypeInt64
+	}
+	return types
+}
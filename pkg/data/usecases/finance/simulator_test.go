@@ -0,0 +1,118 @@
+package finance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func TestZipfCDF(t *testing.T) {
+	cdf := zipfCDF(4, 1)
+	want := []float64{1, 1.5, 1.5 + 1.0/3, 1.5 + 1.0/3 + 0.25}
+	for i := range want {
+		if math.Abs(cdf[i]-want[i]) > 1e-9 {
+			t.Errorf("incorrect cdf[%d]: got %f want %f", i, cdf[i], want[i])
+		}
+	}
+}
+
+func TestSimulatorNext(t *testing.T) {
+	start := time.Unix(0, 0)
+	sc := &SimulatorConfig{
+		Start:       start,
+		End:         start.Add(time.Hour),
+		SymbolCount: 10,
+	}
+	sim := sc.NewSimulator(time.Second, 0)
+
+	counts := map[string]int{}
+	measurements := map[string]int{}
+	last := start
+	irregular := false
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if !sim.Next(p) {
+			t.Fatalf("event not written")
+		}
+		ts := *p.Timestamp()
+		if ts.Before(last) {
+			t.Fatalf("timestamps not increasing: %v before %v", ts, last)
+		}
+		if ts.Nanosecond() != 0 {
+			irregular = true
+		}
+		last = ts
+		counts[p.GetTagValue(labelSymbol).(string)]++
+		measurements[string(p.MeasurementName())]++
+		p.Reset()
+	}
+
+	// 10 symbols at one event per second each over an hour
+	total := measurements["quotes"] + measurements["trades"]
+	if total < 34000 || total > 38000 {
+		t.Errorf("event count too far from expectation: got %d want ~%d", total, 36000)
+	}
+	if share := float64(measurements["quotes"]) / float64(total); math.Abs(share-QuoteProbability) > 0.02 {
+		t.Errorf("quote share too far from expectation: got %f want %f", share, QuoteProbability)
+	}
+	if !irregular {
+		t.Errorf("timestamps are on a whole second grid")
+	}
+	if counts[SymbolName(0)] <= counts[SymbolName(9)]*5 {
+		t.Errorf("popularity not skewed: %s=%d %s=%d", SymbolName(0), counts[SymbolName(0)], SymbolName(9), counts[SymbolName(9)])
+	}
+}
+
+func TestSimulatorLimit(t *testing.T) {
+	start := time.Unix(0, 0)
+	sc := &SimulatorConfig{
+		Start:       start,
+		End:         start.Add(time.Hour),
+		SymbolCount: 10,
+	}
+	sim := sc.NewSimulator(time.Second, 100)
+
+	count := 0
+	p := data.NewPoint()
+	for !sim.Finished() {
+		sim.Next(p)
+		p.Reset()
+		count++
+	}
+	if count != 100 {
+		t.Errorf("incorrect point count: got %d want %d", count, 100)
+	}
+}
+
+func TestSimulatorHeaders(t *testing.T) {
+	sc := &SimulatorConfig{
+		Start:       time.Unix(0, 0),
+		End:         time.Unix(60, 0),
+		SymbolCount: 1,
+	}
+	h := sc.NewSimulator(time.Second, 0).Headers()
+	if got := h.TagKeys; len(got) != 2 || got[0] != "symbol" || got[1] != "exchange" {
+		t.Errorf("incorrect tag keys: got %v", got)
+	}
+	if got := h.FieldKeys["quotes"]; len(got) != 4 {
+		t.Errorf("incorrect quotes fields: got %v", got)
+	}
+	if got := h.FieldKeys["trades"]; len(got) != 2 || got[0] != "price" || got[1] != "size" {
+		t.Errorf("incorrect trades fields: got %v", got)
+	}
+	for measurement, keys := range h.FieldKeys {
+		types := h.FieldTypes[measurement]
+		if len(types) != len(keys) {
+			t.Errorf("incorrect %s field types: got %v for fields %v", measurement, types, keys)
+			continue
+		}
+		for i, fieldType := range types {
+			if fieldType != common.FieldTypeInt64 {
+				t.Errorf("incorrect type of %s %s: got %q want %q", measurement, keys[i], fieldType, common.FieldTypeInt64)
+			}
+		}
+	}
+}
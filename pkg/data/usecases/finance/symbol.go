@@ -0,0 +1,150 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	symbolNameFmt = "symbol_%d"
+
+	// LotSize is the number of shares in a round lot; quote and trade sizes
+	// are multiples of it.
+	LotSize = 100
+
+	// Prices are integers in cents.
+	minInitialPrice = 1000
+	maxInitialPrice = 100000
+	// volatility is the standard deviation of a mid price move per quote,
+	// relative to the mid price.
+	volatility = 0.0005
+	// spreadDivisor sets the half spread to roughly 0.5bp of the mid price.
+	spreadDivisor = 20000
+	maxQuoteLots  = 10
+	meanTradeLots = 2
+)
+
+var (
+	// ExchangeChoices contains all the exchange values for the finance use case.
+	ExchangeChoices = []string{
+		"NYSE",
+		"NASDAQ",
+		"ARCA",
+		"BATS",
+		"IEX",
+	}
+
+	labelSymbol   = []byte("symbol")
+	labelExchange = []byte("exchange")
+
+	labelQuotes = []byte("quotes") // heap optimization
+	labelTrades = []byte("trades") // heap optimization
+
+	quoteFieldKeys = [][]byte{
+		[]byte("bid_price"),
+		[]byte("bid_size"),
+		[]byte("ask_price"),
+		[]byte("ask_size"),
+	}
+	tradeFieldKeys = [][]byte{
+		[]byte("price"),
+		[]byte("size"),
+	}
+)
+
+// SymbolName returns the name of the i-th symbol of the universe.
+func SymbolName(i int) string {
+	return fmt.Sprintf(symbolNameFmt, i)
+}
+
+// Symbol models the order book top of a single traded instrument. Quotes move
+// the mid price by a random walk; trades execute at the current bid or ask.
+type Symbol struct {
+	tags []common.Tag
+
+	mid        int64
+	halfSpread int64
+	bidSize    int64
+	askSize    int64
+}
+
+// NewSymbol creates the i-th symbol of the universe with a random initial price.
+func NewSymbol(i int) *Symbol {
+	s := &Symbol{
+		tags: []common.Tag{
+			{Key: labelSymbol, Value: SymbolName(i)},
+			{Key: labelExchange, Value: ExchangeChoices[rand.Intn(len(ExchangeChoices))]},
+		},
+		mid: minInitialPrice + rand.Int63n(maxInitialPrice-minInitialPrice),
+	}
+	s.updateQuote()
+	return s
+}
+
+// Tags returns the symbol tags.
+func (s *Symbol) Tags() []common.Tag {
+	return s.tags
+}
+
+// Bid returns the current best bid price in cents.
+func (s *Symbol) Bid() int64 {
+	return s.mid - s.halfSpread
+}
+
+// Ask returns the current best ask price in cents.
+func (s *Symbol) Ask() int64 {
+	return s.mid + s.halfSpread
+}
+
+// QuoteToPoint moves the quote and writes it to p with timestamp ts.
+func (s *Symbol) QuoteToPoint(p *data.Point, ts time.Time) {
+	step := int64(math.Round(rand.NormFloat64() * volatility * float64(s.mid)))
+	s.mid += step
+	s.updateQuote()
+
+	s.appendTags(p)
+	p.SetMeasurementName(labelQuotes)
+	p.SetTimestamp(&ts)
+	p.AppendField(quoteFieldKeys[0], s.Bid())
+	p.AppendField(quoteFieldKeys[1], s.bidSize)
+	p.AppendField(quoteFieldKeys[2], s.Ask())
+	p.AppendField(quoteFieldKeys[3], s.askSize)
+}
+
+// TradeToPoint executes a trade against the current quote and writes it to p
+// with timestamp ts. Buys lift the ask, sells hit the bid.
+func (s *Symbol) TradeToPoint(p *data.Point, ts time.Time) {
+	price := s.Bid()
+	if rand.Intn(2) == 0 {
+		price = s.Ask()
+	}
+	size := LotSize * (1 + int64(rand.ExpFloat64()*meanTradeLots))
+
+	s.appendTags(p)
+	p.SetMeasurementName(labelTrades)
+	p.SetTimestamp(&ts)
+	p.AppendField(tradeFieldKeys[0], price)
+	p.AppendField(tradeFieldKeys[1], size)
+}
+
+func (s *Symbol) appendTags(p *data.Point) {
+	for _, tag := range s.tags {
+		p.AppendTag(tag.Key, tag.Value)
+	}
+}
+
+// updateQuote derives the spread and sizes from the mid price, keeping the
+// bid at least one cent.
+func (s *Symbol) updateQuote() {
+	s.halfSpread = 1 + s.mid/spreadDivisor
+	if min := s.halfSpread + 1; s.mid < min {
+		s.mid = min
+	}
+	s.bidSize = LotSize * (1 + rand.Int63n(maxQuoteLots))
+	s.askSize = LotSize * (1 + rand.Int63n(maxQuoteLots))
+}
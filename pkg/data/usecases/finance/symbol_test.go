@@ -0,0 +1,85 @@
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestNewSymbol(t *testing.T) {
+	s := NewSymbol(7)
+	tags := s.Tags()
+	if got := len(tags); got != 2 {
+		t.Fatalf("incorrect tag count: got %d want %d", got, 2)
+	}
+	if got := tags[0].Value; got != "symbol_7" {
+		t.Errorf("incorrect symbol: got %v want %s", got, "symbol_7")
+	}
+	if s.mid < minInitialPrice || s.mid >= maxInitialPrice {
+		t.Errorf("initial price out of range: %d", s.mid)
+	}
+	if s.Bid() >= s.Ask() {
+		t.Errorf("bid %d not below ask %d", s.Bid(), s.Ask())
+	}
+}
+
+func TestSymbolQuoteToPoint(t *testing.T) {
+	s := NewSymbol(0)
+	ts := time.Unix(0, 123456789)
+	p := data.NewPoint()
+	for i := 0; i < 1000; i++ {
+		p.Reset()
+		s.QuoteToPoint(p, ts)
+
+		bid := p.GetFieldValue(quoteFieldKeys[0]).(int64)
+		ask := p.GetFieldValue(quoteFieldKeys[2]).(int64)
+		if bid < 1 || bid >= ask {
+			t.Fatalf("invalid quote: bid %d ask %d", bid, ask)
+		}
+		for _, k := range []int{1, 3} {
+			if size := p.GetFieldValue(quoteFieldKeys[k]).(int64); size < LotSize || size%LotSize != 0 {
+				t.Fatalf("invalid %s: %d", quoteFieldKeys[k], size)
+			}
+		}
+	}
+	if got := string(p.MeasurementName()); got != "quotes" {
+		t.Errorf("incorrect measurement name: got %s want %s", got, "quotes")
+	}
+	if got := *p.Timestamp(); !got.Equal(ts) {
+		t.Errorf("incorrect timestamp: got %v want %v", got, ts)
+	}
+}
+
+func TestSymbolTradeToPoint(t *testing.T) {
+	s := NewSymbol(0)
+	p := data.NewPoint()
+	for i := 0; i < 1000; i++ {
+		p.Reset()
+		s.TradeToPoint(p, time.Now())
+
+		price := p.GetFieldValue(tradeFieldKeys[0]).(int64)
+		if price != s.Bid() && price != s.Ask() {
+			t.Fatalf("trade price %d not at bid %d or ask %d", price, s.Bid(), s.Ask())
+		}
+		if size := p.GetFieldValue(tradeFieldKeys[1]).(int64); size < LotSize || size%LotSize != 0 {
+			t.Fatalf("invalid size: %d", size)
+		}
+	}
+	if got := string(p.MeasurementName()); got != "trades" {
+		t.Errorf("incorrect measurement name: got %s want %s", got, "trades")
+	}
+}
+
+func TestSymbolPriceStaysPositive(t *testing.T) {
+	s := NewSymbol(0)
+	s.mid = 2
+	p := data.NewPoint()
+	for i := 0; i < 1000; i++ {
+		p.Reset()
+		s.QuoteToPoint(p, time.Now())
+		if s.Bid() < 1 {
+			t.Fatalf("bid dropped below one cent: %d", s.Bid())
+		}
+	}
+}
@@ -90,3 +90,8 @@ func NewDiagnosticsMeasurement(start time.Time) *DiagnosticsMeasurement {
 		SubsystemMeasurement: sub,
 	}
 }
+
+// Shape lays the shape over the current load.
+func (m *DiagnosticsMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, maxLoad, 1)
+}
@@ -122,3 +122,9 @@ func NewReadingsMeasurement(start time.Time) *ReadingsMeasurement {
 		SubsystemMeasurement: sub,
 	}
 }
+
+// Shape lays the shape over the velocity and fuel consumption readings.
+func (m *ReadingsMeasurement) Shape(c common.ShapeConfig, interval time.Duration) {
+	m.ApplyShape(c, interval, 0, maxVelocity, 3)
+	m.ApplyShape(c, interval, 0, maxFuelConsumption, 6)
+}
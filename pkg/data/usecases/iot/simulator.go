@@ -56,6 +56,15 @@ type Simulator struct {
 	offset int
 }
 
+// Fields returns the fields of the readings and diagnostics measurements of a
+// truck, without creating one.
+func Fields() map[string][]string {
+	return map[string][]string{
+		string(labelReadings):    common.Labels(readingsFields),
+		string(labelDiagnostics): common.Labels(diagnosticsFields),
+	}
+}
+
 // Fields returns the fields of an entry.
 func (s Simulator) Fields() map[string][]string {
 	return s.base.Fields()
@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+var (
+	labelContainerCPU = []byte("container_cpu") // heap optimization
+
+	// Reuse NormalDistributions as arguments to other distributions. This is
+	// safe to do because the higher-level distribution advances the ND and
+	// immediately uses its value and saves the state
+	cpuUsageND     = common.ND(0.0, 5.0)
+	cpuSecondsND   = common.ND(2.0, 1.0)
+	cpuThrottledND = common.ND(0.0, 0.2)
+
+	containerCPUFields = []common.LabeledDistributionMaker{
+		{Label: []byte("usage_percent"), DistributionMaker: func() common.Distribution {
+			return common.CWD(cpuUsageND, 0.0, 100.0, rand.Float64()*50.0)
+		}},
+		{Label: []byte("usage_seconds_total"), DistributionMaker: func() common.Distribution {
+			return common.MWD(cpuSecondsND, 0)
+		}},
+		{Label: []byte("throttled_seconds_total"), DistributionMaker: func() common.Distribution {
+			return common.MWD(cpuThrottledND, 0)
+		}},
+	}
+)
+
+// ContainerCPUMeasurement models the cpu usage of a pod's container.
+type ContainerCPUMeasurement struct {
+	*common.SubsystemMeasurement
+}
+
+// NewContainerCPUMeasurement creates a new ContainerCPUMeasurement with start time.
+func NewContainerCPUMeasurement(start time.Time) *ContainerCPUMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, containerCPUFields)
+	return &ContainerCPUMeasurement{sub}
+}
+
+// ToPoint serializes ContainerCPUMeasurement to serialize.Point.
+func (m *ContainerCPUMeasurement) ToPoint(p *data.Point) {
+	m.SubsystemMeasurement.ToPoint(p, labelContainerCPU, containerCPUFields)
+}
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+var (
+	labelContainerMemory = []byte("container_memory") // heap optimization
+
+	// memoryLimitChoices are the choices for modeling a container's memory limit.
+	memoryLimitChoices = []int64{256 << 20, 512 << 20, 1 << 30, 2 << 30}
+
+	containerMemoryFieldKeys = [][]byte{
+		[]byte("working_set_bytes"),
+		[]byte("rss_bytes"),
+		[]byte("cache_bytes"),
+		[]byte("limit_bytes"),
+	}
+)
+
+// ContainerMemoryMeasurement models the memory usage of a pod's container.
+type ContainerMemoryMeasurement struct {
+	*common.SubsystemMeasurement
+	limit int64 // this doesn't change for the lifetime of a pod
+}
+
+// NewContainerMemoryMeasurement creates a new ContainerMemoryMeasurement with start time.
+func NewContainerMemoryMeasurement(start time.Time) *ContainerMemoryMeasurement {
+	sub := common.NewSubsystemMeasurement(start, 2)
+	limit := common.RandomInt64SliceChoice(memoryLimitChoices)
+
+	// Reuse NormalDistributions as arguments to other distributions. This is
+	// safe to do because the higher-level distribution advances the ND and
+	// immediately uses its value and saves the state
+	nd := common.ND(0.0, float64(limit)/64)
+
+	// rss bytes
+	sub.Distributions[0] = common.CWD(nd, 0.0, float64(limit), rand.Float64()*float64(limit)/2)
+	// cache bytes
+	sub.Distributions[1] = common.CWD(nd, 0.0, float64(limit)/4, rand.Float64()*float64(limit)/4)
+	return &ContainerMemoryMeasurement{
+		SubsystemMeasurement: sub,
+		limit:                limit,
+	}
+}
+
+// ToPoint serializes ContainerMemoryMeasurement to serialize.Point.
+func (m *ContainerMemoryMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelContainerMemory)
+	p.SetTimestamp(&m.Timestamp)
+
+	rss := int64(m.Distributions[0].Get())
+	cache := int64(m.Distributions[1].Get())
+	workingSet := rss + cache
+	if workingSet > m.limit {
+		workingSet = m.limit
+	}
+
+	p.AppendField(containerMemoryFieldKeys[0], workingSet)
+	p.AppendField(containerMemoryFieldKeys[1], rss)
+	p.AppendField(containerMemoryFieldKeys[2], cache)
+	p.AppendField(containerMemoryFieldKeys[3], m.limit)
+}
@@ -0,0 +1,41 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+var (
+	labelContainerNetwork = []byte("container_network") // heap optimization
+
+	// Reuse NormalDistributions as arguments to other distributions. This is
+	// safe to do because the higher-level distribution advances the ND and
+	// immediately uses its value and saves the state
+	networkBytesND  = common.ND(50000, 10000)
+	networkErrorsND = common.ND(0, 0.5)
+
+	containerNetworkFields = []common.LabeledDistributionMaker{
+		{Label: []byte("rx_bytes_total"), DistributionMaker: func() common.Distribution { return common.MWD(networkBytesND, 0) }},
+		{Label: []byte("tx_bytes_total"), DistributionMaker: func() common.Distribution { return common.MWD(networkBytesND, 0) }},
+		{Label: []byte("rx_errors_total"), DistributionMaker: func() common.Distribution { return common.MWD(networkErrorsND, 0) }},
+		{Label: []byte("tx_errors_total"), DistributionMaker: func() common.Distribution { return common.MWD(networkErrorsND, 0) }},
+	}
+)
+
+// ContainerNetworkMeasurement models the network counters of a pod.
+type ContainerNetworkMeasurement struct {
+	*common.SubsystemMeasurement
+}
+
+// NewContainerNetworkMeasurement creates a new ContainerNetworkMeasurement with start time.
+func NewContainerNetworkMeasurement(start time.Time) *ContainerNetworkMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, containerNetworkFields)
+	return &ContainerNetworkMeasurement{sub}
+}
+
+// ToPoint serializes ContainerNetworkMeasurement to serialize.Point.
+func (m *ContainerNetworkMeasurement) ToPoint(p *data.Point) {
+	m.ToPointAllInt64(p, labelContainerNetwork, containerNetworkFields)
+}
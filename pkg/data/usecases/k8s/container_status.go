@@ -0,0 +1,62 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// restartThreshold is the value a uniform [0,1) draw has to reach for a
+	// container to restart in a given tick.
+	restartThreshold = 0.995
+)
+
+var (
+	labelContainerStatus = []byte("container_status") // heap optimization
+
+	containerStatusFieldKeys = [][]byte{
+		[]byte("restarts_total"),
+		[]byte("ready"),
+	}
+)
+
+// ContainerStatusMeasurement models the restart counter and readiness of a pod's container.
+type ContainerStatusMeasurement struct {
+	*common.SubsystemMeasurement
+	lastRestarts float64
+	ready        int64
+}
+
+// NewContainerStatusMeasurement creates a new ContainerStatusMeasurement with start time.
+func NewContainerStatusMeasurement(start time.Time) *ContainerStatusMeasurement {
+	sub := common.NewSubsystemMeasurement(start, 1)
+	// restarts only advance when the motive fires above the threshold
+	sub.Distributions[0] = common.LD(common.UD(0, 1), common.MWD(&common.ConstantDistribution{State: 1}, 0), restartThreshold)
+	return &ContainerStatusMeasurement{
+		SubsystemMeasurement: sub,
+		ready:                1,
+	}
+}
+
+// Tick advances the restart counter. A container is reported as not ready
+// in the tick it restarted in.
+func (m *ContainerStatusMeasurement) Tick(d time.Duration) {
+	m.SubsystemMeasurement.Tick(d)
+	restarts := m.Distributions[0].Get()
+	m.ready = 1
+	if restarts != m.lastRestarts {
+		m.ready = 0
+	}
+	m.lastRestarts = restarts
+}
+
+// ToPoint serializes ContainerStatusMeasurement to serialize.Point.
+func (m *ContainerStatusMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelContainerStatus)
+	p.SetTimestamp(&m.Timestamp)
+
+	p.AppendField(containerStatusFieldKeys[0], int64(m.Distributions[0].Get()))
+	p.AppendField(containerStatusFieldKeys[1], m.ready)
+}
@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestContainerMeasurementsToPoint(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		m      interface{ ToPoint(*data.Point) }
+		name   []byte
+		fields int
+	}{
+		{m: NewContainerCPUMeasurement(now), name: labelContainerCPU, fields: len(containerCPUFields)},
+		{m: NewContainerMemoryMeasurement(now), name: labelContainerMemory, fields: len(containerMemoryFieldKeys)},
+		{m: NewContainerNetworkMeasurement(now), name: labelContainerNetwork, fields: len(containerNetworkFields)},
+		{m: NewContainerStatusMeasurement(now), name: labelContainerStatus, fields: len(containerStatusFieldKeys)},
+	}
+	for _, c := range cases {
+		p := data.NewPoint()
+		c.m.ToPoint(p)
+		if got := string(p.MeasurementName()); got != string(c.name) {
+			t.Errorf("incorrect measurement name: got %s want %s", got, c.name)
+		}
+		if got := len(p.FieldKeys()); got != c.fields {
+			t.Errorf("incorrect field count for %s: got %d want %d", c.name, got, c.fields)
+		}
+		if got := *p.Timestamp(); got != now {
+			t.Errorf("incorrect timestamp for %s: got %v want %v", c.name, got, now)
+		}
+	}
+}
+
+func TestContainerMemoryMeasurementLimit(t *testing.T) {
+	m := NewContainerMemoryMeasurement(time.Now())
+	for i := 0; i < 1000; i++ {
+		m.Tick(time.Second)
+		p := data.NewPoint()
+		m.ToPoint(p)
+		ws := p.GetFieldValue(containerMemoryFieldKeys[0]).(int64)
+		limit := p.GetFieldValue(containerMemoryFieldKeys[3]).(int64)
+		if ws > limit || ws < 0 {
+			t.Fatalf("working set out of range: got %d limit %d", ws, limit)
+		}
+	}
+}
+
+func TestContainerStatusMeasurementTick(t *testing.T) {
+	m := NewContainerStatusMeasurement(time.Now())
+	last := int64(0)
+	for i := 0; i < 5000; i++ {
+		m.Tick(time.Second)
+		p := data.NewPoint()
+		m.ToPoint(p)
+		restarts := p.GetFieldValue(containerStatusFieldKeys[0]).(int64)
+		ready := p.GetFieldValue(containerStatusFieldKeys[1]).(int64)
+		if restarts < last {
+			t.Fatalf("restarts decreased: got %d after %d", restarts, last)
+		}
+		want := int64(1)
+		if restarts != last {
+			want = 0
+		}
+		if ready != want {
+			t.Errorf("incorrect ready (restarts %d -> %d): got %d want %d", last, restarts, ready, want)
+		}
+		last = restarts
+	}
+	if last == 0 {
+		t.Errorf("container never restarted")
+	}
+}
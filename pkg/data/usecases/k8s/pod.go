@@ -0,0 +1,163 @@
+package k8s
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// PodsPerNode is the number of pod slots scheduled on every node.
+	PodsPerNode = 10
+	// NodesPerCluster is the number of nodes in every cluster.
+	NodesPerCluster = 10
+	// ReplicasPerDeployment is the number of pod slots owned by every deployment.
+	ReplicasPerDeployment = 3
+
+	clusterNameFmt    = "cluster_%d"
+	nodeNameFmt       = "node_%d"
+	deploymentNameFmt = "deployment_%d"
+	podNameFmt        = "%s-%s"
+
+	// podSuffixAlphabet mirrors the characters Kubernetes uses for generated names.
+	podSuffixAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+	podSuffixLen      = 5
+)
+
+var (
+	// NamespaceChoices contains all the namespace values for the k8s use case.
+	NamespaceChoices = []string{
+		"default",
+		"kube-system",
+		"monitoring",
+		"ingress",
+		"payments",
+		"checkout",
+		"search",
+		"analytics",
+	}
+
+	labelPod        = []byte("pod")
+	labelNamespace  = []byte("namespace")
+	labelDeployment = []byte("deployment")
+	labelNode       = []byte("node")
+	labelCluster    = []byte("cluster")
+)
+
+// NodeCount returns the number of nodes needed to schedule podCount pod slots.
+func NodeCount(podCount int) int {
+	return (podCount + PodsPerNode - 1) / PodsPerNode
+}
+
+// NodeName returns the name of the node the i-th pod slot is scheduled on.
+func NodeName(i int) string {
+	return fmt.Sprintf(nodeNameFmt, i/PodsPerNode)
+}
+
+// ClusterName returns the name of the cluster the i-th pod slot belongs to.
+func ClusterName(i int) string {
+	return fmt.Sprintf(clusterNameFmt, i/PodsPerNode/NodesPerCluster)
+}
+
+// DeploymentName returns the name of the deployment owning the i-th pod slot.
+func DeploymentName(i int) string {
+	return fmt.Sprintf(deploymentNameFmt, i/ReplicasPerDeployment)
+}
+
+// Namespace returns the namespace of the deployment owning the i-th pod slot.
+func Namespace(i int) string {
+	return NamespaceChoices[(i/ReplicasPerDeployment)%len(NamespaceChoices)]
+}
+
+// Pod models one replica slot of a deployment. The pod running in the slot is
+// replaced when its lifetime runs out: it gets a new name and fresh container
+// metrics, so every replacement starts a new set of series.
+type Pod struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+
+	deployment   string
+	now          time.Time
+	meanLifetime time.Duration
+	lifetime     time.Duration
+	age          time.Duration
+	// Replacements is the number of times the pod in this slot was replaced.
+	Replacements uint64
+}
+
+// NewPod creates the i-th pod slot in a simulated k8s use case.
+func NewPod(i int, start time.Time, meanLifetime time.Duration) *Pod {
+	deployment := DeploymentName(i)
+	p := &Pod{
+		tags: []common.Tag{
+			{Key: labelPod, Value: newPodName(deployment)},
+			{Key: labelNamespace, Value: Namespace(i)},
+			{Key: labelDeployment, Value: deployment},
+			{Key: labelNode, Value: NodeName(i)},
+			{Key: labelCluster, Value: ClusterName(i)},
+		},
+		simulatedMeasurements: newPodMeasurements(start),
+		deployment:            deployment,
+		now:                   start,
+		meanLifetime:          meanLifetime,
+	}
+	p.lifetime = p.newLifetime()
+	return p
+}
+
+// TickAll advances all Distributions of a Pod. When the pod outlives its
+// lifetime it is replaced by a new pod in the same slot.
+func (p *Pod) TickAll(d time.Duration) {
+	p.now = p.now.Add(d)
+	p.age += d
+	if p.meanLifetime > 0 && p.age >= p.lifetime {
+		p.replace()
+		return
+	}
+	for i := range p.simulatedMeasurements {
+		p.simulatedMeasurements[i].Tick(d)
+	}
+}
+
+// Measurements returns the pod measurements.
+func (p *Pod) Measurements() []common.SimulatedMeasurement {
+	return p.simulatedMeasurements
+}
+
+// Tags returns the pod tags.
+func (p *Pod) Tags() []common.Tag {
+	return p.tags
+}
+
+func (p *Pod) replace() {
+	p.tags[0].Value = newPodName(p.deployment)
+	p.simulatedMeasurements = newPodMeasurements(p.now)
+	p.age = 0
+	p.lifetime = p.newLifetime()
+	p.Replacements++
+}
+
+// newLifetime draws an exponentially distributed lifetime, so replacements
+// are spread evenly over time instead of happening in waves.
+func (p *Pod) newLifetime() time.Duration {
+	return time.Duration(rand.ExpFloat64() * float64(p.meanLifetime))
+}
+
+func newPodName(deployment string) string {
+	suffix := make([]byte, podSuffixLen)
+	for i := range suffix {
+		suffix[i] = podSuffixAlphabet[rand.Intn(len(podSuffixAlphabet))]
+	}
+	return fmt.Sprintf(podNameFmt, deployment, suffix)
+}
+
+func newPodMeasurements(start time.Time) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewContainerCPUMeasurement(start),
+		NewContainerMemoryMeasurement(start),
+		NewContainerNetworkMeasurement(start),
+		NewContainerStatusMeasurement(start),
+	}
+}
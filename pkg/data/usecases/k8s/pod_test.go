@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTopology(t *testing.T) {
+	cases := []struct {
+		i          int
+		node       string
+		cluster    string
+		deployment string
+		namespace  string
+	}{
+		{i: 0, node: "node_0", cluster: "cluster_0", deployment: "deployment_0", namespace: "default"},
+		{i: 4, node: "node_0", cluster: "cluster_0", deployment: "deployment_1", namespace: "kube-system"},
+		{i: 10, node: "node_1", cluster: "cluster_0", deployment: "deployment_3", namespace: "ingress"},
+		{i: 100, node: "node_10", cluster: "cluster_1", deployment: "deployment_33", namespace: "kube-system"},
+	}
+	for _, c := range cases {
+		if got := NodeName(c.i); got != c.node {
+			t.Errorf("incorrect node for %d: got %s want %s", c.i, got, c.node)
+		}
+		if got := ClusterName(c.i); got != c.cluster {
+			t.Errorf("incorrect cluster for %d: got %s want %s", c.i, got, c.cluster)
+		}
+		if got := DeploymentName(c.i); got != c.deployment {
+			t.Errorf("incorrect deployment for %d: got %s want %s", c.i, got, c.deployment)
+		}
+		if got := Namespace(c.i); got != c.namespace {
+			t.Errorf("incorrect namespace for %d: got %s want %s", c.i, got, c.namespace)
+		}
+	}
+
+	if got := NodeCount(21); got != 3 {
+		t.Errorf("incorrect node count: got %d want %d", got, 3)
+	}
+}
+
+func TestNewPod(t *testing.T) {
+	start := time.Now()
+	p := NewPod(4, start, time.Hour)
+
+	if got := len(p.Measurements()); got != 4 {
+		t.Errorf("incorrect pod measurement count: got %d want %d", got, 4)
+	}
+	tags := p.Tags()
+	if got := len(tags); got != 5 {
+		t.Fatalf("incorrect pod tag count: got %d want %d", got, 5)
+	}
+	name := tags[0].Value.(string)
+	if !strings.HasPrefix(name, "deployment_1-") || len(name) != len("deployment_1-")+podSuffixLen {
+		t.Errorf("incorrect pod name: %s", name)
+	}
+	if got := tags[1].Value; got != "kube-system" {
+		t.Errorf("incorrect namespace: got %v", got)
+	}
+}
+
+func TestPodTickAllNoChurn(t *testing.T) {
+	start := time.Now()
+	p := NewPod(0, start, 0)
+	name := p.Tags()[0].Value
+
+	for i := 0; i < 100; i++ {
+		p.TickAll(time.Hour)
+	}
+	if got := p.Tags()[0].Value; got != name {
+		t.Errorf("pod replaced with churn disabled: got %v want %v", got, name)
+	}
+	if got := p.Measurements()[0].(*ContainerCPUMeasurement).Timestamp; got != start.Add(100*time.Hour) {
+		t.Errorf("incorrect timestamp: got %v want %v", got, start.Add(100*time.Hour))
+	}
+}
+
+func TestPodTickAllChurn(t *testing.T) {
+	rand.Seed(123)
+	start := time.Now()
+	p := NewPod(0, start, time.Minute)
+	names := map[interface{}]bool{p.Tags()[0].Value: true}
+
+	for i := 0; i < 100; i++ {
+		p.TickAll(time.Minute)
+		names[p.Tags()[0].Value] = true
+		if got := p.Measurements()[0].(*ContainerCPUMeasurement).Timestamp; got != start.Add(time.Duration(i+1)*time.Minute) {
+			t.Fatalf("incorrect timestamp after tick %d: got %v", i, got)
+		}
+	}
+	if p.Replacements < 30 {
+		t.Errorf("too few replacements for a one minute mean lifetime: got %d", p.Replacements)
+	}
+	if got := uint64(len(names)); got != p.Replacements+1 {
+		t.Errorf("incorrect number of distinct pod names: got %d want %d", got, p.Replacements+1)
+	}
+	if got := p.Tags()[2].Value; got != "deployment_0" {
+		t.Errorf("deployment changed on replacement: got %v", got)
+	}
+}
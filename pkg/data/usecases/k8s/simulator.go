@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a k8s Simulator.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	Start time.Time
+	End   time.Time
+
+	// InitPodCount is the number of pod slots to start with in the first reporting period
+	InitPodCount uint64
+	// PodCount is the total number of pod slots to have in the last reporting period
+	PodCount uint64
+	// PodMeanLifetime is the mean time a pod lives before it is replaced; 0 disables churn
+	PodMeanLifetime time.Duration
+}
+
+// NewSimulator produces a k8s Simulator with the given config over the
+// specified interval and points limit. Pod slots are regular generators of a
+// common.BaseSimulator; churn happens inside each Pod as it ticks.
+func (c *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	base := &common.BaseSimulatorConfig{
+		Start: c.Start,
+		End:   c.End,
+
+		InitGeneratorScale: c.InitPodCount,
+		GeneratorScale:     c.PodCount,
+		GeneratorConstructor: func(i int, start time.Time) common.Generator {
+			return NewPod(i, start, c.PodMeanLifetime)
+		},
+	}
+	return base.NewSimulator(interval, limit)
+}
+
+// Fields returns the fields of the measurements of a pod, without creating
+// one.
+func Fields() map[string][]string {
+	return map[string][]string{
+		string(labelContainerCPU):     common.Labels(containerCPUFields),
+		string(labelContainerMemory):  common.KeysToStrings(containerMemoryFieldKeys),
+		string(labelContainerNetwork): common.Labels(containerNetworkFields),
+		string(labelContainerStatus):  common.KeysToStrings(containerStatusFieldKeys),
+	}
+}
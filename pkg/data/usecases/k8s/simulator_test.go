@@ -0,0 +1,44 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestSimulatorChurn(t *testing.T) {
+	start := time.Now()
+	sc := &SimulatorConfig{
+		Start:           start,
+		End:             start.Add(6 * time.Hour),
+		InitPodCount:    10,
+		PodCount:        10,
+		PodMeanLifetime: 30 * time.Minute,
+	}
+	sim := sc.NewSimulator(time.Minute, 0)
+
+	if got := sim.TagKeys(); len(got) != 5 || got[0] != "pod" {
+		t.Errorf("incorrect tag keys: got %v", got)
+	}
+	if got := len(sim.Fields()); got != 4 {
+		t.Errorf("incorrect measurement count: got %d want %d", got, 4)
+	}
+
+	pods := map[string]bool{}
+	p := data.NewPoint()
+	count := 0
+	for !sim.Finished() {
+		sim.Next(p)
+		pods[p.GetTagValue(labelPod).(string)] = true
+		p.Reset()
+		count++
+	}
+	if want := 6 * 60 * 10 * 4; count != want {
+		t.Errorf("incorrect point count: got %d want %d", count, want)
+	}
+	// every slot replaces its pod about 12 times over 6 hours
+	if len(pods) < 50 {
+		t.Errorf("too little churn: got %d distinct pods", len(pods))
+	}
+}
@@ -4,23 +4,38 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/custom"
 	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/data/usecases/finance"
 	"github.com/timescale/tsbs/pkg/data/usecases/iot"
+	"github.com/timescale/tsbs/pkg/data/usecases/k8s"
 	"math"
 )
 
-const errCannotParseTimeFmt = "cannot parse time from string '%s': %v"
+const (
+	errCannotParseTimeFmt    = "cannot parse time from string '%s': %v"
+	errInitialScaleTooBigFmt = "initial scale %d is larger than the scale %d of use case '%s'"
+)
 
 func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig, error) {
+	ret, _, err := getSimulatorConfig(dgc)
+	return ret, err
+}
+
+// getSimulatorConfig returns the config of the use case along with the fields
+// of the simulators it creates, which are known without creating one. The
+// fields of UseCaseMulti are nil, as it cannot be combined itself.
+func getSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig, map[string][]string, error) {
 	var ret common.SimulatorConfig
+	var fields map[string][]string
 	var err error
 	tsStart, err := utils.ParseUTCTime(dgc.TimeStart)
 	if err != nil {
-		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeStart, err)
+		return nil, nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeStart, err)
 	}
 	tsEnd, err := utils.ParseUTCTime(dgc.TimeEnd)
 	if err != nil {
-		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
+		return nil, nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
 	}
 
 	switch dgc.Use {
@@ -32,7 +47,10 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHost,
+			Shape:           dgc.Shape(),
+			Outage:          dgc.Outage(),
 		}
+		fields = devops.HostFields()
 	case common.UseCaseIoT:
 		ret = &iot.SimulatorConfig{
 			Start: tsStart,
@@ -41,7 +59,9 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitGeneratorScale:   dgc.InitialScale,
 			GeneratorScale:       dgc.Scale,
 			GeneratorConstructor: iot.NewTruck,
+			Shape:                dgc.Shape(),
 		}
+		fields = iot.Fields()
 	case common.UseCaseCPUOnly:
 		ret = &devops.CPUOnlySimulatorConfig{
 			Start: tsStart,
@@ -50,7 +70,10 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHostCPUOnly,
+			Shape:           dgc.Shape(),
+			Outage:          dgc.Outage(),
 		}
+		fields = devops.HostCPUOnlyFields()
 	case common.UseCaseCPUSingle:
 		ret = &devops.CPUOnlySimulatorConfig{
 			Start: tsStart,
@@ -59,7 +82,21 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHostCPUSingle,
+			Shape:           dgc.Shape(),
+			Outage:          dgc.Outage(),
 		}
+		fields = devops.HostCPUSingleFields()
+	case common.UseCaseDevopsStatus:
+		ret = &devops.DevopsSimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			InitHostCount:   dgc.InitialScale,
+			HostCount:       dgc.Scale,
+			HostConstructor: devops.NewHostStatus,
+			Outage:          dgc.Outage(),
+		}
+		fields = devops.HostStatusFields()
 	case common.UseCaseDevopsGeneric:
 		if dgc.InitialScale == dgc.Scale {
 			// if no initial scale argument given we will start with 50%. The lower bound is 1
@@ -74,10 +111,93 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 				HostCount:       dgc.Scale,
 				HostConstructor: devops.NewHostGenericMetrics,
 				MaxMetricCount:  dgc.MaxMetricCountPerHost,
+				Outage:          dgc.Outage(),
 			},
 		}
+		fields = devops.HostGenericMetricsFields(dgc.MaxMetricCountPerHost)
+	case common.UseCaseK8s:
+		ret = &k8s.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			InitPodCount:    dgc.InitialScale,
+			PodCount:        dgc.Scale,
+			PodMeanLifetime: dgc.PodMeanLifetime,
+		}
+		fields = k8s.Fields()
+	case common.UseCaseFinance:
+		ret = &finance.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			SymbolCount: dgc.Scale,
+		}
+		fields = finance.Fields()
+	case common.UseCaseCustom:
+		schema, err := custom.LoadSchema(dgc.CustomSchema)
+		if err != nil {
+			return nil, nil, err
+		}
+		// entity count and interval declared in the schema are defaults, the
+		// scale and log interval given override them
+		initialScale, scale := dgc.InitialScale, dgc.Scale
+		if schema.Entities.Count > 0 && !dgc.ScaleGiven {
+			if initialScale == scale {
+				initialScale = schema.Entities.Count
+			}
+			scale = schema.Entities.Count
+		}
+		sc := &custom.SimulatorConfig{
+			BaseSimulatorConfig: common.BaseSimulatorConfig{
+				Start: tsStart,
+				End:   tsEnd,
+
+				InitGeneratorScale:   initialScale,
+				GeneratorScale:       scale,
+				GeneratorConstructor: schema.NewEntity,
+			},
+		}
+		if !dgc.LogIntervalGiven {
+			sc.Interval = schema.Interval
+		}
+		ret = sc
+		fields = schema.Fields()
+	case common.UseCaseMulti:
+		ret, err = getMultiSimulatorConfig(dgc)
 	default:
 		err = fmt.Errorf("unknown use case: '%s'", dgc.Use)
 	}
-	return ret, err
+	return ret, fields, err
+}
+
+// getMultiSimulatorConfig creates the config of each use case listed in
+// dgc.UseCases, with its own scale, and combines them.
+func getMultiSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig, error) {
+	cases, err := common.ParseUseCases(dgc.UseCases, dgc.Scale)
+	if err != nil {
+		return nil, err
+	}
+	ret := &common.MultiSimulatorConfig{}
+	for _, uc := range cases {
+		caseConfig := *dgc
+		caseConfig.Use = uc.Use
+		caseConfig.UseCases = ""
+		caseConfig.Scale = uc.Scale
+		caseConfig.ScaleGiven = dgc.ScaleGiven || uc.ScaleGiven
+		// without an initial scale of its own, each use case starts at its scale
+		if dgc.InitialScale == dgc.Scale {
+			caseConfig.InitialScale = uc.Scale
+		} else if dgc.InitialScale > uc.Scale {
+			return nil, fmt.Errorf(errInitialScaleTooBigFmt, dgc.InitialScale, uc.Scale, uc.Use)
+		}
+		scfg, fields, err := getSimulatorConfig(&caseConfig)
+		if err != nil {
+			return nil, err
+		}
+		ret.Cases = append(ret.Cases, common.MultiSimulatorCase{Use: uc.Use, Config: scfg, Fields: fields})
+	}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
@@ -2,8 +2,14 @@ package usecases
 
 import (
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/custom"
 	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/data/usecases/finance"
 	"github.com/timescale/tsbs/pkg/data/usecases/iot"
+	"github.com/timescale/tsbs/pkg/data/usecases/k8s"
+	"io/ioutil"
+	"math/rand"
+	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -38,6 +44,12 @@ func TestGetSimulatorConfig(t *testing.T) {
 	checkType(common.UseCaseIoT, &iot.SimulatorConfig{})
 	checkType(common.UseCaseCPUOnly, &devops.CPUOnlySimulatorConfig{})
 	checkType(common.UseCaseCPUSingle, &devops.CPUOnlySimulatorConfig{})
+	checkType(common.UseCaseK8s, &k8s.SimulatorConfig{})
+	checkType(common.UseCaseFinance, &finance.SimulatorConfig{})
+	checkType(common.UseCaseDevopsStatus, &devops.DevopsSimulatorConfig{})
+	dgc.UseCases = "devops,iot"
+	checkType(common.UseCaseMulti, &common.MultiSimulatorConfig{})
+	dgc.UseCases = ""
 
 	dgc.Use = "bogus use case"
 	_, err := GetSimulatorConfig(dgc)
@@ -45,3 +57,286 @@ func TestGetSimulatorConfig(t *testing.T) {
 		t.Errorf("unexpected lack of error for bogus use case")
 	}
 }
+
+func TestGetSimulatorConfigCustom(t *testing.T) {
+	f, err := ioutil.TempFile("", "custom-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	schema := `
+entities:
+  count: 7
+interval: 1m
+measurements:
+  - name: m
+    fields:
+      - name: f
+        distribution: {type: ND, mean: 0, stddev: 1}
+`
+	if _, err := f.WriteString(schema); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       common.UseCaseCustom,
+			Scale:     1,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T01:00:00Z",
+		},
+		InitialScale: 1,
+		LogInterval:  defaultLogInterval,
+		CustomSchema: f.Name(),
+	}
+	scfg, err := GetSimulatorConfig(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sc, ok := scfg.(*custom.SimulatorConfig)
+	if !ok {
+		t.Fatalf("use '%s' does not give right scfg: got %T", common.UseCaseCustom, scfg)
+	}
+	if sc.GeneratorScale != 7 || sc.InitGeneratorScale != 7 {
+		t.Errorf("schema entity count not applied: got %d/%d want 7/7", sc.InitGeneratorScale, sc.GeneratorScale)
+	}
+	if sc.Interval != time.Minute {
+		t.Errorf("schema interval not applied: got %v want %v", sc.Interval, time.Minute)
+	}
+	if dgc.Scale != 1 || dgc.InitialScale != 1 || dgc.LogInterval != defaultLogInterval {
+		t.Errorf("config changed by the schema: got %d/%d %v", dgc.InitialScale, dgc.Scale, dgc.LogInterval)
+	}
+
+	// a scale and log interval given override the schema
+	dgc.Scale, dgc.InitialScale, dgc.ScaleGiven = 3, 3, true
+	dgc.LogInterval, dgc.LogIntervalGiven = defaultLogInterval, true
+	scfg, err = GetSimulatorConfig(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sc = scfg.(*custom.SimulatorConfig)
+	if sc.GeneratorScale != 3 || sc.InitGeneratorScale != 3 {
+		t.Errorf("given scale not kept: got %d/%d want 3/3", sc.InitGeneratorScale, sc.GeneratorScale)
+	}
+	if sc.Interval != 0 {
+		t.Errorf("given log interval not kept: got schema interval %v", sc.Interval)
+	}
+
+	dgc.CustomSchema = f.Name() + ".missing"
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for missing schema")
+	}
+}
+
+func TestGetSimulatorConfigMulti(t *testing.T) {
+	f, err := ioutil.TempFile("", "custom-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	schema := `
+interval: 1m
+measurements:
+  - name: m
+    fields:
+      - name: f
+        distribution: {type: ND, mean: 0, stddev: 1}
+`
+	if _, err := f.WriteString(schema); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       common.UseCaseMulti,
+			Scale:     2,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T00:02:00Z",
+		},
+		InitialScale: 2,
+		LogInterval:  defaultLogInterval,
+		CustomSchema: f.Name(),
+		UseCases:     "devops=3,iot=4,custom",
+	}
+	scfg, err := GetSimulatorConfig(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mc, ok := scfg.(*common.MultiSimulatorConfig)
+	if !ok {
+		t.Fatalf("use '%s' does not give right scfg: got %T", common.UseCaseMulti, scfg)
+	}
+	if got := len(mc.Cases); got != 3 {
+		t.Fatalf("incorrect number of cases: got %d want 3", got)
+	}
+	if got := mc.Cases[0].Config.(*devops.DevopsSimulatorConfig).HostCount; got != 3 {
+		t.Errorf("incorrect devops scale: got %d want 3", got)
+	}
+	if got := mc.Cases[1].Config.(*iot.SimulatorConfig).GeneratorScale; got != 4 {
+		t.Errorf("incorrect iot scale: got %d want 4", got)
+	}
+	if got := mc.Cases[2].Config.(*custom.SimulatorConfig).GeneratorScale; got != 2 {
+		t.Errorf("incorrect custom scale: got %d want 2", got)
+	}
+	if got := mc.Cases[2].Config.(*custom.SimulatorConfig).Interval; got != time.Minute {
+		t.Errorf("incorrect custom interval: got %v want %v", got, time.Minute)
+	}
+	if dgc.LogInterval != defaultLogInterval {
+		t.Errorf("log interval changed by a case: got %v", dgc.LogInterval)
+	}
+
+	sim := scfg.NewSimulator(dgc.LogInterval, 0)
+	headers := sim.Headers()
+	for _, measurement := range []string{"cpu", "readings", "m"} {
+		if _, ok := headers.FieldKeys[measurement]; !ok {
+			t.Errorf("measurement %s missing from headers", measurement)
+		}
+	}
+	if got, want := headers.TagKeys[0], "hostname"; got != want {
+		t.Errorf("incorrect first tag key: got %s want %s", got, want)
+	}
+
+	dgc.UseCases = "devops,cpu-only"
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for use cases sharing hosts")
+	}
+
+	// an initial scale given on its own applies to every use case
+	dgc.UseCases = "devops=3,iot=4"
+	dgc.InitialScale = 1
+	scfg, err = GetSimulatorConfig(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mc = scfg.(*common.MultiSimulatorConfig)
+	if got := mc.Cases[0].Config.(*devops.DevopsSimulatorConfig).InitHostCount; got != 1 {
+		t.Errorf("incorrect devops initial scale: got %d want 1", got)
+	}
+	if got := mc.Cases[1].Config.(*iot.SimulatorConfig).InitGeneratorScale; got != 1 {
+		t.Errorf("incorrect iot initial scale: got %d want 1", got)
+	}
+	dgc.InitialScale = 4
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for initial scale larger than a use case scale")
+	}
+}
+
+func TestGetSimulatorConfigMultiMeasurementClash(t *testing.T) {
+	f, err := ioutil.TempFile("", "custom-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	// iot simulates readings with other fields
+	schema := `
+measurements:
+  - name: readings
+    fields:
+      - name: f
+        distribution: {type: ND, mean: 0, stddev: 1}
+`
+	if _, err := f.WriteString(schema); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       common.UseCaseMulti,
+			Scale:     2,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T00:02:00Z",
+		},
+		InitialScale: 2,
+		LogInterval:  defaultLogInterval,
+		CustomSchema: f.Name(),
+		UseCases:     "iot,custom",
+	}
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for use cases with clashing measurements")
+	}
+}
+
+func TestGetSimulatorConfigFields(t *testing.T) {
+	f, err := ioutil.TempFile("", "custom-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	schema := `
+measurements:
+  - name: m
+    fields:
+      - name: a
+        distribution: {type: ND, mean: 0, stddev: 1}
+      - name: b
+        int: true
+        distribution: {type: UD, low: 0, high: 10}
+`
+	if _, err := f.WriteString(schema); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Scale:     3,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T00:02:00Z",
+		},
+		InitialScale:          3,
+		LogInterval:           defaultLogInterval,
+		MaxMetricCountPerHost: 5,
+		CustomSchema:          f.Name(),
+	}
+	// the fields known without a simulator are those of the simulator
+	for _, use := range []string{
+		common.UseCaseDevops,
+		common.UseCaseIoT,
+		common.UseCaseCPUOnly,
+		common.UseCaseCPUSingle,
+		common.UseCaseDevopsStatus,
+		common.UseCaseDevopsGeneric,
+		common.UseCaseK8s,
+		common.UseCaseFinance,
+		common.UseCaseCustom,
+	} {
+		caseConfig := *dgc
+		caseConfig.Use = use
+		scfg, fields, err := getSimulatorConfig(&caseConfig)
+		if err != nil {
+			t.Errorf("unexpected error with use case %s: %v", use, err)
+			continue
+		}
+		want := scfg.NewSimulator(caseConfig.LogInterval, 0).Fields()
+		if !reflect.DeepEqual(fields, want) {
+			t.Errorf("incorrect fields of use case %s: got %v want %v", use, fields, want)
+		}
+	}
+}
+
+func TestGetSimulatorConfigMultiKeepsRandomNumbers(t *testing.T) {
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       common.UseCaseMulti,
+			Scale:     2,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T00:02:00Z",
+		},
+		InitialScale: 2,
+		LogInterval:  defaultLogInterval,
+		UseCases:     "devops,iot,k8s,finance",
+	}
+	rand.Seed(123)
+	want := rand.Int63()
+
+	rand.Seed(123)
+	if _, err := GetSimulatorConfig(dgc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rand.Int63(); got != want {
+		t.Errorf("validating the use cases consumed random numbers")
+	}
+}
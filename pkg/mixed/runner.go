@@ -0,0 +1,246 @@
+// Package mixed runs a load benchmark and a query benchmark against the same
+// target at the same time, reporting ingest throughput and query latencies
+// side by side for every reporting period.
+package mixed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const ResultVersion = "0.1"
+
+// change for more useful testing
+var printFn = fmt.Printf
+
+// Config is the configuration of the mixed runner itself; the loader and the
+// query runner are configured separately.
+type Config struct {
+	ReportingPeriod time.Duration `yaml:"reporting-period" mapstructure:"reporting-period" json:"reporting-period"`
+	ResultsFile     string        `yaml:"results-file" mapstructure:"results-file" json:"results-file"`
+}
+
+// AddToFlagSet adds command line flags needed by the Config to the flag set.
+func (c Config) AddToFlagSet(fs *pflag.FlagSet) {
+	fs.Duration("reporting-period", 10*time.Second, "Period to report ingest and query stats")
+	fs.String("results-file", "", "Write the combined per period results json to this file")
+}
+
+// PeriodResult holds the ingest and query stats of one reporting period.
+type PeriodResult struct {
+	Time          int64   `json:"Time"`
+	MetricRate    float64 `json:"MetricRate"`
+	MetricTotal   uint64  `json:"MetricTotal"`
+	RowRate       float64 `json:"RowRate"`
+	RowTotal      uint64  `json:"RowTotal"`
+	QueryRate     float64 `json:"QueryRate"`
+	QueryTotal    uint64  `json:"QueryTotal"`
+	QueryErrors   uint64  `json:"QueryErrors"`
+	LatencyMedian float64 `json:"LatencyMedian"`
+	LatencyP95    float64 `json:"LatencyP95"`
+	LatencyP99    float64 `json:"LatencyP99"`
+	LatencyMax    float64 `json:"LatencyMax"`
+}
+
+// Result aggregates the results of a mixed benchmark.
+type Result struct {
+	ResultFormatVersion string         `json:"ResultFormatVersion"`
+	RunnerConfig        Config         `json:"RunnerConfig"`
+	StartTime           int64          `json:"StartTime"`
+	EndTime             int64          `json:"EndTime"`
+	DurationMillis      int64          `json:"DurationMillis"`
+	Periods             []PeriodResult `json:"Periods"`
+}
+
+// Runner runs a loader and a query runner concurrently and collects their
+// stats in common reporting periods.
+type Runner struct {
+	Config
+	metricCnt uint64
+	rowCnt    uint64
+
+	// mu guards the query stats, which are recorded by all query workers
+	mu         sync.Mutex
+	queryCnt   uint64
+	errCnt     uint64
+	latencies  *hdrhistogram.Histogram // latencies of the current period
+	prevMetric uint64
+	prevRow    uint64
+	prevQuery  uint64
+	prevErr    uint64
+	prevTime   time.Time
+	periods    []PeriodResult
+}
+
+// NewRunner creates a new mixed runner.
+func NewRunner(c Config) *Runner {
+	return &Runner{
+		Config:    c,
+		latencies: newLatencyHistogram(),
+	}
+}
+
+func newLatencyHistogram() *hdrhistogram.Histogram {
+	// same range and precision as the query runner, in microseconds
+	return hdrhistogram.New(1, 3600000000, 4)
+}
+
+// Run loads the data of `b` with `loader` while running the queries of
+// `queries`, and returns once both are done. The loader and the query runner
+// should have their own periodic reporting disabled.
+func (r *Runner) Run(loader load.BenchmarkRunner, b targets.Benchmark, queries *query.BenchmarkRunner,
+	queryPool *sync.Pool, createFn query.ProcessorCreate) {
+	start := time.Now()
+	r.prevTime = start
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		loader.RunBenchmark(&countingBenchmark{Benchmark: b, r: r})
+		wg.Done()
+	}()
+	queries.SetStatsObserver(r.recordStats)
+	go func() {
+		queries.Run(queryPool, func() query.Processor {
+			return &recordingProcessor{Processor: createFn(), r: r}
+		})
+		wg.Done()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if r.ReportingPeriod > 0 {
+		printFn("time,per. metric/s,metric total,per. row/s,row total,per. query/s,query total,query errors,med ms,p95 ms,p99 ms,max ms\n")
+		ticker := time.NewTicker(r.ReportingPeriod)
+	loop:
+		for {
+			select {
+			case now := <-ticker.C:
+				r.report(now)
+			case <-done:
+				break loop
+			}
+		}
+		ticker.Stop()
+	} else {
+		<-done
+	}
+
+	end := time.Now()
+	if r.ReportingPeriod > 0 {
+		// the last, shorter, period
+		r.report(end)
+	}
+	r.summary(end.Sub(start))
+	if len(r.ResultsFile) > 0 {
+		r.saveResult(start, end)
+	}
+}
+
+// recordStats records the latencies of a cold query run, leaving out the
+// partial stats of its parts.
+func (r *Runner) recordStats(stats []*query.Stat) {
+	for _, s := range stats {
+		if !s.IsPartial() {
+			r.recordLatency(s.Value())
+		}
+	}
+}
+
+func (r *Runner) recordLatency(millis float64) {
+	r.mu.Lock()
+	r.queryCnt++
+	r.latencies.RecordValue(int64(millis * 1e3))
+	r.mu.Unlock()
+}
+
+func (r *Runner) recordError() {
+	r.mu.Lock()
+	r.errCnt++
+	r.mu.Unlock()
+}
+
+// report closes the current reporting period, prints its stats and keeps
+// them for the results file.
+func (r *Runner) report(now time.Time) {
+	metricCnt := atomic.LoadUint64(&r.metricCnt)
+	rowCnt := atomic.LoadUint64(&r.rowCnt)
+
+	r.mu.Lock()
+	queryCnt, errCnt := r.queryCnt, r.errCnt
+	latencies := r.latencies
+	r.latencies = newLatencyHistogram()
+	r.mu.Unlock()
+
+	took := now.Sub(r.prevTime).Seconds()
+	p := PeriodResult{
+		Time:          now.Unix(),
+		MetricRate:    float64(metricCnt-r.prevMetric) / took,
+		MetricTotal:   metricCnt,
+		RowRate:       float64(rowCnt-r.prevRow) / took,
+		RowTotal:      rowCnt,
+		QueryRate:     float64(queryCnt-r.prevQuery) / took,
+		QueryTotal:    queryCnt,
+		QueryErrors:   errCnt - r.prevErr,
+		LatencyMedian: float64(latencies.ValueAtQuantile(50.0)) / 1e3,
+		LatencyP95:    float64(latencies.ValueAtQuantile(95.0)) / 1e3,
+		LatencyP99:    float64(latencies.ValueAtQuantile(99.0)) / 1e3,
+		LatencyMax:    float64(latencies.Max()) / 1e3,
+	}
+	r.periods = append(r.periods, p)
+	r.prevMetric, r.prevRow, r.prevQuery, r.prevErr, r.prevTime = metricCnt, rowCnt, queryCnt, errCnt, now
+
+	printFn("%d,%0.2f,%E,%0.2f,%E,%0.2f,%d,%d,%0.2f,%0.2f,%0.2f,%0.2f\n",
+		p.Time, p.MetricRate, float64(p.MetricTotal), p.RowRate, float64(p.RowTotal),
+		p.QueryRate, p.QueryTotal, p.QueryErrors,
+		p.LatencyMedian, p.LatencyP95, p.LatencyP99, p.LatencyMax)
+}
+
+// summary prints the overall rates of the mixed run
+func (r *Runner) summary(took time.Duration) {
+	metricCnt := atomic.LoadUint64(&r.metricCnt)
+	r.mu.Lock()
+	queryCnt, errCnt := r.queryCnt, r.errCnt
+	r.mu.Unlock()
+	printFn("\nMixed workload summary:\n")
+	printFn("loaded %d metrics and ran %d queries (%d failed) in %0.3fsec (mean rates %0.2f metrics/sec, %0.2f queries/sec)\n",
+		metricCnt, queryCnt, errCnt, took.Seconds(),
+		float64(metricCnt)/took.Seconds(), float64(queryCnt)/took.Seconds())
+}
+
+func (r *Runner) saveResult(start, end time.Time) {
+	result := Result{
+		ResultFormatVersion: ResultVersion,
+		RunnerConfig:        r.Config,
+		StartTime:           start.Unix(),
+		EndTime:             end.Unix(),
+		DurationMillis:      end.Sub(start).Milliseconds(),
+		Periods:             r.periods,
+	}
+
+	_, _ = fmt.Printf("Saving results json file to %s\n", r.ResultsFile)
+	file, err := json.MarshalIndent(result, "", " ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(r.ResultsFile, file, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,197 @@
+package mixed
+
+import (
+	"encoding/gob"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+type testDataSource struct {
+	left int
+}
+
+func (d *testDataSource) NextItem() data.LoadedPoint {
+	if d.left == 0 {
+		return data.LoadedPoint{}
+	}
+	d.left--
+	return data.NewLoadedPoint(d.left)
+}
+
+func (d *testDataSource) Headers() *common.GeneratedDataHeaders { return nil }
+
+type testBatch struct{ n uint }
+
+func (b *testBatch) Len() uint                 { return b.n }
+func (b *testBatch) Append(_ data.LoadedPoint) { b.n++ }
+
+type testBatchFactory struct{}
+
+func (f *testBatchFactory) New() targets.Batch { return &testBatch{} }
+
+type testProcessor struct {
+	closed *int32
+}
+
+func (p *testProcessor) Init(_ int, _, _ bool) {}
+
+func (p *testProcessor) ProcessBatch(b targets.Batch, _ bool) (uint64, uint64) {
+	n := uint64(b.Len())
+	return 2 * n, n
+}
+
+func (p *testProcessor) Close(_ bool) { atomic.AddInt32(p.closed, 1) }
+
+type testBenchmark struct {
+	points int
+	closed int32
+}
+
+func (b *testBenchmark) GetDataSource() targets.DataSource {
+	return &testDataSource{left: b.points}
+}
+func (b *testBenchmark) GetBatchFactory() targets.BatchFactory { return &testBatchFactory{} }
+func (b *testBenchmark) GetPointIndexer(_ uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+func (b *testBenchmark) GetProcessor() targets.Processor { return &testProcessor{closed: &b.closed} }
+func (b *testBenchmark) GetDBCreator() targets.DBCreator { return nil }
+
+// testQueryProcessor fails every third query
+type testQueryProcessor struct {
+	calls int
+}
+
+func (p *testQueryProcessor) Init(_ int) {}
+
+func (p *testQueryProcessor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
+	p.calls++
+	if p.calls%3 == 0 {
+		return nil, errors.New("server error")
+	}
+	return []*query.Stat{
+		query.GetStat().Init(q.HumanLabelName(), 4),
+		query.GetPartialStat().Init([]byte("part"), 1),
+	}, nil
+}
+
+func writeQueries(t *testing.T, n int) string {
+	f, err := ioutil.TempFile("", "mixed_queries*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := gob.NewEncoder(f)
+	for i := 0; i < n; i++ {
+		q := query.NewHTTP()
+		q.HumanLabel = []byte("q")
+		if err := enc.Encode(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f.Name()
+}
+
+func TestRunnerRun(t *testing.T) {
+	var printed int
+	oldPrintFn := printFn
+	printFn = func(_ string, _ ...interface{}) (int, error) {
+		printed++
+		return 0, nil
+	}
+	defer func() { printFn = oldPrintFn }()
+
+	queriesFile := writeQueries(t, 9)
+	defer os.Remove(queriesFile)
+	resultsFile, err := ioutil.TempFile("", "mixed_results*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(resultsFile.Name())
+
+	b := &testBenchmark{points: 25}
+	loader := load.GetBenchmarkRunner(load.BenchmarkRunnerConfig{
+		BatchSize: 10,
+		Workers:   2,
+		DoLoad:    true,
+	})
+	queries := query.NewBenchmarkRunner(query.BenchmarkRunnerConfig{
+		Workers:  1,
+		FileName: queriesFile,
+		OnError:  "skip",
+	})
+
+	r := NewRunner(Config{ReportingPeriod: time.Hour, ResultsFile: resultsFile.Name()})
+	r.Run(loader, b, queries, &query.HTTPPool, func() query.Processor { return &testQueryProcessor{} })
+
+	if r.metricCnt != 50 || r.rowCnt != 25 {
+		t.Errorf("wrong ingest counts: got %d metrics, %d rows; want 50 and 25", r.metricCnt, r.rowCnt)
+	}
+	if r.queryCnt != 6 || r.errCnt != 3 {
+		t.Errorf("wrong query counts: got %d queries, %d errors; want 6 and 3", r.queryCnt, r.errCnt)
+	}
+	if b.closed != 2 {
+		t.Errorf("wrapped processors not closed: got %d want 2", b.closed)
+	}
+	// the final, partial, period is reported when both runs are done
+	if len(r.periods) != 1 {
+		t.Fatalf("wrong number of periods: got %d want 1", len(r.periods))
+	}
+	p := r.periods[0]
+	if p.MetricTotal != 50 || p.QueryTotal != 6 || p.QueryErrors != 3 {
+		t.Errorf("wrong period totals: %+v", p)
+	}
+	if p.LatencyMax != 4 {
+		t.Errorf("partial stats counted as query latency: got max %f want 4", p.LatencyMax)
+	}
+	if printed == 0 {
+		t.Errorf("nothing was reported")
+	}
+	if fi, err := os.Stat(resultsFile.Name()); err != nil || fi.Size() == 0 {
+		t.Errorf("results file not written: %v", err)
+	}
+}
+
+// okQueryProcessor runs every query in 4ms
+type okQueryProcessor struct{}
+
+func (p *okQueryProcessor) Init(_ int) {}
+
+func (p *okQueryProcessor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
+	return []*query.Stat{query.GetStat().Init(q.HumanLabelName(), 4)}, nil
+}
+
+func TestRunnerRunPrewarm(t *testing.T) {
+	oldPrintFn := printFn
+	printFn = func(_ string, _ ...interface{}) (int, error) { return 0, nil }
+	defer func() { printFn = oldPrintFn }()
+
+	queriesFile := writeQueries(t, 5)
+	defer os.Remove(queriesFile)
+
+	loader := load.GetBenchmarkRunner(load.BenchmarkRunnerConfig{BatchSize: 10, Workers: 1, DoLoad: true})
+	queries := query.NewBenchmarkRunner(query.BenchmarkRunnerConfig{
+		Workers:        1,
+		FileName:       queriesFile,
+		OnError:        "skip",
+		PrewarmQueries: true,
+	})
+
+	r := NewRunner(Config{})
+	r.Run(loader, &testBenchmark{points: 5}, queries, &query.HTTPPool, func() query.Processor { return &okQueryProcessor{} })
+
+	// warm runs repeat the cold ones, they are not queries of their own
+	if r.queryCnt != 5 {
+		t.Errorf("wrong query count: got %d want 5", r.queryCnt)
+	}
+}
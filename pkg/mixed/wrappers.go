@@ -0,0 +1,55 @@
+package mixed
+
+import (
+	"sync/atomic"
+
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// countingBenchmark hands out processors which report the number of metrics
+// and rows they insert to the mixed runner, while the loader keeps its own
+// totals as usual.
+type countingBenchmark struct {
+	targets.Benchmark
+	r *Runner
+}
+
+func (b *countingBenchmark) GetProcessor() targets.Processor {
+	return &countingProcessor{Processor: b.Benchmark.GetProcessor(), r: b.r}
+}
+
+type countingProcessor struct {
+	targets.Processor
+	r *Runner
+}
+
+func (p *countingProcessor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	metricCount, rowCount = p.Processor.ProcessBatch(b, doLoad)
+	atomic.AddUint64(&p.r.metricCnt, metricCount)
+	atomic.AddUint64(&p.r.rowCnt, rowCount)
+	return metricCount, rowCount
+}
+
+// Close closes the wrapped processor, if it needs closing
+func (p *countingProcessor) Close(doLoad bool) {
+	if c, ok := p.Processor.(targets.ProcessorCloser); ok {
+		c.Close(doLoad)
+	}
+}
+
+// recordingProcessor reports every failure of a query to the mixed runner
+// before handing it on to the query runner. Latencies are reported by the
+// query runner itself, once they are corrected as for its own stats.
+type recordingProcessor struct {
+	query.Processor
+	r *Runner
+}
+
+func (p *recordingProcessor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	stats, err := p.Processor.ProcessQuery(q, isWarm)
+	if err != nil {
+		p.r.recordError()
+	}
+	return stats, err
+}
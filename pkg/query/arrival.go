@@ -0,0 +1,72 @@
+package query
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	arrivalFixed   = "fixed"
+	arrivalPoisson = "poisson"
+)
+
+var arrivalDistributionChoices = []string{arrivalFixed, arrivalPoisson}
+
+// arrivalSchedule hands out the intended send times of an open-loop load.
+// The times only depend on the target rate, not on how long the previous
+// queries took, so a slow query can not hold back the ones scheduled after it.
+type arrivalSchedule struct {
+	mu       sync.Mutex
+	next     time.Time
+	interval float64 // mean time between two arrivals, in nanoseconds
+	poisson  bool
+	rnd      *rand.Rand
+}
+
+// newArrivalSchedule returns a schedule of `rate` queries per second starting
+// at `start`, with either fixed or exponentially distributed (Poisson) gaps.
+func newArrivalSchedule(rate float64, distribution string, start time.Time, seed int64) (*arrivalSchedule, error) {
+	if rate <= 0 {
+		return nil, fmt.Errorf("arrival rate must be positive, got %f", rate)
+	}
+	a := &arrivalSchedule{
+		next:     start,
+		interval: float64(time.Second) / rate,
+	}
+	switch distribution {
+	case "", arrivalFixed:
+	case arrivalPoisson:
+		a.poisson = true
+		a.rnd = rand.New(rand.NewSource(seed))
+	default:
+		return nil, fmt.Errorf("unknown arrival distribution %s; allowed: %v", distribution, arrivalDistributionChoices)
+	}
+	return a, nil
+}
+
+// nextSendTime returns the time at which the next query should be sent.
+func (a *arrivalSchedule) nextSendTime() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t := a.next
+	gap := a.interval
+	if a.poisson {
+		gap *= a.rnd.ExpFloat64()
+	}
+	a.next = a.next.Add(time.Duration(gap))
+	return t
+}
+
+// correctForArrivalDelay adds the time a query spent waiting past its
+// intended send time to its stats, keeping the raw values as service times.
+// This compensates for coordinated omission: without it a slow query would
+// hide the latency of every query queued up behind it.
+func correctForArrivalDelay(stats []*Stat, delay time.Duration) {
+	delayMillis := float64(delay.Nanoseconds()) / 1e6
+	for _, s := range stats {
+		s.serviceTime = s.value
+		s.value += delayMillis
+	}
+}
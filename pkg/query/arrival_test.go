@@ -0,0 +1,65 @@
+package query
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewArrivalScheduleErrors(t *testing.T) {
+	if _, err := newArrivalSchedule(0, arrivalFixed, time.Now(), 0); err == nil {
+		t.Errorf("expected error for zero rate")
+	}
+	if _, err := newArrivalSchedule(10, "bursty", time.Now(), 0); err == nil {
+		t.Errorf("expected error for unknown distribution")
+	}
+}
+
+func TestArrivalScheduleFixed(t *testing.T) {
+	start := time.Unix(0, 0)
+	a, err := newArrivalSchedule(4, arrivalFixed, start, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		want := start.Add(time.Duration(i) * 250 * time.Millisecond)
+		if got := a.nextSendTime(); !got.Equal(want) {
+			t.Errorf("arrival %d: got %v want %v", i, got, want)
+		}
+	}
+}
+
+func TestArrivalSchedulePoisson(t *testing.T) {
+	start := time.Unix(0, 0)
+	rate := 100.0
+	n := 10000
+	a, err := newArrivalSchedule(rate, arrivalPoisson, start, 123)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prev := a.nextSendTime()
+	for i := 1; i < n; i++ {
+		next := a.nextSendTime()
+		if next.Before(prev) {
+			t.Fatalf("arrival %d goes back in time: %v before %v", i, next, prev)
+		}
+		prev = next
+	}
+	// mean inter-arrival time should be close to 1/rate
+	mean := prev.Sub(start).Seconds() / float64(n-1)
+	if math.Abs(mean-1/rate) > 0.05/rate {
+		t.Errorf("mean inter-arrival time too far off: got %f want %f", mean, 1/rate)
+	}
+}
+
+func TestCorrectForArrivalDelay(t *testing.T) {
+	stats := []*Stat{GetStat().Init([]byte("a"), 10), GetPartialStat().Init([]byte("b"), 2)}
+	correctForArrivalDelay(stats, 5*time.Millisecond)
+	want := []struct{ value, serviceTime float64 }{{15, 10}, {7, 2}}
+	for i, s := range stats {
+		if s.value != want[i].value || s.serviceTime != want[i].serviceTime {
+			t.Errorf("stat %d: got value %f service time %f, want %f and %f",
+				i, s.value, s.serviceTime, want[i].value, want[i].serviceTime)
+		}
+	}
+}
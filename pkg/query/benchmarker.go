@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/compression"
 	"golang.org/x/time/rate"
 )
 
@@ -25,19 +27,26 @@ const (
 
 // BenchmarkRunnerConfig is the configuration of the benchmark runner.
 type BenchmarkRunnerConfig struct {
-	DBName           string `mapstructure:"db-name"`
-	Limit            uint64 `mapstructure:"max-queries"`
-	LimitRPS         uint64 `mapstructure:"max-rps"`
-	MemProfile       string `mapstructure:"memprofile"`
-	HDRLatenciesFile string `mapstructure:"hdr-latencies"`
-	Workers          uint   `mapstructure:"workers"`
-	PrintResponses   bool   `mapstructure:"print-responses"`
-	Debug            int    `mapstructure:"debug"`
-	FileName         string `mapstructure:"file"`
-	BurnIn           uint64 `mapstructure:"burn-in"`
-	PrintInterval    uint64 `mapstructure:"print-interval"`
-	PrewarmQueries   bool   `mapstructure:"prewarm-queries"`
-	ResultsFile      string `mapstructure:"results-file"`
+	DBName              string        `mapstructure:"db-name"`
+	Limit               uint64        `mapstructure:"max-queries"`
+	LimitRPS            uint64        `mapstructure:"max-rps"`
+	MemProfile          string        `mapstructure:"memprofile"`
+	HDRLatenciesFile    string        `mapstructure:"hdr-latencies"`
+	Workers             uint          `mapstructure:"workers"`
+	PrintResponses      bool          `mapstructure:"print-responses"`
+	Debug               int           `mapstructure:"debug"`
+	FileName            string        `mapstructure:"file"`
+	BurnIn              uint64        `mapstructure:"burn-in"`
+	PrintInterval       uint64        `mapstructure:"print-interval"`
+	PrewarmQueries      bool          `mapstructure:"prewarm-queries"`
+	ResultsFile         string        `mapstructure:"results-file"`
+	Duration            time.Duration `mapstructure:"duration"`
+	ArrivalRate         float64       `mapstructure:"arrival-rate"`
+	ArrivalDistribution string        `mapstructure:"arrival-distribution"`
+	OnError             string        `mapstructure:"on-error"`
+	MaxRetries          uint          `mapstructure:"max-retries"`
+	RetryBackoff        time.Duration `mapstructure:"retry-backoff"`
+	CaptureResultsFile  string        `mapstructure:"capture-results"`
 }
 
 // AddToFlagSet adds command line flags needed by the BenchmarkRunnerConfig to the flag set.
@@ -55,16 +64,27 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Int("debug", 0, "Whether to print debug messages.")
 	fs.String("file", "", "File name to read queries from")
 	fs.String("results-file", "", "Write the test results summary json to this file")
+	fs.Float64("arrival-rate", 0, "Send queries open-loop at this many queries per second, measuring latency from the intended send time, 0 = closed-loop")
+	fs.String("arrival-distribution", arrivalFixed, fmt.Sprintf("Distribution of open-loop arrivals, one of %v", arrivalDistributionChoices))
+	fs.String("on-error", errorPolicyAbort, fmt.Sprintf("What to do when a query fails, one of %v", errorPolicyChoices))
+	fs.Uint("max-retries", 3, "Number of times a failed query is retried with the retry error policy before it is skipped")
+	fs.Duration("retry-backoff", time.Second, "Time to wait before the first retry of a failed query, doubled on every following retry")
+	fs.String("capture-results", "", "Write the normalized result set of every query to this file, to compare against another database with tsbs_compare_results")
+	fs.Duration("duration", 0, "Run queries for this long, replaying the query input from memory when it runs out, 0 = until the input is exhausted")
 }
 
 // BenchmarkRunner contains the common components for running a query benchmarking
 // program against a database.
 type BenchmarkRunner struct {
 	BenchmarkRunnerConfig
-	br      *bufio.Reader
-	sp      statProcessor
-	scanner *scanner
-	ch      chan Query
+	br       *bufio.Reader
+	sp       statProcessor
+	scanner  *scanner
+	ch       chan Query
+	arrivals *arrivalSchedule
+	results  *resultWriter
+
+	statsObserver func([]*Stat)
 }
 
 // NewBenchmarkRunner creates a new instance of BenchmarkRunner which is
@@ -78,6 +98,7 @@ func NewBenchmarkRunner(config BenchmarkRunnerConfig) *BenchmarkRunner {
 		prewarmQueries:   runner.PrewarmQueries,
 		burnIn:           runner.BurnIn,
 		hdrLatenciesFile: runner.HDRLatenciesFile,
+		openLoop:         runner.ArrivalRate > 0,
 	}
 
 	runner.sp = newStatProcessor(spArgs)
@@ -89,11 +110,38 @@ func (b *BenchmarkRunner) SetLimit(limit uint64) {
 	b.Limit = limit
 }
 
+// SetStatsObserver sets a function that is handed the stats of every cold
+// query run, just as the StatProcessor gets them, i.e. corrected for arrival
+// delay in open-loop runs. It is called by the workers, concurrently, and must
+// not keep the stats.
+func (b *BenchmarkRunner) SetStatsObserver(fn func([]*Stat)) {
+	b.statsObserver = fn
+}
+
 // DoPrintResponses indicates whether responses for queries should be printed
 func (b *BenchmarkRunner) DoPrintResponses() bool {
 	return b.PrintResponses
 }
 
+// DoCaptureResults indicates whether the normalized results of queries
+// should be captured with CaptureResult
+func (b *BenchmarkRunner) DoCaptureResults() bool {
+	return len(b.CaptureResultsFile) > 0
+}
+
+// CaptureResult records the normalized result rows of a query. It is safe
+// to be called by concurrent workers, but should only be called for the cold
+// run of a query.
+func (b *BenchmarkRunner) CaptureResult(q Query, rows []ResultRow) {
+	if b.results == nil {
+		return
+	}
+	err := b.results.write(&QueryResult{ID: q.GetID(), Label: string(q.HumanLabelName()), Rows: rows})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 // DebugLevel returns the level of debug messages for this benchmark
 func (b *BenchmarkRunner) DebugLevel() int {
 	return b.Debug
@@ -116,20 +164,24 @@ type Processor interface {
 	ProcessQuery(q Query, isWarm bool) ([]*Stat, error)
 }
 
-// GetBufferedReader returns the buffered Reader that should be used by the loader
+// GetBufferedReader returns the buffered Reader that should be used by the loader.
+// Input compressed with gzip or zstd is decompressed ahead of the reads.
 func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 	if b.br == nil {
+		var in io.Reader = os.Stdin
 		if len(b.FileName) > 0 {
 			// Read from specified file
 			file, err := os.Open(b.FileName)
 			if err != nil {
 				panic(fmt.Sprintf("cannot open file for read %s: %v", b.FileName, err))
 			}
-			b.br = bufio.NewReaderSize(file, defaultReadSize)
-		} else {
-			// Read from STDIN
-			b.br = bufio.NewReaderSize(os.Stdin, defaultReadSize)
+			in = file
+		}
+		br, err := compression.NewReader(in, defaultReadSize)
+		if err != nil {
+			panic(fmt.Sprintf("cannot decompress input %s: %v", b.FileName, err))
 		}
+		b.br = br
 	}
 	return b.br
 }
@@ -146,12 +198,39 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	if spArgs.burnIn > b.Limit {
 		panic("burn-in is larger than limit")
 	}
+	if len(b.OnError) > 0 {
+		if err := validateErrorPolicy(b.OnError); err != nil {
+			panic(err)
+		}
+	}
+	if b.ArrivalRate > 0 && b.LimitRPS > 0 {
+		panic("max-rps and arrival-rate are mutually exclusive")
+	}
+	if b.DoCaptureResults() {
+		if b.PrintResponses {
+			// both read the query responses, which can be read only once
+			panic("capture-results and print-responses are mutually exclusive")
+		}
+		var err error
+		b.results, err = newResultWriter(b.CaptureResultsFile)
+		if err != nil {
+			panic(err)
+		}
+	}
 	b.ch = make(chan Query, b.Workers)
 
 	// Launch the stats processor:
+	b.sp.init(b.Workers)
 	go b.sp.process(b.Workers)
 
 	rateLimiter := getRateLimiter(b.LimitRPS, b.Workers)
+	if b.ArrivalRate > 0 {
+		var err error
+		b.arrivals, err = newArrivalSchedule(b.ArrivalRate, b.ArrivalDistribution, time.Now(), time.Now().UnixNano())
+		if err != nil {
+			panic(err)
+		}
+	}
 
 	// Launch query processors
 	var wg sync.WaitGroup
@@ -163,12 +242,22 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	// Read in jobs, closing the job channel when done:
 	// Wall clock start time
 	wallStart := time.Now()
-	b.scanner.setReader(b.GetBufferedReader()).scan(queryPool, b.ch)
+	b.scanner.setReader(b.GetBufferedReader())
+	if b.Duration > 0 {
+		b.scanner.scanUntil(queryPool, b.ch, wallStart.Add(b.Duration))
+	} else {
+		b.scanner.scan(queryPool, b.ch)
+	}
 	close(b.ch)
 
 	// Block for workers to finish sending requests, closing the stats channel when done:
 	wg.Wait()
 	b.sp.CloseAndWait()
+	if b.results != nil {
+		if err := b.results.close(); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	// Wall clock end time
 	wallEnd := time.Now()
@@ -219,12 +308,30 @@ func (b *BenchmarkRunner) saveTestResult(took time.Duration, start time.Time, en
 func (b *BenchmarkRunner) processorHandler(wg *sync.WaitGroup, rateLimiter *rate.Limiter, queryPool *sync.Pool, processor Processor, workerNum int) {
 	processor.Init(workerNum)
 	for query := range b.ch {
-		r := rateLimiter.Reserve()
-		time.Sleep(r.Delay())
+		var intended time.Time
+		if b.arrivals != nil {
+			// open-loop: wait for the scheduled send time, if it hasn't passed already
+			intended = b.arrivals.nextSendTime()
+			time.Sleep(time.Until(intended))
+		} else {
+			r := rateLimiter.Reserve()
+			time.Sleep(r.Delay())
+		}
 
-		stats, err := processor.ProcessQuery(query, false)
-		if err != nil {
-			panic(err)
+		stats, sent, ok := b.processQuery(processor, query, false)
+		if !ok {
+			// the failure has been accounted for, don't bother warming up
+			queryPool.Put(query)
+			continue
+		}
+		if b.arrivals != nil {
+			// the stats measure the service time only; the time spent waiting
+			// for a free worker (or retrying) past the intended send time is
+			// latency too
+			correctForArrivalDelay(stats, sent.Sub(intended))
+		}
+		if b.statsObserver != nil {
+			b.statsObserver(stats)
 		}
 		b.sp.send(stats)
 
@@ -234,9 +341,14 @@ func (b *BenchmarkRunner) processorHandler(wg *sync.WaitGroup, rateLimiter *rate
 		spArgs := b.sp.getArgs()
 		if spArgs.prewarmQueries {
 			// Warm run
-			stats, err = processor.ProcessQuery(query, true)
-			if err != nil {
-				panic(err)
+			stats, _, ok = b.processQuery(processor, query, true)
+			if !ok {
+				queryPool.Put(query)
+				continue
+			}
+			if b.arrivals != nil {
+				// the warm run is not scheduled, it has no arrival delay
+				correctForArrivalDelay(stats, 0)
 			}
 			b.sp.sendWarm(stats)
 		}
@@ -9,6 +9,9 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/internal/compression"
 )
 
 type testProcessor struct {
@@ -104,6 +107,60 @@ func TestProcessorHandlerPreWarm(t *testing.T) {
 		t.Errorf("total queries wrong: want %d got %d", 2*qLimit, p1.count+p2.count)
 	}
 }
+
+type slowProcessor struct {
+	took time.Duration
+}
+
+func (p *slowProcessor) Init(_ int) {}
+
+func (p *slowProcessor) ProcessQuery(_ Query, _ bool) ([]*Stat, error) {
+	time.Sleep(p.took)
+	return []*Stat{GetStat().Init([]byte("slow"), float64(p.took.Nanoseconds())/1e6)}, nil
+}
+
+func TestProcessorHandlerOpenLoop(t *testing.T) {
+	qLimit := 5
+	took := 10 * time.Millisecond
+	var stats []*Stat
+	b := &BenchmarkRunner{}
+	b.sp = &mockStatProcessor{
+		args:   &statProcessorArgs{openLoop: true},
+		onSend: func(s []*Stat) { stats = append(stats, s...) },
+	}
+	// one query per millisecond against a single worker that needs 10ms
+	// per query: every query after the first one is sent late
+	arrivals, err := newArrivalSchedule(1000, arrivalFixed, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.arrivals = arrivals
+	b.ch = make(chan Query, qLimit)
+	for i := 0; i < qLimit; i++ {
+		b.ch <- testQueryPool.Get().(*testQuery)
+	}
+	close(b.ch)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	b.processorHandler(&wg, rate.NewLimiter(rate.Inf, 0), &testQueryPool, &slowProcessor{took: took}, 0)
+
+	if len(stats) != qLimit {
+		t.Fatalf("wrong number of stats: got %d want %d", len(stats), qLimit)
+	}
+	tookMillis := float64(took.Nanoseconds()) / 1e6
+	for i, s := range stats {
+		if s.serviceTime != tookMillis {
+			t.Errorf("stat %d: service time changed: got %f want %f", i, s.serviceTime, tookMillis)
+		}
+		// query i was due at i ms but could only start after i*10ms
+		minDelay := float64(i) * (tookMillis - 1)
+		if s.value < s.serviceTime+minDelay {
+			t.Errorf("stat %d: latency not corrected: got %f want at least %f", i, s.value, s.serviceTime+minDelay)
+		}
+	}
+}
+
 func TestBenchmarkRunnerGetBufferedReaderPanicOnMissingFile(t *testing.T) {
 	dumbFileName := "some-random-file-that-should-not-exist"
 	_, err := os.Stat(dumbFileName)
@@ -157,6 +214,32 @@ func TestBenchmarkRunnerGetBufferedReaderCached(t *testing.T) {
 	b.GetBufferedReader()
 }
 
+func TestBenchmarkRunnerGetBufferedReaderCompressed(t *testing.T) {
+	const want = "encoded queries"
+	f, err := ioutil.TempFile("", "temp_file_*.gz")
+	if err != nil {
+		t.Fatalf("Could not create temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	w, _ := compression.NewWriter(f, compression.Gzip)
+	w.Write([]byte(want))
+	w.Close()
+	f.Close()
+
+	b := &BenchmarkRunner{
+		BenchmarkRunnerConfig: BenchmarkRunnerConfig{
+			FileName: f.Name(),
+		},
+	}
+	got, err := ioutil.ReadAll(b.GetBufferedReader())
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("incorrect decompressed input: got %q want %q", got, want)
+	}
+}
+
 func TestBenchmarkRunnerRunPanicOnNoWorkers(t *testing.T) {
 	runner := &BenchmarkRunner{}
 	defer func() {
@@ -294,11 +377,13 @@ func TestBenchmarkRunnerRunNoQueries(t *testing.T) {
 }
 
 type mockStatProcessor struct {
-	args      *statProcessorArgs
-	onSend    func([]*Stat)
-	onProcess func(uint)
-	closed    bool
-	wg        *sync.WaitGroup
+	args        *statProcessorArgs
+	onSend      func([]*Stat)
+	onSendError func([]byte, string)
+	onSendRetry func([]byte)
+	onProcess   func(uint)
+	closed      bool
+	wg          *sync.WaitGroup
 }
 
 func (m *mockStatProcessor) getArgs() *statProcessorArgs {
@@ -314,6 +399,17 @@ func (m *mockStatProcessor) sendWarm(stats []*Stat) {
 		m.onSend(stats)
 	}
 }
+func (m *mockStatProcessor) sendError(label []byte, kind string) {
+	if m.onSendError != nil {
+		m.onSendError(label, kind)
+	}
+}
+func (m *mockStatProcessor) sendRetry(label []byte) {
+	if m.onSendRetry != nil {
+		m.onSendRetry(label)
+	}
+}
+func (m *mockStatProcessor) init(workers uint) {}
 func (m *mockStatProcessor) process(workers uint) {
 	if m.onProcess != nil {
 		m.onProcess(workers)
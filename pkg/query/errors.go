@@ -0,0 +1,93 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	errorPolicyAbort = "abort"
+	errorPolicySkip  = "skip"
+	errorPolicyRetry = "retry"
+
+	errorKindTimeout    = "timeout"
+	errorKindConnection = "connection"
+	errorKindServer     = "server"
+)
+
+var (
+	errorPolicyChoices = []string{errorPolicyAbort, errorPolicySkip, errorPolicyRetry}
+	errorKinds         = []string{errorKindTimeout, errorKindConnection, errorKindServer}
+)
+
+// classifyError tells whether a query failed because it timed out, because
+// the connection to the database failed or because the database itself
+// returned an error. Anything not recognized as one of the first two is
+// assumed to come from the database.
+func classifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return errorKindTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errorKindTimeout
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) {
+		return errorKindConnection
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return errorKindConnection
+	}
+	return errorKindServer
+}
+
+// processQuery runs a query, dealing with failures as configured by the
+// error policy. A query that is given up is reported to the stat processor
+// as one error, and every failed attempt before it is retried as a retry.
+// It returns the stats of the query along with the time of the attempt that
+// succeeded, or false if the query failed and has to be skipped.
+func (b *BenchmarkRunner) processQuery(processor Processor, q Query, isWarm bool) ([]*Stat, time.Time, bool) {
+	backoff := b.RetryBackoff
+	for attempt := uint(0); ; attempt++ {
+		sent := time.Now()
+		stats, err := processor.ProcessQuery(q, isWarm)
+		if err == nil {
+			return stats, sent, true
+		}
+
+		switch b.OnError {
+		case errorPolicySkip:
+			b.sp.sendError(q.HumanLabelName(), classifyError(err))
+			return nil, sent, false
+		case errorPolicyRetry:
+			if attempt >= b.MaxRetries {
+				b.sp.sendError(q.HumanLabelName(), classifyError(err))
+				return nil, sent, false
+			}
+			b.sp.sendRetry(q.HumanLabelName())
+			time.Sleep(backoff)
+			backoff *= 2
+		default:
+			panic(err)
+		}
+	}
+}
+
+func validateErrorPolicy(policy string) error {
+	for _, p := range errorPolicyChoices {
+		if policy == p {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid error policy %s; allowed: %v", policy, errorPolicyChoices)
+}
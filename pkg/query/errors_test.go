@@ -0,0 +1,182 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		desc string
+		err  error
+		want string
+	}{
+		{
+			desc: "context deadline",
+			err:  fmt.Errorf("query failed: %w", context.DeadlineExceeded),
+			want: errorKindTimeout,
+		},
+		{
+			desc: "net timeout",
+			err:  &net.OpError{Op: "read", Err: timeoutError{}},
+			want: errorKindTimeout,
+		},
+		{
+			desc: "connection refused",
+			err:  &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED},
+			want: errorKindConnection,
+		},
+		{
+			desc: "unexpected EOF",
+			err:  fmt.Errorf("reading response: %w", io.ErrUnexpectedEOF),
+			want: errorKindConnection,
+		},
+		{
+			desc: "anything else",
+			err:  errors.New("http request did not return status 200 OK: 500 Internal Server Error"),
+			want: errorKindServer,
+		},
+	}
+	for _, c := range cases {
+		if got := classifyError(c.err); got != c.want {
+			t.Errorf("%s: got %s want %s", c.desc, got, c.want)
+		}
+	}
+}
+
+// failingProcessor fails the first `failures` queries it processes
+type failingProcessor struct {
+	failures int
+	calls    int
+}
+
+func (p *failingProcessor) Init(_ int) {}
+
+func (p *failingProcessor) ProcessQuery(q Query, _ bool) ([]*Stat, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, context.DeadlineExceeded
+	}
+	return []*Stat{GetStat().Init(q.HumanLabelName(), 1)}, nil
+}
+
+func TestProcessQueryErrorPolicies(t *testing.T) {
+	cases := []struct {
+		desc       string
+		policy     string
+		maxRetries uint
+		failures   int
+		wantOk     bool
+		wantCalls  int
+		wantErrors int
+		wantRetry  int
+	}{
+		{desc: "no failure", policy: errorPolicySkip, wantOk: true, wantCalls: 1},
+		{desc: "skip", policy: errorPolicySkip, failures: 1, wantCalls: 1, wantErrors: 1},
+		{desc: "retry succeeds", policy: errorPolicyRetry, maxRetries: 3, failures: 2, wantOk: true, wantCalls: 3, wantRetry: 2},
+		{desc: "retries exhausted", policy: errorPolicyRetry, maxRetries: 2, failures: 5, wantCalls: 3, wantErrors: 1, wantRetry: 2},
+	}
+	for _, c := range cases {
+		var errorKindsSent []string
+		retriesSent := 0
+		b := &BenchmarkRunner{
+			BenchmarkRunnerConfig: BenchmarkRunnerConfig{
+				OnError:      c.policy,
+				MaxRetries:   c.maxRetries,
+				RetryBackoff: time.Microsecond,
+			},
+			sp: &mockStatProcessor{
+				onSendError: func(label []byte, kind string) {
+					if string(label) != "q" {
+						t.Errorf("%s: wrong error label: got %s", c.desc, label)
+					}
+					errorKindsSent = append(errorKindsSent, kind)
+				},
+				onSendRetry: func(label []byte) {
+					if string(label) != "q" {
+						t.Errorf("%s: wrong retry label: got %s", c.desc, label)
+					}
+					retriesSent++
+				},
+			},
+		}
+		p := &failingProcessor{failures: c.failures}
+		stats, _, ok := b.processQuery(p, &testQuery{HumanLabel: []byte("q")}, false)
+		if ok != c.wantOk {
+			t.Errorf("%s: got ok %v want %v", c.desc, ok, c.wantOk)
+		}
+		if ok && len(stats) != 1 {
+			t.Errorf("%s: got %d stats want 1", c.desc, len(stats))
+		}
+		if p.calls != c.wantCalls {
+			t.Errorf("%s: got %d calls want %d", c.desc, p.calls, c.wantCalls)
+		}
+		if len(errorKindsSent) != c.wantErrors {
+			t.Errorf("%s: got %d errors want %d", c.desc, len(errorKindsSent), c.wantErrors)
+		}
+		if retriesSent != c.wantRetry {
+			t.Errorf("%s: got %d retries want %d", c.desc, retriesSent, c.wantRetry)
+		}
+		for _, kind := range errorKindsSent {
+			if kind != errorKindTimeout {
+				t.Errorf("%s: wrong error kind: got %s want %s", c.desc, kind, errorKindTimeout)
+			}
+		}
+	}
+}
+
+func TestProcessQueryAbortPanics(t *testing.T) {
+	for _, policy := range []string{"", errorPolicyAbort} {
+		b := &BenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{OnError: policy}}
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("policy %q: expected panic on error", policy)
+				}
+			}()
+			b.processQuery(&failingProcessor{failures: 1}, &testQuery{}, false)
+		}()
+	}
+}
+
+func TestStatGroupPushError(t *testing.T) {
+	sg := newStatGroup(0)
+	sg.push(5)
+	sg.pushError(errorKindTimeout)
+	sg.pushError(errorKindServer)
+	sg.pushError(errorKindServer)
+
+	if sg.count != 1 {
+		t.Errorf("failed query counted as latency: got count %d want 1", sg.count)
+	}
+	if sg.errorsCount != 3 || sg.errors[errorKindServer] != 2 {
+		t.Errorf("wrong error counts: total %d, by kind %v", sg.errorsCount, sg.errors)
+	}
+	want := "errors: 3 (timeout: 1, connection: 0, server: 2)"
+	if !strings.Contains(sg.string(), want) {
+		t.Errorf("errors not printed: got %s want it to contain %s", sg.string(), want)
+	}
+
+	sp := &defaultStatProcessor{
+		args:        &statProcessorArgs{limit: new(uint64)},
+		statMapping: map[string]*statGroup{"q": sg},
+	}
+	totals := sp.GetTotalsMap()["overallErrors"].(map[string]interface{})
+	counts := totals["q"].(map[string]int64)
+	if counts["total"] != 3 || counts[errorKindServer] != 2 || counts[errorKindConnection] != 0 {
+		t.Errorf("wrong error totals: %v", counts)
+	}
+}
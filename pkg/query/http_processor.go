@@ -0,0 +1,177 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+const contentTypeJSON = "application/json"
+
+// HTTPResult is what a HTTPResponseParser tells of the result of a query.
+type HTTPResult struct {
+	// Description is a short description of the result for the debug output
+	// and the printed responses, e.g. "3 series, 180 samples"
+	Description string
+	// HasSize is set when Series and Samples are the size of the result,
+	// reported in the run summary
+	HasSize bool
+	Series  int64
+	Samples int64
+}
+
+// HTTPResponseParser checks the body of a query response with status 200,
+// returning what it tells of the result, or an error if the query failed
+// nonetheless.
+type HTTPResponseParser func(body []byte) (HTTPResult, error)
+
+// HTTPProcessorOptions are the options of the query processors of databases
+// queried over HTTP.
+type HTTPProcessorOptions struct {
+	// URLs of the database, used round-robin by worker
+	URLs []string
+	// Username and Password for basic authentication, if Username is set
+	Username string
+	Password string
+	// Debug is the debug level: 1 prints the result of each query, 2 adds
+	// the query description, 3 the request and 4 the response
+	Debug int
+	// PrettyPrintResponses prints every response as indented JSON
+	PrettyPrintResponses bool
+	// ParseResponse checks the responses; nil accepts every response
+	ParseResponse HTTPResponseParser
+}
+
+// NewHTTPProcessorCreate returns a function creating query processors which
+// send the HTTP queries round-robin (by worker) to the given URLs, the query
+// body, if any, as JSON.
+func NewHTTPProcessorCreate(opts *HTTPProcessorOptions) ProcessorCreate {
+	client := &http.Client{
+		Transport: &http.Transport{MaxIdleConnsPerHost: 1024},
+	}
+	return func() Processor {
+		return &httpProcessor{opts: opts, client: client}
+	}
+}
+
+// Processor interface implementation
+type httpProcessor struct {
+	opts   *HTTPProcessorOptions
+	client *http.Client
+	url    string
+}
+
+// Processor interface implementation
+func (p *httpProcessor) Init(workerNum int) {
+	p.url = p.opts.URLs[workerNum%len(p.opts.URLs)]
+}
+
+// Processor interface implementation
+func (p *httpProcessor) ProcessQuery(q Query, isWarm bool) ([]*Stat, error) {
+	hq := q.(*HTTP)
+	lag, res, err := p.do(hq)
+	if err != nil {
+		return nil, err
+	}
+	stat := GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	if res.HasSize {
+		stat.SetResultSize(res.Series, res.Samples)
+	}
+	return []*Stat{stat}, nil
+}
+
+func (p *httpProcessor) newRequest(q *HTTP) (*http.Request, error) {
+	var body *bytes.Reader
+	if len(q.Body) > 0 {
+		body = bytes.NewReader(q.Body)
+	}
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = http.NewRequest(string(q.Method), p.url+string(q.Path), nil)
+	} else {
+		req, err = http.NewRequest(string(q.Method), p.url+string(q.Path), body)
+		if err == nil {
+			req.Header.Set("Content-Type", contentTypeJSON)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.opts.Username != "" {
+		req.SetBasicAuth(p.opts.Username, p.opts.Password)
+	}
+	return req, nil
+}
+
+func (p *httpProcessor) do(q *HTTP) (float64, HTTPResult, error) {
+	// populate a request with data from the Query:
+	req, err := p.newRequest(q)
+	if err != nil {
+		return 0, HTTPResult{}, fmt.Errorf("error while creating request: %s", err)
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, HTTPResult{}, fmt.Errorf("query execution error: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, HTTPResult{}, fmt.Errorf("error while reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, HTTPResult{}, fmt.Errorf("non-200 statuscode received: %d; Body: %s", resp.StatusCode, string(body))
+	}
+	lag := float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
+
+	var res HTTPResult
+	if p.opts.ParseResponse != nil {
+		res, err = p.opts.ParseResponse(body)
+		if err != nil {
+			return lag, res, fmt.Errorf("query %d: %w", q.GetID(), err)
+		}
+	}
+
+	// Print debug messages, if applicable:
+	if p.opts.Debug > 0 {
+		fmt.Fprintf(os.Stderr, "debug: %s in %7.2fms", q.HumanLabel, lag)
+		if res.Description != "" {
+			fmt.Fprintf(os.Stderr, ", %s", res.Description)
+		}
+		if p.opts.Debug > 1 {
+			fmt.Fprintf(os.Stderr, " -- %s", q.HumanDescription)
+		}
+		fmt.Fprintln(os.Stderr)
+		if p.opts.Debug > 2 {
+			fmt.Fprintf(os.Stderr, "debug:   request: %s\n", q.String())
+		}
+		if p.opts.Debug > 3 {
+			fmt.Fprintf(os.Stderr, "debug:   response: %s\n", body)
+		}
+	}
+
+	// Pretty print JSON responses, if applicable:
+	if p.opts.PrettyPrintResponses {
+		var pretty bytes.Buffer
+		prefix := fmt.Sprintf("ID %d: ", q.GetID())
+		if err := json.Indent(&pretty, body, prefix, "  "); err != nil {
+			return lag, res, err
+		}
+		if res.Description != "" {
+			if _, err := fmt.Fprintf(os.Stderr, "%s%s\n", prefix, res.Description); err != nil {
+				return lag, res, err
+			}
+		}
+		if _, err := fmt.Fprintf(os.Stderr, "%s%s\n", prefix, pretty.Bytes()); err != nil {
+			return lag, res, err
+		}
+	}
+	return lag, res, nil
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPProcessorProcessQuery(t *testing.T) {
+	errParse := errors.New("parse error")
+	cases := []struct {
+		desc     string
+		status   int
+		body     string
+		username string
+		parse    HTTPResponseParser
+		err      bool
+	}{
+		{desc: "ok", status: http.StatusOK},
+		{desc: "ok with body and auth", status: http.StatusOK, body: `{"size":0}`, username: "user"},
+		{desc: "ok with parser", status: http.StatusOK, parse: func([]byte) (HTTPResult, error) { return HTTPResult{Description: "1 row"}, nil }},
+		{desc: "ok with result size", status: http.StatusOK, parse: func([]byte) (HTTPResult, error) {
+			return HTTPResult{HasSize: true, Series: 2, Samples: 5}, nil
+		}},
+		{desc: "bad request", status: http.StatusBadRequest, err: true},
+		{desc: "parse error", status: http.StatusOK, parse: func([]byte) (HTTPResult, error) { return HTTPResult{}, errParse }, err: true},
+	}
+	for _, c := range cases {
+		var path, contentType, user, pass string
+		var hasAuth bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.RequestURI()
+			contentType = r.Header.Get("Content-Type")
+			user, pass, hasAuth = r.BasicAuth()
+			w.WriteHeader(c.status)
+			w.Write([]byte(`{}`))
+		}))
+		create := NewHTTPProcessorCreate(&HTTPProcessorOptions{
+			URLs:          []string{"http://localhost:1", server.URL},
+			Username:      c.username,
+			Password:      "secret",
+			ParseResponse: c.parse,
+		})
+		p := create()
+		p.Init(1)
+
+		q := NewHTTP()
+		q.HumanLabel = []byte("HTTP query")
+		q.Method = []byte(http.MethodPost)
+		q.Path = []byte("/query?q=1")
+		q.Body = []byte(c.body)
+		stats, err := p.ProcessQuery(q, false)
+		server.Close()
+
+		if path != "/query?q=1" {
+			t.Errorf("%s: incorrect path: got %s", c.desc, path)
+		}
+		if wantType := map[bool]string{true: contentTypeJSON}[c.body != ""]; contentType != wantType {
+			t.Errorf("%s: incorrect content type: got %q want %q", c.desc, contentType, wantType)
+		}
+		if hasAuth != (c.username != "") || user != c.username || (hasAuth && pass != "secret") {
+			t.Errorf("%s: incorrect basic auth: got %q:%q", c.desc, user, pass)
+		}
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			if c.parse != nil && !errors.Is(err, errParse) {
+				t.Errorf("%s: incorrect error: got %v want %v", c.desc, err, errParse)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+			continue
+		}
+		if len(stats) != 1 || string(stats[0].label) != "HTTP query" {
+			t.Errorf("%s: incorrect stats: %v", c.desc, stats)
+			continue
+		}
+		var want HTTPResult
+		if c.parse != nil {
+			want, _ = c.parse(nil)
+		}
+		if s := stats[0]; s.hasResultSize != want.HasSize || s.series != want.Series || s.samples != want.Samples {
+			t.Errorf("%s: incorrect result size: got %v, %d, %d", c.desc, s.hasResultSize, s.series, s.samples)
+		}
+	}
+}
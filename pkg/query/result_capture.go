@@ -0,0 +1,243 @@
+package query
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResultRow is one row of a normalized query result: the time bucket (if
+// any), the group keys and the values, each in column order. Null values are
+// kept as nil so they can be told apart from zeros.
+type ResultRow struct {
+	Time   *time.Time `json:"time,omitempty"`
+	Keys   []string   `json:"keys,omitempty"`
+	Values []*float64 `json:"values,omitempty"`
+}
+
+// QueryResult is the normalized result set of a single query, as captured
+// by the query runners with --capture-results.
+type QueryResult struct {
+	ID    uint64      `json:"id"`
+	Label string      `json:"label"`
+	Rows  []ResultRow `json:"rows"`
+}
+
+// NormalizeRow converts the column values of a result row, as returned by
+// a database driver, into a ResultRow. Times go to the time bucket, strings
+// and byte slices to the group keys and numbers to the values, so results of
+// databases naming or typing their columns differently can be compared.
+func NormalizeRow(columns []interface{}) ResultRow {
+	var row ResultRow
+	for _, c := range columns {
+		switch v := c.(type) {
+		case nil:
+			row.Values = append(row.Values, nil)
+		case time.Time:
+			t := v.UTC()
+			row.Time = &t
+		case *time.Time:
+			if v != nil {
+				t := v.UTC()
+				row.Time = &t
+			}
+		case string:
+			row.Keys = append(row.Keys, v)
+		case []byte:
+			row.Keys = append(row.Keys, string(v))
+		default:
+			f, ok := toFloat(v)
+			if !ok {
+				row.Keys = append(row.Keys, fmt.Sprintf("%v", v))
+				continue
+			}
+			row.Values = append(row.Values, &f)
+		}
+	}
+	return row
+}
+
+// NormalizeRows converts the rows of a result, each given as its column
+// values, into ResultRows.
+func NormalizeRows(rows [][]interface{}) []ResultRow {
+	var results []ResultRow
+	for _, columns := range rows {
+		results = append(results, NormalizeRow(columns))
+	}
+	return results
+}
+
+// SQLRows is the part of a result set of database/sql needed to read its
+// rows, as implemented by *sql.Rows and *sqlx.Rows.
+type SQLRows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+}
+
+// scanRow reads the column values of the current row of a result set.
+func scanRow(r SQLRows, columns int) ([]interface{}, error) {
+	values := make([]interface{}, columns)
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	if err := r.Scan(values...); err != nil {
+		return nil, fmt.Errorf("error while reading values: %v", err)
+	}
+	for i := range values {
+		values[i] = *values[i].(*interface{})
+	}
+	return values, nil
+}
+
+// ScanRows reads the column values of all the rows of a result set, for
+// capturing results. Reading the rows is part of running the query, while
+// normalizing them with NormalizeRows is not and is left out of its time.
+func ScanRows(r SQLRows) ([][]interface{}, error) {
+	cols, err := r.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]interface{}
+	for r.Next() {
+		values, err := scanRow(r, len(cols))
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// MapRows reads all the rows of a result set as maps of column name to value,
+// for printing responses.
+func MapRows(r SQLRows) ([]map[string]interface{}, error) {
+	cols, err := r.Columns()
+	if err != nil {
+		return nil, err
+	}
+	rows := []map[string]interface{}{}
+	for r.Next() {
+		values, err := scanRow(r, len(cols))
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, column := range cols {
+			row[column] = values[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case fmt.Stringer:
+		// e.g. arbitrary precision numerics of SQL drivers
+		f, err := strconv.ParseFloat(n.String(), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// sortRows orders rows by time bucket and group keys, so that results of
+// databases returning rows in a different order compare equal.
+func sortRows(rows []ResultRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch {
+		case a.Time == nil && b.Time != nil:
+			return true
+		case a.Time != nil && b.Time == nil:
+			return false
+		case a.Time != nil && !a.Time.Equal(*b.Time):
+			return a.Time.Before(*b.Time)
+		}
+		return strings.Join(a.Keys, "\x00") < strings.Join(b.Keys, "\x00")
+	})
+}
+
+// resultWriter writes captured query results to a file as JSON lines. It is
+// shared by all workers.
+type resultWriter struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+	enc  *json.Encoder
+}
+
+func newResultWriter(fileName string) (*resultWriter, error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create results capture file %s: %v", fileName, err)
+	}
+	w := bufio.NewWriter(file)
+	return &resultWriter{file: file, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (rw *resultWriter) write(r *QueryResult) error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return rw.enc.Encode(r)
+}
+
+func (rw *resultWriter) close() error {
+	if err := rw.w.Flush(); err != nil {
+		return err
+	}
+	return rw.file.Close()
+}
+
+// ReadResults reads query results captured with --capture-results, keyed by
+// query ID.
+func ReadResults(r io.Reader) (map[uint64]*QueryResult, error) {
+	results := make(map[uint64]*QueryResult)
+	dec := json.NewDecoder(r)
+	for {
+		var qr QueryResult
+		err := dec.Decode(&qr)
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode query result: %v", err)
+		}
+		results[qr.ID] = &qr
+	}
+}
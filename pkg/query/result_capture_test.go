@@ -0,0 +1,159 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestNormalizeRow(t *testing.T) {
+	ts := time.Date(2016, 1, 1, 0, 0, 0, 0, time.FixedZone("X", 3600))
+	utc := ts.UTC()
+	got := NormalizeRow([]interface{}{ts, "host_0", []byte("eu"), 1.5, int64(2), uint32(3), json.Number("4.25"), nil})
+	want := ResultRow{
+		Time:   &utc,
+		Keys:   []string{"host_0", "eu"},
+		Values: []*float64{floatPtr(1.5), floatPtr(2), floatPtr(3), floatPtr(4.25), nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect row:\ngot  %+v\nwant %+v", got, want)
+	}
+	if got.Time.Location() != time.UTC {
+		t.Errorf("time not normalized to UTC: %v", got.Time)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	t1 := time.Unix(1, 0)
+	t2 := time.Unix(2, 0)
+	rows := []ResultRow{
+		{Time: &t2, Keys: []string{"a"}},
+		{Time: &t1, Keys: []string{"b"}},
+		{Time: &t1, Keys: []string{"a"}},
+		{Keys: []string{"z"}},
+	}
+	sortRows(rows)
+	want := []string{"z", "a", "b", "a"}
+	for i, r := range rows {
+		if r.Keys[0] != want[i] {
+			t.Errorf("row %d: got key %s want %s", i, r.Keys[0], want[i])
+		}
+	}
+	if !rows[3].Time.Equal(t2) {
+		t.Errorf("last row should be the latest: got %v", rows[3].Time)
+	}
+}
+
+func TestResultWriterAndReadResults(t *testing.T) {
+	f, err := ioutil.TempFile("", "captured_results*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	b := &BenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{CaptureResultsFile: f.Name()}}
+	if !b.DoCaptureResults() {
+		t.Fatalf("capture should be enabled")
+	}
+	b.results, err = newResultWriter(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Unix(60, 0).UTC()
+	rows := []ResultRow{{Time: &ts, Keys: []string{"host_0"}, Values: []*float64{floatPtr(1), nil}}}
+	b.CaptureResult(&testQuery{ID: 7, HumanLabel: []byte("label")}, rows)
+	b.CaptureResult(&testQuery{ID: 8, HumanLabel: []byte("label")}, nil)
+	if err := b.results.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := ReadResults(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("wrong number of results: got %d want 2", len(results))
+	}
+	want := &QueryResult{ID: 7, Label: "label", Rows: rows}
+	if !reflect.DeepEqual(results[7], want) {
+		t.Errorf("incorrect result:\ngot  %+v\nwant %+v", results[7], want)
+	}
+
+	if _, err := ReadResults(bytes.NewReader([]byte("{not json"))); err == nil {
+		t.Errorf("expected error for malformed input")
+	}
+}
+
+func TestNormalizeRows(t *testing.T) {
+	rows := NormalizeRows([][]interface{}{{"a", int64(1)}, {"b", 2.5}})
+	if got := len(rows); got != 2 {
+		t.Fatalf("incorrect number of rows: got %d want 2", got)
+	}
+	if rows[1].Keys[0] != "b" || *rows[1].Values[0] != 2.5 {
+		t.Errorf("incorrect second row: got %v %v", rows[1].Keys, *rows[1].Values[0])
+	}
+}
+
+// testSQLRows is an in-memory result set
+type testSQLRows struct {
+	cols []string
+	rows [][]interface{}
+	next int
+	err  error
+}
+
+func (r *testSQLRows) Columns() ([]string, error) { return r.cols, nil }
+
+func (r *testSQLRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *testSQLRows) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, v := range r.rows[r.next-1] {
+		*dest[i].(*interface{}) = v
+	}
+	return nil
+}
+
+func TestScanRowsAndMapRows(t *testing.T) {
+	cols := []string{"host", "max"}
+	values := [][]interface{}{{"host_0", 1.5}, {"host_1", nil}}
+
+	scanned, err := ScanRows(&testSQLRows{cols: cols, rows: values})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, values) {
+		t.Errorf("incorrect scanned rows: got %v want %v", scanned, values)
+	}
+
+	mapped, err := MapRows(&testSQLRows{cols: cols, rows: values})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []map[string]interface{}{{"host": "host_0", "max": 1.5}, {"host": "host_1", "max": nil}}
+	if !reflect.DeepEqual(mapped, want) {
+		t.Errorf("incorrect mapped rows: got %v want %v", mapped, want)
+	}
+
+	failing := &testSQLRows{cols: cols, rows: values, err: errors.New("broken")}
+	if _, err := ScanRows(failing); err == nil {
+		t.Errorf("scan error not returned")
+	}
+}
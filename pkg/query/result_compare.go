@@ -0,0 +1,111 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Mismatch describes a query whose results differ between two captures.
+type Mismatch struct {
+	ID     uint64
+	Reason string
+}
+
+// LabelComparison holds the outcome of comparing the results of all the
+// queries with the same label.
+type LabelComparison struct {
+	Compared   int
+	Missing    int // queries captured in the reference only
+	Mismatches []Mismatch
+}
+
+// CompareResults compares captured query results against reference results,
+// query by query. Values are equal when they differ by no more than
+// `tolerance`, relative to the larger of the two for values above 1. The
+// outcome is grouped by query label.
+func CompareResults(reference, results map[uint64]*QueryResult, tolerance float64) map[string]*LabelComparison {
+	comparisons := make(map[string]*LabelComparison)
+	for id, ref := range reference {
+		c, ok := comparisons[ref.Label]
+		if !ok {
+			c = &LabelComparison{}
+			comparisons[ref.Label] = c
+		}
+		res, ok := results[id]
+		if !ok {
+			c.Missing++
+			continue
+		}
+		c.Compared++
+		if reason := compareQueryResult(ref, res, tolerance); reason != "" {
+			c.Mismatches = append(c.Mismatches, Mismatch{ID: id, Reason: reason})
+		}
+	}
+	return comparisons
+}
+
+// compareQueryResult returns why two results of the same query differ, or
+// an empty string if they don't.
+func compareQueryResult(ref, res *QueryResult, tolerance float64) string {
+	if ref.Label != res.Label {
+		return fmt.Sprintf("different queries: %s vs %s", ref.Label, res.Label)
+	}
+	if len(ref.Rows) != len(res.Rows) {
+		return fmt.Sprintf("row count: %d vs %d", len(ref.Rows), len(res.Rows))
+	}
+	sortRows(ref.Rows)
+	sortRows(res.Rows)
+	for i := range ref.Rows {
+		if reason := compareRow(&ref.Rows[i], &res.Rows[i], tolerance); reason != "" {
+			return fmt.Sprintf("row %d: %s", i, reason)
+		}
+	}
+	return ""
+}
+
+func compareRow(a, b *ResultRow, tolerance float64) string {
+	switch {
+	case (a.Time == nil) != (b.Time == nil):
+		return fmt.Sprintf("time: %s vs %s", formatTime(a), formatTime(b))
+	case a.Time != nil && !a.Time.Equal(*b.Time):
+		return fmt.Sprintf("time: %s vs %s", formatTime(a), formatTime(b))
+	}
+	if strings.Join(a.Keys, ",") != strings.Join(b.Keys, ",") {
+		return fmt.Sprintf("keys: %v vs %v", a.Keys, b.Keys)
+	}
+	if len(a.Values) != len(b.Values) {
+		return fmt.Sprintf("value count: %d vs %d", len(a.Values), len(b.Values))
+	}
+	for i := range a.Values {
+		if !floatsEqual(a.Values[i], b.Values[i], tolerance) {
+			return fmt.Sprintf("value %d: %s vs %s", i, formatValue(a.Values[i]), formatValue(b.Values[i]))
+		}
+	}
+	return ""
+}
+
+func floatsEqual(a, b *float64, tolerance float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if math.IsNaN(*a) || math.IsNaN(*b) {
+		return math.IsNaN(*a) && math.IsNaN(*b)
+	}
+	scale := math.Max(1, math.Max(math.Abs(*a), math.Abs(*b)))
+	return math.Abs(*a-*b) <= tolerance*scale
+}
+
+func formatTime(r *ResultRow) string {
+	if r.Time == nil {
+		return "none"
+	}
+	return r.Time.Format("2006-01-02T15:04:05.999999999Z07:00")
+}
+
+func formatValue(v *float64) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v", *v)
+}
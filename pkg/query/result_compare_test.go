@@ -0,0 +1,67 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareResults(t *testing.T) {
+	t1 := time.Unix(60, 0).UTC()
+	t2 := time.Unix(120, 0).UTC()
+	row := func(ts *time.Time, key string, values ...*float64) ResultRow {
+		return ResultRow{Time: ts, Keys: []string{key}, Values: values}
+	}
+	reference := map[uint64]*QueryResult{
+		0: {ID: 0, Label: "a", Rows: []ResultRow{row(&t1, "h0", floatPtr(1000)), row(&t2, "h0", floatPtr(2))}},
+		1: {ID: 1, Label: "a", Rows: []ResultRow{row(&t1, "h0", floatPtr(1))}},
+		2: {ID: 2, Label: "b", Rows: []ResultRow{row(&t1, "h0", nil)}},
+		3: {ID: 3, Label: "b", Rows: []ResultRow{row(&t1, "h0", floatPtr(1))}},
+		4: {ID: 4, Label: "b"},
+	}
+	results := map[uint64]*QueryResult{
+		// same rows in another order, within relative tolerance
+		0: {ID: 0, Label: "a", Rows: []ResultRow{row(&t2, "h0", floatPtr(2)), row(&t1, "h0", floatPtr(1000.0005))}},
+		// outside tolerance
+		1: {ID: 1, Label: "a", Rows: []ResultRow{row(&t1, "h0", floatPtr(1.01))}},
+		// null vs zero
+		2: {ID: 2, Label: "b", Rows: []ResultRow{row(&t1, "h0", floatPtr(0))}},
+		// different time bucket
+		3: {ID: 3, Label: "b", Rows: []ResultRow{row(&t2, "h0", floatPtr(1))}},
+		// 4 is missing
+	}
+
+	got := CompareResults(reference, results, 1e-6*1000)
+	a, b := got["a"], got["b"]
+	if a == nil || b == nil {
+		t.Fatalf("missing labels in comparison: %v", got)
+	}
+	if a.Compared != 2 || len(a.Mismatches) != 1 || a.Mismatches[0].ID != 1 {
+		t.Errorf("wrong comparison for a: %+v", a)
+	} else if !strings.Contains(a.Mismatches[0].Reason, "value 0") {
+		t.Errorf("wrong reason for a: %s", a.Mismatches[0].Reason)
+	}
+	if b.Compared != 2 || b.Missing != 1 || len(b.Mismatches) != 2 {
+		t.Errorf("wrong comparison for b: %+v", b)
+	}
+	for _, m := range b.Mismatches {
+		if m.ID == 3 && !strings.Contains(m.Reason, "time") {
+			t.Errorf("wrong reason for query 3: %s", m.Reason)
+		}
+		if m.ID == 2 && !strings.Contains(m.Reason, "null") {
+			t.Errorf("wrong reason for query 2: %s", m.Reason)
+		}
+	}
+}
+
+func TestCompareQueryResultRowCount(t *testing.T) {
+	ref := &QueryResult{Label: "a", Rows: []ResultRow{{}, {}}}
+	res := &QueryResult{Label: "a", Rows: []ResultRow{{}}}
+	if reason := compareQueryResult(ref, res, 0); !strings.Contains(reason, "row count: 2 vs 1") {
+		t.Errorf("wrong reason: %s", reason)
+	}
+	res.Label = "b"
+	if reason := compareQueryResult(ref, res, 0); !strings.Contains(reason, "different queries") {
+		t.Errorf("wrong reason: %s", reason)
+	}
+}
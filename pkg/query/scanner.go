@@ -1,10 +1,13 @@
 package query
 
 import (
+	"bytes"
 	"encoding/gob"
 	"io"
+	"io/ioutil"
 	"log"
 	"sync"
+	"time"
 )
 
 // scanner is used to read in Queries from a Reader where they are
@@ -27,20 +30,51 @@ func (s *scanner) setReader(r io.Reader) *scanner {
 
 // scan reads encoded Queries and places them into a channel
 func (s *scanner) scan(pool *sync.Pool, c chan Query) {
-	decoder := gob.NewDecoder(s.r)
+	s.decode(s.r, pool, c, 0, time.Time{})
+}
+
+// scanUntil buffers all encoded Queries in memory and replays them into a
+// channel, starting over from the first one whenever the input runs out,
+// until the deadline passes or the queries limit is reached
+func (s *scanner) scanUntil(pool *sync.Pool, c chan Query, deadline time.Time) {
+	buf, err := ioutil.ReadAll(s.r)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	n := uint64(0)
+	for {
+		sent, done := s.decode(bytes.NewReader(buf), pool, c, n, deadline)
+		if done || sent == n {
+			// stop also on an empty input, replaying it would spin forever
+			break
+		}
+		n = sent
+	}
+}
+
+// decode reads encoded Queries from r and places them into a channel,
+// numbering them starting from n. It returns the number of the next query
+// and whether the scan was stopped by the limit or the deadline (a zero
+// deadline means no deadline) rather than by the end of the input
+func (s *scanner) decode(r io.Reader, pool *sync.Pool, c chan Query, n uint64, deadline time.Time) (uint64, bool) {
+	decoder := gob.NewDecoder(r)
+
 	for {
 		if *s.limit > 0 && n >= *s.limit {
 			// request queries limit reached, time to quit
-			break
+			return n, true
+		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			// time budget used up, time to quit
+			return n, true
 		}
 
 		q := pool.Get().(Query)
 		err := decoder.Decode(q)
 		if err == io.EOF {
 			// EOF, all done
-			break
+			return n, false
 		}
 		if err != nil {
 			// Can't read, time to quit
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 )
 
 type testQuery struct {
@@ -113,6 +114,90 @@ func TestScannerLimit(t *testing.T) {
 	}
 }
 
+func TestScannerScanUntil(t *testing.T) {
+	totalQueries := uint64(7)
+	var b bytes.Buffer
+	err := encodeQueries(&b, totalQueries, func(i uint64) Query {
+		return &testQuery{
+			HumanLabel:       []byte(fmt.Sprintf("testlabel%d", i)),
+			HumanDescription: []byte("testDesc"),
+		}
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	cases := []struct {
+		desc     string
+		limit    uint64
+		duration time.Duration
+		input    []byte
+		want     uint64 // 0 means more than totalQueries are expected
+	}{
+		{
+			desc:     "replays input until deadline",
+			duration: 50 * time.Millisecond,
+			input:    b.Bytes(),
+		},
+		{
+			desc:     "limit stops replay before deadline",
+			limit:    10,
+			duration: time.Minute,
+			input:    b.Bytes(),
+			want:     10,
+		},
+		{
+			desc:     "empty input does not spin",
+			duration: time.Minute,
+			input:    []byte{},
+		},
+	}
+
+	for _, c := range cases {
+		limit := c.limit
+		queryChan := make(chan Query, 1)
+		var got []Query
+		done := make(chan struct{})
+		go func() {
+			for q := range queryChan {
+				got = append(got, q)
+			}
+			close(done)
+		}()
+		start := time.Now()
+		input := bufio.NewReader(bytes.NewReader(c.input))
+		newScanner(&limit).setReader(input).scanUntil(&testQueryPool, queryChan, start.Add(c.duration))
+		close(queryChan)
+		<-done
+
+		switch {
+		case len(c.input) == 0:
+			if len(got) != 0 {
+				t.Errorf("%s: got %d queries, want none", c.desc, len(got))
+			}
+			if time.Since(start) > time.Second {
+				t.Errorf("%s: scan did not return on empty input", c.desc)
+			}
+			continue
+		case c.want == 0:
+			if uint64(len(got)) <= totalQueries {
+				t.Errorf("%s: got %d queries, want more than %d", c.desc, len(got), totalQueries)
+			}
+		case uint64(len(got)) != c.want:
+			t.Errorf("%s: got %d queries, want %d", c.desc, len(got), c.want)
+		}
+		for i, q := range got {
+			if q.GetID() != uint64(i) {
+				t.Errorf("%s: wrong ID for query: got %d want %d", c.desc, q.GetID(), i)
+			}
+			want := fmt.Sprintf("testlabel%d", uint64(i)%totalQueries)
+			if label := string(q.HumanLabelName()); label != want {
+				t.Errorf("%s: wrong label for query %d: got %s want %s", c.desc, i, label, want)
+			}
+		}
+	}
+}
+
 func TestScanTimescaleDB(t *testing.T) {
 	labelFmt := "tslabel%d"
 	descFmt := "tsdesc%d"
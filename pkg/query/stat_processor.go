@@ -19,6 +19,9 @@ type statProcessor interface {
 	getArgs() *statProcessorArgs
 	send(stats []*Stat)
 	sendWarm(stats []*Stat)
+	sendError(label []byte, kind string)
+	sendRetry(label []byte)
+	init(workers uint)
 	process(workers uint)
 	CloseAndWait()
 	GetTotalsMap() map[string]interface{}
@@ -30,6 +33,7 @@ type statProcessorArgs struct {
 	burnIn           uint64  // burnIn is the number of statistics to ignore before analyzing
 	printInterval    uint64  // printInterval is how often print intermediate stats (number of queries)
 	hdrLatenciesFile string  // hdrLatenciesFile is the filename to Write the High Dynamic Range (HDR) Histogram of Response Latencies to
+	openLoop         bool    // openLoop tells the StatProcessor that stats are corrected for arrival delay and carry service times
 
 }
 
@@ -76,11 +80,40 @@ func (sp *defaultStatProcessor) sendWarm(stats []*Stat) {
 	sp.send(stats)
 }
 
+func (sp *defaultStatProcessor) sendError(label []byte, kind string) {
+	s := GetStat().Init(label, 0)
+	s.errorKind = kind
+	sp.c <- s
+}
+
+func (sp *defaultStatProcessor) sendRetry(label []byte) {
+	s := GetStat().Init(label, 0)
+	s.isRetry = true
+	sp.c <- s
+}
+
+// init prepares the StatProcessor to receive stats. It has to be called
+// before process is started, so that no stats are sent before there is a
+// channel to receive them.
+func (sp *defaultStatProcessor) init(workers uint) {
+	sp.c = make(chan *Stat, workers)
+	sp.wg.Add(1)
+}
+
+// statGroupOf returns the statGroup of the queries with the given label,
+// creating it for the first one.
+func (sp *defaultStatProcessor) statGroupOf(label []byte) *statGroup {
+	group, ok := sp.statMapping[string(label)]
+	if !ok {
+		group = newStatGroup(*sp.args.limit)
+		sp.statMapping[string(label)] = group
+	}
+	return group
+}
+
 // process collects latency results, aggregating them into summary
 // statistics. Optionally, they are printed to stderr at regular intervals.
 func (sp *defaultStatProcessor) process(workers uint) {
-	sp.c = make(chan *Stat, workers)
-	sp.wg.Add(1)
 	const allQueriesLabel = labelAllQueries
 	sp.statMapping = map[string]*statGroup{
 		allQueriesLabel: newStatGroup(*sp.args.limit),
@@ -97,6 +130,21 @@ func (sp *defaultStatProcessor) process(workers uint) {
 	prevRequestCount := uint64(0)
 
 	for stat := range sp.c {
+		// failed queries and retried attempts have no latency to record,
+		// and are counted neither towards the burn-in, the number of queries
+		// run nor the query rates
+		if stat.isRetry {
+			sp.statGroupOf(stat.label).pushRetry()
+			sp.statMapping[allQueriesLabel].pushRetry()
+			statPool.Put(stat)
+			continue
+		}
+		if stat.errorKind != "" {
+			sp.statGroupOf(stat.label).pushError(stat.errorKind)
+			sp.statMapping[allQueriesLabel].pushError(stat.errorKind)
+			statPool.Put(stat)
+			continue
+		}
 		atomic.AddUint64(&sp.opsCount, 1)
 		if i < sp.args.burnIn {
 			i++
@@ -108,21 +156,17 @@ func (sp *defaultStatProcessor) process(workers uint) {
 				log.Fatal(err)
 			}
 		}
-		if _, ok := sp.statMapping[string(stat.label)]; !ok {
-			sp.statMapping[string(stat.label)] = newStatGroup(*sp.args.limit)
-		}
-
-		sp.statMapping[string(stat.label)].push(stat.value)
+		sp.push(sp.statGroupOf(stat.label), stat)
 
 		if !stat.isPartial {
-			sp.statMapping[allQueriesLabel].push(stat.value)
+			sp.push(sp.statMapping[allQueriesLabel], stat)
 
 			// Only needed when differentiating between cold & warm
 			if sp.args.prewarmQueries {
 				if stat.isWarm {
-					sp.statMapping[labelWarmQueries].push(stat.value)
+					sp.push(sp.statMapping[labelWarmQueries], stat)
 				} else {
-					sp.statMapping[labelColdQueries].push(stat.value)
+					sp.push(sp.statMapping[labelColdQueries], stat)
 				}
 			}
 
@@ -164,10 +208,11 @@ func (sp *defaultStatProcessor) process(workers uint) {
 			prevTime = now
 		}
 	}
-	sinceStart := time.Now().Sub(sp.startTime)
+	sp.endTime = time.Now()
+	sinceStart := sp.endTime.Sub(sp.startTime)
 	overallQueryRate := float64(sp.opsCount) / float64(sinceStart.Seconds())
 	// the final stats output goes to stdout:
-	_, err := fmt.Printf("Run complete after %d queries with %d workers (Overall query rate %0.2f queries/sec):\n", i-sp.args.burnIn, workers, overallQueryRate)
+	_, err := fmt.Printf("Run complete after %d queries with %d workers in %0.2fsec (Overall query rate %0.2f queries/sec):\n", i-sp.args.burnIn, workers, sinceStart.Seconds(), overallQueryRate)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -194,6 +239,17 @@ func (sp *defaultStatProcessor) process(workers uint) {
 	sp.wg.Done()
 }
 
+// push adds a stat to a statGroup, along with its service time in open-loop runs.
+func (sp *defaultStatProcessor) push(sg *statGroup, stat *Stat) {
+	sg.push(stat.value)
+	if sp.args.openLoop {
+		sg.pushServiceTime(stat.serviceTime)
+	}
+	if stat.hasResultSize {
+		sg.pushResultSize(stat.series, stat.samples)
+	}
+}
+
 func generateQuantileMap(hist *hdrhistogram.Histogram) (int64, map[string]float64) {
 	ops := hist.TotalCount()
 	q0 := 0.0
@@ -223,7 +279,10 @@ func (sp *defaultStatProcessor) GetTotalsMap() map[string]interface{} {
 	totals["limit"] = sp.args.limit
 	// burnIn is the number of statistics to ignore before analyzing
 	totals["burnIn"] = sp.args.burnIn
-	sinceStart := time.Now().Sub(sp.startTime)
+	// use the window in which the stats were actually collected, which
+	// in time-bounded runs can overshoot the requested duration
+	sinceStart := sp.endTime.Sub(sp.startTime)
+	totals["elapsedSeconds"] = sinceStart.Seconds()
 	// calculate overall query rates
 	queryRates := make(map[string]interface{})
 	for label, statGroup := range sp.statMapping {
@@ -238,6 +297,29 @@ func (sp *defaultStatProcessor) GetTotalsMap() map[string]interface{} {
 		quantiles[stripRegex(label)] = all
 	}
 	totals["overallQuantiles"] = quantiles
+	// count failed queries by label and kind of error
+	errorCounts := make(map[string]interface{})
+	for label, statGroup := range sp.statMapping {
+		counts := map[string]int64{"total": statGroup.errorsCount, "retries": statGroup.retries}
+		for _, kind := range errorKinds {
+			counts[kind] = statGroup.errors[kind]
+		}
+		errorCounts[stripRegex(label)] = counts
+	}
+	totals["overallErrors"] = errorCounts
+	if sp.args.openLoop {
+		// overallQuantiles are measured from the intended send times,
+		// these are the raw latencies of the same queries
+		serviceQuantiles := make(map[string]interface{})
+		for label, statGroup := range sp.statMapping {
+			if statGroup.serviceTimeHDRHistogram == nil {
+				continue
+			}
+			_, all := generateQuantileMap(statGroup.serviceTimeHDRHistogram)
+			serviceQuantiles[stripRegex(label)] = all
+		}
+		totals["overallServiceTimeQuantiles"] = serviceQuantiles
+	}
 	return totals
 }
 
@@ -77,3 +77,60 @@ func TestStatProcessorSendWarm(t *testing.T) {
 		t.Errorf("empty stat array changed channel length: got %d want %d", got, wantLen)
 	}
 }
+
+func TestStatProcessorProcessErrorsDuringBurnIn(t *testing.T) {
+	limit := uint64(0)
+	sp := newStatProcessor(&statProcessorArgs{limit: &limit, burnIn: 2}).(*defaultStatProcessor)
+	sp.init(1)
+	go sp.process(1)
+
+	label := []byte("foo")
+	sp.sendError(label, "timeout")
+	sp.sendRetry(label)
+	sp.sendError(label, "timeout")
+	for i := 0; i < 3; i++ {
+		sp.send([]*Stat{GetStat().Init(label, float64(i+1))})
+	}
+	sp.CloseAndWait()
+
+	all := sp.statMapping[labelAllQueries]
+	if got := all.errorsCount; got != 2 {
+		t.Errorf("incorrect error count: got %d want %d", got, 2)
+	}
+	// errors must not count towards the burn-in, so only the first two
+	// successful queries are burnt
+	if got := all.count; got != 1 {
+		t.Errorf("incorrect query count: got %d want %d", got, 1)
+	}
+	if got := sp.statMapping[string(label)].errorsCount; got != 2 {
+		t.Errorf("incorrect error count for label: got %d want %d", got, 2)
+	}
+	if got := all.retries; got != 1 {
+		t.Errorf("incorrect retry count: got %d want %d", got, 1)
+	}
+	// neither errors nor retries count towards the query rates
+	if got := sp.opsCount; got != 3 {
+		t.Errorf("incorrect ops count: got %d want %d", got, 3)
+	}
+}
+
+func TestStatProcessorProcessResultSize(t *testing.T) {
+	limit := uint64(0)
+	sp := newStatProcessor(&statProcessorArgs{limit: &limit}).(*defaultStatProcessor)
+	sp.init(1)
+	go sp.process(1)
+
+	foo, bar := []byte("foo"), []byte("bar")
+	sp.send([]*Stat{GetStat().Init(foo, 1).SetResultSize(2, 5)})
+	sp.send([]*Stat{GetStat().Init(foo, 2).SetResultSize(1, 2)})
+	sp.send([]*Stat{GetStat().Init(bar, 3)})
+	sp.CloseAndWait()
+
+	all := sp.statMapping[labelAllQueries]
+	if all.sizedCount != 2 || all.series != 3 || all.samples != 7 {
+		t.Errorf("incorrect result size: got %d, %d, %d", all.sizedCount, all.series, all.samples)
+	}
+	if got := sp.statMapping[string(bar)].sizedCount; got != 0 {
+		t.Errorf("incorrect sized count for label without result sizes: got %d want %d", got, 0)
+	}
+}
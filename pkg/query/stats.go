@@ -15,10 +15,18 @@ var (
 // Stat represents one statistical measurement, typically used to store the
 // latency of a query (or part of query).
 type Stat struct {
-	label     []byte
-	value     float64
-	isWarm    bool
-	isPartial bool
+	label       []byte
+	value       float64
+	serviceTime float64 // serviceTime is the uncorrected value in open-loop runs
+	errorKind   string  // errorKind is set when the stat reports a failed query instead of a latency
+	isRetry     bool    // isRetry is set when the stat reports a failed attempt that is retried
+	isWarm      bool
+	isPartial   bool
+
+	// hasResultSize is set when the stat reports the size of the result
+	hasResultSize bool
+	series        int64
+	samples       int64
 }
 
 var statPool = &sync.Pool{
@@ -47,15 +55,51 @@ func (s *Stat) Init(label []byte, value float64) *Stat {
 	s.label = s.label[:0] // clear
 	s.label = append(s.label, label...)
 	s.value = value
+	s.serviceTime = 0.0
+	s.errorKind = ""
+	s.isRetry = false
 	s.isWarm = false
+	s.hasResultSize = false
+	s.series = 0
+	s.samples = 0
+	return s
+}
+
+// SetResultSize sets the number of series and samples in the result of the
+// query, reported in the run summary
+func (s *Stat) SetResultSize(series, samples int64) *Stat {
+	s.hasResultSize = true
+	s.series = series
+	s.samples = samples
 	return s
 }
 
+// Label returns the label of the query (or part of query) the Stat measures
+func (s *Stat) Label() []byte {
+	return s.label
+}
+
+// Value returns the measured latency in milliseconds
+func (s *Stat) Value() float64 {
+	return s.value
+}
+
+// IsPartial tells whether the Stat measures only part of a query
+func (s *Stat) IsPartial() bool {
+	return s.isPartial
+}
+
 func (s *Stat) reset() *Stat {
 	s.label = s.label[:0]
 	s.value = 0.0
+	s.serviceTime = 0.0
+	s.errorKind = ""
+	s.isRetry = false
 	s.isWarm = false
 	s.isPartial = false
+	s.hasResultSize = false
+	s.series = 0
+	s.samples = 0
 	return s
 }
 
@@ -64,6 +108,23 @@ type statGroup struct {
 	latencyHDRHistogram *hdrhistogram.Histogram
 	sum                 float64
 	count               int64
+
+	// serviceTimeHDRHistogram holds the raw latencies of an open-loop run,
+	// while latencyHDRHistogram holds the ones measured from the intended
+	// send time. It stays nil in closed-loop runs.
+	serviceTimeHDRHistogram *hdrhistogram.Histogram
+
+	// errors counts the failed queries by kind of error
+	errors      map[string]int64
+	errorsCount int64
+	// retries counts the failed attempts that were retried
+	retries int64
+
+	// sizedCount counts the queries whose result size is known, with
+	// series and samples in their results
+	sizedCount int64
+	series     int64
+	samples    int64
 }
 
 // newStatGroup returns a new StatGroup with an initial size
@@ -75,13 +136,17 @@ func newStatGroup(size uint64) *statGroup {
 	//   - 1 microsecond up to 10 millisecond,
 	//   - 10 millisecond (or better) from 10 millisecond up to 10 seconds,
 	//   - 1 second (or better) from 10 second up to 3600 seconds,
-	lH := hdrhistogram.New(1, 3600000000, 4)
+	lH := newLatencyHDRHistogram()
 	return &statGroup{
 		count:               0,
 		latencyHDRHistogram: lH,
 	}
 }
 
+func newLatencyHDRHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(1, 3600000000, 4)
+}
+
 // push updates a StatGroup with a new value.
 func (s *statGroup) push(n float64) {
 	s.latencyHDRHistogram.RecordValue(int64(n * hdrScaleFactor))
@@ -89,9 +154,38 @@ func (s *statGroup) push(n float64) {
 	s.count++
 }
 
+// pushServiceTime updates a StatGroup with a new raw service time.
+func (s *statGroup) pushServiceTime(n float64) {
+	if s.serviceTimeHDRHistogram == nil {
+		s.serviceTimeHDRHistogram = newLatencyHDRHistogram()
+	}
+	s.serviceTimeHDRHistogram.RecordValue(int64(n * hdrScaleFactor))
+}
+
+// pushError updates a StatGroup with a failed query.
+func (s *statGroup) pushError(kind string) {
+	if s.errors == nil {
+		s.errors = make(map[string]int64)
+	}
+	s.errors[kind]++
+	s.errorsCount++
+}
+
+// pushRetry updates a StatGroup with a failed attempt that is retried.
+func (s *statGroup) pushRetry() {
+	s.retries++
+}
+
+// pushResultSize updates a StatGroup with the size of a query result.
+func (s *statGroup) pushResultSize(series, samples int64) {
+	s.sizedCount++
+	s.series += series
+	s.samples += samples
+}
+
 // string makes a simple description of a statGroup.
 func (s *statGroup) string() string {
-	return fmt.Sprintf("min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms, sum: %5.1fsec, count: %d",
+	str := fmt.Sprintf("min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms, sum: %5.1fsec, count: %d",
 		s.Min(),
 		s.Median(),
 		s.Mean(),
@@ -99,6 +193,34 @@ func (s *statGroup) string() string {
 		s.StdDev(),
 		s.sum/hdrScaleFactor,
 		s.count)
+	if h := s.serviceTimeHDRHistogram; h != nil {
+		str += fmt.Sprintf("\nservice time: min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms",
+			float64(h.Min())/hdrScaleFactor,
+			float64(h.ValueAtQuantile(50.0))/hdrScaleFactor,
+			h.Mean()/hdrScaleFactor,
+			float64(h.Max())/hdrScaleFactor,
+			h.StdDev()/hdrScaleFactor)
+	}
+	if s.sizedCount > 0 {
+		str += fmt.Sprintf("\nresult size: mean series: %.1f, mean samples: %.1f, samples: %d",
+			float64(s.series)/float64(s.sizedCount),
+			float64(s.samples)/float64(s.sizedCount),
+			s.samples)
+	}
+	if s.errorsCount > 0 {
+		str += fmt.Sprintf("\nerrors: %d (", s.errorsCount)
+		for i, kind := range errorKinds {
+			if i > 0 {
+				str += ", "
+			}
+			str += fmt.Sprintf("%s: %d", kind, s.errors[kind])
+		}
+		str += ")"
+	}
+	if s.retries > 0 {
+		str += fmt.Sprintf("\nretries: %d", s.retries)
+	}
+	return str
 }
 
 func (s *statGroup) write(w io.Writer) error {
@@ -49,6 +49,7 @@ func TestStatReset(t *testing.T) {
 	s.isWarm = true
 	s.label = []byte("foo")
 	s.value = 100.0
+	s.SetResultSize(2, 5)
 	s.reset()
 	if s.isPartial {
 		t.Errorf("reset() failed - isPartial = true")
@@ -62,6 +63,9 @@ func TestStatReset(t *testing.T) {
 	if s.value != 0.0 {
 		t.Errorf("reset() failed - value is not 0.0")
 	}
+	if s.hasResultSize || s.series != 0 || s.samples != 0 {
+		t.Errorf("reset() failed - result size is set")
+	}
 }
 
 func TestStateGroupMedian(t *testing.T) {
@@ -405,3 +409,43 @@ func TestWriteStatGroupMap(t *testing.T) {
 		}
 	}
 }
+
+func TestStatGroupPushServiceTime(t *testing.T) {
+	sg := newStatGroup(0)
+	sg.push(15)
+	if strings.Contains(sg.string(), "service time") {
+		t.Errorf("closed-loop stat group should not print service times: %s", sg.string())
+	}
+
+	sg.pushServiceTime(10)
+	if sg.serviceTimeHDRHistogram.TotalCount() != 1 {
+		t.Errorf("service time not recorded")
+	}
+	if got := sg.serviceTimeHDRHistogram.Max(); got != int64(10*hdrScaleFactor) {
+		t.Errorf("wrong service time recorded: got %d", got)
+	}
+	if got := sg.Max(); got != 15 {
+		t.Errorf("service time changed the latency histogram: got max %f", got)
+	}
+	if !strings.Contains(sg.string(), "service time") {
+		t.Errorf("open-loop stat group should print service times: %s", sg.string())
+	}
+}
+
+func TestStatGroupPushResultSize(t *testing.T) {
+	sg := newStatGroup(0)
+	sg.push(15)
+	if strings.Contains(sg.string(), "result size") {
+		t.Errorf("stat group without result sizes should not print them: %s", sg.string())
+	}
+
+	sg.pushResultSize(2, 5)
+	sg.pushResultSize(1, 2)
+	if sg.sizedCount != 2 || sg.series != 3 || sg.samples != 7 {
+		t.Errorf("result sizes not recorded: got %d, %d, %d", sg.sizedCount, sg.series, sg.samples)
+	}
+	want := "\nresult size: mean series: 1.5, mean samples: 3.5, samples: 7"
+	if !strings.Contains(sg.string(), want) {
+		t.Errorf("stat group should print result sizes: got %s want %s", sg.string(), want)
+	}
+}
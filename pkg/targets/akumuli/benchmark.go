@@ -1,27 +1,62 @@
 package akumuli
 
 import (
+	"bytes"
+	"sync"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/internal/inputs"
 	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
 	"github.com/timescale/tsbs/pkg/targets"
-	"sync"
 )
 
-func NewBenchmark(loadFileName, endpoint string, bufPool *sync.Pool) targets.Benchmark {
-	return &benchmark{
-		loadFileName: loadFileName,
-		endpoint:     endpoint,
-		bufPool:      bufPool,
+// SpecificConfig holds the Akumuli specific loading options.
+type SpecificConfig struct {
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
 	}
+	return &conf, nil
+}
+
+func NewBenchmark(opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		ds = &fileDataSource{reader: load.GetBufferedReader(dataSourceConfig.File.Location)}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &benchmark{
+		ds:       ds,
+		endpoint: opts.Endpoint,
+		bufPool:  &bufPool,
+	}, nil
 }
 
 type benchmark struct {
-	loadFileName string
-	endpoint     string
-	bufPool      *sync.Pool
+	ds       targets.DataSource
+	endpoint string
+	bufPool  *sync.Pool
 }
 
 func (b *benchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{reader: load.GetBufferedReader(b.loadFileName)}
+	return b.ds
 }
 
 func (b *benchmark) GetBatchFactory() targets.BatchFactory {
@@ -25,9 +25,15 @@ func (t *akumuliTarget) TargetName() string {
 }
 
 func (t *akumuliTarget) Serializer() serialize.PointSerializer {
-	return &Serializer{}
+	return NewAkumuliSerializer()
 }
 
-func (t *akumuliTarget) Benchmark(string, *source.DataSourceConfig, *viper.Viper) (targets.Benchmark, error) {
-	panic("not implemented")
+func (t *akumuliTarget) Benchmark(
+	_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	akumuliSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(akumuliSpecificConfig, dataSourceConfig)
 }
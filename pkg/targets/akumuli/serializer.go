@@ -2,7 +2,6 @@ package akumuli
 
 import (
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
@@ -34,9 +33,29 @@ func NewAkumuliSerializer() *Serializer {
 // Serialize writes Point data to the given writer, conforming to the
 // AKUMULI RESP protocol.  Serializer adds extra data to guide data loader.
 // This function writes output that contains binary and text data in RESP format.
+//
+// Missing and string field values are left out of both the series name and
+// the values, so a point without some values is written to a series of its
+// own. Booleans are written as 1 or 0 and unsigned integers as floats, since
+// Akumuli values are numbers.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	deferPoint := false
 
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	present := make([]int, 0, len(fieldValues))
+	for i, v := range fieldValues {
+		switch v.(type) {
+		case nil, string, []byte:
+		default:
+			present = append(present, i)
+		}
+	}
+	if len(present) == 0 {
+		// no values to write
+		return nil
+	}
+
 	buf := make([]byte, 0, 1024)
 	// Add cue
 	const HeaderLength = 8
@@ -44,13 +63,12 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	buf = append(buf, "+"...)
 
 	// Series name
-	fieldKeys := p.FieldKeys()
 	measurementName := p.MeasurementName()
-	for i := 0; i < len(fieldKeys); i++ {
+	for i, j := range present {
 		buf = append(buf, measurementName...)
 		buf = append(buf, '.')
-		buf = append(buf, fieldKeys[i]...)
-		if i+1 < len(fieldKeys) {
+		buf = append(buf, fieldKeys[j]...)
+		if i+1 < len(present) {
 			buf = append(buf, '|')
 		} else {
 			buf = append(buf, ' ')
@@ -60,6 +78,9 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	tagKeys := p.TagKeys()
 	tagValues := p.TagValues()
 	for i := 0; i < len(tagKeys); i++ {
+		if tagValues[i] == nil {
+			continue
+		}
 		buf = append(buf, ' ')
 		buf = append(buf, tagKeys[i]...)
 		buf = append(buf, '=')
@@ -80,38 +101,29 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 			binary.LittleEndian.PutUint32(buf[:4], id)
 		} else {
 			// Shortcut
-			s.index++
-			tmp := make([]byte, 0, 1024)
-			tmp = append(tmp, placeholderText...)
-			tmp = append(tmp, "*2\n"...)
-			tmp = append(tmp, buf[HeaderLength:]...)
-			tmp = append(tmp, '\n')
-			tmp = append(tmp, fmt.Sprintf(":%d\n", s.index)...)
-			s.book[series] = s.index
-			// Update cue
-			binary.LittleEndian.PutUint16(tmp[4:6], uint16(len(tmp)))
-			binary.LittleEndian.PutUint16(tmp[6:HeaderLength], uint16(0))
-			binary.LittleEndian.PutUint32(tmp[:4], s.index)
-			binary.LittleEndian.PutUint32(buf[:4], s.index)
-			_, err = w.Write(tmp)
-			if err != nil {
+			if err = s.addToBook(series, w); err != nil {
 				return err
 			}
+			binary.LittleEndian.PutUint32(buf[:4], s.index)
 			deferPoint = true
 			buf = buf[:HeaderLength]
 			buf = append(buf, fmt.Sprintf(":%d", s.index)...)
 		}
 	} else {
-		// Replace the series name with the value from the book
-		if id, ok := s.book[series]; ok {
-			buf = buf[:HeaderLength]
-			buf = append(buf, fmt.Sprintf(":%d", id)...)
-			binary.LittleEndian.PutUint16(buf[4:6], uint16(len(buf)))
-			binary.LittleEndian.PutUint16(buf[6:HeaderLength], uint16(0))
-			binary.LittleEndian.PutUint32(buf[:4], id)
-		} else {
-			return errors.New("unexpected series name")
+		// Replace the series name with the value from the book. Points with
+		// missing values may bring up series that are not in it yet.
+		id, ok := s.book[series]
+		if !ok {
+			if err = s.addToBook(series, w); err != nil {
+				return err
+			}
+			id = s.index
 		}
+		buf = buf[:HeaderLength]
+		buf = append(buf, fmt.Sprintf(":%d", id)...)
+		binary.LittleEndian.PutUint16(buf[4:6], uint16(len(buf)))
+		binary.LittleEndian.PutUint16(buf[6:HeaderLength], uint16(0))
+		binary.LittleEndian.PutUint32(buf[:4], id)
 	}
 
 	buf = append(buf, '\n')
@@ -122,15 +134,23 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	buf = append(buf, '\n')
 
 	// Values
-	fieldValues := p.FieldValues()
-	buf = append(buf, fmt.Sprintf("*%d\n", len(fieldValues))...)
-	for i := 0; i < len(fieldValues); i++ {
+	buf = append(buf, fmt.Sprintf("*%d\n", len(present))...)
+	for _, i := range present {
 		v := fieldValues[i]
-		switch v.(type) {
+		switch t := v.(type) {
 		case int, int64:
 			buf = append(buf, ':')
 		case float64:
 			buf = append(buf, '+')
+		case uint64:
+			buf = append(buf, '+')
+			v = float64(t)
+		case bool:
+			buf = append(buf, ':')
+			v = 0
+			if t {
+				v = 1
+			}
 		}
 		buf = serialize.FastFormatAppend(v, buf)
 		buf = append(buf, '\n')
@@ -138,7 +158,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 
 	// Update cue
 	binary.LittleEndian.PutUint16(buf[4:6], uint16(len(buf)))
-	binary.LittleEndian.PutUint16(buf[6:HeaderLength], uint16(len(fieldValues)))
+	binary.LittleEndian.PutUint16(buf[6:HeaderLength], uint16(len(present)))
 	if deferPoint {
 		s.deferred = append(s.deferred, buf...)
 		return nil
@@ -146,3 +166,23 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	_, err = w.Write(buf)
 	return err
 }
+
+// addToBook assigns the next id to series and writes the book entry that maps
+// the series name to it, so the loader can send it ahead of the points.
+func (s *Serializer) addToBook(series string, w io.Writer) error {
+	const HeaderLength = 8
+	s.index++
+	tmp := make([]byte, 0, 1024)
+	tmp = append(tmp, placeholderText...)
+	tmp = append(tmp, "*2\n"...)
+	tmp = append(tmp, series...)
+	tmp = append(tmp, '\n')
+	tmp = append(tmp, fmt.Sprintf(":%d\n", s.index)...)
+	s.book[series] = s.index
+	// Update cue
+	binary.LittleEndian.PutUint16(tmp[4:6], uint16(len(tmp)))
+	binary.LittleEndian.PutUint16(tmp[6:HeaderLength], uint16(0))
+	binary.LittleEndian.PutUint32(tmp[:4], s.index)
+	_, err := w.Write(tmp)
+	return err
+}
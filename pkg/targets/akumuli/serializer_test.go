@@ -78,3 +78,76 @@ func TestAkumuliSerializerSerialize(t *testing.T) {
 		}
 	}
 }
+
+func TestAkumuliSerializerSerializeNilField(t *testing.T) {
+	serializer := NewAkumuliSerializer()
+	buf := new(bytes.Buffer)
+	// the second point closes the book, so the deferred first one is written
+	for i := 0; i < 2; i++ {
+		if err := serializer.Serialize(serialize.TestPointWithNilField(), buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "big_usage_guest") {
+		t.Errorf("Output incorrect: nil field in series name:\n%s", got)
+	}
+	if !strings.Contains(got, "+cpu.usage_guest_nice ") {
+		t.Errorf("Output incorrect: present field missing from series name:\n%s", got)
+	}
+	if !strings.Contains(got, "*1\n+38.24311829") {
+		t.Errorf("Output incorrect: wrong values:\n%s", got)
+	}
+
+	p := serialize.TestPointWithNilField()
+	p.ClearFieldValue(serialize.TestColFloat)
+	buf.Reset()
+	if err := serializer.Serialize(p, buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Output incorrect: point without values written:\n%s", buf.String())
+	}
+}
+
+func TestAkumuliSerializerSerializeNilTag(t *testing.T) {
+	serializer := NewAkumuliSerializer()
+	buf := new(bytes.Buffer)
+	// the second point closes the book, so the deferred first one is written
+	for i := 0; i < 2; i++ {
+		if err := serializer.Serialize(serialize.TestPointWithNilTag(), buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "hostname") {
+		t.Errorf("Output incorrect: nil tag in series name:\n%s", got)
+	}
+	if !strings.Contains(got, "+cpu.usage_guest_nice ") {
+		t.Errorf("Output incorrect: series name missing:\n%s", got)
+	}
+}
+
+func TestAkumuliSerializerSerializeTypedFields(t *testing.T) {
+	serializer := NewAkumuliSerializer()
+	buf := new(bytes.Buffer)
+	// the second point closes the book, so the deferred first one is written
+	for i := 0; i < 2; i++ {
+		if err := serializer.Serialize(serialize.TestPointTypedFields(), buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "cpu.status") {
+		t.Errorf("Output incorrect: string field in series name:\n%s", got)
+	}
+	if !strings.Contains(got, "+cpu.reachable|cpu.requests_total ") {
+		t.Errorf("Output incorrect: numeric fields missing from series name:\n%s", got)
+	}
+	if !strings.Contains(got, "*2\n:1\n+18446744073709552000\n") {
+		t.Errorf("Output incorrect: wrong values:\n%s", got)
+	}
+}
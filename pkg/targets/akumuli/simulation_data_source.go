@@ -0,0 +1,78 @@
+package akumuli
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator:  sim,
+		headers:    sim.Headers(),
+		serializer: NewAkumuliSerializer(),
+	}
+}
+
+// simulationDataSource runs the simulated points through the RESP serializer
+// and hands out the resulting records one at a time. The serializer defers
+// points until its series book is complete, so a single simulated point can
+// produce zero or several records.
+type simulationDataSource struct {
+	simulator  common.Simulator
+	headers    *common.GeneratedDataHeaders
+	serializer *Serializer
+	buf        bytes.Buffer
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	for d.buf.Len() == 0 {
+		if !d.serializeNext() {
+			return data.LoadedPoint{}
+		}
+	}
+
+	// every record starts with the series id and the record length,
+	// same as in the files read by fileDataSource
+	nbytes := binary.LittleEndian.Uint16(d.buf.Bytes()[4:6])
+	body := make([]byte, nbytes)
+	if _, err := d.buf.Read(body); err != nil {
+		log.Fatalf("could not read serialized point: %v", err)
+	}
+	return data.NewLoadedPoint(body)
+}
+
+// serializeNext simulates the next point and appends its serialized form to
+// the internal buffer. Returns false when the simulator is finished.
+func (d *simulationDataSource) serializeNext() bool {
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return false
+	}
+
+	if err := d.serializer.Serialize(newSimulatorPoint, &d.buf); err != nil {
+		log.Fatalf("could not serialize simulated point: %v", err)
+	}
+	return true
+}
@@ -2,9 +2,9 @@ package cassandra
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
 	"github.com/gocql/gocql"
+	"github.com/timescale/tsbs/internal/inputs"
 	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data/source"
 	"github.com/timescale/tsbs/pkg/targets"
@@ -12,15 +12,11 @@ import (
 )
 
 type benchmark struct {
-	dbc                *dbCreator
-	dataSourceFileName string
+	dbc *dbCreator
+	ds  targets.DataSource
 }
 
 func NewBenchmark(dbSpecificConfig *SpecificConfig, dsConfig *source.DataSourceConfig) (targets.Benchmark, error) {
-	if dsConfig.Type != source.FileDataSourceType {
-		return nil, errors.New("only FILE data source implemented for Cassandra")
-	}
-
 	if _, ok := consistencyMapping[dbSpecificConfig.ConsistencyLevel]; !ok {
 		return nil, fmt.Errorf(
 			"invalid consistency level %s; allowed: %v",
@@ -28,6 +24,18 @@ func NewBenchmark(dbSpecificConfig *SpecificConfig, dsConfig *source.DataSourceC
 			consistencyMapping,
 		)
 	}
+	var ds targets.DataSource
+	if dsConfig.Type == source.FileDataSourceType {
+		ds = &fileDataSource{scanner: bufio.NewScanner(load.GetBufferedReader(dsConfig.File.Location))}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dsConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
 	return &benchmark{
 		dbc: &dbCreator{
 			hosts:             dbSpecificConfig.Hosts,
@@ -35,12 +43,12 @@ func NewBenchmark(dbSpecificConfig *SpecificConfig, dsConfig *source.DataSourceC
 			replicationFactor: dbSpecificConfig.ReplicationFactor,
 			writeTimeout:      dbSpecificConfig.WriteTimeout,
 		},
-		dataSourceFileName: dsConfig.File.Location,
+		ds: ds,
 	}, nil
 }
 
 func (b *benchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{scanner: bufio.NewScanner(load.GetBufferedReader(b.dataSourceFileName))}
+	return b.ds
 }
 
 func (b *benchmark) GetBatchFactory() targets.BatchFactory {
@@ -9,7 +9,7 @@ type SpecificConfig struct {
 	Hosts             string        `yaml:"hosts" mapstructure:"hosts"`
 	ReplicationFactor int           `yaml:"replication-factor" mapstructure:"replication-factor"`
 	ConsistencyLevel  string        `yaml:"consistency" mapstructure:"consistency"`
-	WriteTimeout      time.Duration `yaml:"write-timeout" mapstructureL:"write-timeout"`
+	WriteTimeout      time.Duration `yaml:"write-timeout" mapstructure:"write-timeout"`
 }
 
 func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
@@ -32,6 +32,12 @@ func (t *cassandraTarget) Serializer() serialize.PointSerializer {
 	return &Serializer{}
 }
 
-func (t *cassandraTarget) Benchmark(string, *source.DataSourceConfig, *viper.Viper) (targets.Benchmark, error) {
-	panic("not implemented")
+func (t *cassandraTarget) Benchmark(
+	_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	cassandraSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(cassandraSpecificConfig, dataSourceConfig)
 }
@@ -1,6 +1,7 @@
 package cassandra
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
@@ -30,6 +31,8 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 			seriesIDPrefix = append(seriesIDPrefix, tagKeys[i]...)
 			seriesIDPrefix = append(seriesIDPrefix, '=')
 			seriesIDPrefix = append(seriesIDPrefix, []byte(t)...)
+		case nil:
+			continue
 		default:
 			panic("non-string tags not implemented for cassandra")
 		}
@@ -60,6 +63,8 @@ func typeNameForCassandra(v interface{}) string {
 	switch v.(type) {
 	case int, int64:
 		return "bigint"
+	case uint64:
+		return "varint"
 	case float64:
 		return "double"
 	case float32:
@@ -87,8 +92,28 @@ func generateFieldBuf(tsNanos int64, tsBucket string, seriesIDPrefix, key []byte
 	buf = append(buf, []byte(tsBucket)...)
 	buf = append(buf, comma...)
 	buf = append(buf, []byte(fmt.Sprintf("%d,", tsNanos))...)
-	buf = serialize.FastFormatAppend(value, buf)
+	buf = appendValue(buf, value)
 
 	buf = append(buf, []byte("\n")...)
 	return buf
 }
+
+// appendValue appends value as a CQL literal; blobs are written as hex
+// literals, so strings cannot break the CSV line or the INSERT statement.
+func appendValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return appendBlob(buf, []byte(v))
+	case []byte:
+		return appendBlob(buf, v)
+	}
+	return serialize.FastFormatAppend(value, buf)
+}
+
+func appendBlob(buf, b []byte) []byte {
+	buf = append(buf, "0x"...)
+	n := len(buf)
+	buf = append(buf, make([]byte, hex.EncodedLen(len(b)))...)
+	hex.Encode(buf[n:], b)
+	return buf
+}
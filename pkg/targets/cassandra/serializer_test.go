@@ -22,6 +22,18 @@ func TestCassandraSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointNoTags(),
 			Output:     "series_double,cpu,usage_guest_nice,2016-01-01,1451606400000000000,38.24311829\n",
 		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "series_double,cpu,usage_guest_nice,2016-01-01,1451606400000000000,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with string, boolean and unsigned fields",
+			InputPoint: serialize.TestPointTypedFields(),
+			Output: "series_blob,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,status,2016-01-01,1451606400000000000,0x6f6b\n" +
+				"series_boolean,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,reachable,2016-01-01,1451606400000000000,true\n" +
+				"series_varint,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,requests_total,2016-01-01,1451606400000000000,18446744073709551615\n",
+		},
 	}
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
@@ -54,6 +66,11 @@ func TestTypeNameForCassandra(t *testing.T) {
 			v:    int(5000000000),
 			want: "bigint",
 		},
+		{
+			desc: "type uint64",
+			v:    uint64(18446744073709551615),
+			want: "varint",
+		},
 		{
 			desc: "type float32",
 			v:    float32(3.2),
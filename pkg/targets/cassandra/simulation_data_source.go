@@ -0,0 +1,76 @@
+package cassandra
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+// simulationDataSource serializes each simulated point into the same CSV
+// lines a data file would contain. Cassandra stores one row per field, so
+// a single simulated point is handed out as several items.
+type simulationDataSource struct {
+	simulator  common.Simulator
+	headers    *common.GeneratedDataHeaders
+	serializer Serializer
+	pending    []string
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	for len(d.pending) == 0 {
+		if !d.serializeNext() {
+			return data.LoadedPoint{}
+		}
+	}
+
+	line := d.pending[0]
+	d.pending = d.pending[1:]
+	return data.NewLoadedPoint(line)
+}
+
+// serializeNext simulates the next point and queues its serialized lines.
+// Returns false when the simulator is finished.
+func (d *simulationDataSource) serializeNext() bool {
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return false
+	}
+
+	buf := &bytes.Buffer{}
+	if err := d.serializer.Serialize(newSimulatorPoint, buf); err != nil {
+		log.Fatalf("could not serialize simulated point: %v", err)
+	}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		d.pending = append(d.pending, scanner.Text())
+	}
+	return true
+}
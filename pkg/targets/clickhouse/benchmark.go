@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/timescale/tsbs/internal/inputs"
 	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/source"
 	"github.com/timescale/tsbs/pkg/targets"
 )
 
@@ -17,10 +19,10 @@ type ClickhouseConfig struct {
 	User     string
 	Password string
 
-	LogBatches bool
+	LogBatches bool `yaml:"log-batches" mapstructure:"log-batches"`
 	InTableTag bool
 	Debug      int
-	DbName     string
+	DbName     string `yaml:"-" mapstructure:"-"`
 }
 
 // String values of tags and fields to insert - string representation
@@ -31,6 +33,9 @@ type insertData struct {
 
 var tableCols map[string][]string
 
+// tableColTypes holds the types of the field columns in tableCols, if any are typed
+var tableColTypes map[string][]string
+
 var tagColumnTypes []string
 
 // allows for testing
@@ -90,21 +95,31 @@ func (f *factory) New() targets.Batch {
 
 const tagsPrefix = "tags"
 
-func NewBenchmark(file string, hashWorkers bool, conf *ClickhouseConfig) targets.Benchmark {
-	return &benchmark{
-		ds: &fileDataSource{
-			scanner: bufio.NewScanner(load.GetBufferedReader(file)),
-		},
-		hashWorkers: hashWorkers,
-		conf:        conf,
+func NewBenchmark(conf *ClickhouseConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		ds = &fileDataSource{
+			scanner: bufio.NewScanner(load.GetBufferedReader(dataSourceConfig.File.Location)),
+		}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
 	}
+
+	return &benchmark{
+		ds:   ds,
+		conf: conf,
+	}, nil
 }
 
 // targets.Benchmark interface implementation
 type benchmark struct {
-	ds          targets.DataSource
-	hashWorkers bool
-	conf        *ClickhouseConfig
+	ds   targets.DataSource
+	conf *ClickhouseConfig
 }
 
 func (b *benchmark) GetDataSource() targets.DataSource {
@@ -116,7 +131,7 @@ func (b *benchmark) GetBatchFactory() targets.BatchFactory {
 }
 
 func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
-	if b.hashWorkers {
+	if maxPartitions > 1 {
 		return &hostnameIndexer{
 			partitions: maxPartitions,
 		}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
 )
 
 func TestGetConnectString(t *testing.T) {
@@ -135,12 +136,44 @@ func TestDecodeEOF(t *testing.T) {
 	}
 }
 
+func TestPointToInsertData(t *testing.T) {
+	cases := []struct {
+		desc       string
+		input      *data.Point
+		wantTags   string
+		wantFields string
+	}{
+		{
+			desc:       "multiple fields",
+			input:      serialize.TestPointMultiField(),
+			wantTags:   "hostname=host_0,region=eu-west-1,datacenter=eu-west-1b",
+			wantFields: "1451606400000000000,5000000000,38,38.24311829",
+		},
+		{
+			desc:       "nil field",
+			input:      serialize.TestPointWithNilField(),
+			wantTags:   "",
+			wantFields: "1451606400000000000,,38.24311829",
+		},
+	}
+	for _, c := range cases {
+		row := pointToInsertData(c.input, nil)
+		if row.tags != c.wantTags {
+			t.Errorf("%s: incorrect tags: got %s want %s", c.desc, row.tags, c.wantTags)
+		}
+		if row.fields != c.wantFields {
+			t.Errorf("%s: incorrect fields: got %s want %s", c.desc, row.fields, c.wantFields)
+		}
+	}
+}
+
 func TestHeaders(t *testing.T) {
 	cases := []struct {
 		desc         string
 		input        string
 		wantTags     []string
 		wantCols     map[string][]string
+		wantColTypes map[string][]string
 		wantTypes    []string
 		shouldFatal  bool
 		wantBuffered int
@@ -177,6 +210,23 @@ func TestHeaders(t *testing.T) {
 			wantCols:     map[string][]string{"cols": {"col1", "col2"}, "cols2": {"col21", "col22"}},
 			wantBuffered: len([]byte("row1\nrow2\n")),
 		},
+		{
+			desc:         "comment lines are skipped",
+			input:        "tags,tag1 string,tag2 string\ncols,col1,col2\n#disorder,late-rate=0.1\n\n",
+			wantTags:     []string{"tag1", "tag2"},
+			wantTypes:    []string{"string", "string"},
+			wantCols:     map[string][]string{"cols": {"col1", "col2"}},
+			wantBuffered: 0,
+		},
+		{
+			desc:         "typed columns",
+			input:        "tags,tag1 string\ncols,col1 string,col2,col3 bool\n\n",
+			wantTags:     []string{"tag1"},
+			wantTypes:    []string{"string"},
+			wantCols:     map[string][]string{"cols": {"col1", "col2", "col3"}},
+			wantColTypes: map[string][]string{"cols": {"string", "", "bool"}},
+			wantBuffered: 0,
+		},
 		{
 			desc:        "too few lines",
 			input:       "tags\ncols\n",
@@ -220,6 +270,11 @@ func TestHeaders(t *testing.T) {
 					t.Errorf("%s: cols row incorrect: got\n%v\nwant\n%v\n", c.desc, got, want)
 				}
 			}
+			for key, want := range c.wantColTypes {
+				if got := headers.FieldTypes[key]; !strArrEq(got, want) {
+					t.Errorf("%s: col types row incorrect: got\n%v\nwant\n%v\n", c.desc, got, want)
+				}
+			}
 		}
 	}
 }
@@ -235,3 +290,38 @@ func strArrEq(a []string, b []string) bool {
 	}
 	return true
 }
+
+func TestConvertBasedOnType(t *testing.T) {
+	cases := []struct {
+		serializedType string
+		value          string
+		want           interface{}
+	}{
+		{serializedType: "float64", value: "", want: nil},
+		{serializedType: "float64", value: "1.5", want: 1.5},
+		{serializedType: "string", value: "ok", want: "ok"},
+		{serializedType: "bool", value: "true", want: uint8(1)},
+		{serializedType: "bool", value: "false", want: uint8(0)},
+		{serializedType: "uint64", value: "18446744073709551615", want: uint64(18446744073709551615)},
+	}
+	for _, c := range cases {
+		if got := convertBasedOnType(c.serializedType, c.value); got != c.want {
+			t.Errorf("incorrect value for %s '%s': got %#v want %#v", c.serializedType, c.value, got, c.want)
+		}
+	}
+}
+
+func TestTagsCacheKey(t *testing.T) {
+	// entities of other use cases leave the first tags empty, so the key has
+	// to tell them apart by the rest
+	truck1 := tagsCacheKey([]string{"", "truck_1", "South"}, 3)
+	truck2 := tagsCacheKey([]string{"", "truck_2", "South"}, 3)
+	if truck1 == truck2 {
+		t.Errorf("tags with the same first value share a key: %s", truck1)
+	}
+	// tags beyond the common ones are not part of the key
+	withExtra := tagsCacheKey([]string{"", "truck_1", "South", "port=80"}, 3)
+	if withExtra != truck1 {
+		t.Errorf("extra tags change the key: got %s want %s", withExtra, truck1)
+	}
+}
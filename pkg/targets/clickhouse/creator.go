@@ -84,6 +84,9 @@ func (d *dbCreator) CreateDB(dbName string) error {
 	if tableCols == nil {
 		tableCols = make(map[string][]string)
 	}
+	if tableColTypes == nil {
+		tableColTypes = make(map[string][]string)
+	}
 	tableCols["tags"] = d.headers.TagKeys
 	tagColumnTypes = d.headers.TagTypes
 
@@ -91,7 +94,7 @@ func (d *dbCreator) CreateDB(dbName string) error {
 		//tableName: cpu
 		// fieldColumns content:
 		// usage_user,usage_system,usage_idle,usage_nice,usage_iowait,usage_irq,usage_softirq,usage_steal,usage_guest,usage_guest_nice
-		createMetricsTable(d.config, db, tableName, fieldColumns)
+		createMetricsTable(d.config, db, tableName, fieldColumns, d.headers.FieldTypes[tableName])
 	}
 
 	return nil
@@ -110,8 +113,9 @@ func createTagsTable(conf *ClickhouseConfig, db *sqlx.DB, tagNames, tagTypes []s
 }
 
 // createMetricsTable builds CREATE TABLE SQL statement and runs it
-func createMetricsTable(conf *ClickhouseConfig, db *sqlx.DB, tableName string, fieldColumns []string) {
+func createMetricsTable(conf *ClickhouseConfig, db *sqlx.DB, tableName string, fieldColumns, fieldTypes []string) {
 	tableCols[tableName] = fieldColumns
+	tableColTypes[tableName] = fieldTypes
 
 	// We'll have some service columns in table to be created and columnNames contains all column names to be created
 	var columnNames []string
@@ -122,17 +126,25 @@ func createMetricsTable(conf *ClickhouseConfig, db *sqlx.DB, tableName string, f
 		columnNames = append(columnNames, partitioningColumn)
 	}
 
+	// The partitioning column is a number like the fields without a type
+	columnTypes := make([]string, len(columnNames), len(columnNames)+len(fieldColumns))
+	columnTypes = append(columnTypes, fieldTypes...)
+
 	// Add all column names from fieldColumns into columnNames
 	columnNames = append(columnNames, fieldColumns...)
 
 	// columnsWithType - column specifications with type. Ex.: "cpu_usage Float64"
 	var columnsWithType []string
-	for _, column := range columnNames {
+	for i, column := range columnNames {
 		if len(column) == 0 {
 			// Skip nameless columns
 			continue
 		}
-		columnsWithType = append(columnsWithType, fmt.Sprintf("%s Nullable(Float64)", column))
+		columnType := ""
+		if i < len(columnTypes) {
+			columnType = columnTypes[i]
+		}
+		columnsWithType = append(columnsWithType, fmt.Sprintf("%s %s", column, fieldTypeToClickHouseType(columnType)))
 	}
 
 	sql := fmt.Sprintf(`
@@ -185,10 +197,23 @@ func generateTagsTableQuery(tagNames, tagTypes []string) string {
 		index)
 }
 
+// fieldTypeToClickHouseType returns the column type of a field of the given
+// type; fields without a type are numbers, stored as floats.
+func fieldTypeToClickHouseType(fieldType string) string {
+	if fieldType == "" {
+		return "Nullable(Float64)"
+	}
+	return serializedTypeToClickHouseType(fieldType)
+}
+
 func serializedTypeToClickHouseType(serializedType string) string {
 	switch serializedType {
 	case "string":
 		return "Nullable(String)"
+	case "bool":
+		return "Nullable(UInt8)"
+	case "uint64":
+		return "Nullable(UInt64)"
 	case "float32":
 		return "Nullable(Float32)"
 	case "float64":
@@ -66,3 +66,17 @@ func TestGenerateTagsTableQueryPanicOnWrongType(t *testing.T) {
 
 	t.Fatalf("test should have stopped at this point")
 }
+
+func TestFieldTypeToClickHouseType(t *testing.T) {
+	cases := map[string]string{
+		"":       "Nullable(Float64)",
+		"string": "Nullable(String)",
+		"bool":   "Nullable(UInt8)",
+		"uint64": "Nullable(UInt64)",
+	}
+	for fieldType, want := range cases {
+		if got := fieldTypeToClickHouseType(fieldType); got != want {
+			t.Errorf("incorrect type for %q: got %s want %s", fieldType, got, want)
+		}
+	}
+}
@@ -100,6 +100,10 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 				// empty line - end of header
 				break
 			}
+			if strings.HasPrefix(line, common.HeaderCommentPrefix) {
+				// comment line - describes the data, not a table
+				continue
+			}
 			// append new table/columns set to the list of tables/columns set
 			cols = append(cols, line)
 		}
@@ -121,12 +125,14 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 	}
 	tagNames, tagTypes := extractTagNamesAndTypes(parts[1:])
 	fieldKeys := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	// cols content are lines (metrics descriptions) as:
 	// cpu,usage_user,usage_system,usage_idle,usage_nice,usage_iowait,usage_irq,usage_softirq,usage_steal,usage_guest,usage_guest_nice
 	// disk,total,free,used,used_percent,inodes_total,inodes_free,inodes_used
 	// nginx,accepts,active,handled,reading,requests,waiting,writing
 	// generalised description:
 	// tableName,fieldName1,...,fieldNameX
+	// where typed fields are followed by their type, e.g. 'status string'
 	for _, colsForMeasure := range cols {
 		tableSpec := strings.Split(colsForMeasure, ",")
 		// tableSpec contain
@@ -136,12 +142,13 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 
 		// Ex.: cpu OR disk OR nginx
 		tableName := tableSpec[0]
-		fieldKeys[tableName] = tableSpec[1:]
+		fieldKeys[tableName], fieldTypes[tableName] = common.ParseFieldColumns(tableSpec[1:])
 	}
 	d.headers = &common.GeneratedDataHeaders{
-		TagKeys:   tagNames,
-		TagTypes:  tagTypes,
-		FieldKeys: fieldKeys,
+		TagKeys:    tagNames,
+		TagTypes:   tagTypes,
+		FieldKeys:  fieldKeys,
+		FieldTypes: fieldTypes,
 	}
 	return d.headers
 }
@@ -16,8 +16,15 @@ func NewTarget() targets.ImplementedTarget {
 
 type clickhouseTarget struct{}
 
-func (c clickhouseTarget) Benchmark(string, *source.DataSourceConfig, *viper.Viper) (targets.Benchmark, error) {
-	panic("implement me")
+func (c clickhouseTarget) Benchmark(
+	targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var conf ClickhouseConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	conf.DbName = targetDB
+	return NewBenchmark(&conf, dataSourceConfig)
 }
 
 func (c clickhouseTarget) Serializer() serialize.PointSerializer {
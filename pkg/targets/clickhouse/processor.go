@@ -87,6 +87,7 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 	commonTagsLen := len(tableCols["tags"])
 
 	colLen := len(tableCols[tableName]) + 2
+	fieldTypes := tableColTypes[tableName]
 	if p.conf.InTableTag {
 		colLen++
 	}
@@ -164,16 +165,12 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 		if p.conf.InTableTag {
 			r = append(r, tags[0]) // tags[0] = hostname
 		}
-		for _, v := range metrics[1:] {
-			if v == "" {
-				r = append(r, nil)
-				continue
+		for i, v := range metrics[1:] {
+			fieldType := "float64"
+			if i < len(fieldTypes) && fieldTypes[i] != "" {
+				fieldType = fieldTypes[i]
 			}
-			f64, err := strconv.ParseFloat(v, 64)
-			if err != nil {
-				panic(err)
-			}
-			r = append(r, f64)
+			r = append(r, convertBasedOnType(fieldType, v))
 		}
 
 		dataRows = append(dataRows, r)
@@ -186,8 +183,7 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 	p.csi.mutex.RLock()
 	for _, tagRow := range tagRows {
 		// tagRow contains what was called `tags` earlier - see one screen higher
-		// tagRow[0] = hostname
-		if _, ok := p.csi.m[tagRow[0]]; !ok {
+		if _, ok := p.csi.m[tagsCacheKey(tagRow, commonTagsLen)]; !ok {
 			// Tags of this hostname are not listed as inserted - new tags line, add it for creation
 			newTags = append(newTags, tagRow)
 		}
@@ -198,10 +194,10 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 	if len(newTags) > 0 {
 		// We have new tags to insert
 		p.csi.mutex.Lock()
-		hostnameToTags := insertTags(p.conf, p.db, len(p.csi.m), newTags, true)
+		keyToTags := insertTags(p.conf, p.db, len(p.csi.m), newTags, true)
 		// Insert new tags into map as well
-		for hostName, tagsId := range hostnameToTags {
-			p.csi.m[hostName] = tagsId
+		for key, tagsId := range keyToTags {
+			p.csi.m[key] = tagsId
 		}
 		p.csi.mutex.Unlock()
 	}
@@ -209,9 +205,8 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 	// Deal with tag ids for each data row
 	p.csi.mutex.RLock()
 	for i := range dataRows {
-		// tagKey = hostname
-		tagKey := tagRows[i][0]
-		// Insert id of the tag (tags.id) for this host into tags_id position of the dataRows record
+		tagKey := tagsCacheKey(tagRows[i], commonTagsLen)
+		// Insert id of the tag (tags.id) for this entity into tags_id position of the dataRows record
 		// refers to
 		// nil,		// tags_id
 
@@ -266,9 +261,16 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 	return ret
 }
 
+// tagsCacheKey returns the key of the tags cache for a row of tags, made of
+// the values of the common tags. Every value is part of it, since data
+// combined from several use cases leaves the first tags of some entities empty.
+func tagsCacheKey(tagRow []string, commonTagsLen int) string {
+	return strings.Join(tagRow[:commonTagsLen], ",")
+}
+
 // insertTags fills tags table with values
 func insertTags(conf *ClickhouseConfig, db *sqlx.DB, startID int, rows [][]string, returnResults bool) map[string]int64 {
-	// Map hostname to tags_id
+	// Map tags to tags_id
 	ret := make(map[string]int64)
 
 	// reflect tags table structure which is
@@ -339,10 +341,10 @@ func insertTags(conf *ClickhouseConfig, db *sqlx.DB, startID int, rows [][]strin
 			panic(err)
 		}
 
-		// Fill map hostname -> id
+		// Fill map tags -> id
 		if returnResults {
-			// Map hostname -> tags_id
-			ret[row[0]] = int64(id)
+			// Map tags -> tags_id
+			ret[tagsCacheKey(row, len(cols))] = int64(id)
 		}
 	}
 
@@ -366,6 +368,21 @@ func convertBasedOnType(serializedType, value string) interface{} {
 	switch serializedType {
 	case "string":
 		return value
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			panic(fmt.Sprintf("could not parse '%s' to bool", value))
+		}
+		if b {
+			return uint8(1)
+		}
+		return uint8(0)
+	case "uint64":
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			panic(fmt.Sprintf("could not parse '%s' to uint64", value))
+		}
+		return u
 	case "float32":
 		f, err := strconv.ParseFloat(value, 32)
 		if err != nil {
@@ -0,0 +1,87 @@
+package clickhouse
+
+import (
+	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+// simulationDataSource converts simulated points directly into the
+// insertData rows that the file data source would produce.
+type simulationDataSource struct {
+	simulator common.Simulator
+	headers   *common.GeneratedDataHeaders
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	if d.headers == nil {
+		fatal("headers not read before starting to read points")
+		return data.LoadedPoint{}
+	}
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return data.LoadedPoint{}
+	}
+
+	return data.NewLoadedPoint(&point{
+		table: string(newSimulatorPoint.MeasurementName()),
+		row:   pointToInsertData(newSimulatorPoint, d.headers.FieldKeys[string(newSimulatorPoint.MeasurementName())]),
+	})
+}
+
+// pointToInsertData builds the string representation of the tags and fields
+// of a point, in the same format as the lines of a data file:
+// tags: hostname=host_0,region=eu-west-1,datacenter=eu-west-1b
+// fields: 1451606400000000000,58,2,24
+// The fields are written in the order of columns, empty if the point has none.
+func pointToInsertData(p *data.Point, columns []string) *insertData {
+	row := &insertData{}
+	tagValues := p.TagValues()
+	tagKeys := p.TagKeys()
+	buf := make([]byte, 0, 256)
+	for i, v := range tagValues {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, tagKeys[i]...)
+		buf = append(buf, '=')
+		buf = serialize.FastFormatAppend(v, buf)
+	}
+	row.tags = string(buf)
+
+	buf = buf[:0]
+	buf = strconv.AppendInt(buf, p.Timestamp().UTC().UnixNano(), 10)
+	for _, v := range serialize.ColumnValues(p, columns) {
+		buf = append(buf, ',')
+		buf = serialize.FastFormatAppend(v, buf)
+	}
+	row.fields = string(buf)
+	return row
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// EncodedPoint is the data of a point that is already encoded the way its
+// target sends it, so that a batch of points is sent as the concatenation of
+// their encodings.
+type EncodedPoint interface {
+	// Encoded returns the encoded point
+	Encoded() []byte
+	// MetricCount returns the number of metrics, i.e. field values, of the point
+	MetricCount() uint64
+}
+
+// ByteBatch implements targets.Batch by concatenating the encodings of its
+// EncodedPoints.
+type ByteBatch struct {
+	buf     []byte
+	rows    uint64
+	metrics uint64
+}
+
+func (b *ByteBatch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *ByteBatch) Append(item data.LoadedPoint) {
+	p := item.Data.(EncodedPoint)
+	b.buf = append(b.buf, p.Encoded()...)
+	b.rows++
+	b.metrics += p.MetricCount()
+}
+
+// Bytes returns the concatenated encodings of the points of the batch.
+func (b *ByteBatch) Bytes() []byte {
+	return b.buf
+}
+
+// Counts returns the number of metrics and rows of the batch, as
+// targets.Processor.ProcessBatch reports them.
+func (b *ByteBatch) Counts() (metricCount, rowCount uint64) {
+	return b.metrics, b.rows
+}
+
+func (b *ByteBatch) reset() {
+	b.buf = b.buf[:0]
+	b.rows = 0
+	b.metrics = 0
+}
+
+// ByteBatchFactory implements targets.BatchFactory, handing out ByteBatches
+// from a pool so that their buffers are reused once the batches are put back.
+type ByteBatchFactory struct {
+	pool sync.Pool
+}
+
+// NewByteBatchFactory returns a ByteBatchFactory with an empty pool.
+func NewByteBatchFactory() *ByteBatchFactory {
+	f := &ByteBatchFactory{}
+	f.pool.New = func() interface{} { return &ByteBatch{} }
+	return f
+}
+
+func (f *ByteBatchFactory) New() targets.Batch {
+	return f.pool.Get().(*ByteBatch)
+}
+
+// Put empties b and returns it to the pool, once it has been processed.
+func (f *ByteBatchFactory) Put(b *ByteBatch) {
+	b.reset()
+	f.pool.Put(b)
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+type testEncodedPoint struct {
+	encoded string
+	metrics uint64
+}
+
+func (p *testEncodedPoint) Encoded() []byte     { return []byte(p.encoded) }
+func (p *testEncodedPoint) MetricCount() uint64 { return p.metrics }
+
+func TestByteBatch(t *testing.T) {
+	f := NewByteBatchFactory()
+	b := f.New().(*ByteBatch)
+	b.Append(data.NewLoadedPoint(&testEncodedPoint{encoded: "ab", metrics: 2}))
+	b.Append(data.NewLoadedPoint(&testEncodedPoint{encoded: "c", metrics: 3}))
+	if got := b.Len(); got != 2 {
+		t.Errorf("incorrect batch length: got %d want 2", got)
+	}
+	if got := string(b.Bytes()); got != "abc" {
+		t.Errorf("incorrect batch bytes: got %s want abc", got)
+	}
+	if metrics, rows := b.Counts(); metrics != 5 || rows != 2 {
+		t.Errorf("incorrect counts: got %d metrics and %d rows, want 5 and 2", metrics, rows)
+	}
+
+	f.Put(b)
+	if b.Len() != 0 || len(b.Bytes()) != 0 {
+		t.Errorf("batch not emptied when put back")
+	}
+	if metrics, _ := b.Counts(); metrics != 0 {
+		t.Errorf("metric count not reset: got %d", metrics)
+	}
+}
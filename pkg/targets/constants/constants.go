@@ -14,6 +14,9 @@ const (
 	FormatVictoriaMetrics = "victoriametrics"
 	FormatTimestream      = "timestream"
 	FormatQuestDB         = "questdb"
+	FormatOTLP            = "otlp"
+	FormatElasticsearch   = "elasticsearch"
+	FormatGraphite        = "graphite"
 )
 
 func SupportedFormats() []string {
@@ -30,5 +33,8 @@ func SupportedFormats() []string {
 		FormatVictoriaMetrics,
 		FormatTimestream,
 		FormatQuestDB,
+		FormatOTLP,
+		FormatElasticsearch,
+		FormatGraphite,
 	}
 }
@@ -0,0 +1,101 @@
+package crate
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+
+	"github.com/blagojts/viper"
+	"github.com/jackc/pgx/v4"
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// the logger is used in implementations of interface methods that
+// do not return error on failures to allow testing such methods
+var fatal = log.Fatalf
+
+// SpecificConfig holds the CrateDB specific loading options.
+type SpecificConfig struct {
+	Hosts       string `yaml:"hosts" mapstructure:"hosts"`
+	Port        uint   `yaml:"port" mapstructure:"port"`
+	User        string `yaml:"user" mapstructure:"user"`
+	Pass        string `yaml:"pass" mapstructure:"pass"`
+	NumReplicas int    `yaml:"replicas" mapstructure:"replicas"`
+	NumShards   int    `yaml:"shards" mapstructure:"shards"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+type benchmark struct {
+	dbc *dbCreator
+	ds  targets.DataSource
+}
+
+func NewBenchmark(opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password='%s' dbname=doc",
+		opts.Hosts, opts.Port, opts.User, opts.Pass,
+	)
+	connConfig, err := pgx.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse connection config: %v", err)
+	}
+
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		ds = &fileDataSource{scanner: bufio.NewScanner(load.GetBufferedReader(dataSourceConfig.File.Location))}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	return &benchmark{
+		dbc: &dbCreator{
+			cfg:         connConfig,
+			numReplicas: opts.NumReplicas,
+			numShards:   opts.NumShards,
+			ds:          ds,
+		},
+		ds: ds,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	tableDefs := make(map[string]*tableDef)
+	for _, td := range b.dbc.tableDefs {
+		tableDefs[td.name] = td
+	}
+	return &processor{
+		tableDefs: tableDefs,
+		connCfg:   b.dbc.cfg,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return b.dbc
+}
@@ -1,4 +1,4 @@
-package main
+package crate
 
 import (
 	"context"
@@ -17,6 +17,7 @@ type tableDef struct {
 	tags     []string
 	tagTypes []string
 	cols     []string
+	colTypes []string
 }
 
 // fqn returns the fully-qualified name of a table
@@ -80,6 +81,7 @@ func (d *dbCreator) readDataHeader(header *common.GeneratedDataHeaders) ([]*tabl
 				tags:     header.TagKeys,
 				tagTypes: header.TagTypes,
 				cols:     fieldCols,
+				colTypes: header.FieldTypes[tableName],
 			},
 		)
 	}
@@ -111,10 +113,14 @@ func (d *dbCreator) createMetricsTable(table *tableDef) error {
 	}
 
 	var metricCols []string
-	for _, column := range table.cols {
+	for i, column := range table.cols {
+		colType := "double"
+		if i < len(table.colTypes) {
+			colType = fieldTypeToCrateType(table.colTypes[i])
+		}
 		metricCols = append(
 			metricCols,
-			fmt.Sprintf("%s %s", column, "double"))
+			fmt.Sprintf("%s %s", column, colType))
 	}
 
 	// TODO partition table by configurable time interval
@@ -137,6 +143,21 @@ func (d *dbCreator) createMetricsTable(table *tableDef) error {
 	return nil
 }
 
+// fieldTypeToCrateType returns the column type of a field of the given type.
+// CrateDB has no unsigned integers, so uint64 fields are stored as doubles
+// like the fields without a type.
+func fieldTypeToCrateType(fieldType string) string {
+	switch fieldType {
+	case common.FieldTypeString:
+		return "string"
+	case common.FieldTypeBool:
+		return "boolean"
+	case common.FieldTypeInt64:
+		return "long"
+	}
+	return "double"
+}
+
 // loader.DBCreator interface implementation
 //
 // returns true if there are any tables in a schema
@@ -1,4 +1,4 @@
-package main
+package crate
 
 import (
 	"testing"
@@ -46,6 +46,23 @@ func TestDBCreatorReadDataHeader(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "typed columns",
+			input: &common.GeneratedDataHeaders{
+				TagTypes:   nil,
+				TagKeys:    []string{"tag1"},
+				FieldKeys:  map[string][]string{"status": {"col1", "col2"}},
+				FieldTypes: map[string][]string{"status": {"string", ""}},
+			},
+			expectedTables: map[string]tableDef{
+				"status": {
+					name:     "status",
+					tags:     []string{"tag1"},
+					cols:     []string{"col1", "col2"},
+					colTypes: []string{"string", ""},
+				},
+			},
+		},
 		{
 			desc: "no field keys no table defs",
 			input: &common.GeneratedDataHeaders{
@@ -88,6 +105,10 @@ func TestDBCreatorReadDataHeader(t *testing.T) {
 					t.Errorf("%s: incorrect cols: got\n%s\nwant\n%s\n",
 						c.desc, tableDef.cols, expectedTableDef.cols)
 				}
+				if !arrEq(tableDef.colTypes, expectedTableDef.colTypes) {
+					t.Errorf("%s: incorrect col types: got\n%s\nwant\n%s\n",
+						c.desc, tableDef.colTypes, expectedTableDef.colTypes)
+				}
 			}
 		}
 	}
@@ -107,3 +128,17 @@ func arrEq(a []string, b []string) bool {
 	}
 	return true
 }
+
+func TestFieldTypeToCrateType(t *testing.T) {
+	cases := map[string]string{
+		"":       "double",
+		"string": "string",
+		"bool":   "boolean",
+		"uint64": "double",
+	}
+	for fieldType, want := range cases {
+		if got := fieldTypeToCrateType(fieldType); got != want {
+			t.Errorf("incorrect type for %q: got %s want %s", fieldType, got, want)
+		}
+	}
+}
@@ -33,6 +33,12 @@ func (t *crateTarget) Serializer() serialize.PointSerializer {
 	return &Serializer{}
 }
 
-func (t *crateTarget) Benchmark(string, *source.DataSourceConfig, *viper.Viper) (targets.Benchmark, error) {
-	panic("not implemented")
+func (t *crateTarget) Benchmark(
+	_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	crateSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(crateSpecificConfig, dataSourceConfig)
 }
@@ -1,4 +1,4 @@
-package main
+package crate
 
 import (
 	"bufio"
@@ -62,8 +62,9 @@ type fileDataSource struct {
 // Decodes a data point of a following format:
 //       <measurement_type>\t<tags>\t<timestamp>\t<metric1>\t...\t<metricN>
 //
-// Converts metric values to double-precision floating-point number, timestamp
-// to time.Time and tags to bytes array.
+// Converts metric values to double-precision floating-point number, or the
+// type of the field if it has one, or nil if missing, timestamp to time.Time
+// and tags to bytes array.
 func (d *fileDataSource) NextItem() data.LoadedPoint {
 	ok := d.scanner.Scan()
 	if !ok && d.scanner.Err() == nil {
@@ -84,7 +85,11 @@ func (d *fileDataSource) NextItem() data.LoadedPoint {
 	table := parts[0]
 	tags := []byte(parts[1])
 
-	metrics, err := parseMetrics(strings.Split(parts[3], "\t"))
+	var types []string
+	if d.headers != nil {
+		types = d.headers.FieldTypes[table]
+	}
+	metrics, err := parseMetrics(strings.Split(parts[3], "\t"), types)
 	if err != nil {
 		fatal("cannot parse metrics: %v", err)
 		return data.LoadedPoint{}
@@ -135,6 +140,7 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 		tagTypes[i] = tagAndTypeSplit[1]
 	}
 	fields := make(map[string][]string)
+	var fieldTypes map[string][]string
 	for {
 		ok := d.scanner.Scan()
 		if !ok && d.scanner.Err() == nil {
@@ -150,18 +156,29 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 		if len(line) == 0 {
 			break
 		}
+		if strings.HasPrefix(line, common.HeaderCommentPrefix) {
+			continue
+		}
 
 		parts := strings.SplitN(line, ",", 2)
 		if len(parts) < 2 {
 			fatal("metric columns are missing")
 			return nil
 		}
-		fields[parts[0]] = strings.Split(parts[1], ",")
+		keys, types := common.ParseFieldColumns(strings.Split(parts[1], ","))
+		fields[parts[0]] = keys
+		if types != nil {
+			if fieldTypes == nil {
+				fieldTypes = make(map[string][]string)
+			}
+			fieldTypes[parts[0]] = types
+		}
 	}
 	d.headers = &common.GeneratedDataHeaders{
-		TagTypes:  tagTypes,
-		TagKeys:   tags,
-		FieldKeys: fields,
+		TagTypes:   tagTypes,
+		TagKeys:    tags,
+		FieldKeys:  fields,
+		FieldTypes: fieldTypes,
 	}
 	return d.headers
 }
@@ -174,10 +191,19 @@ func parseTime(v string) (time.Time, error) {
 	return time.Unix(0, ts), nil
 }
 
-func parseMetrics(values []string) (row, error) {
+func parseMetrics(values, types []string) (row, error) {
 	metrics := make(row, len(values))
 	for i := range values {
-		metric, err := strconv.ParseFloat(values[i], 64)
+		if values[i] == "" {
+			metrics[i] = nil
+			continue
+		}
+		fieldType := ""
+		if i < len(types) && types[i] != common.FieldTypeUint64 {
+			// uint64 fields are stored as doubles
+			fieldType = types[i]
+		}
+		metric, err := common.ParseFieldValue(values[i], fieldType)
 		if err != nil {
 			return nil, err
 		}
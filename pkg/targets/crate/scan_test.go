@@ -1,4 +1,4 @@
-package main
+package crate
 
 import (
 	"bufio"
@@ -220,6 +220,19 @@ func TestDataSourceHeaders(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "typed columns",
+			input: "tags,tag1 string\ncpu,col1,col2\nstatus,col1 string,col2 bool\n\n",
+			expectedHeader: &common.GeneratedDataHeaders{
+				TagTypes: []string{"string"},
+				TagKeys:  []string{"tag1"},
+				FieldKeys: map[string][]string{
+					"cpu":    {"col1", "col2"},
+					"status": {"col1", "col2"},
+				},
+				FieldTypes: map[string][]string{"status": {"string", "bool"}},
+			},
+		},
 		{
 			desc:           "too few lines",
 			input:          "tags\ncols\n",
@@ -256,3 +269,58 @@ func TestDataSourceHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestPointToRow(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("cpu"))
+	p.SetTimestamp(&time.Time{})
+	p.AppendTag([]byte("hostname"), "host_0")
+	p.AppendField([]byte("usage_user"), 38.243)
+	p.AppendField([]byte("usage_system"), int64(5))
+	p.AppendField([]byte("usage_idle"), nil)
+
+	r, err := pointToRow(p, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := row{
+		[]byte("{\"hostname\":\"host_0\"}"),
+		time.Time{}.UTC(),
+		38.243, float64(5), nil,
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("incorrect row: got %v want %v", r, want)
+	}
+
+	p.AppendField([]byte("status"), "ok")
+	p.AppendField([]byte("reachable"), true)
+	p.AppendField([]byte("requests_total"), uint64(1<<63))
+	r, err = pointToRow(p, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = append(want, "ok", true, float64(1<<63))
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("incorrect row: got %v want %v", r, want)
+	}
+
+	p.AppendField([]byte("usage_steal"), []float64{1})
+	if _, err := pointToRow(p, nil); err == nil {
+		t.Errorf("expected error for unsupported field type")
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	got, err := parseMetrics([]string{"1.5", "ok", "true", "18446744073709551615", ""}, []string{"", "string", "bool", "uint64", "string"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := row{1.5, "ok", true, float64(18446744073709551615), nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect metrics: got %v want %v", got, want)
+	}
+
+	if _, err := parseMetrics([]string{"maybe"}, []string{"bool"}); err == nil {
+		t.Errorf("expected error for invalid bool")
+	}
+}
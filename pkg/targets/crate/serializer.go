@@ -10,12 +10,21 @@ import (
 const TAB = '\t'
 
 // CrateDBSerializer writes a Point in a serialized form for CrateDB
-type Serializer struct{}
+type Serializer struct {
+	columns map[string][]string
+}
+
+// SetColumns sets the field keys of each measurement, as written in the
+// header, so fields a Point leaves out are written as empty values.
+func (s *Serializer) SetColumns(columns map[string][]string) {
+	s.columns = columns
+}
 
 // Serialize Point p to the given Writer w, so it can be  loaded by the CrateDB
 // loader. The format is TSV with one line per point, that contains the
 // measurement type, tags with keys and values as a JSON object, timestamp,
-// and metric values.
+// and metric values. Missing metric values are written as empty values,
+// which are loaded as NULL.
 //
 // An example of a serialized point:
 //     cpu\t{"hostname":"host_0","rack":"1"}\t1451606400000000000\t38\t0\t50\t41234
@@ -27,22 +36,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	buf = append(buf, TAB)
 
 	// tags
-	tagKeys := p.TagKeys()
-	tagValues := p.TagValues()
-	if len(tagKeys) > 0 {
-		buf = append(buf, '{')
-		for i, key := range tagKeys {
-			buf = append(buf, '"')
-			buf = append(buf, key...)
-			buf = append(buf, []byte("\":\"")...)
-			buf = serialize.FastFormatAppend(tagValues[i], buf)
-			buf = append(buf, []byte("\",")...)
-		}
-		buf = buf[:len(buf)-1]
-		buf = append(buf, '}')
-	} else {
-		buf = append(buf, []byte("null")...)
-	}
+	buf = appendTags(buf, p)
 
 	// timestamp
 	buf = append(buf, TAB)
@@ -50,7 +44,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	buf = append(buf, ts...)
 
 	// metrics
-	fieldValues := p.FieldValues()
+	fieldValues := serialize.ColumnValues(p, s.columns[string(p.MeasurementName())])
 	for _, v := range fieldValues {
 		buf = append(buf, TAB)
 		buf = serialize.FastFormatAppend(v, buf)
@@ -59,3 +53,23 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	_, err := w.Write(buf)
 	return err
 }
+
+// appendTags appends the tags of the Point to buf as a JSON object, or null
+// if the Point has no tags.
+func appendTags(buf []byte, p *data.Point) []byte {
+	tagKeys := p.TagKeys()
+	tagValues := p.TagValues()
+	if len(tagKeys) == 0 {
+		return append(buf, []byte("null")...)
+	}
+	buf = append(buf, '{')
+	for i, key := range tagKeys {
+		buf = append(buf, '"')
+		buf = append(buf, key...)
+		buf = append(buf, []byte("\":\"")...)
+		buf = serialize.FastFormatAppend(tagValues[i], buf)
+		buf = append(buf, []byte("\",")...)
+	}
+	buf = buf[:len(buf)-1]
+	return append(buf, '}')
+}
@@ -27,11 +27,34 @@ func TestCrateDBSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointNoTags(),
 			Output:     "cpu\tnull\t1451606400000000000\t38.24311829\n",
 		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     "cpu\tnull\t1451606400000000000\t\t38.24311829\n",
+		},
 	}
 
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
 
+func TestCrateDBSerializerSerializeAbsentFields(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a Point missing leading columns",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu\t{\"hostname\":\"host_0\",\"region\":\"eu-west-1\",\"datacenter\":\"eu-west-1b\"}\t1451606400000000000\t\t\t38.24311829\n",
+		},
+	}
+
+	s := &Serializer{}
+	s.SetColumns(map[string][]string{
+		string(serialize.TestMeasurement): {
+			string(serialize.TestColInt64), string(serialize.TestColInt), string(serialize.TestColFloat),
+		},
+	})
+	serialize.SerializerTest(t, cases, s)
+}
+
 func TestCrateDBSerializerSerializeErr(t *testing.T) {
 	p := serialize.TestPointMultiField()
 	s := &Serializer{}
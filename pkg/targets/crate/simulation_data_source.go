@@ -0,0 +1,91 @@
+package crate
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+// simulationDataSource converts simulated points directly into rows of
+// tags, timestamp and metric values, without going through the TSV format.
+type simulationDataSource struct {
+	simulator common.Simulator
+	headers   *common.GeneratedDataHeaders
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return data.LoadedPoint{}
+	}
+
+	r, err := pointToRow(newSimulatorPoint, d.headers.FieldKeys[string(newSimulatorPoint.MeasurementName())])
+	if err != nil {
+		fatal("cannot convert simulated point: %v", err)
+		return data.LoadedPoint{}
+	}
+	return data.NewLoadedPoint(&point{
+		table: string(newSimulatorPoint.MeasurementName()),
+		row:   r,
+	})
+}
+
+// pointToRow returns the same row the file data source decodes from the
+// serialized form of p, with its fields in the order of columns. Missing field
+// values are inserted as NULL.
+func pointToRow(p *data.Point, columns []string) (row, error) {
+	fieldValues := serialize.ColumnValues(p, columns)
+	r := make(row, 2, len(fieldValues)+2)
+	r[0] = appendTags(make([]byte, 0, 256), p)
+	r[1] = p.Timestamp().UTC()
+	for _, v := range fieldValues {
+		switch val := v.(type) {
+		case nil:
+			r = append(r, nil)
+		case float64:
+			r = append(r, val)
+		case float32:
+			r = append(r, float64(val))
+		case int:
+			r = append(r, float64(val))
+		case int64:
+			r = append(r, float64(val))
+		case uint64:
+			r = append(r, float64(val))
+		case string, bool:
+			r = append(r, val)
+		case []byte:
+			r = append(r, string(val))
+		default:
+			return nil, fmt.Errorf("unsupported field type %T", v)
+		}
+	}
+	return r, nil
+}
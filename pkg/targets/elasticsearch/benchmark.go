@@ -0,0 +1,155 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// allows for testing
+var fatal = log.Fatalf
+
+// NewBenchmark creates the benchmark that loads the data of the given source
+// into the indices of dbName, whose names are the index names of the data
+// prefixed with dbName, e.g. benchmark-cpu-2016.01.01.
+func NewBenchmark(dbName string, opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if len(opts.URLs) == 0 {
+		return nil, fmt.Errorf("missing 'urls' flag")
+	}
+	if dbName == "" || strings.ToLower(dbName) != dbName {
+		return nil, fmt.Errorf("invalid database name '%s': index names must be lowercase", dbName)
+	}
+
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		br := load.GetBufferedReader(dataSourceConfig.File.Location)
+		ds = newFileDataSource(br, dbName+"-")
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator, dbName+"-")
+	}
+
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &benchmark{
+		opts:       opts,
+		dbName:     dbName,
+		dataSource: ds,
+		bufPool:    &bufPool,
+	}, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	opts       *SpecificConfig
+	dbName     string
+	dataSource targets.DataSource
+	bufPool    *sync.Pool
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{bufPool: b.bufPool}
+}
+
+func (b *benchmark) GetPointIndexer(_ uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{opts: b.opts, bufPool: b.bufPool}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{
+		// pick first one since it always exists
+		client: newClient(b.opts.URLs[0], b.opts.Username, b.opts.Password),
+		opts:   b.opts,
+	}
+}
+
+type factory struct {
+	bufPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return &batch{buf: f.bufPool.Get().(*bytes.Buffer)}
+}
+
+// batch is the body of a bulk request.
+type batch struct {
+	buf     *bytes.Buffer
+	rows    uint64
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	doc := item.Data.(*document)
+	b.rows++
+	b.metrics += uint64(countFields(doc.source))
+
+	b.buf.Write(doc.action)
+	b.buf.WriteByte('\n')
+	b.buf.Write(doc.source)
+	b.buf.WriteByte('\n')
+}
+
+// countFields returns the number of fields of a document written by a
+// Serializer: its top level members except for the timestamp and the labels
+// object, which is the only object among them.
+func countFields(source []byte) int {
+	members, objects := 0, 0
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range source {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case ':':
+			if depth == 1 {
+				members++
+			}
+		case '{':
+			depth++
+			if depth == 2 {
+				objects++
+			}
+		case '}':
+			depth--
+		}
+	}
+	return members - objects - 1
+}
@@ -0,0 +1,343 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+)
+
+const (
+	testAction = `{"create":{"_index":"cpu-2016.01.01"}}`
+	testSource = `{"@timestamp":1451606400000,"labels":{"hostname":"host_0","note":"a:{b}"},"usage_user":1.5,"usage_system":2}`
+)
+
+func TestCountFields(t *testing.T) {
+	cases := []struct {
+		source string
+		want   int
+	}{
+		{source: testSource, want: 2},
+		{source: `{"@timestamp":1451606400000,"usage_user":1.5}`, want: 1},
+		{source: `{"@timestamp":1451606400000,"labels":{"a":"b"},"status":"x\":y","ok":true}`, want: 2},
+	}
+	for _, c := range cases {
+		if got := countFields([]byte(c.source)); got != c.want {
+			t.Errorf("%s: incorrect count: got %d want %d", c.source, got, c.want)
+		}
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	doc := newDocument([]byte(testAction), []byte(testSource), "benchmark-")
+	if got, want := string(doc.action), `{"create":{"_index":"benchmark-cpu-2016.01.01"}}`; got != want {
+		t.Errorf("incorrect action: got %s want %s", got, want)
+	}
+	if string(doc.source) != testSource {
+		t.Errorf("incorrect source: got %s", doc.source)
+	}
+
+	var fatalCalled bool
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+	defer func() { fatal = defaultFatal }()
+	newDocument([]byte(`{"index":{}}`), []byte(testSource), "benchmark-")
+	if !fatalCalled {
+		t.Errorf("fatal not called for unexpected action")
+	}
+}
+
+var defaultFatal = fatal
+
+func TestBatch(t *testing.T) {
+	b := &batch{buf: &bytes.Buffer{}}
+	b.Append(data.NewLoadedPoint(newDocument([]byte(testAction), []byte(testSource), "b-")))
+	b.Append(data.NewLoadedPoint(newDocument([]byte(testAction), []byte(testSource), "b-")))
+	if b.Len() != 2 || b.rows != 2 {
+		t.Errorf("incorrect number of rows: got %d want 2", b.rows)
+	}
+	if b.metrics != 4 {
+		t.Errorf("incorrect number of metrics: got %d want 4", b.metrics)
+	}
+	pair := `{"create":{"_index":"b-cpu-2016.01.01"}}` + "\n" + testSource + "\n"
+	if got := b.buf.String(); got != pair+pair {
+		t.Errorf("incorrect body: got %s", got)
+	}
+}
+
+func TestFileDataSource(t *testing.T) {
+	input := testAction + "\n" + testSource + "\n" + testAction + "\n" + testSource + "\n"
+	ds := newFileDataSource(strings.NewReader(input), "db-")
+	count := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		doc := item.Data.(*document)
+		if !bytes.Contains(doc.action, []byte(`"db-cpu-2016.01.01"`)) {
+			t.Errorf("index not prefixed: %s", doc.action)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("incorrect number of documents: got %d want 2", count)
+	}
+
+	var fatalCalled bool
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+	defer func() { fatal = defaultFatal }()
+	ds = newFileDataSource(strings.NewReader(testAction+"\n"), "db-")
+	if item := ds.NextItem(); item.Data != nil || !fatalCalled {
+		t.Errorf("missing document not reported")
+	}
+}
+
+func TestFileDataSourceLongLine(t *testing.T) {
+	source := `{"tag":"` + strings.Repeat("x", 128*1024) + `"}`
+	ds := newFileDataSource(strings.NewReader(testAction+"\n"+source+"\n"), "db-")
+	item := ds.NextItem()
+	if item.Data == nil {
+		t.Fatalf("long document not read")
+	}
+	if got := string(item.Data.(*document).source); got != source {
+		t.Errorf("incorrect source: got %d bytes want %d", len(got), len(source))
+	}
+}
+
+func TestSimulationDataSource(t *testing.T) {
+	const hosts, epochs = 2, 3
+	conf := &devops.CPUOnlySimulatorConfig{
+		Start:           serialize.TestNow,
+		End:             serialize.TestNow.Add(epochs * time.Second),
+		InitHostCount:   hosts,
+		HostCount:       hosts,
+		HostConstructor: devops.NewHostCPUOnly,
+	}
+	ds := newSimulationDataSource(conf.NewSimulator(time.Second, 0), "benchmark-")
+	count := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		doc := item.Data.(*document)
+		if got, want := string(doc.action), `{"create":{"_index":"benchmark-cpu-2016.01.01"}}`; got != want {
+			t.Errorf("incorrect action: got %s want %s", got, want)
+		}
+		if n := countFields(doc.source); n != 10 {
+			t.Errorf("incorrect number of fields: got %d want 10", n)
+		}
+		count++
+	}
+	if count != hosts*epochs {
+		t.Errorf("incorrect number of documents: got %d want %d", count, hosts*epochs)
+	}
+}
+
+func TestNewBenchmarkInvalid(t *testing.T) {
+	ds := &source.DataSourceConfig{Type: source.FileDataSourceType}
+	if _, err := NewBenchmark("Benchmark", &SpecificConfig{URLs: []string{"http://localhost:9200"}}, ds); err == nil {
+		t.Errorf("unexpected lack of error for uppercase database name")
+	}
+	if _, err := NewBenchmark("benchmark", &SpecificConfig{}, ds); err == nil {
+		t.Errorf("unexpected lack of error without urls")
+	}
+}
+
+// bulkReceiver is a stub bulk API which rejects the first action of the
+// first rejections requests it gets as if it was overloaded.
+type bulkReceiver struct {
+	rejections int
+	failure    bool
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (r *bulkReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/_bulk" || req.Header.Get("Content-Type") != contentTypeNDJSON {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	var body []byte
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, _ = ioutil.ReadAll(gz)
+	} else {
+		body, _ = ioutil.ReadAll(req.Body)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, string(body))
+
+	actions := bytes.Count(body, []byte("\n")) / 2
+	items := make([]string, actions)
+	errors := false
+	for i := range items {
+		switch {
+		case i == 0 && r.failure:
+			items[i] = `{"create":{"status":400,"error":{"type":"mapper_parsing_exception"}}}`
+			errors = true
+		case i == 0 && r.rejections > 0:
+			items[i] = `{"create":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}`
+			r.rejections--
+			errors = true
+		default:
+			items[i] = `{"create":{"status":201}}`
+		}
+	}
+	fmt.Fprintf(w, `{"errors":%v,"items":[%s]}`, errors, strings.Join(items, ","))
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	receiver := &bulkReceiver{rejections: 1}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	opts := &SpecificConfig{URLs: []string{server.URL}}
+	pool := &sync.Pool{New: func() interface{} { return &bytes.Buffer{} }}
+	for _, doLoad := range []bool{false, true} {
+		p := &processor{opts: opts, bufPool: pool}
+		p.Init(0, doLoad, false)
+		b := (&factory{bufPool: pool}).New().(*batch)
+		b.Append(data.NewLoadedPoint(newDocument([]byte(testAction), []byte(testSource), "b-")))
+		b.Append(data.NewLoadedPoint(newDocument([]byte(testAction), []byte(testSource), "b-")))
+		metrics, rows := p.ProcessBatch(b, doLoad)
+		if metrics != 4 || rows != 2 {
+			t.Errorf("doLoad %v: incorrect counts: got %d metrics and %d rows, want 4 and 2", doLoad, metrics, rows)
+		}
+		if b.buf.Len() != 0 {
+			t.Errorf("doLoad %v: batch not reset", doLoad)
+		}
+		p.Close(doLoad)
+	}
+	// the rejected action is sent again on its own
+	if len(receiver.requests) != 2 {
+		t.Fatalf("incorrect number of requests: got %d want 2", len(receiver.requests))
+	}
+	if got := strings.Count(receiver.requests[1], "\n"); got != 2 {
+		t.Errorf("incorrect retry: got %d lines want 2", got)
+	}
+}
+
+func TestProcessorBulkFailure(t *testing.T) {
+	server := httptest.NewServer(&bulkReceiver{failure: true})
+	defer server.Close()
+
+	p := &processor{opts: &SpecificConfig{URLs: []string{server.URL}, UseGzip: true}}
+	p.Init(0, true, false)
+	pair := testAction + "\n" + testSource + "\n"
+	if _, err := p.bulk([]byte(pair + pair)); err == nil {
+		t.Errorf("unexpected lack of error for failed action")
+	}
+}
+
+func TestRejectedActions(t *testing.T) {
+	pairs := []string{testAction + "\n" + "{\"n\":0}\n", testAction + "\n" + "{\"n\":1}\n", testAction + "\n" + "{\"n\":2}\n"}
+	items := []map[string]bulkOutcome{
+		{"create": {Status: 201}},
+		{"create": {Status: 429, Error: []byte(`{}`)}},
+		{"create": {Status: 201}},
+	}
+	rejected, err := rejectedActions([]byte(strings.Join(pairs, "")), items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(rejected) != pairs[1] {
+		t.Errorf("incorrect rejected actions: got %q want %q", rejected, pairs[1])
+	}
+	if _, err := rejectedActions([]byte(strings.Join(pairs, "")), items[:2]); err == nil {
+		t.Errorf("unexpected lack of error for missing items")
+	}
+}
+
+// clusterStub is a stub cluster which keeps the index templates and indices
+// created, and the requests it gets.
+type clusterStub struct {
+	templates map[string]string
+	indices   []string
+	requests  []string
+}
+
+func (c *clusterStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	c.requests = append(c.requests, req.Method+" "+req.URL.Path)
+	body, _ := ioutil.ReadAll(req.Body)
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/_index_template/"):
+		name := strings.TrimPrefix(req.URL.Path, "/_index_template/")
+		_, ok := c.templates[name]
+		switch req.Method {
+		case http.MethodPut:
+			c.templates[name] = string(body)
+		case http.MethodDelete:
+			delete(c.templates, name)
+		}
+		if !ok && req.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case strings.HasPrefix(req.URL.Path, "/_cat/indices/"):
+		var names []string
+		for _, index := range c.indices {
+			names = append(names, fmt.Sprintf(`{"index":"%s"}`, index))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(names, ","))
+	case req.Method == http.MethodDelete:
+		c.indices = nil
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestDBCreator(t *testing.T) {
+	cluster := &clusterStub{templates: map[string]string{}}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+
+	opts := &SpecificConfig{URLs: []string{server.URL}, Shards: 2, Replicas: 1, RefreshInterval: "30s"}
+	b := &benchmark{opts: opts}
+	d := b.GetDBCreator()
+	d.Init()
+	if d.DBExists("benchmark") {
+		t.Errorf("database exists before it is created")
+	}
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !d.DBExists("benchmark") {
+		t.Errorf("database does not exist after it is created")
+	}
+	template := cluster.templates["benchmark"]
+	for _, want := range []string{`"index_patterns":["benchmark-*"]`, `"number_of_shards":2`, `"number_of_replicas":1`, `"refresh_interval":"30s"`} {
+		if !strings.Contains(template, want) {
+			t.Errorf("index template lacks %s: %s", want, template)
+		}
+	}
+
+	cluster.indices = []string{"benchmark-cpu-2016.01.01", "benchmark-cpu-2016.01.02"}
+	cluster.requests = nil
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"GET /_cat/indices/benchmark-*",
+		"DELETE /benchmark-cpu-2016.01.01,benchmark-cpu-2016.01.02",
+		"DELETE /_index_template/benchmark",
+	}
+	if strings.Join(cluster.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("incorrect requests:\ngot  %v\nwant %v", cluster.requests, want)
+	}
+	if d.DBExists("benchmark") {
+		t.Errorf("database exists after it is removed")
+	}
+}
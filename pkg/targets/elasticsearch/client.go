@@ -0,0 +1,89 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	headerContentType     = "Content-Type"
+	headerContentEncoding = "Content-Encoding"
+	contentTypeJSON       = "application/json"
+	contentTypeNDJSON     = "application/x-ndjson"
+)
+
+// client sends requests to a node of an Elasticsearch or OpenSearch cluster.
+type client struct {
+	url      string
+	username string
+	password string
+	http     *http.Client
+}
+
+func newClient(url, username, password string) *client {
+	return &client{
+		url:      url,
+		username: username,
+		password: password,
+		http: &http.Client{
+			Transport: &http.Transport{MaxIdleConnsPerHost: 1024},
+		},
+	}
+}
+
+// newRequest returns a request of the given path, with a JSON body if body
+// is not nil.
+func (c *client) newRequest(method, path string, body []byte) (*http.Request, error) {
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = http.NewRequest(method, c.url+path, nil)
+	} else {
+		req, err = http.NewRequest(method, c.url+path, bytes.NewReader(body))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set(headerContentType, contentTypeJSON)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
+
+// do sends the request and returns the status code and body of the response.
+func (c *client) do(req *http.Request) (int, []byte, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: cannot read response: %w", req.Method, req.URL.Path, err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// request sends a request of the given path and returns the body of the
+// response, or an error if the status code is not one of ok.
+func (c *client) request(method, path string, body []byte, ok ...int) ([]byte, error) {
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	status, respBody, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range ok {
+		if status == code {
+			return respBody, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s returned status %d: %s", method, path, status, respBody)
+}
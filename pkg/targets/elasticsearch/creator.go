@@ -0,0 +1,137 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxDeletePathLen caps the length of the index names deleted with one
+// request, to keep the URL short enough for the cluster to accept it.
+const maxDeletePathLen = 2048
+
+// dbCreator creates a database as an index template, named after it, for the
+// indices whose names start with the database name and a dash. Removing the
+// database deletes those indices along with the template.
+type dbCreator struct {
+	client *client
+	opts   *SpecificConfig
+}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	req, err := d.client.newRequest(http.MethodHead, templatePath(dbName), nil)
+	if err != nil {
+		fatal("cannot check index template: %v", err)
+		return false
+	}
+	status, body, err := d.client.do(req)
+	if err != nil {
+		fatal("cannot check index template: %v", err)
+		return false
+	}
+	switch status {
+	case http.StatusOK:
+		return true
+	case http.StatusNotFound:
+		return false
+	}
+	fatal("cannot check index template: status %d: %s", status, body)
+	return false
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	body, err := d.client.request(http.MethodGet, "/_cat/indices/"+url.PathEscape(indexPattern(dbName))+"?h=index&format=json", nil, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("cannot list indices: %w", err)
+	}
+	var indices []struct {
+		Index string `json:"index"`
+	}
+	if err := json.Unmarshal(body, &indices); err != nil {
+		return fmt.Errorf("cannot decode indices: %w", err)
+	}
+	// indices are deleted by name, since deleting by pattern may be forbidden
+	var names []string
+	length := 0
+	for i, index := range indices {
+		names = append(names, index.Index)
+		length += len(index.Index) + 1
+		if length < maxDeletePathLen && i+1 < len(indices) {
+			continue
+		}
+		if _, err := d.client.request(http.MethodDelete, "/"+strings.Join(names, ","), nil, http.StatusOK); err != nil {
+			return fmt.Errorf("cannot delete indices: %w", err)
+		}
+		names, length = names[:0], 0
+	}
+
+	if _, err := d.client.request(http.MethodDelete, templatePath(dbName), nil, http.StatusOK, http.StatusNotFound); err != nil {
+		return fmt.Errorf("cannot delete index template: %w", err)
+	}
+	return nil
+}
+
+func (d *dbCreator) CreateDB(dbName string) error {
+	template, err := json.Marshal(indexTemplate(dbName, d.opts))
+	if err != nil {
+		return err
+	}
+	if _, err := d.client.request(http.MethodPut, templatePath(dbName), template, http.StatusOK); err != nil {
+		return fmt.Errorf("cannot create index template: %w", err)
+	}
+	return nil
+}
+
+func templatePath(dbName string) string {
+	return "/_index_template/" + url.PathEscape(dbName)
+}
+
+// indexPattern matches the indices of the database.
+func indexPattern(dbName string) string {
+	return dbName + "-*"
+}
+
+// indexTemplate returns the composable index template of the database. Tags
+// and other strings are keywords, and floating point fields are doubles
+// rather than the floats of the dynamic mapping.
+func indexTemplate(dbName string, opts *SpecificConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"index_patterns": []string{indexPattern(dbName)},
+		"template": map[string]interface{}{
+			"settings": map[string]interface{}{
+				"index": map[string]interface{}{
+					"number_of_shards":   opts.Shards,
+					"number_of_replicas": opts.Replicas,
+					"refresh_interval":   opts.RefreshInterval,
+				},
+			},
+			"mappings": map[string]interface{}{
+				"dynamic_templates": []interface{}{
+					map[string]interface{}{
+						"strings_as_keywords": map[string]interface{}{
+							"match_mapping_type": "string",
+							"mapping":            map[string]interface{}{"type": "keyword"},
+						},
+					},
+					map[string]interface{}{
+						"floats_as_doubles": map[string]interface{}{
+							"match_mapping_type": "double",
+							"mapping":            map[string]interface{}{"type": "double"},
+						},
+					},
+				},
+				"properties": map[string]interface{}{
+					TimestampField: map[string]interface{}{
+						"type":   "date",
+						"format": "strict_date_optional_time||epoch_millis",
+					},
+					LabelsField: map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+	}
+}
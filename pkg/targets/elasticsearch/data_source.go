@@ -0,0 +1,123 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// document is a bulk action along with the document it creates.
+type document struct {
+	action []byte
+	source []byte
+}
+
+// newDocument returns the document of the given lines, with the name of the
+// index in the action prefixed with indexPrefix.
+func newDocument(action, source []byte, indexPrefix string) *document {
+	if !bytes.HasPrefix(action, actionPrefix) {
+		fatal("unexpected bulk action: %s", action)
+		return nil
+	}
+	doc := &document{
+		action: make([]byte, 0, len(action)+len(indexPrefix)),
+		source: append([]byte(nil), source...),
+	}
+	doc.action = append(doc.action, actionPrefix...)
+	doc.action = append(doc.action, indexPrefix...)
+	doc.action = append(doc.action, action[len(actionPrefix):]...)
+	return doc
+}
+
+// maxLineSize is the longest bulk line a fileDataSource accepts; documents with
+// many fields or long tag values easily exceed bufio's 64KB default.
+const maxLineSize = 16 * 1024 * 1024
+
+// fileDataSource reads the pairs of lines written by a Serializer.
+type fileDataSource struct {
+	scanner     *bufio.Scanner
+	indexPrefix string
+}
+
+// newFileDataSource returns a fileDataSource reading from r, prefixing the
+// index names with indexPrefix.
+func newFileDataSource(r io.Reader, indexPrefix string) *fileDataSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &fileDataSource{scanner: scanner, indexPrefix: indexPrefix}
+}
+
+func (d *fileDataSource) NextItem() data.LoadedPoint {
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			fatal("scan error: %v", err)
+		}
+		return data.LoadedPoint{}
+	}
+	action := append([]byte(nil), d.scanner.Bytes()...)
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			fatal("scan error: %v", err)
+		} else {
+			fatal("missing document of bulk action: %s", action)
+		}
+		return data.LoadedPoint{}
+	}
+	return data.NewLoadedPoint(newDocument(action, d.scanner.Bytes(), d.indexPrefix))
+}
+
+func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
+
+func newSimulationDataSource(sim common.Simulator, indexPrefix string) targets.DataSource {
+	return &simulationDataSource{
+		simulator:   sim,
+		headers:     sim.Headers(),
+		indexPrefix: indexPrefix,
+	}
+}
+
+// simulationDataSource generates points with a simulator and converts each
+// one to a bulk action and document, the same representation the file data
+// source reads.
+type simulationDataSource struct {
+	simulator   common.Simulator
+	headers     *common.GeneratedDataHeaders
+	indexPrefix string
+	serializer  Serializer
+	buf         bytes.Buffer
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	for !d.simulator.Finished() {
+		if d.simulator.Next(newSimulatorPoint) {
+			d.buf.Reset()
+			if err := d.serializer.Serialize(newSimulatorPoint, &d.buf); err != nil {
+				fatal("could not serialize simulated point: %v", err)
+				return data.LoadedPoint{}
+			}
+			// points without fields make no document
+			if d.buf.Len() > 0 {
+				lines := bytes.SplitN(bytes.TrimSuffix(d.buf.Bytes(), []byte("\n")), []byte("\n"), 2)
+				return data.NewLoadedPoint(newDocument(lines[0], lines[1], d.indexPrefix))
+			}
+		}
+		newSimulatorPoint.Reset()
+	}
+	return data.LoadedPoint{}
+}
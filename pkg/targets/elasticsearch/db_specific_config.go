@@ -0,0 +1,28 @@
+package elasticsearch
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+)
+
+// SpecificConfig holds the Elasticsearch and OpenSearch specific loading
+// options.
+type SpecificConfig struct {
+	URLs            []string      `yaml:"urls" mapstructure:"urls"`
+	Username        string        `yaml:"username" mapstructure:"username"`
+	Password        string        `yaml:"password" mapstructure:"password"`
+	Shards          int           `yaml:"shards" mapstructure:"shards"`
+	Replicas        int           `yaml:"replicas" mapstructure:"replicas"`
+	RefreshInterval string        `yaml:"refresh-interval" mapstructure:"refresh-interval"`
+	Backoff         time.Duration `yaml:"backoff" mapstructure:"backoff"`
+	UseGzip         bool          `yaml:"gzip" mapstructure:"gzip"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
@@ -0,0 +1,48 @@
+package elasticsearch
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &elasticsearchTarget{}
+}
+
+type elasticsearchTarget struct {
+}
+
+func (t *elasticsearchTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"urls", "http://localhost:9200", "Elasticsearch or OpenSearch URLs, comma-separated. Will be used in a round-robin fashion.")
+	flagSet.String(flagPrefix+"username", "", "User name for basic authentication. If empty no credentials are sent.")
+	flagSet.String(flagPrefix+"password", "", "Password for basic authentication.")
+	flagSet.Int(flagPrefix+"shards", 1, "Number of primary shards of each index.")
+	flagSet.Int(flagPrefix+"replicas", 0, "Number of replicas of each shard.")
+	flagSet.String(flagPrefix+"refresh-interval", "1s", "How often the indices are refreshed, making new documents searchable (-1 to disable).")
+	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to sleep between requests when the cluster rejects them because it is overloaded.")
+	flagSet.Bool(flagPrefix+"gzip", false, "Whether to gzip encode requests.")
+}
+
+func (t *elasticsearchTarget) TargetName() string {
+	return constants.FormatElasticsearch
+}
+
+func (t *elasticsearchTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *elasticsearchTarget) Benchmark(
+	targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	specificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(targetDB, specificConfig, dataSourceConfig)
+}
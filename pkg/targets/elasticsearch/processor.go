@@ -0,0 +1,143 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// bulkPath is the path of the bulk API, with responses reduced to what is
+// needed to tell the documents that failed.
+const bulkPath = "/_bulk?filter_path=errors,items.*.status,items.*.error"
+
+// bulkResponse is a response of the bulk API, with one item per action.
+type bulkResponse struct {
+	Errors bool                     `json:"errors"`
+	Items  []map[string]bulkOutcome `json:"items"`
+}
+
+// bulkOutcome is the outcome of a single action.
+type bulkOutcome struct {
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error"`
+}
+
+type processor struct {
+	opts    *SpecificConfig
+	bufPool *sync.Pool
+	client  *client
+	gzipBuf bytes.Buffer
+}
+
+func (p *processor) Init(workerNum int, _, _ bool) {
+	url := p.opts.URLs[workerNum%len(p.opts.URLs)]
+	p.client = newClient(url, p.opts.Username, p.opts.Password)
+}
+
+func (p *processor) Close(_ bool) {}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	batch := b.(*batch)
+
+	// Write the batch: resend the documents rejected by an overloaded
+	// cluster until all are accepted.
+	if doLoad {
+		body := batch.buf.Bytes()
+		for {
+			rejected, err := p.bulk(body)
+			if err != nil {
+				fatal("Error writing: %v", err)
+				break
+			}
+			if len(rejected) == 0 {
+				break
+			}
+			time.Sleep(p.opts.Backoff)
+			body = rejected
+		}
+	}
+	metricCnt := batch.metrics
+	rowCnt := batch.rows
+
+	// Return the batch buffer to the pool.
+	batch.buf.Reset()
+	p.bufPool.Put(batch.buf)
+	return metricCnt, rowCnt
+}
+
+// bulk sends the actions of body with a bulk request. It returns the
+// actions rejected because the cluster is overloaded, which can be retried,
+// or an error if any action failed otherwise.
+func (p *processor) bulk(body []byte) ([]byte, error) {
+	reqBody := body
+	if p.opts.UseGzip {
+		p.gzipBuf.Reset()
+		gz := gzip.NewWriter(&p.gzipBuf)
+		if _, err := gz.Write(body); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		reqBody = p.gzipBuf.Bytes()
+	}
+	req, err := p.client.newRequest(http.MethodPost, bulkPath, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(headerContentType, contentTypeNDJSON)
+	if p.opts.UseGzip {
+		req.Header.Set(headerContentEncoding, "gzip")
+	}
+	status, respBody, err := p.client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusTooManyRequests {
+		return body, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("bulk request returned status %d: %s", status, respBody)
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("cannot decode bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return nil, nil
+	}
+	return rejectedActions(body, resp.Items)
+}
+
+// rejectedActions returns the actions of body, along with their documents,
+// whose items have the status of a rejection by an overloaded cluster. It
+// returns an error if any other action failed.
+func rejectedActions(body []byte, items []map[string]bulkOutcome) ([]byte, error) {
+	var rejected []byte
+	for i := 0; len(body) > 0; i++ {
+		if i >= len(items) {
+			return nil, fmt.Errorf("bulk response has %d items, fewer than the actions", len(items))
+		}
+		// each action is followed by its document
+		end := bytes.IndexByte(body, '\n') + 1
+		end += bytes.IndexByte(body[end:], '\n') + 1
+		action := body[:end]
+		body = body[end:]
+		for _, outcome := range items[i] {
+			switch {
+			case outcome.Status == http.StatusTooManyRequests:
+				rejected = append(rejected, action...)
+			case len(outcome.Error) > 0:
+				return nil, fmt.Errorf("bulk action %d failed with status %d: %s", i, outcome.Status, outcome.Error)
+			}
+		}
+	}
+	return rejected, nil
+}
@@ -0,0 +1,75 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// QueryProcessorOptions are the options of the query processors.
+type QueryProcessorOptions struct {
+	// URLs of the cluster nodes, used round-robin by worker
+	URLs []string
+	// Username and Password for basic authentication, if Username is set
+	Username string
+	Password string
+	// Debug is the debug level: 1 prints the time the cluster took for each
+	// query, 2 adds the query description, 3 the request and 4 the response
+	Debug int
+	// PrettyPrintResponses prints every response as indented JSON
+	PrettyPrintResponses bool
+}
+
+// NewQueryProcessorCreate returns a function creating query processors which
+// send the search requests round-robin (by worker) to the given nodes.
+func NewQueryProcessorCreate(opts *QueryProcessorOptions) query.ProcessorCreate {
+	return query.NewHTTPProcessorCreate(&query.HTTPProcessorOptions{
+		URLs:                 opts.URLs,
+		Username:             opts.Username,
+		Password:             opts.Password,
+		Debug:                opts.Debug,
+		PrettyPrintResponses: opts.PrettyPrintResponses,
+		ParseResponse:        describeSearchResponse,
+	})
+}
+
+// searchResponse is the part of a search response needed to tell whether
+// the search completed.
+type searchResponse struct {
+	Took     int64 `json:"took"`
+	TimedOut bool  `json:"timed_out"`
+	Shards   struct {
+		Total    int             `json:"total"`
+		Failed   int             `json:"failed"`
+		Failures json.RawMessage `json:"failures"`
+	} `json:"_shards"`
+	Hits struct {
+		Hits []json.RawMessage `json:"hits"`
+	} `json:"hits"`
+}
+
+// parseSearchResponse decodes a search response, returning an error if the
+// search timed out or failed on any shard, since its result is incomplete.
+func parseSearchResponse(body []byte) (*searchResponse, error) {
+	var resp searchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %w", err)
+	}
+	if resp.TimedOut {
+		return nil, fmt.Errorf("search timed out after %dms", resp.Took)
+	}
+	if resp.Shards.Failed > 0 {
+		return nil, fmt.Errorf("search failed on %d of %d shards: %s", resp.Shards.Failed, resp.Shards.Total, resp.Shards.Failures)
+	}
+	return &resp, nil
+}
+
+// describeSearchResponse is the query.HTTPResponseParser of the search API.
+func describeSearchResponse(body []byte) (query.HTTPResult, error) {
+	resp, err := parseSearchResponse(body)
+	if err != nil {
+		return query.HTTPResult{}, err
+	}
+	return query.HTTPResult{Description: fmt.Sprintf("took %dms, %d hits", resp.Took, len(resp.Hits.Hits))}, nil
+}
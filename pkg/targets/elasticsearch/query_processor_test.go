@@ -0,0 +1,81 @@
+package elasticsearch
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	searchResponseOK       = `{"took":3,"timed_out":false,"_shards":{"total":2,"successful":2,"skipped":0,"failed":0},"hits":{"total":{"value":2,"relation":"eq"},"hits":[{"_source":{}},{"_source":{}}]}}`
+	searchResponseTimedOut = `{"took":30000,"timed_out":true,"_shards":{"total":2,"successful":2,"failed":0},"hits":{"hits":[]}}`
+	searchResponseFailed   = `{"took":3,"timed_out":false,"_shards":{"total":2,"successful":1,"failed":1,"failures":[{"reason":"no mapping"}]},"hits":{"hits":[]}}`
+)
+
+func TestParseSearchResponse(t *testing.T) {
+	resp, err := parseSearchResponse([]byte(searchResponseOK))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Took != 3 || len(resp.Hits.Hits) != 2 {
+		t.Errorf("incorrect response: got took %d and %d hits, want 3 and 2", resp.Took, len(resp.Hits.Hits))
+	}
+	for _, body := range []string{searchResponseTimedOut, searchResponseFailed, "<html>"} {
+		if _, err := parseSearchResponse([]byte(body)); err == nil {
+			t.Errorf("unexpected lack of error for %s", body)
+		}
+	}
+}
+
+func TestQueryProcessorProcessQuery(t *testing.T) {
+	cases := []struct {
+		desc   string
+		status int
+		body   string
+		err    bool
+	}{
+		{desc: "ok", status: http.StatusOK, body: searchResponseOK},
+		{desc: "bad request", status: http.StatusBadRequest, body: `{"error":{"type":"parsing_exception"},"status":400}`, err: true},
+		{desc: "failed shard", status: http.StatusOK, body: searchResponseFailed, err: true},
+	}
+	for _, c := range cases {
+		var path, body, user string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			b, _ := ioutil.ReadAll(r.Body)
+			body = string(b)
+			user, _, _ = r.BasicAuth()
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+		create := NewQueryProcessorCreate(&QueryProcessorOptions{URLs: []string{"http://localhost:1", server.URL}, Username: "elastic"})
+		p := create()
+		p.Init(1)
+
+		q := query.NewHTTP()
+		q.HumanLabel = []byte("Elasticsearch query")
+		q.Method = []byte(http.MethodPost)
+		q.Path = []byte("/benchmark-cpu-*/_search")
+		q.Body = []byte(`{"size":0}`)
+		stats, err := p.ProcessQuery(q, false)
+		server.Close()
+		if path != "/benchmark-cpu-*/_search" || body != `{"size":0}` || user != "elastic" {
+			t.Errorf("%s: incorrect request: got %s with %s as %s", c.desc, path, body, user)
+		}
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if len(stats) != 1 || string(stats[0].Label()) != "Elasticsearch query" {
+			t.Errorf("%s: incorrect stats: got %v", c.desc, stats)
+		}
+	}
+}
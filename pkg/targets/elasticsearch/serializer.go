@@ -0,0 +1,165 @@
+package elasticsearch
+
+import (
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+const (
+	// TimestampField is the field holding the time of a document, in
+	// milliseconds since the epoch.
+	TimestampField = "@timestamp"
+	// LabelsField is the object holding the tags of a document.
+	LabelsField = "labels"
+	// IndexDateFormat is the format of the date suffix of index names.
+	IndexDateFormat = "2006.01.02"
+)
+
+// actionPrefix starts every bulk action line written by the Serializer, up
+// to the name of the index.
+var actionPrefix = []byte(`{"create":{"_index":"`)
+
+// Serializer writes a Point as a pair of lines of the Elasticsearch bulk API.
+type Serializer struct{}
+
+// Serialize writes Point data to the given writer as a create action into
+// the index of its measurement and day, followed by the document itself.
+// Tags go into the labels object, fields are top level. Missing fields are
+// left out, and so are points without fields.
+//
+// This function writes output that looks like:
+// {"create":{"_index":"<measurement>-<yyyy.mm.dd>"}}\n
+// {"@timestamp":<epoch millis>,"labels":{"<tag key>":"<tag value>"},"<field name>":<field value>}\n
+//
+// For example:
+// {"create":{"_index":"cpu-2016.01.01"}}\n
+// {"@timestamp":1451606400000,"labels":{"hostname":"host_0"},"usage_user":58.13}\n
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	hasFields := false
+	for _, v := range fieldValues {
+		if isValue(v) {
+			hasFields = true
+			break
+		}
+	}
+	if !hasFields {
+		return nil
+	}
+
+	ts := p.Timestamp().UTC()
+	buf := make([]byte, 0, 1024)
+	buf = append(buf, actionPrefix...)
+	buf = append(buf, p.MeasurementName()...)
+	buf = append(buf, '-')
+	buf = ts.AppendFormat(buf, IndexDateFormat)
+	buf = append(buf, "\"}}\n"...)
+
+	buf = append(buf, `{"`+TimestampField+`":`...)
+	buf = strconv.AppendInt(buf, ts.UnixNano()/1e6, 10)
+
+	tagKeys := p.TagKeys()
+	tagValues := p.TagValues()
+	firstTag := true
+	for i, v := range tagValues {
+		if !isValue(v) {
+			continue
+		}
+		if firstTag {
+			buf = append(buf, `,"`+LabelsField+`":{`...)
+			firstTag = false
+		} else {
+			buf = append(buf, ',')
+		}
+		buf = appendMember(buf, tagKeys[i], v)
+	}
+	if !firstTag {
+		buf = append(buf, '}')
+	}
+
+	for i, v := range fieldValues {
+		if !isValue(v) {
+			continue
+		}
+		buf = append(buf, ',')
+		buf = appendMember(buf, fieldKeys[i], v)
+	}
+	buf = append(buf, "}\n"...)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// isValue tells whether v can be written as a JSON value: NaN and infinite
+// numbers cannot, and are left out like missing values.
+func isValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case float64:
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	case float32:
+		return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+	}
+	return true
+}
+
+// appendMember appends "key":value to buf.
+func appendMember(buf, key []byte, v interface{}) []byte {
+	buf = appendString(buf, key)
+	buf = append(buf, ':')
+	switch v := v.(type) {
+	case string:
+		return appendString(buf, []byte(v))
+	case []byte:
+		return appendString(buf, v)
+	case uint64:
+		// larger values do not fit the long type of the mapping
+		if v > math.MaxInt64 {
+			return appendFloat(buf, float64(v), 64)
+		}
+	case float64:
+		return appendFloat(buf, v, 64)
+	case float32:
+		return appendFloat(buf, float64(v), 32)
+	}
+	return serialize.FastFormatAppend(v, buf)
+}
+
+// appendFloat appends v so that it always reads as a floating point number,
+// e.g. 58.0 rather than 58, since dynamic mapping would otherwise map the
+// field of the first document holding a whole number as long.
+func appendFloat(buf []byte, v float64, bitSize int) []byte {
+	start := len(buf)
+	buf = strconv.AppendFloat(buf, v, 'g', -1, bitSize)
+	for _, c := range buf[start:] {
+		if c == '.' || c == 'e' {
+			return buf
+		}
+	}
+	return append(buf, '.', '0')
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendString appends s as a JSON string, escaping quotes, backslashes and
+// control characters.
+func appendString(buf, s []byte) []byte {
+	buf = append(buf, '"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
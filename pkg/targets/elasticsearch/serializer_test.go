@@ -0,0 +1,97 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestElasticsearchSerializerSerialize(t *testing.T) {
+	const action = `{"create":{"_index":"cpu-2016.01.01"}}` + "\n"
+	const labels = `"labels":{"hostname":"host_0","region":"eu-west-1","datacenter":"eu-west-1b"}`
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     action + `{"@timestamp":1451606400000,` + labels + `,"usage_guest_nice":38.24311829}` + "\n",
+		},
+		{
+			Desc:       "a regular Point using int as value",
+			InputPoint: serialize.TestPointInt(),
+			Output:     action + `{"@timestamp":1451606400000,` + labels + `,"usage_guest":38}` + "\n",
+		},
+		{
+			Desc:       "a regular Point with multiple fields",
+			InputPoint: serialize.TestPointMultiField(),
+			Output:     action + `{"@timestamp":1451606400000,` + labels + `,"big_usage_guest":5000000000,"usage_guest":38,"usage_guest_nice":38.24311829}` + "\n",
+		},
+		{
+			Desc:       "a Point with no tags",
+			InputPoint: serialize.TestPointNoTags(),
+			Output:     action + `{"@timestamp":1451606400000,"usage_guest_nice":38.24311829}` + "\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     action + `{"@timestamp":1451606400000,"usage_guest_nice":38.24311829}` + "\n",
+		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     action + `{"@timestamp":1451606400000,"usage_guest_nice":38.24311829}` + "\n",
+		},
+		{
+			Desc:       "a Point with string, boolean and unsigned fields",
+			InputPoint: serialize.TestPointTypedFields(),
+			Output:     action + `{"@timestamp":1451606400000,` + labels + `,"status":"ok","reachable":true,"requests_total":1.8446744073709552e+19}` + "\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
+
+func TestSerializeValues(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("readings"))
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendTag([]byte("name"), "truck \"0\"\n")
+	p.AppendTag([]byte("load_capacity"), 1500.0)
+	p.AppendField([]byte("whole"), 58.0)
+	p.AppendField([]byte("nan"), math.NaN())
+	p.AppendField([]byte("small"), float32(0.5))
+	var buf bytes.Buffer
+	if err := (&Serializer{}).Serialize(p, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("incorrect number of lines: got %d want 2", len(lines))
+	}
+	want := `{"@timestamp":1451606400000,"labels":{"name":"truck \"0\"\u000a","load_capacity":1500.0},"whole":58.0,"small":0.5}`
+	if got := string(lines[1]); got != want {
+		t.Errorf("incorrect document:\ngot  %s\nwant %s", got, want)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(lines[1], &doc); err != nil {
+		t.Errorf("document is not valid JSON: %v", err)
+	}
+}
+
+func TestSerializeNoFields(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("cpu"))
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendTag([]byte("hostname"), "host_0")
+	p.AppendField([]byte("usage_user"), nil)
+	var buf bytes.Buffer
+	if err := (&Serializer{}).Serialize(p, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output for point without fields: %s", buf.String())
+	}
+}
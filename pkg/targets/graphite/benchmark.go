@@ -0,0 +1,165 @@
+package graphite
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"net"
+	"time"
+
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/common"
+)
+
+// allows for testing
+var fatal = log.Fatalf
+
+// NewBenchmark creates the benchmark that sends the data of the given source
+// to a Carbon receiver. The data is made of the lines the Serializer writes,
+// read from a file or generated on the fly: tagged series in the plaintext
+// protocol of Graphite 1.1. Processors rename and frame them as opts say when
+// sending them:
+//
+//   - tagged naming keeps the series as they are, e.g.
+//     cpu.usage_user;hostname=host_0;region=eu-west-1, while path naming puts
+//     the tag values into the dotted path, e.g.
+//     cpu.host_0.eu-west-1.usage_user, for Carbon without tag support
+//   - the plaintext protocol sends a <series> <value> <timestamp> line per
+//     value, while the pickle protocol sends lists of (series, (timestamp,
+//     value)) tuples, each pickled in a length-prefixed message
+func NewBenchmark(opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		br := load.GetBufferedReader(dataSourceConfig.File.Location)
+		ds = &fileDataSource{scanner: bufio.NewScanner(br)}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	return &benchmark{
+		opts:       opts,
+		dataSource: ds,
+		batches:    common.NewByteBatchFactory(),
+	}, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	opts       *SpecificConfig
+	dataSource targets.DataSource
+	batches    *common.ByteBatchFactory
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+// GetBatchFactory returns batches that hold the lines of their points, as the
+// Serializer writes them; the naming and framing of opts are only applied by
+// the processor sending a batch.
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return b.batches
+}
+
+// GetPointIndexer spreads the points over the workers by their measurement
+// and tags, e.g. by host in devops. Whether the tags end up in the tagged
+// series or in the path, the values of a Graphite series are then all sent
+// over the connection of one worker, in timestamp order.
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return common.NewGenericPointIndexer(maxPartitions, seriesOfPoint)
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{opts: b.opts, batches: b.batches}
+}
+
+// GetDBCreator returns nil, as Graphite creates series as their first data
+// arrives.
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return nil
+}
+
+// seriesOfPoint returns the measurement and tags shared by the lines of a
+// point.
+func seriesOfPoint(item *data.LoadedPoint) []byte {
+	lines := item.Data.(*point).lines
+	if j := bytes.IndexByte(lines, '\n'); j >= 0 {
+		lines = lines[:j]
+	}
+	l, err := parseLine(lines)
+	if err != nil {
+		fatal("cannot read Graphite data: %v", err)
+		return nil
+	}
+	series := make([]byte, 0, len(l.measurement)+len(l.tags))
+	series = append(series, l.measurement...)
+	return append(series, l.tags...)
+}
+
+// processor sends batches over a TCP connection of its own to the Carbon
+// receiver, encoding them in the naming and protocol of opts.
+type processor struct {
+	opts    *SpecificConfig
+	batches *common.ByteBatchFactory
+	encoder *encoder
+	conn    net.Conn
+}
+
+func (p *processor) Init(_ int, doLoad, _ bool) {
+	p.encoder = newEncoder(p.opts)
+	if !doLoad {
+		return
+	}
+	var err error
+	p.conn, err = net.DialTimeout("tcp", p.opts.Address, p.opts.Timeout)
+	if err != nil {
+		fatal("cannot connect to Carbon receiver %s: %v", p.opts.Address, err)
+	}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	batch := b.(*common.ByteBatch)
+	if doLoad {
+		msg, err := p.encoder.encode(batch.Bytes())
+		if err != nil {
+			fatal("cannot encode batch: %v", err)
+		}
+		if err := p.send(msg); err != nil {
+			fatal("cannot send to Carbon receiver %s: %v", p.opts.Address, err)
+		}
+	}
+	metricCount, rowCount := batch.Counts()
+	p.batches.Put(batch)
+	return metricCount, rowCount
+}
+
+func (p *processor) send(msg []byte) error {
+	if p.opts.Timeout > 0 {
+		if err := p.conn.SetWriteDeadline(time.Now().Add(p.opts.Timeout)); err != nil {
+			return err
+		}
+	}
+	_, err := p.conn.Write(msg)
+	return err
+}
+
+func (p *processor) Close(_ bool) {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+}
@@ -0,0 +1,274 @@
+package graphite
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/common"
+)
+
+var defaultFatal = fatal
+
+func TestSpecificConfigValidate(t *testing.T) {
+	cases := []struct {
+		config SpecificConfig
+		want   string
+		err    bool
+	}{
+		{config: SpecificConfig{Protocol: ProtocolPlaintext}, want: DefaultPlaintextAddress},
+		{config: SpecificConfig{Protocol: ProtocolPickle}, want: DefaultPickleAddress},
+		{config: SpecificConfig{Protocol: ProtocolPickle, Address: "carbon:2014"}, want: "carbon:2014"},
+		{config: SpecificConfig{Protocol: "udp"}, err: true},
+	}
+	for _, c := range cases {
+		err := c.config.validate()
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.config.Protocol)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.config.Protocol, err)
+		}
+		if c.config.Address != c.want {
+			t.Errorf("%s: incorrect address: got %s want %s", c.config.Protocol, c.config.Address, c.want)
+		}
+	}
+
+	ds := &source.DataSourceConfig{Type: source.FileDataSourceType}
+	if _, err := NewBenchmark(&SpecificConfig{Protocol: "udp"}, ds); err == nil {
+		t.Errorf("unexpected lack of error for unknown protocol")
+	}
+}
+
+func TestFileDataSource(t *testing.T) {
+	ds := &fileDataSource{scanner: bufio.NewScanner(strings.NewReader(testLines))}
+	want := []struct {
+		lines   string
+		metrics uint64
+	}{
+		{lines: testLines[:strings.Index(testLines, "mem")], metrics: 2},
+		{lines: "mem.free 1024 1451606410\n", metrics: 1},
+	}
+	count := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		if count >= len(want) {
+			t.Fatalf("too many points")
+		}
+		p := item.Data.(*point)
+		if string(p.lines) != want[count].lines || p.metrics != want[count].metrics {
+			t.Errorf("point %d: incorrect lines or count: got %d metrics of\n%s", count, p.metrics, p.lines)
+		}
+		count++
+	}
+	if count != len(want) {
+		t.Errorf("incorrect number of points: got %d want %d", count, len(want))
+	}
+
+	var fatalCalled bool
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+	defer func() { fatal = defaultFatal }()
+	ds = &fileDataSource{scanner: bufio.NewScanner(strings.NewReader("cpu.usage_user 58\n"))}
+	if item := ds.NextItem(); item.Data != nil || !fatalCalled {
+		t.Errorf("malformed line not reported")
+	}
+}
+
+func TestSimulationDataSource(t *testing.T) {
+	const hosts, epochs = 2, 3
+	conf := &devops.CPUOnlySimulatorConfig{
+		Start:           serialize.TestNow,
+		End:             serialize.TestNow.Add(epochs * time.Second),
+		InitHostCount:   hosts,
+		HostCount:       hosts,
+		HostConstructor: devops.NewHostCPUOnly,
+	}
+	ds := newSimulationDataSource(conf.NewSimulator(time.Second, 0))
+	count := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		p := item.Data.(*point)
+		if p.metrics != 10 {
+			t.Errorf("incorrect number of metrics: got %d want 10", p.metrics)
+		}
+		if !strings.HasPrefix(string(p.lines), "cpu.usage_user;hostname=host_") {
+			t.Errorf("unexpected first line: %s", p.lines)
+		}
+		count++
+	}
+	if count != hosts*epochs {
+		t.Errorf("incorrect number of points: got %d want %d", count, hosts*epochs)
+	}
+}
+
+func newTestPoint(lines string) data.LoadedPoint {
+	return data.NewLoadedPoint(&point{lines: []byte(lines), metrics: uint64(strings.Count(lines, "\n"))})
+}
+
+func TestBatch(t *testing.T) {
+	b := (&benchmark{batches: common.NewByteBatchFactory()}).GetBatchFactory().New()
+	b.Append(newTestPoint(testLines[:strings.Index(testLines, "mem")]))
+	b.Append(newTestPoint("mem.free 1024 1451606410\n"))
+	if b.Len() != 2 {
+		t.Errorf("incorrect batch length: got %d want 2", b.Len())
+	}
+	if metrics, _ := b.(*common.ByteBatch).Counts(); metrics != 3 {
+		t.Errorf("incorrect number of metrics: got %d want 3", metrics)
+	}
+	if lines := b.(*common.ByteBatch).Bytes(); string(lines) != testLines {
+		t.Errorf("incorrect lines:\n%s", lines)
+	}
+}
+
+func TestPointIndexer(t *testing.T) {
+	const partitions = 4
+	i := (&benchmark{}).GetPointIndexer(partitions)
+	indexes := make(map[string]uint)
+	for j := 0; j < 3; j++ {
+		for _, host := range []string{"host_0", "host_1", "host_2", "host_3", "host_4"} {
+			// lines of later points differ in values and time only
+			lines := fmt.Sprintf("cpu.usage_user;hostname=%s %d %d\n", host, j, 1451606400+10*j)
+			idx := i.GetIndex(newTestPoint(lines))
+			if idx >= partitions {
+				t.Fatalf("index out of range: got %d", idx)
+			}
+			if want, ok := indexes[host]; ok && idx != want {
+				t.Errorf("%s: index changed: got %d want %d", host, idx, want)
+			}
+			indexes[host] = idx
+		}
+	}
+	if _, ok := (&benchmark{}).GetPointIndexer(1).(*targets.ConstantIndexer); !ok {
+		t.Errorf("a single partition should not need hashing")
+	}
+}
+
+// carbonReceiver is a stub Carbon receiver that keeps everything it gets.
+type carbonReceiver struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	received []string
+}
+
+func newCarbonReceiver(t *testing.T) *carbonReceiver {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	r := &carbonReceiver{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			r.wg.Add(1)
+			go func() {
+				defer r.wg.Done()
+				b, _ := ioutil.ReadAll(conn)
+				r.mu.Lock()
+				r.received = append(r.received, string(b))
+				r.mu.Unlock()
+			}()
+		}
+	}()
+	return r
+}
+
+// close stops listening and waits for the connections to be closed.
+func (r *carbonReceiver) close() {
+	r.listener.Close()
+	r.wg.Wait()
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	cases := []struct {
+		protocol string
+		tagged   bool
+		check    func(t *testing.T, received string)
+	}{
+		{
+			protocol: ProtocolPlaintext,
+			tagged:   true,
+			check: func(t *testing.T, received string) {
+				if received != testLines {
+					t.Errorf("incorrect lines received:\n%s", received)
+				}
+			},
+		},
+		{
+			protocol: ProtocolPlaintext,
+			check: func(t *testing.T, received string) {
+				if !strings.HasPrefix(received, "cpu.host_0.Ubuntu15_10.usage_user 58.5 1451606400\n") {
+					t.Errorf("incorrect lines received:\n%s", received)
+				}
+			},
+		},
+		{
+			protocol: ProtocolPickle,
+			check: func(t *testing.T, received string) {
+				msgs := unpickle(t, []byte(received))
+				if len(msgs) != 1 || len(msgs[0]) != 3 {
+					t.Errorf("incorrect pickle messages received: %v", msgs)
+				}
+			},
+		},
+	}
+	for _, c := range cases {
+		receiver := newCarbonReceiver(t)
+		opts := &SpecificConfig{Protocol: c.protocol, Address: receiver.listener.Addr().String(), Tagged: c.tagged, Timeout: time.Second}
+		batches := common.NewByteBatchFactory()
+		for _, doLoad := range []bool{false, true} {
+			p := &processor{opts: opts, batches: batches}
+			p.Init(0, doLoad, false)
+			b := batches.New()
+			b.Append(newTestPoint(testLines[:strings.Index(testLines, "mem")]))
+			b.Append(newTestPoint("mem.free 1024 1451606410\n"))
+			metrics, rows := p.ProcessBatch(b, doLoad)
+			if metrics != 3 || rows != 2 {
+				t.Errorf("%s: incorrect counts: got %d metrics and %d rows, want 3 and 2", c.protocol, metrics, rows)
+			}
+			p.Close(doLoad)
+		}
+		receiver.close()
+		if len(receiver.received) != 1 {
+			t.Fatalf("%s: incorrect number of connections: got %d want 1", c.protocol, len(receiver.received))
+		}
+		c.check(t, receiver.received[0])
+	}
+}
+
+func TestProcessorConnectionFailure(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	var fatalCalled bool
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+	defer func() { fatal = defaultFatal }()
+	p := &processor{opts: &SpecificConfig{Protocol: ProtocolPlaintext, Address: addr, Timeout: time.Second}}
+	p.Init(0, true, false)
+	if !fatalCalled {
+		t.Errorf("connection failure not reported")
+	}
+}
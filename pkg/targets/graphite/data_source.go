@@ -0,0 +1,129 @@
+package graphite
+
+import (
+	"bufio"
+	"bytes"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// point holds the lines of a point, one per field, in the plaintext protocol
+// written by a Serializer.
+type point struct {
+	lines   []byte
+	metrics uint64
+}
+
+// Encoded returns the lines of the point, for the batches of common.ByteBatch.
+func (p *point) Encoded() []byte {
+	return p.lines
+}
+
+// MetricCount returns the number of lines, i.e. values, of the point.
+func (p *point) MetricCount() uint64 {
+	return p.metrics
+}
+
+// fileDataSource reads the lines written by a Serializer, and groups the
+// consecutive lines of each point.
+type fileDataSource struct {
+	scanner *bufio.Scanner
+	// next is the first line of the next point, read with the previous one
+	next []byte
+}
+
+func (d *fileDataSource) NextItem() data.LoadedPoint {
+	p := &point{}
+	if d.next != nil {
+		p.lines = append(p.lines, d.next...)
+		d.next = nil
+	} else if d.scanner.Scan() {
+		p.lines = append(p.lines, d.scanner.Bytes()...)
+	} else {
+		if err := d.scanner.Err(); err != nil {
+			fatal("scanner error: %v", err)
+		}
+		return data.LoadedPoint{}
+	}
+	first, err := parseLine(p.lines)
+	if err != nil {
+		fatal("cannot read Graphite data: %v", err)
+		return data.LoadedPoint{}
+	}
+	p.lines = append(p.lines, '\n')
+	p.metrics = 1
+
+	for d.scanner.Scan() {
+		b := d.scanner.Bytes()
+		l, err := parseLine(b)
+		if err != nil {
+			fatal("cannot read Graphite data: %v", err)
+			return data.LoadedPoint{}
+		}
+		if !samePoint(&first, &l) {
+			d.next = append(d.next[:0], b...)
+			break
+		}
+		p.lines = append(p.lines, b...)
+		p.lines = append(p.lines, '\n')
+		p.metrics++
+	}
+	if err := d.scanner.Err(); err != nil {
+		fatal("scanner error: %v", err)
+	}
+	return data.NewLoadedPoint(p)
+}
+
+func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+// simulationDataSource generates points with a simulator and serializes
+// each one, the same representation the file data source reads.
+type simulationDataSource struct {
+	simulator  common.Simulator
+	headers    *common.GeneratedDataHeaders
+	serializer Serializer
+	buf        bytes.Buffer
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	for !d.simulator.Finished() {
+		if d.simulator.Next(newSimulatorPoint) {
+			d.buf.Reset()
+			if err := d.serializer.Serialize(newSimulatorPoint, &d.buf); err != nil {
+				fatal("cannot serialize point: %v", err)
+				return data.LoadedPoint{}
+			}
+			// points without number fields have no lines
+			if d.buf.Len() > 0 {
+				lines := append([]byte(nil), d.buf.Bytes()...)
+				return data.NewLoadedPoint(&point{
+					lines:   lines,
+					metrics: uint64(bytes.Count(lines, []byte{'\n'})),
+				})
+			}
+		}
+		newSimulatorPoint.Reset()
+	}
+	return data.LoadedPoint{}
+}
@@ -0,0 +1,59 @@
+package graphite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blagojts/viper"
+)
+
+// Protocols Graphite is loaded over
+const (
+	ProtocolPlaintext = "plaintext"
+	ProtocolPickle    = "pickle"
+)
+
+// Default addresses of the Carbon receivers of the protocols
+const (
+	DefaultPlaintextAddress = "localhost:2003"
+	DefaultPickleAddress    = "localhost:2004"
+)
+
+const errUnknownProtocolFmt = "unknown Graphite protocol '%s'; expected %s or %s"
+
+// SpecificConfig holds the Graphite specific loading options.
+type SpecificConfig struct {
+	Protocol string        `yaml:"protocol" mapstructure:"protocol"`
+	Address  string        `yaml:"address" mapstructure:"address"`
+	Tagged   bool          `yaml:"tagged" mapstructure:"tagged"`
+	Timeout  time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// validate checks the protocol and sets the default address of the protocol
+// if none is given.
+func (c *SpecificConfig) validate() error {
+	switch c.Protocol {
+	case ProtocolPlaintext:
+		if c.Address == "" {
+			c.Address = DefaultPlaintextAddress
+		}
+	case ProtocolPickle:
+		if c.Address == "" {
+			c.Address = DefaultPickleAddress
+		}
+	default:
+		return fmt.Errorf(errUnknownProtocolFmt, c.Protocol, ProtocolPlaintext, ProtocolPickle)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package graphite
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &graphiteTarget{}
+}
+
+type graphiteTarget struct {
+}
+
+func (t *graphiteTarget) TargetName() string {
+	return constants.FormatGraphite
+}
+
+func (t *graphiteTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *graphiteTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	graphiteSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(graphiteSpecificConfig, dataSourceConfig)
+}
+
+func (t *graphiteTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"protocol", ProtocolPlaintext, "Protocol to send data over: plaintext or pickle")
+	flagSet.String(flagPrefix+"address", "",
+		"Carbon receiver to send data to (default "+DefaultPlaintextAddress+" for plaintext, "+DefaultPickleAddress+" for pickle)")
+	flagSet.Bool(flagPrefix+"tagged", false,
+		"Whether to send tagged series of Graphite 1.1, instead of dotted paths of the tag values")
+	flagSet.Duration(flagPrefix+"timeout", 30*time.Second, "Timeout of connecting and of sending each batch")
+}
@@ -0,0 +1,192 @@
+package graphite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// maxPickleSize is the largest pickle message Carbon accepts.
+const maxPickleSize = 1 << 20
+
+// Opcodes of the pickle protocol 2 used to encode pickle messages.
+const (
+	pickleProto     = 0x80
+	pickleEmptyList = ']'
+	pickleMark      = '('
+	pickleUnicode   = 'X' // BINUNICODE, with a 4-byte little-endian length
+	pickleFloat     = 'G' // BINFLOAT, an 8-byte big-endian double
+	pickleTuple2    = 0x86
+	pickleAppends   = 'e'
+	pickleStop      = '.'
+)
+
+// pickleItemSize is the size of a pickled item without its path.
+const pickleItemSize = 1 + 4 + 2*9 + 2
+
+// line is a line of the plaintext protocol written by a Serializer:
+// <measurement>.<field><tags> <value> <timestamp>
+type line struct {
+	measurement []byte
+	field       []byte
+	// tags are the ;<key>=<value> pairs of the series, if any
+	tags      []byte
+	value     []byte
+	timestamp []byte
+}
+
+// parseLine parses b, a line without its newline.
+func parseLine(b []byte) (line, error) {
+	var l line
+	i := bytes.IndexByte(b, ' ')
+	j := bytes.LastIndexByte(b, ' ')
+	if i <= 0 || i == j {
+		return l, fmt.Errorf("malformed line '%s': expected a series, a value and a timestamp", b)
+	}
+	series := b[:i]
+	l.value = b[i+1 : j]
+	l.timestamp = b[j+1:]
+	if k := bytes.IndexByte(series, ';'); k >= 0 {
+		series, l.tags = series[:k], series[k:]
+	}
+	k := bytes.IndexByte(series, '.')
+	if k < 0 {
+		return l, fmt.Errorf("malformed line '%s': expected a series named <measurement>.<field>", b)
+	}
+	l.measurement = series[:k]
+	l.field = series[k+1:]
+	return l, nil
+}
+
+// samePoint tells whether a and b are lines of the same point, i.e. of the
+// same measurement, tags and time.
+func samePoint(a, b *line) bool {
+	return bytes.Equal(a.measurement, b.measurement) &&
+		bytes.Equal(a.tags, b.tags) &&
+		bytes.Equal(a.timestamp, b.timestamp)
+}
+
+// appendPath appends the path of l to buf: the tagged series, or the dotted
+// path <measurement>.<tag values...>.<field> if tagged is false.
+func appendPath(buf []byte, l *line, tagged bool) []byte {
+	buf = append(buf, l.measurement...)
+	if tagged {
+		buf = append(buf, '.')
+		buf = append(buf, l.field...)
+		return append(buf, l.tags...)
+	}
+	tags := l.tags
+	for len(tags) > 0 {
+		// skip the leading ;
+		tags = tags[1:]
+		tag := tags
+		if i := bytes.IndexByte(tags, ';'); i >= 0 {
+			tag, tags = tags[:i], tags[i:]
+		} else {
+			tags = nil
+		}
+		buf = append(buf, '.')
+		buf = appendSanitized(buf, tag[bytes.IndexByte(tag, '=')+1:], invalidNodeChars)
+	}
+	buf = append(buf, '.')
+	return append(buf, l.field...)
+}
+
+// encoder encodes the lines written by a Serializer in the protocol and with
+// the series names of the loading options.
+type encoder struct {
+	pickle bool
+	tagged bool
+
+	buf  []byte
+	path []byte
+}
+
+func newEncoder(opts *SpecificConfig) *encoder {
+	return &encoder{pickle: opts.Protocol == ProtocolPickle, tagged: opts.Tagged}
+}
+
+// encode returns lines encoded for sending. Plaintext is sent as lines, and
+// pickle as many messages as it takes to keep them below the largest size
+// Carbon accepts. The returned bytes are only valid until the next call.
+func (e *encoder) encode(lines []byte) ([]byte, error) {
+	// the lines are tagged plaintext already
+	if !e.pickle && e.tagged {
+		return lines, nil
+	}
+
+	e.buf = e.buf[:0]
+	msgStart := -1
+	for len(lines) > 0 {
+		b := lines
+		if i := bytes.IndexByte(lines, '\n'); i >= 0 {
+			b, lines = lines[:i], lines[i+1:]
+		} else {
+			lines = nil
+		}
+		l, err := parseLine(b)
+		if err != nil {
+			return nil, err
+		}
+		e.path = appendPath(e.path[:0], &l, e.tagged)
+
+		if !e.pickle {
+			e.buf = append(e.buf, e.path...)
+			e.buf = append(e.buf, ' ')
+			e.buf = append(e.buf, l.value...)
+			e.buf = append(e.buf, ' ')
+			e.buf = append(e.buf, l.timestamp...)
+			e.buf = append(e.buf, '\n')
+			continue
+		}
+
+		ts, err := strconv.ParseFloat(string(l.timestamp), 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed timestamp in line '%s': %v", b, err)
+		}
+		value, err := strconv.ParseFloat(string(l.value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed value in line '%s': %v", b, err)
+		}
+		// 4 bytes of length precede the message, 2 bytes end it
+		if msgStart >= 0 && len(e.buf)-msgStart-4+len(e.path)+pickleItemSize+2 > maxPickleSize {
+			e.buf = finishPickle(e.buf, msgStart)
+			msgStart = -1
+		}
+		if msgStart < 0 {
+			msgStart = len(e.buf)
+			e.buf = append(e.buf, 0, 0, 0, 0, pickleProto, 2, pickleEmptyList, pickleMark)
+		}
+		e.buf = appendPickleItem(e.buf, e.path, ts, value)
+	}
+	if msgStart >= 0 {
+		e.buf = finishPickle(e.buf, msgStart)
+	}
+	return e.buf, nil
+}
+
+// appendPickleItem appends the pickled tuple (path, (timestamp, value)).
+func appendPickleItem(buf, path []byte, ts, value float64) []byte {
+	buf = append(buf, pickleUnicode, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(buf[len(buf)-4:], uint32(len(path)))
+	buf = append(buf, path...)
+	buf = appendPickleFloat(buf, ts)
+	buf = appendPickleFloat(buf, value)
+	return append(buf, pickleTuple2, pickleTuple2)
+}
+
+func appendPickleFloat(buf []byte, v float64) []byte {
+	buf = append(buf, pickleFloat, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(buf[len(buf)-8:], math.Float64bits(v))
+	return buf
+}
+
+// finishPickle ends the list of the message starting at msgStart and sets
+// its length.
+func finishPickle(buf []byte, msgStart int) []byte {
+	buf = append(buf, pickleAppends, pickleStop)
+	binary.BigEndian.PutUint32(buf[msgStart:], uint32(len(buf)-msgStart-4))
+	return buf
+}
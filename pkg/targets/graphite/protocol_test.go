@@ -0,0 +1,210 @@
+package graphite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testLines = "cpu.usage_user;hostname=host_0;os=Ubuntu15.10 58.5 1451606400\n" +
+	"cpu.usage_system;hostname=host_0;os=Ubuntu15.10 2 1451606400\n" +
+	"mem.free 1024 1451606410\n"
+
+func TestParseLine(t *testing.T) {
+	cases := []struct {
+		input string
+		want  line
+		err   bool
+	}{
+		{
+			input: "cpu.usage_user;hostname=host_0;os=Ubuntu15.10 58.5 1451606400",
+			want: line{
+				measurement: []byte("cpu"),
+				field:       []byte("usage_user"),
+				tags:        []byte(";hostname=host_0;os=Ubuntu15.10"),
+				value:       []byte("58.5"),
+				timestamp:   []byte("1451606400"),
+			},
+		},
+		{
+			input: "mem.free 1024 1451606410",
+			want: line{
+				measurement: []byte("mem"),
+				field:       []byte("free"),
+				value:       []byte("1024"),
+				timestamp:   []byte("1451606410"),
+			},
+		},
+		{input: "cpu.usage_user 58.5", err: true},
+		{input: "cpu 58.5 1451606400", err: true},
+		{input: "", err: true},
+	}
+	for _, c := range cases {
+		got, err := parseLine([]byte(c.input))
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.input, err)
+			continue
+		}
+		if fmt.Sprintf("%s", got) != fmt.Sprintf("%s", c.want) {
+			t.Errorf("%s: incorrect line: got %s want %s", c.input, got, c.want)
+		}
+	}
+}
+
+func TestSamePoint(t *testing.T) {
+	parse := func(s string) *line {
+		l, err := parseLine([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return &l
+	}
+	a := parse("cpu.usage_user;hostname=host_0 58 1451606400")
+	cases := []struct {
+		other string
+		want  bool
+	}{
+		{"cpu.usage_system;hostname=host_0 2 1451606400", true},
+		{"cpu.usage_system;hostname=host_1 2 1451606400", false},
+		{"cpu.usage_system;hostname=host_0 2 1451606410", false},
+		{"mem.free;hostname=host_0 2 1451606400", false},
+	}
+	for _, c := range cases {
+		if got := samePoint(a, parse(c.other)); got != c.want {
+			t.Errorf("%s: got %v want %v", c.other, got, c.want)
+		}
+	}
+}
+
+func TestEncoderPlaintext(t *testing.T) {
+	cases := []struct {
+		tagged bool
+		want   string
+	}{
+		{tagged: true, want: testLines},
+		{
+			tagged: false,
+			want: "cpu.host_0.Ubuntu15_10.usage_user 58.5 1451606400\n" +
+				"cpu.host_0.Ubuntu15_10.usage_system 2 1451606400\n" +
+				"mem.free 1024 1451606410\n",
+		},
+	}
+	for _, c := range cases {
+		e := newEncoder(&SpecificConfig{Protocol: ProtocolPlaintext, Tagged: c.tagged})
+		got, err := e.encode([]byte(testLines))
+		if err != nil {
+			t.Fatalf("tagged %v: unexpected error: %v", c.tagged, err)
+		}
+		if string(got) != c.want {
+			t.Errorf("tagged %v: incorrect output:\ngot\n%s\nwant\n%s", c.tagged, got, c.want)
+		}
+	}
+
+	e := newEncoder(&SpecificConfig{Protocol: ProtocolPlaintext})
+	if _, err := e.encode([]byte("cpu 58.5\n")); err == nil {
+		t.Errorf("unexpected lack of error for malformed line")
+	}
+}
+
+// pickleItem is an item of a pickled list of (path, (timestamp, value)).
+type pickleItem struct {
+	path      string
+	timestamp float64
+	value     float64
+}
+
+// unpickle decodes the pickle messages in b, which must only use the opcodes
+// the encoder writes.
+func unpickle(t *testing.T, b []byte) [][]pickleItem {
+	t.Helper()
+	var msgs [][]pickleItem
+	for len(b) > 0 {
+		if len(b) < 4 {
+			t.Fatalf("truncated message length")
+		}
+		size := int(binary.BigEndian.Uint32(b))
+		if size > maxPickleSize {
+			t.Fatalf("message too large: %d bytes", size)
+		}
+		msg := b[4 : 4+size]
+		b = b[4+size:]
+		if string(msg[:4]) != "\x80\x02](" || string(msg[len(msg)-2:]) != "e." {
+			t.Fatalf("unexpected start or end of message: %q %q", msg[:4], msg[len(msg)-2:])
+		}
+		msg = msg[4 : len(msg)-2]
+		var items []pickleItem
+		for len(msg) > 0 {
+			if msg[0] != pickleUnicode {
+				t.Fatalf("unexpected opcode %q", msg[0])
+			}
+			n := int(binary.LittleEndian.Uint32(msg[1:]))
+			item := pickleItem{path: string(msg[5 : 5+n])}
+			msg = msg[5+n:]
+			if msg[0] != pickleFloat || msg[9] != pickleFloat || msg[18] != pickleTuple2 || msg[19] != pickleTuple2 {
+				t.Fatalf("unexpected opcodes of tuple: %q", msg[:20])
+			}
+			item.timestamp = math.Float64frombits(binary.BigEndian.Uint64(msg[1:]))
+			item.value = math.Float64frombits(binary.BigEndian.Uint64(msg[10:]))
+			items = append(items, item)
+			msg = msg[20:]
+		}
+		msgs = append(msgs, items)
+	}
+	return msgs
+}
+
+func TestEncoderPickle(t *testing.T) {
+	e := newEncoder(&SpecificConfig{Protocol: ProtocolPickle})
+	b, err := e.encode([]byte(testLines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []pickleItem{
+		{"cpu.host_0.Ubuntu15_10.usage_user", 1451606400, 58.5},
+		{"cpu.host_0.Ubuntu15_10.usage_system", 1451606400, 2},
+		{"mem.free", 1451606410, 1024},
+	}
+	msgs := unpickle(t, b)
+	if len(msgs) != 1 {
+		t.Fatalf("incorrect number of messages: got %d want 1", len(msgs))
+	}
+	if fmt.Sprint(msgs[0]) != fmt.Sprint(want) {
+		t.Errorf("incorrect items:\ngot  %v\nwant %v", msgs[0], want)
+	}
+
+	if _, err := e.encode([]byte("cpu.usage_user high 1451606400\n")); err == nil {
+		t.Errorf("unexpected lack of error for malformed value")
+	}
+}
+
+func TestEncoderPickleSplit(t *testing.T) {
+	const count = 20000
+	line := "cpu.usage_user;hostname=host_0;region=eu-central-1;datacenter=eu-central-1a 58.5 1451606400\n"
+	e := newEncoder(&SpecificConfig{Protocol: ProtocolPickle, Tagged: true})
+	b, err := e.encode([]byte(strings.Repeat(line, count)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msgs := unpickle(t, b)
+	if len(msgs) < 2 {
+		t.Errorf("items not split into messages: got %d message(s)", len(msgs))
+	}
+	total := 0
+	for _, items := range msgs {
+		total += len(items)
+	}
+	if total != count {
+		t.Errorf("incorrect number of items: got %d want %d", total, count)
+	}
+	if got := msgs[0][0].path; got != line[:strings.IndexByte(line, ' ')] {
+		t.Errorf("incorrect tagged path: got %s", got)
+	}
+}
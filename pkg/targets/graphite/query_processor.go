@@ -0,0 +1,72 @@
+package graphite
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// QueryProcessorOptions are the options of the query processors.
+type QueryProcessorOptions struct {
+	// URLs of the Graphite render APIs, used round-robin by worker
+	URLs []string
+	// Debug is the debug level: 1 prints the size of each result, 2 adds
+	// the query description, 3 the request and 4 the response
+	Debug int
+	// PrettyPrintResponses prints every response as indented JSON
+	PrettyPrintResponses bool
+}
+
+// NewQueryProcessorCreate returns a function creating query processors which
+// send the queries round-robin (by worker) to the given Graphite render APIs.
+func NewQueryProcessorCreate(opts *QueryProcessorOptions) query.ProcessorCreate {
+	return query.NewHTTPProcessorCreate(&query.HTTPProcessorOptions{
+		URLs:                 opts.URLs,
+		Debug:                opts.Debug,
+		PrettyPrintResponses: opts.PrettyPrintResponses,
+		ParseResponse:        describeResponse,
+	})
+}
+
+// queryResult is the size of the result of a query.
+type queryResult struct {
+	series int
+	// datapoints that are not null
+	datapoints int
+}
+
+// parseResponse returns the number of series and datapoints in a JSON
+// response of the render API.
+func parseResponse(body []byte) (queryResult, error) {
+	var res queryResult
+	var series []struct {
+		Datapoints [][2]*float64 `json:"datapoints"`
+	}
+	if err := json.Unmarshal(body, &series); err != nil {
+		return res, fmt.Errorf("cannot decode response: %w", err)
+	}
+	res.series = len(series)
+	for _, s := range series {
+		for _, dp := range s.Datapoints {
+			if dp[0] != nil {
+				res.datapoints++
+			}
+		}
+	}
+	return res, nil
+}
+
+// describeResponse is the query.HTTPResponseParser of the render API.
+func describeResponse(body []byte) (query.HTTPResult, error) {
+	res, err := parseResponse(body)
+	if err != nil {
+		return query.HTTPResult{}, err
+	}
+	return query.HTTPResult{
+		Description: fmt.Sprintf("%d series, %d datapoints", res.series, res.datapoints),
+		HasSize:     true,
+		Series:      int64(res.series),
+		Samples:     int64(res.datapoints),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package graphite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const renderResponse = `[
+	{"target":"host_0","tags":{"name":"host_0"},"datapoints":[[58.1,1451606400],[null,1451606460],[24.9,1451606520]]},
+	{"target":"host_1","tags":{"name":"host_1"},"datapoints":[[61.5,1451606400],[22.9,1451606460]]}]`
+
+func TestParseResponse(t *testing.T) {
+	cases := []struct {
+		desc string
+		body string
+		want queryResult
+		err  bool
+	}{
+		{desc: "series", body: renderResponse, want: queryResult{series: 2, datapoints: 4}},
+		{desc: "empty", body: `[]`},
+		{desc: "error", body: `{"error":"bad target"}`, err: true},
+		{desc: "not json", body: "<html>", err: true},
+	}
+	for _, c := range cases {
+		got, err := parseResponse([]byte(c.body))
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: incorrect result: got %+v want %+v", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestQueryProcessorProcessQuery(t *testing.T) {
+	cases := []struct {
+		desc   string
+		status int
+		body   string
+		err    bool
+	}{
+		{desc: "ok", status: http.StatusOK, body: renderResponse},
+		{desc: "bad request", status: http.StatusBadRequest, body: "Bad Request", err: true},
+		{desc: "not json", status: http.StatusOK, body: "<html>", err: true},
+	}
+	for _, c := range cases {
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.RequestURI()
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+		create := NewQueryProcessorCreate(&QueryProcessorOptions{URLs: []string{"http://localhost:1", server.URL}})
+		p := create()
+		p.Init(1)
+
+		q := query.NewHTTP()
+		q.HumanLabel = []byte("Graphite query")
+		q.Method = []byte(http.MethodGet)
+		q.Path = []byte("/render?target=cpu.%2A.usage_user&from=0&until=60&format=json")
+		stats, err := p.ProcessQuery(q, false)
+		server.Close()
+		if !strings.HasPrefix(path, "/render?") {
+			t.Errorf("%s: incorrect request path: got %s", c.desc, path)
+		}
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if len(stats) != 1 || string(stats[0].Label()) != "Graphite query" {
+			t.Errorf("%s: incorrect stats: got %v", c.desc, stats)
+		}
+	}
+}
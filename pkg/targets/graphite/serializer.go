@@ -0,0 +1,144 @@
+package graphite
+
+import (
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+// NameTag is the tag Graphite keeps the name of tagged series in, so tags of
+// the points named like it are renamed to ReservedNameTag.
+const (
+	NameTag         = "name"
+	ReservedNameTag = "name_"
+)
+
+// Characters that cannot be part of the nodes of a metric name, tag keys or
+// tag values, which are replaced by an underscore. Spaces and newlines
+// delimit the lines of the plaintext protocol, semicolons the tags.
+const (
+	invalidNodeChars     = " \t\r\n.;=~"
+	invalidTagKeyChars   = " \t\r\n;!^=~"
+	invalidTagValueChars = " \t\r\n;~"
+)
+
+// Serializer writes a Point in the Graphite plaintext protocol, as tagged
+// series of Graphite 1.1.
+type Serializer struct {
+	tags  []byte
+	buf   []byte
+	value []byte
+}
+
+// Serialize writes Point data to the given writer, one line per field. The
+// series of each line is named after the measurement and the field, and is
+// tagged with the tags of the point. Fields that are not numbers or
+// booleans are left out, booleans are written as 1 or 0. Tags without value
+// are left out, since Graphite tags cannot be empty. Graphite timestamps are
+// whole seconds.
+//
+// This function writes output that looks like:
+// <measurement>.<field name>;<tag key>=<tag value> <field value> <epoch seconds>\n
+//
+// For example:
+// cpu.usage_user;hostname=host_0;region=eu-west-1 58.13 1451606400\n
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	s.tags = s.tags[:0]
+	tagValues := p.TagValues()
+	for i, key := range p.TagKeys() {
+		if tagValues[i] == nil {
+			continue
+		}
+		s.value = serialize.FastFormatAppend(tagValues[i], s.value[:0])
+		if len(s.value) == 0 {
+			continue
+		}
+		s.tags = append(s.tags, ';')
+		if string(key) == NameTag {
+			s.tags = append(s.tags, ReservedNameTag...)
+		} else {
+			s.tags = appendSanitized(s.tags, key, invalidTagKeyChars)
+		}
+		s.tags = append(s.tags, '=')
+		s.tags = appendSanitized(s.tags, s.value, invalidTagValueChars)
+	}
+
+	s.value = strconv.AppendInt(s.value[:0], p.Timestamp().Unix(), 10)
+	s.buf = s.buf[:0]
+	fieldValues := p.FieldValues()
+	for i, key := range p.FieldKeys() {
+		start := len(s.buf)
+		s.buf = appendSanitized(s.buf, p.MeasurementName(), invalidNodeChars)
+		s.buf = append(s.buf, '.')
+		s.buf = appendSanitized(s.buf, key, invalidNodeChars)
+		s.buf = append(s.buf, s.tags...)
+		s.buf = append(s.buf, ' ')
+		var ok bool
+		if s.buf, ok = appendValue(s.buf, fieldValues[i]); !ok {
+			s.buf = s.buf[:start]
+			continue
+		}
+		s.buf = append(s.buf, ' ')
+		s.buf = append(s.buf, s.value...)
+		s.buf = append(s.buf, '\n')
+	}
+	if len(s.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.Write(s.buf)
+	return err
+}
+
+// appendValue appends v to buf as a number. It returns false if v is not a
+// finite number or a boolean.
+func appendValue(buf []byte, v interface{}) ([]byte, bool) {
+	switch v := v.(type) {
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10), true
+	case int64:
+		return strconv.AppendInt(buf, v, 10), true
+	case uint64:
+		return strconv.AppendUint(buf, v, 10), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return buf, false
+		}
+		return strconv.AppendFloat(buf, v, 'f', -1, 64), true
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return buf, false
+		}
+		return strconv.AppendFloat(buf, float64(v), 'f', -1, 32), true
+	case bool:
+		if v {
+			return append(buf, '1'), true
+		}
+		return append(buf, '0'), true
+	}
+	return buf, false
+}
+
+// appendSanitized appends s to buf, replacing the characters in invalid by
+// underscores.
+func appendSanitized(buf, s []byte, invalid string) []byte {
+	for _, c := range s {
+		if isInvalid(c, invalid) {
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+func isInvalid(c byte, invalid string) bool {
+	for i := 0; i < len(invalid); i++ {
+		if invalid[i] == c {
+			return true
+		}
+	}
+	return false
+}
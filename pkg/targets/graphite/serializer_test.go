@@ -0,0 +1,103 @@
+package graphite
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestGraphiteSerializerSerialize(t *testing.T) {
+	const tags = ";hostname=host_0;region=eu-west-1;datacenter=eu-west-1b"
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu.usage_guest_nice" + tags + " 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a regular Point using int as value",
+			InputPoint: serialize.TestPointInt(),
+			Output:     "cpu.usage_guest" + tags + " 38 1451606400\n",
+		},
+		{
+			Desc:       "a regular Point with multiple fields",
+			InputPoint: serialize.TestPointMultiField(),
+			Output: "cpu.big_usage_guest" + tags + " 5000000000 1451606400\n" +
+				"cpu.usage_guest" + tags + " 38 1451606400\n" +
+				"cpu.usage_guest_nice" + tags + " 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with no tags",
+			InputPoint: serialize.TestPointNoTags(),
+			Output:     "cpu.usage_guest_nice 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "cpu.usage_guest_nice 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     "cpu.usage_guest_nice 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with string, boolean and unsigned fields",
+			InputPoint: serialize.TestPointTypedFields(),
+			Output: "cpu.reachable" + tags + " 1 1451606400\n" +
+				"cpu.requests_total" + tags + " 18446744073709551615 1451606400\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
+
+func TestSerializeSanitized(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("disk io"))
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendTag([]byte("name"), "truck_0")
+	p.AppendTag([]byte("os"), "Ubuntu 15.10")
+	p.AppendTag([]byte("path"), "/dev;sda~1")
+	p.AppendTag([]byte("empty"), "")
+	p.AppendTag([]byte("load_capacity"), 1500.0)
+	p.AppendField([]byte("reads.total"), int64(3))
+	p.AppendField([]byte("nan"), math.NaN())
+	p.AppendField([]byte("inf"), float32(math.Inf(1)))
+
+	b := new(bytes.Buffer)
+	if err := (&Serializer{}).Serialize(p, b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "disk_io.reads_total;name_=truck_0;os=Ubuntu_15.10;path=/dev_sda_1;load_capacity=1500 3 1451606400\n"
+	if got := b.String(); got != want {
+		t.Errorf("incorrect output:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSerializeNoNumbers(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("status"))
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendTag([]byte("hostname"), "host_0")
+	p.AppendField([]byte("state"), "ok")
+	p.AppendField([]byte("missing"), nil)
+
+	b := new(bytes.Buffer)
+	if err := (&Serializer{}).Serialize(p, b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("unexpected output for point without numbers: %s", b.String())
+	}
+}
+
+func TestSerializeError(t *testing.T) {
+	err := (&Serializer{}).Serialize(serialize.TestPointDefault(), &serialize.ErrWriter{})
+	if err == nil || err.Error() != serialize.ErrWriterAlwaysErr {
+		t.Errorf("unexpected error: got %v want %s", err, serialize.ErrWriterAlwaysErr)
+	}
+}
@@ -0,0 +1,108 @@
+package influx
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+var consistencyChoices = map[string]struct{}{
+	"any":    {},
+	"one":    {},
+	"quorum": {},
+	"all":    {},
+}
+
+// SpecificConfig holds the InfluxDB specific loading options.
+type SpecificConfig struct {
+	URLs              []string      `yaml:"urls" mapstructure:"urls"`
+	ReplicationFactor int           `yaml:"replication-factor" mapstructure:"replication-factor"`
+	Consistency       string        `yaml:"consistency" mapstructure:"consistency"`
+	AuthToken         string        `yaml:"auth-token" mapstructure:"auth-token"`
+	Organization      string        `yaml:"organization" mapstructure:"organization"`
+	Backoff           time.Duration `yaml:"backoff" mapstructure:"backoff"`
+	UseGzip           bool          `yaml:"gzip" mapstructure:"gzip"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	opts       *SpecificConfig
+	dbName     string
+	dataSource targets.DataSource
+	bufPool    *sync.Pool
+}
+
+func NewBenchmark(dbName string, opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if len(opts.URLs) == 0 {
+		return nil, fmt.Errorf("missing 'urls' flag")
+	}
+	if _, ok := consistencyChoices[opts.Consistency]; !ok {
+		return nil, fmt.Errorf("invalid consistency settings: %s", opts.Consistency)
+	}
+
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		br := load.GetBufferedReader(dataSourceConfig.File.Location)
+		ds = &fileDataSource{scanner: bufio.NewScanner(br)}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &benchmark{
+		opts:       opts,
+		dbName:     dbName,
+		dataSource: ds,
+		bufPool:    &bufPool,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{bufPool: b.bufPool}
+}
+
+func (b *benchmark) GetPointIndexer(_ uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{opts: b.opts, dbName: b.dbName, bufPool: b.bufPool}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{
+		// pick first one since it always exists
+		daemonURL:         b.opts.URLs[0],
+		authToken:         b.opts.AuthToken,
+		replicationFactor: b.opts.ReplicationFactor,
+	}
+}
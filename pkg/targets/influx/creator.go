@@ -1,4 +1,4 @@
-package main
+package influx
 
 import (
 	"encoding/json"
@@ -11,12 +11,12 @@ import (
 )
 
 type dbCreator struct {
-	daemonURL string
+	daemonURL         string
+	authToken         string
+	replicationFactor int
 }
 
-func (d *dbCreator) Init() {
-	d.daemonURL = daemonURLs[0] // pick first one since it always exists
-}
+func (d *dbCreator) Init() {}
 
 func (d *dbCreator) DBExists(dbName string) bool {
 	dbs, err := d.listDatabases()
@@ -25,7 +25,7 @@ func (d *dbCreator) DBExists(dbName string) bool {
 	}
 
 	for _, db := range dbs {
-		if db == loader.DatabaseName() {
+		if db == dbName {
 			return true
 		}
 	}
@@ -36,9 +36,9 @@ func (d *dbCreator) listDatabases() ([]string, error) {
 	client := http.Client{}
 	u := fmt.Sprintf("%s/query?q=show%%20databases", d.daemonURL)
 	req, err := http.NewRequest("GET", u, nil)
-	if authToken != "" {
+	if d.authToken != "" {
 		req.Header = http.Header{
-			headerAuthorization: []string{fmt.Sprintf("Token %s", authToken)},
+			headerAuthorization: []string{fmt.Sprintf("Token %s", d.authToken)},
 		}
 	}
 	resp, err := client.Do(req)
@@ -86,10 +86,10 @@ func (d *dbCreator) RemoveOldDB(dbName string) error {
 	u := fmt.Sprintf("%s/query?q=drop+database+%s", d.daemonURL, dbName)
 	client := http.Client{}
 	req, err := http.NewRequest("POST", u, nil)
-	if authToken != "" {
+	if d.authToken != "" {
 		req.Header = http.Header{
 			"Content-Type":      []string{"text/plain"},
-			headerAuthorization: []string{fmt.Sprintf("Token %s", authToken)},
+			headerAuthorization: []string{fmt.Sprintf("Token %s", d.authToken)},
 		}
 	}
 	resp, err := client.Do(req)
@@ -113,13 +113,13 @@ func (d *dbCreator) CreateDB(dbName string) error {
 	u.Path = "query"
 	v := u.Query()
 	v.Set("consistency", "all")
-	v.Set("q", fmt.Sprintf("CREATE DATABASE %s WITH REPLICATION %d", dbName, replicationFactor))
+	v.Set("q", fmt.Sprintf("CREATE DATABASE %s WITH REPLICATION %d", dbName, d.replicationFactor))
 	u.RawQuery = v.Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
-	if authToken != "" {
+	if d.authToken != "" {
 		req.Header = http.Header{
-			headerAuthorization: []string{fmt.Sprintf("Token %s", authToken)},
+			headerAuthorization: []string{fmt.Sprintf("Token %s", d.authToken)},
 		}
 	}
 	if err != nil {
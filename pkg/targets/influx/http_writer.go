@@ -1,4 +1,4 @@
-package main
+package influx
 
 // This file lifted wholesale from mountainflux by Mark Rushakoff.
 
@@ -39,6 +39,10 @@ type HTTPWriterConfig struct {
 
 	// Debug label for more informative errors.
 	DebugInfo string
+
+	// AuthToken is sent in the Authorization header with the Token scheme
+	// when non-empty (InfluxDB v2).
+	AuthToken string
 }
 
 // HTTPWriter is a Writer that writes to an InfluxDB HTTP server.
@@ -100,7 +104,7 @@ func (w *HTTPWriter) executeReq(req *fasthttp.Request, resp *fasthttp.Response)
 func (w *HTTPWriter) WriteLineProtocol(body []byte, isGzip bool) (int64, error) {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
-	w.initializeReq(req, body, isGzip, authToken)
+	w.initializeReq(req, body, isGzip, w.c.AuthToken)
 
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(resp)
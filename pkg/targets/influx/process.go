@@ -1,11 +1,12 @@
-package main
+package influx
 
 import (
 	"bytes"
 	"fmt"
-	"github.com/timescale/tsbs/pkg/targets"
+	"sync"
 	"time"
 
+	"github.com/timescale/tsbs/pkg/targets"
 	"github.com/valyala/fasthttp"
 )
 
@@ -18,16 +19,20 @@ type processor struct {
 	backingOffChan chan bool
 	backingOffDone chan struct{}
 	httpWriter     *HTTPWriter
+	opts           *SpecificConfig
+	dbName         string
+	bufPool        *sync.Pool
 }
 
 func (p *processor) Init(numWorker int, _, _ bool) {
-	daemonURL := daemonURLs[numWorker%len(daemonURLs)]
+	daemonURL := p.opts.URLs[numWorker%len(p.opts.URLs)]
 	cfg := HTTPWriterConfig{
 		DebugInfo: fmt.Sprintf("worker #%d, dest url: %s", numWorker, daemonURL),
 		Host:      daemonURL,
-		Database:  loader.DatabaseName(),
+		Database:  p.dbName,
+		AuthToken: p.opts.AuthToken,
 	}
-	w := NewHTTPWriter(cfg, consistency)
+	w := NewHTTPWriter(cfg, p.opts.Consistency)
 	p.initWithHTTPWriter(numWorker, w)
 }
 
@@ -50,20 +55,20 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 	if doLoad {
 		var err error
 		for {
-			if useGzip {
-				compressedBatch := bufPool.Get().(*bytes.Buffer)
+			if p.opts.UseGzip {
+				compressedBatch := p.bufPool.Get().(*bytes.Buffer)
 				fasthttp.WriteGzip(compressedBatch, batch.buf.Bytes())
 				_, err = p.httpWriter.WriteLineProtocol(compressedBatch.Bytes(), true)
 				// Return the compressed batch buffer to the pool.
 				compressedBatch.Reset()
-				bufPool.Put(compressedBatch)
+				p.bufPool.Put(compressedBatch)
 			} else {
 				_, err = p.httpWriter.WriteLineProtocol(batch.buf.Bytes(), false)
 			}
 
 			if err == errBackoff {
 				p.backingOffChan <- true
-				time.Sleep(backoff)
+				time.Sleep(p.opts.Backoff)
 			} else {
 				p.backingOffChan <- false
 				break
@@ -78,7 +83,7 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 
 	// Return the batch buffer to the pool.
 	batch.buf.Reset()
-	bufPool.Put(batch.buf)
+	p.bufPool.Put(batch.buf)
 	return metricCnt, uint64(rowCnt)
 }
 
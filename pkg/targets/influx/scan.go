@@ -1,9 +1,11 @@
-package main
+package influx
 
 import (
 	"bufio"
 	"bytes"
+	"log"
 	"strings"
+	"sync"
 
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
@@ -14,6 +16,9 @@ const errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
 
 var newLine = []byte("\n")
 
+// allows for testing
+var fatal = log.Fatalf
+
 type fileDataSource struct {
 	scanner *bufio.Scanner
 }
@@ -58,8 +63,10 @@ func (b *batch) Append(item data.LoadedPoint) {
 	b.buf.Write(newLine)
 }
 
-type factory struct{}
+type factory struct {
+	bufPool *sync.Pool
+}
 
 func (f *factory) New() targets.Batch {
-	return &batch{buf: bufPool.Get().(*bytes.Buffer)}
+	return &batch{buf: f.bufPool.Get().(*bytes.Buffer)}
 }
@@ -1,22 +1,16 @@
-package main
+package influx
 
 import (
 	"bufio"
 	"bytes"
 	"fmt"
-	"sync"
 	"testing"
 
 	"github.com/timescale/tsbs/pkg/data"
 )
 
 func TestBatch(t *testing.T) {
-	bufPool = sync.Pool{
-		New: func() interface{} {
-			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
-		},
-	}
-	f := &factory{}
+	f := &factory{bufPool: newTestBufPool()}
 	b := f.New().(*batch)
 	if b.Len() != 0 {
 		t.Errorf("batch not initialized with count 0")
@@ -83,13 +83,35 @@ func appendField(buf, key []byte, v interface{}) []byte {
 	buf = append(buf, key...)
 	buf = append(buf, '=')
 
-	buf = serialize.FastFormatAppend(v, buf)
+	switch v := v.(type) {
+	case string:
+		buf = appendQuoted(buf, []byte(v))
+	case []byte:
+		buf = appendQuoted(buf, v)
+	default:
+		buf = serialize.FastFormatAppend(v, buf)
+	}
 
-	// Influx uses 'i' to indicate integers:
+	// Influx uses 'i' to indicate integers and 'u' for unsigned ones:
 	switch v.(type) {
 	case int, int64:
 		buf = append(buf, 'i')
+	case uint64:
+		buf = append(buf, 'u')
 	}
 
 	return buf
 }
+
+// appendQuoted appends a string field value in double quotes, escaping the
+// quotes and backslashes in it.
+func appendQuoted(buf, v []byte) []byte {
+	buf = append(buf, '"')
+	for _, c := range v {
+		if c == '"' || c == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '"')
+}
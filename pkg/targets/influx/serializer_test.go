@@ -34,8 +34,19 @@ func TestInfluxSerializerSerialize(t *testing.T) {
 			Desc:       "a Point with a nil field",
 			InputPoint: serialize.TestPointWithNilField(),
 			Output:     "cpu usage_guest_nice=38.24311829 1451606400000000000\n",
+		}, {
+			Desc:       "a Point with string, boolean and unsigned fields",
+			InputPoint: serialize.TestPointTypedFields(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b status=\"ok\",reachable=true,requests_total=18446744073709551615u 1451606400000000000\n",
 		},
 	}
 
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
+
+func TestAppendQuoted(t *testing.T) {
+	got := string(appendQuoted(nil, []byte(`say "hi" \ bye`)))
+	if want := `"say \"hi\" \\ bye"`; got != want {
+		t.Errorf("incorrect quoting: got %s want %s", got, want)
+	}
+}
@@ -46,8 +46,28 @@ func (rcv *MongoReading) MutateValue(n float64) bool {
 	return rcv._tab.MutateFloat64Slot(6, n)
 }
 
+func (rcv *MongoReading) Kind() ReadingKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetInt8(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *MongoReading) MutateKind(n ReadingKind) bool {
+	return rcv._tab.MutateInt8Slot(8, n)
+}
+
+func (rcv *MongoReading) Text() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
 func MongoReadingStart(builder *flatbuffers.Builder) {
-	builder.StartObject(2)
+	builder.StartObject(4)
 }
 func MongoReadingAddKey(builder *flatbuffers.Builder, key flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(key), 0)
@@ -55,6 +75,12 @@ func MongoReadingAddKey(builder *flatbuffers.Builder, key flatbuffers.UOffsetT)
 func MongoReadingAddValue(builder *flatbuffers.Builder, value float64) {
 	builder.PrependFloat64Slot(1, value, 0.0)
 }
+func MongoReadingAddKind(builder *flatbuffers.Builder, kind ReadingKind) {
+	builder.PrependInt8Slot(2, kind, 0)
+}
+func MongoReadingAddText(builder *flatbuffers.Builder, text flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(text), 0)
+}
 func MongoReadingEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -1,4 +1,4 @@
-package main
+package mongo
 
 import (
 	"fmt"
@@ -9,10 +9,8 @@ import (
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
-	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/targets"
-	"github.com/timescale/tsbs/pkg/targets/mongo"
 )
 
 type hostnameIndexer struct {
@@ -20,8 +18,8 @@ type hostnameIndexer struct {
 }
 
 func (i *hostnameIndexer) GetIndex(item data.LoadedPoint) uint {
-	p := item.Data.(*mongo.MongoPoint)
-	t := &mongo.MongoTag{}
+	p := item.Data.(*MongoPoint)
+	t := &MongoTag{}
 	for j := 0; j < p.TagsLength(); j++ {
 		p.Tags(t, j)
 		key := string(t.Key())
@@ -43,15 +41,15 @@ type aggBenchmark struct {
 	mongoBenchmark
 }
 
-func newAggBenchmark(l load.BenchmarkRunner, conf *load.BenchmarkRunnerConfig) *aggBenchmark {
+func newAggBenchmark(dbName string, opts *SpecificConfig, ds targets.DataSource) *aggBenchmark {
 	// Pre-create the needed empty subdoc for new aggregate docs
 	generateEmptyHourDoc()
 
-	return &aggBenchmark{mongoBenchmark{conf.FileName, l, &dbCreator{}}}
+	return &aggBenchmark{newMongoBenchmark(dbName, opts, ds)}
 }
 
 func (b *aggBenchmark) GetProcessor() targets.Processor {
-	return &aggProcessor{dbc: b.dbc}
+	return &aggProcessor{dbc: b.dbc, dbName: b.dbName}
 }
 
 func (b *aggBenchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
@@ -78,6 +76,7 @@ var pPool = &sync.Pool{New: func() interface{} { return &point{} }}
 
 type aggProcessor struct {
 	dbc        *dbCreator
+	dbName     string
 	collection *mgo.Collection
 
 	createdDocs map[string]bool
@@ -87,7 +86,7 @@ type aggProcessor struct {
 func (p *aggProcessor) Init(_ int, doLoad, _ bool) {
 	if doLoad {
 		sess := p.dbc.session.Copy()
-		db := sess.DB(loader.DatabaseName())
+		db := sess.DB(p.dbName)
 		p.collection = db.C(collectionName)
 	}
 	p.createdDocs = make(map[string]bool)
@@ -127,7 +126,7 @@ func (p *aggProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint6
 	eventCnt := uint64(0)
 	for _, event := range batch.arr {
 		tagsMap := map[string]string{}
-		t := &mongo.MongoTag{}
+		t := &MongoTag{}
 		for j := 0; j < event.TagsLength(); j++ {
 			event.Tags(t, j)
 			tagsMap[string(t.Key())] = string(t.Value())
@@ -161,10 +160,10 @@ func (p *aggProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint6
 		}
 		x := pPool.Get().(*point)
 		x.Fields = map[string]interface{}{}
-		f := &mongo.MongoReading{}
+		f := &MongoReading{}
 		for j := 0; j < event.FieldsLength(); j++ {
 			event.Fields(f, j)
-			x.Fields[string(f.Key())] = f.Value()
+			x.Fields[string(f.Key())] = readingValue(f)
 		}
 		x.Timestamp = ts
 		eventCnt += uint64(len(x.Fields))
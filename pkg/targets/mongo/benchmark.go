@@ -0,0 +1,56 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	collectionName     = "point_data"
+	aggDocID           = "doc_id"
+	aggDateFmt         = "20060102_15" // see Go docs for how we arrive at this time format
+	aggKeyID           = "key_id"
+	aggInsertBatchSize = 500 // found via trial-and-error
+	timestampField     = "timestamp_ns"
+)
+
+// SpecificConfig holds the MongoDB specific loading options.
+type SpecificConfig struct {
+	URL          string        `yaml:"url" mapstructure:"url"`
+	WriteTimeout time.Duration `yaml:"write-timeout" mapstructure:"write-timeout"`
+	DocumentPer  bool          `yaml:"document-per-event" mapstructure:"document-per-event"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// NewBenchmark returns a Benchmark that stores one document per event when
+// opts.DocumentPer is set, and aggregates events by host and hour otherwise.
+func NewBenchmark(dbName string, opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		ds = &fileDataSource{lenBuf: make([]byte, 8), r: load.GetBufferedReader(dataSourceConfig.File.Location)}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	if opts.DocumentPer {
+		return newNaiveBenchmark(dbName, opts, ds), nil
+	}
+	return newAggBenchmark(dbName, opts, ds), nil
+}
@@ -1,4 +1,4 @@
-package main
+package mongo
 
 import (
 	"bufio"
@@ -8,11 +8,9 @@ import (
 	"log"
 
 	flatbuffers "github.com/google/flatbuffers/go"
-	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
-	"github.com/timescale/tsbs/pkg/targets/mongo"
 )
 
 type fileDataSource struct {
@@ -21,7 +19,7 @@ type fileDataSource struct {
 }
 
 func (d *fileDataSource) NextItem() data.LoadedPoint {
-	item := &mongo.MongoPoint{}
+	item := &MongoPoint{}
 
 	_, err := d.r.Read(d.lenBuf)
 	if err == io.EOF {
@@ -59,7 +57,7 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 }
 
 type batch struct {
-	arr []*mongo.MongoPoint
+	arr []*MongoPoint
 }
 
 func (b *batch) Len() uint {
@@ -67,24 +65,36 @@ func (b *batch) Len() uint {
 }
 
 func (b *batch) Append(item data.LoadedPoint) {
-	that := item.Data.(*mongo.MongoPoint)
+	that := item.Data.(*MongoPoint)
 	b.arr = append(b.arr, that)
 }
 
 type factory struct{}
 
 func (f *factory) New() targets.Batch {
-	return &batch{arr: []*mongo.MongoPoint{}}
+	return &batch{arr: []*MongoPoint{}}
 }
 
 type mongoBenchmark struct {
-	loaderFileName string
-	l              load.BenchmarkRunner
-	dbc            *dbCreator
+	dbName     string
+	dataSource targets.DataSource
+	dbc        *dbCreator
+}
+
+func newMongoBenchmark(dbName string, opts *SpecificConfig, ds targets.DataSource) mongoBenchmark {
+	return mongoBenchmark{
+		dbName:     dbName,
+		dataSource: ds,
+		dbc: &dbCreator{
+			daemonURL:    opts.URL,
+			writeTimeout: opts.WriteTimeout,
+			documentPer:  opts.DocumentPer,
+		},
+	}
 }
 
 func (b *mongoBenchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{lenBuf: make([]byte, 8), r: load.GetBufferedReader(b.loaderFileName)}
+	return b.dataSource
 }
 
 func (b *mongoBenchmark) GetBatchFactory() targets.BatchFactory {
@@ -1,21 +1,25 @@
-package main
+package mongo
 
 import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 )
 
 type dbCreator struct {
-	session *mgo.Session
+	daemonURL    string
+	writeTimeout time.Duration
+	documentPer  bool
+	session      *mgo.Session
 }
 
 func (d *dbCreator) Init() {
 	var err error
-	d.session, err = mgo.DialWithTimeout(daemonURL, writeTimeout)
+	d.session, err = mgo.DialWithTimeout(d.daemonURL, d.writeTimeout)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -70,7 +74,7 @@ func (d *dbCreator) CreateDB(dbName string) error {
 
 	collection := d.session.DB(dbName).C(collectionName)
 	var key []string
-	if documentPer {
+	if d.documentPer {
 		key = []string{"measurement", "tags.hostname", timestampField}
 	} else {
 		key = []string{aggKeyID, "measurement", "tags.hostname"}
@@ -89,7 +93,7 @@ func (d *dbCreator) CreateDB(dbName string) error {
 
 	// To make updates for new records more efficient, we need a efficient doc
 	// lookup index
-	if !documentPer {
+	if !d.documentPer {
 		err = collection.EnsureIndex(mgo.Index{
 			Key:        []string{aggDocID},
 			Unique:     false,
@@ -1,13 +1,11 @@
-package main
+package mongo
 
 import (
 	"log"
 	"sync"
 
 	"github.com/globalsign/mgo"
-	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/targets"
-	"github.com/timescale/tsbs/pkg/targets/mongo"
 )
 
 // naiveBenchmark allows you to run a benchmark using the naive, one document per
@@ -16,12 +14,12 @@ type naiveBenchmark struct {
 	mongoBenchmark
 }
 
-func newNaiveBenchmark(l load.BenchmarkRunner, loaderConf *load.BenchmarkRunnerConfig) *naiveBenchmark {
-	return &naiveBenchmark{mongoBenchmark{loaderConf.FileName, l, &dbCreator{}}}
+func newNaiveBenchmark(dbName string, opts *SpecificConfig, ds targets.DataSource) *naiveBenchmark {
+	return &naiveBenchmark{newMongoBenchmark(dbName, opts, ds)}
 }
 
 func (b *naiveBenchmark) GetProcessor() targets.Processor {
-	return &naiveProcessor{dbc: b.dbc}
+	return &naiveProcessor{dbc: b.dbc, dbName: b.dbName}
 }
 
 func (b *naiveBenchmark) GetPointIndexer(_ uint) targets.PointIndexer {
@@ -39,6 +37,7 @@ var spPool = &sync.Pool{New: func() interface{} { return &singlePoint{} }}
 
 type naiveProcessor struct {
 	dbc        *dbCreator
+	dbName     string
 	collection *mgo.Collection
 
 	pvs []interface{}
@@ -47,7 +46,7 @@ type naiveProcessor struct {
 func (p *naiveProcessor) Init(_ int, doLoad, _ bool) {
 	if doLoad {
 		sess := p.dbc.session.Copy()
-		db := sess.DB(loader.DatabaseName())
+		db := sess.DB(p.dbName)
 		p.collection = db.C(collectionName)
 	}
 	p.pvs = []interface{}{}
@@ -70,12 +69,12 @@ func (p *naiveProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uin
 		x.Timestamp = event.Timestamp()
 		x.Fields = map[string]interface{}{}
 		x.Tags = map[string]string{}
-		f := &mongo.MongoReading{}
+		f := &MongoReading{}
 		for j := 0; j < event.FieldsLength(); j++ {
 			event.Fields(f, j)
-			x.Fields[string(f.Key())] = f.Value()
+			x.Fields[string(f.Key())] = readingValue(f)
 		}
-		t := &mongo.MongoTag{}
+		t := &MongoTag{}
 		for j := 0; j < event.TagsLength(); j++ {
 			event.Tags(t, j)
 			x.Tags[string(t.Key())] = string(t.Value())
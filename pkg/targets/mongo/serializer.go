@@ -22,7 +22,32 @@ type Serializer struct{}
 // Serialize writes Point data to the given Writer, using basic gob encoding
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	b := fbBuilderPool.Get().(*flatbuffers.Builder)
+	buf := buildMongoPoint(b, p)
 
+	// Write the metadata for the flatbuffer object:
+	lenBuf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lenBuf, uint64(len(buf)))
+	_, err = w.Write(lenBuf)
+	if err != nil {
+		return err
+	}
+
+	// Write the flatbuffer object:
+	_, err = w.Write(buf)
+	if err != nil {
+		return err
+	}
+
+	// Give the flatbuffers builder back to a pool:
+	b.Reset()
+	fbBuilderPool.Put(b)
+
+	return nil
+}
+
+// buildMongoPoint encodes the Point as a MongoPoint flatbuffer using the given
+// builder. The returned bytes are only valid until the builder is reset.
+func buildMongoPoint(b *flatbuffers.Builder, p *data.Point) []byte {
 	timestampNanos := p.Timestamp().UTC().UnixNano()
 	tags := []flatbuffers.UOffsetT{}
 	// In order to keep the ordering the same on deserialization, we need
@@ -78,34 +103,27 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	MongoPointAddFields(b, fieldsArr)
 	point := MongoPointEnd(b)
 	b.Finish(point)
-	buf := b.FinishedBytes()
-
-	// Write the metadata for the flatbuffer object:
-	lenBuf := make([]byte, 8)
-	binary.LittleEndian.PutUint64(lenBuf, uint64(len(buf)))
-	_, err = w.Write(lenBuf)
-	if err != nil {
-		return err
-	}
-
-	// Write the flatbuffer object:
-	_, err = w.Write(buf)
-	if err != nil {
-		return err
-	}
-
-	// Give the flatbuffers builder back to a pool:
-	b.Reset()
-	fbBuilderPool.Put(b)
-
-	return nil
+	return b.FinishedBytes()
 }
 
 func createField(b *flatbuffers.Builder, key []byte, val interface{}) flatbuffers.UOffsetT {
 	keyStr := b.CreateString(string(key))
+	// strings have to be created before the reading is started
+	var text flatbuffers.UOffsetT
+	switch v := val.(type) {
+	case string:
+		text = b.CreateString(v)
+	case []byte:
+		text = b.CreateByteString(v)
+	}
 	MongoReadingStart(b)
 	MongoReadingAddKey(b, keyStr)
-	prependValue(b, val)
+	if text != 0 {
+		MongoReadingAddKind(b, ReadingKindString)
+		MongoReadingAddText(b, text)
+	} else {
+		prependValue(b, val)
+	}
 	return MongoReadingEnd(b)
 }
 func prependValue(b *flatbuffers.Builder, value interface{}) {
@@ -118,7 +136,26 @@ func prependValue(b *flatbuffers.Builder, value interface{}) {
 		MongoReadingAddValue(b, float64(val))
 	case int64:
 		MongoReadingAddValue(b, float64(val))
+	case uint64:
+		MongoReadingAddValue(b, float64(val))
+	case bool:
+		MongoReadingAddKind(b, ReadingKindBool)
+		if val {
+			MongoReadingAddValue(b, 1)
+		}
 	default:
 		panic(fmt.Sprintf("cannot covert %T to float64", val))
 	}
 }
+
+// readingValue returns the value of the reading as the type it is stored as
+// in MongoDB.
+func readingValue(r *MongoReading) interface{} {
+	switch r.Kind() {
+	case ReadingKindString:
+		return string(r.Text())
+	case ReadingKindBool:
+		return r.Value() != 0
+	}
+	return r.Value()
+}
@@ -76,6 +76,18 @@ func TestMongoSerializerSerialize(t *testing.T) {
 				readingVals: serialize.TestPointNoTags().FieldValues(),
 			},
 		},
+		{
+			desc:       "a Point with string, boolean and unsigned fields",
+			inputPoint: serialize.TestPointTypedFields(),
+			want: output{
+				name:        string(serialize.TestMeasurement),
+				ts:          serialize.TestNow.UnixNano(),
+				tagKeys:     serialize.TestTagKeys,
+				tagVals:     serialize.TestTagVals,
+				readingKeys: serialize.TestPointTypedFields().FieldKeys(),
+				readingVals: serialize.TestPointTypedFields().FieldValues(),
+			},
+		},
 	}
 
 	ps := &Serializer{}
@@ -128,16 +140,18 @@ func TestMongoSerializerSerialize(t *testing.T) {
 				t.Errorf("%s: incorrect reading key %d: got %s want %s", c.desc, i, got, want)
 			}
 
-			var wantVal float64
+			var wantVal interface{}
 			switch x := c.want.readingVals[i].(type) {
 			case int:
 				wantVal = float64(x)
 			case int64:
 				wantVal = float64(x)
-			case float64:
+			case uint64:
+				wantVal = float64(x)
+			case float64, string, bool:
 				wantVal = x
 			}
-			if got := reading.Value(); got != wantVal {
+			if got := readingValue(reading); got != wantVal {
 				t.Errorf("%s: incorrect reading val %d: got %v want %v", c.desc, i, got, wantVal)
 			}
 		}
@@ -189,7 +203,7 @@ func TestMongoSerializerTypePanic(t *testing.T) {
 		p := &data.Point{}
 		p.SetMeasurementName(serialize.TestMeasurement)
 		p.SetTimestamp(&serialize.TestNow)
-		p.AppendField([]byte("broken"), "a string?")
+		p.AppendField([]byte("broken"), []float64{1})
 		ps := &Serializer{}
 		b := new(bytes.Buffer)
 
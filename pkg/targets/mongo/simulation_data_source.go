@@ -0,0 +1,61 @@
+package mongo
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+		builder:   flatbuffers.NewBuilder(0),
+	}
+}
+
+// simulationDataSource converts simulated points directly into MongoPoint
+// flatbuffers, skipping the length-prefixed encoding used for files.
+type simulationDataSource struct {
+	simulator common.Simulator
+	headers   *common.GeneratedDataHeaders
+	builder   *flatbuffers.Builder
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return data.LoadedPoint{}
+	}
+
+	return data.NewLoadedPoint(d.toMongoPoint(newSimulatorPoint))
+}
+
+func (d *simulationDataSource) toMongoPoint(p *data.Point) *MongoPoint {
+	d.builder.Reset()
+	// the builder's buffer is reused, so the point needs its own copy
+	itemBuf := append([]byte(nil), buildMongoPoint(d.builder, p)...)
+	item := &MongoPoint{}
+	n := flatbuffers.GetUOffsetT(itemBuf)
+	item.Init(itemBuf, n)
+	return item
+}
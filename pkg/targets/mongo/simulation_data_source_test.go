@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func TestSimulationDataSourceToMongoPoint(t *testing.T) {
+	d := &simulationDataSource{builder: flatbuffers.NewBuilder(0)}
+	p := serialize.TestPointMultiField()
+	got := d.toMongoPoint(p)
+	// building a second point must not clobber the first one
+	_ = d.toMongoPoint(serialize.TestPointDefault())
+
+	if name := string(got.MeasurementName()); name != string(p.MeasurementName()) {
+		t.Errorf("incorrect measurement: got %s want %s", name, p.MeasurementName())
+	}
+	if ts := got.Timestamp(); ts != p.Timestamp().UnixNano() {
+		t.Errorf("incorrect timestamp: got %d want %d", ts, p.Timestamp().UnixNano())
+	}
+	if got.TagsLength() != len(p.TagKeys()) {
+		t.Fatalf("incorrect number of tags: got %d want %d", got.TagsLength(), len(p.TagKeys()))
+	}
+	tag := &MongoTag{}
+	for i, k := range p.TagKeys() {
+		got.Tags(tag, i)
+		if string(tag.Key()) != string(k) {
+			t.Errorf("incorrect tag key %d: got %s want %s", i, tag.Key(), k)
+		}
+		if string(tag.Value()) != p.TagValues()[i].(string) {
+			t.Errorf("incorrect tag value %d: got %s want %v", i, tag.Value(), p.TagValues()[i])
+		}
+	}
+	if got.FieldsLength() != len(p.FieldKeys()) {
+		t.Fatalf("incorrect number of fields: got %d want %d", got.FieldsLength(), len(p.FieldKeys()))
+	}
+	reading := &MongoReading{}
+	for i, k := range p.FieldKeys() {
+		got.Fields(reading, i)
+		if string(reading.Key()) != string(k) {
+			t.Errorf("incorrect field key %d: got %s want %s", i, reading.Key(), k)
+		}
+	}
+}
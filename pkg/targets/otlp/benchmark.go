@@ -0,0 +1,133 @@
+package otlp
+
+import (
+	"log"
+
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/common"
+)
+
+// allows for testing
+var fatal = log.Fatalf
+
+// NewBenchmark creates the benchmark that exports the data of the given
+// source to an OTLP receiver, over OTLP/HTTP or OTLP/gRPC as opts say. Data
+// is either read from a file of length-prefixed ExportMetricsServiceRequests
+// written by the Serializer, or generated and encoded on the fly.
+func NewBenchmark(opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		r, err := newReader(load.GetBufferedReader(dataSourceConfig.File.Location))
+		if err != nil {
+			return nil, err
+		}
+		ds = &fileDataSource{reader: r}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	return &benchmark{
+		opts:       opts,
+		dataSource: ds,
+		batches:    common.NewByteBatchFactory(),
+	}, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	opts       *SpecificConfig
+	dataSource targets.DataSource
+	batches    *common.ByteBatchFactory
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+// GetBatchFactory returns batches that merge the requests of their points
+// into one request: a request only has repeated ResourceMetrics, so the
+// concatenation of encoded requests is the encoded request with the
+// ResourceMetrics of all of them.
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return b.batches
+}
+
+// GetPointIndexer spreads the points over the workers by their Resource,
+// which holds the tags identifying the entity, e.g. the host in devops.
+// Backends behind an OTLP receiver, like Prometheus, reject data points older
+// than the latest of their series, so each resource is exported by one
+// worker, in timestamp order.
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return common.NewGenericPointIndexer(maxPartitions, resourceOfPoint)
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{opts: b.opts, batches: b.batches}
+}
+
+// GetDBCreator returns nil, as OTLP receivers have no databases to create.
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return nil
+}
+
+// resourceOfPoint returns the encoded Resource of the request of a point.
+func resourceOfPoint(item *data.LoadedPoint) []byte {
+	resource, err := resourceOf(item.Data.(*exportRequest).msg)
+	if err != nil {
+		fatal("cannot decode resource of OTLP request: %v", err)
+		return nil
+	}
+	return resource
+}
+
+// processor exports batches over an exporter of its own, i.e. its own
+// HTTP client or gRPC connection.
+type processor struct {
+	opts     *SpecificConfig
+	batches  *common.ByteBatchFactory
+	exporter exporter
+}
+
+func (p *processor) Init(_ int, doLoad, _ bool) {
+	if !doLoad {
+		return
+	}
+	var err error
+	p.exporter, err = newExporter(p.opts)
+	if err != nil {
+		fatal("cannot connect to OTLP receiver %s: %v", p.opts.Endpoint, err)
+	}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	batch := b.(*common.ByteBatch)
+	if doLoad {
+		if err := p.exporter.export(batch.Bytes()); err != nil {
+			fatal("cannot export to OTLP receiver %s: %v", p.opts.Endpoint, err)
+		}
+	}
+	metricCount, rowCount := batch.Counts()
+	p.batches.Put(batch)
+	return metricCount, rowCount
+}
+
+func (p *processor) Close(_ bool) {
+	if p.exporter != nil {
+		p.exporter.close()
+	}
+}
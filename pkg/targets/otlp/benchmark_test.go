@@ -0,0 +1,324 @@
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/common"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func newTestRequest(t *testing.T, host string) *exportRequest {
+	e := &encoder{}
+	e.setResourceTags([]string{"hostname"})
+	tags := map[string]interface{}{"hostname": host, "path": "/"}
+	msg, count := e.encode(newTestPoint(tags, []string{"hostname", "path"}, []string{"free", "used"}, int64(1), 2.0))
+	if count == 0 {
+		t.Fatalf("no data points encoded")
+	}
+	return &exportRequest{msg: append([]byte(nil), msg...), dataPoints: uint64(count)}
+}
+
+// rejectingResponse encodes an ExportMetricsServiceResponse that reports
+// rejected data points.
+func rejectingResponse(rejected uint64) []byte {
+	var partialSuccess []byte
+	partialSuccess = protowire.AppendTag(partialSuccess, partialSuccessRejectedDataPoints, protowire.VarintType)
+	partialSuccess = protowire.AppendVarint(partialSuccess, rejected)
+	partialSuccess = appendString(partialSuccess, partialSuccessErrorMessage, "out of order")
+	return appendMessage(nil, responsePartialSuccess, partialSuccess)
+}
+
+func TestBatch(t *testing.T) {
+	b := (&benchmark{batches: common.NewByteBatchFactory()}).GetBatchFactory().New()
+	b.Append(data.NewLoadedPoint(newTestRequest(t, "host_0")))
+	b.Append(data.NewLoadedPoint(newTestRequest(t, "host_1")))
+	if b.Len() != 2 {
+		t.Errorf("incorrect batch length: got %d want 2", b.Len())
+	}
+	if metrics, _ := b.(*common.ByteBatch).Counts(); metrics != 4 {
+		t.Errorf("incorrect number of metrics: got %d want 4", metrics)
+	}
+
+	// the batch is one request with the resource metrics of each point
+	rms := decode(t, b.(*common.ByteBatch).Bytes()).messages(t, requestResourceMetrics)
+	if len(rms) != 2 {
+		t.Fatalf("incorrect number of resource metrics: got %d want 2", len(rms))
+	}
+	for i, want := range []string{"host_0", "host_1"} {
+		attrs := rms[i].message(t, resourceMetricsResource).attributes(t, resourceAttributes)
+		if got := attrs["hostname"].str(anyValueString); got != want {
+			t.Errorf("resource metrics %d: incorrect host: got %s want %s", i, got, want)
+		}
+	}
+}
+
+func TestPointIndexer(t *testing.T) {
+	const partitions = 4
+	i := (&benchmark{}).GetPointIndexer(partitions)
+	indexes := make(map[string]uint)
+	for j := 0; j < 3; j++ {
+		for _, host := range []string{"host_0", "host_1", "host_2", "host_3", "host_4"} {
+			idx := i.GetIndex(data.NewLoadedPoint(newTestRequest(t, host)))
+			if idx >= partitions {
+				t.Fatalf("index out of range: got %d", idx)
+			}
+			if want, ok := indexes[host]; ok && idx != want {
+				t.Errorf("%s: index changed: got %d want %d", host, idx, want)
+			}
+			indexes[host] = idx
+		}
+	}
+	if _, ok := (&benchmark{}).GetPointIndexer(1).(*targets.ConstantIndexer); !ok {
+		t.Errorf("a single partition should not need hashing")
+	}
+}
+
+func TestSpecificConfigValidate(t *testing.T) {
+	cases := []struct {
+		config SpecificConfig
+		want   string
+		err    bool
+	}{
+		{config: SpecificConfig{Protocol: ProtocolHTTP}, want: DefaultHTTPEndpoint},
+		{config: SpecificConfig{Protocol: ProtocolGRPC}, want: DefaultGRPCEndpoint},
+		{config: SpecificConfig{Protocol: ProtocolGRPC, Endpoint: "collector:4317"}, want: "collector:4317"},
+		{config: SpecificConfig{Protocol: "thrift"}, err: true},
+	}
+	for _, c := range cases {
+		err := c.config.validate()
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.config.Protocol)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.config.Protocol, err)
+		}
+		if c.config.Endpoint != c.want {
+			t.Errorf("%s: incorrect endpoint: got %s want %s", c.config.Protocol, c.config.Endpoint, c.want)
+		}
+	}
+}
+
+// httpReceiver is a stub OTLP/HTTP receiver that keeps the requests it gets
+// and answers with the given status and response.
+type httpReceiver struct {
+	status   int
+	response []byte
+
+	mu       sync.Mutex
+	requests [][]byte
+}
+
+func (r *httpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if got := req.Header.Get("Content-Type"); got != "application/x-protobuf" {
+		http.Error(w, "bad content type "+got, http.StatusUnsupportedMediaType)
+		return
+	}
+	body := req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, b)
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(r.status)
+	w.Write(r.response)
+}
+
+func TestHTTPExporter(t *testing.T) {
+	req := newTestRequest(t, "host_0")
+	cases := []struct {
+		desc     string
+		gzip     bool
+		status   int
+		response []byte
+		err      bool
+	}{
+		{desc: "plain", status: http.StatusOK},
+		{desc: "gzip", gzip: true, status: http.StatusOK},
+		{desc: "rejected", status: http.StatusOK, response: rejectingResponse(2), err: true},
+		{desc: "failed", status: http.StatusServiceUnavailable, err: true},
+	}
+	for _, c := range cases {
+		receiver := &httpReceiver{status: c.status, response: c.response}
+		server := httptest.NewServer(receiver)
+		e, err := newExporter(&SpecificConfig{Protocol: ProtocolHTTP, Endpoint: server.URL, Gzip: c.gzip, Timeout: time.Second})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		err = e.export(req.msg)
+		if c.err && err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		} else if !c.err && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+		if len(receiver.requests) != 1 || string(receiver.requests[0]) != string(req.msg) {
+			t.Errorf("%s: request not received as sent", c.desc)
+		}
+		e.close()
+		server.Close()
+	}
+}
+
+// grpcReceiver is a stub OTLP/gRPC receiver that keeps the requests it gets
+// and answers with the given response.
+type grpcReceiver struct {
+	response []byte
+
+	mu       sync.Mutex
+	requests [][]byte
+}
+
+// serverCodec is the codec of the grpcReceiver, which also needs the name of
+// the codec as String.
+type serverCodec struct {
+	rawCodec
+}
+
+func (serverCodec) String() string {
+	return "proto"
+}
+
+func (r *grpcReceiver) start(t *testing.T) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	server := grpc.NewServer(grpc.CustomCodec(serverCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.collector.metrics.v1.MetricsService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Export",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req []byte
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				r.mu.Lock()
+				r.requests = append(r.requests, req)
+				r.mu.Unlock()
+				return r.response, nil
+			},
+		}},
+	}, r)
+	go server.Serve(lis)
+	return lis.Addr().String(), server.Stop
+}
+
+func TestGRPCExporter(t *testing.T) {
+	req := newTestRequest(t, "host_0")
+	cases := []struct {
+		desc     string
+		gzip     bool
+		response []byte
+		err      bool
+	}{
+		{desc: "plain"},
+		{desc: "gzip", gzip: true},
+		{desc: "rejected", response: rejectingResponse(1), err: true},
+	}
+	for _, c := range cases {
+		receiver := &grpcReceiver{response: c.response}
+		addr, stop := receiver.start(t)
+		e, err := newExporter(&SpecificConfig{Protocol: ProtocolGRPC, Endpoint: addr, Gzip: c.gzip, Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		err = e.export(req.msg)
+		if c.err && err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		} else if !c.err && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+		if len(receiver.requests) != 1 || string(receiver.requests[0]) != string(req.msg) {
+			t.Errorf("%s: request not received as sent", c.desc)
+		}
+		e.close()
+		stop()
+	}
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	receiver := &httpReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	opts := &SpecificConfig{Protocol: ProtocolHTTP, Endpoint: server.URL}
+	batches := common.NewByteBatchFactory()
+	for _, doLoad := range []bool{false, true} {
+		p := &processor{opts: opts, batches: batches}
+		p.Init(0, doLoad, false)
+		b := batches.New()
+		b.Append(data.NewLoadedPoint(newTestRequest(t, "host_0")))
+		b.Append(data.NewLoadedPoint(newTestRequest(t, "host_1")))
+		metrics, rows := p.ProcessBatch(b, doLoad)
+		if metrics != 4 || rows != 2 {
+			t.Errorf("doLoad %v: incorrect counts: got %d metrics and %d rows, want 4 and 2", doLoad, metrics, rows)
+		}
+		if b.Len() != 0 {
+			t.Errorf("doLoad %v: batch not reset", doLoad)
+		}
+		p.Close(doLoad)
+	}
+	if len(receiver.requests) != 1 {
+		t.Fatalf("incorrect number of requests: got %d want 1", len(receiver.requests))
+	}
+	if rms := decode(t, receiver.requests[0]).messages(t, requestResourceMetrics); len(rms) != 2 {
+		t.Errorf("incorrect number of resource metrics: got %d want 2", len(rms))
+	}
+}
+
+func TestSimulationDataSource(t *testing.T) {
+	const hosts, epochs = 2, 3
+	conf := &devops.CPUOnlySimulatorConfig{
+		Start:           testTime,
+		End:             testTime.Add(epochs * time.Second),
+		InitHostCount:   hosts,
+		HostCount:       hosts,
+		HostConstructor: devops.NewHostCPUOnly,
+	}
+	ds := newSimulationDataSource(conf.NewSimulator(time.Second, 0))
+	count := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		req := item.Data.(*exportRequest)
+		if req.dataPoints != 10 {
+			t.Errorf("incorrect number of data points: got %d want 10", req.dataPoints)
+		}
+		rm := decode(t, req.msg).message(t, requestResourceMetrics)
+		attrs := rm.message(t, resourceMetricsResource).attributes(t, resourceAttributes)
+		if _, ok := attrs["hostname"]; !ok {
+			t.Errorf("hostname missing from resource attributes")
+		}
+		count++
+	}
+	if count != hosts*epochs {
+		t.Errorf("incorrect number of requests: got %d want %d", count, hosts*epochs)
+	}
+}
@@ -0,0 +1,72 @@
+package otlp
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// fileDataSource reads the requests written by a Serializer.
+type fileDataSource struct {
+	reader *reader
+}
+
+func (d *fileDataSource) NextItem() data.LoadedPoint {
+	req, err := d.reader.next()
+	if err != nil {
+		fatal("cannot read OTLP data: %v", err)
+		return data.LoadedPoint{}
+	}
+	if req == nil {
+		return data.LoadedPoint{}
+	}
+	return data.NewLoadedPoint(req)
+}
+
+func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	d := &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+	d.encoder.setResourceTags(sim.TagKeys())
+	return d
+}
+
+// simulationDataSource generates points with a simulator and encodes each one
+// as a request, the same representation the file data source reads.
+type simulationDataSource struct {
+	simulator common.Simulator
+	headers   *common.GeneratedDataHeaders
+	encoder   encoder
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	for !d.simulator.Finished() {
+		if d.simulator.Next(newSimulatorPoint) {
+			msg, count := d.encoder.encode(newSimulatorPoint)
+			// points without number fields make no request
+			if count > 0 {
+				return data.NewLoadedPoint(&exportRequest{
+					msg:        append([]byte(nil), msg...),
+					dataPoints: uint64(count),
+				})
+			}
+		}
+		newSimulatorPoint.Reset()
+	}
+	return data.LoadedPoint{}
+}
@@ -0,0 +1,59 @@
+package otlp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blagojts/viper"
+)
+
+// Protocols OTLP is loaded over
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Default endpoints of the protocols
+const (
+	DefaultHTTPEndpoint = "http://localhost:4318/v1/metrics"
+	DefaultGRPCEndpoint = "localhost:4317"
+)
+
+const errUnknownProtocolFmt = "unknown OTLP protocol '%s'; expected %s or %s"
+
+// SpecificConfig holds the OTLP specific loading options.
+type SpecificConfig struct {
+	Protocol string        `yaml:"protocol" mapstructure:"protocol"`
+	Endpoint string        `yaml:"endpoint" mapstructure:"endpoint"`
+	Timeout  time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	Gzip     bool          `yaml:"gzip" mapstructure:"gzip"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// validate checks the protocol and sets the default endpoint of the protocol
+// if none is given.
+func (c *SpecificConfig) validate() error {
+	switch c.Protocol {
+	case ProtocolHTTP:
+		if c.Endpoint == "" {
+			c.Endpoint = DefaultHTTPEndpoint
+		}
+	case ProtocolGRPC:
+		if c.Endpoint == "" {
+			c.Endpoint = DefaultGRPCEndpoint
+		}
+	default:
+		return fmt.Errorf(errUnknownProtocolFmt, c.Protocol, ProtocolHTTP, ProtocolGRPC)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package otlp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the OTLP messages, as defined by opentelemetry-proto v1.
+const (
+	requestResourceMetrics = 1 // ExportMetricsServiceRequest.resource_metrics
+
+	resourceMetricsResource     = 1 // ResourceMetrics.resource
+	resourceMetricsScopeMetrics = 2 // ResourceMetrics.scope_metrics
+	resourceAttributes          = 1 // Resource.attributes
+
+	scopeMetricsScope   = 1 // ScopeMetrics.scope
+	scopeMetricsMetrics = 2 // ScopeMetrics.metrics
+	scopeName           = 1 // InstrumentationScope.name
+
+	metricName      = 1 // Metric.name
+	metricGauge     = 5 // Metric.gauge
+	gaugeDataPoints = 1 // Gauge.data_points
+
+	dataPointTimeUnixNano = 3 // NumberDataPoint.time_unix_nano
+	dataPointAsDouble     = 4 // NumberDataPoint.as_double
+	dataPointAsInt        = 6 // NumberDataPoint.as_int
+	dataPointAttributes   = 7 // NumberDataPoint.attributes
+
+	keyValueKey   = 1 // KeyValue.key
+	keyValueValue = 2 // KeyValue.value
+
+	anyValueString = 1 // AnyValue.string_value
+	anyValueBool   = 2 // AnyValue.bool_value
+	anyValueInt    = 3 // AnyValue.int_value
+	anyValueDouble = 4 // AnyValue.double_value
+
+	responsePartialSuccess           = 1 // ExportMetricsServiceResponse.partial_success
+	partialSuccessRejectedDataPoints = 1 // ExportMetricsPartialSuccess.rejected_data_points
+	partialSuccessErrorMessage       = 2 // ExportMetricsPartialSuccess.error_message
+)
+
+// ScopeName is the name of the instrumentation scope of all metrics.
+const ScopeName = "tsbs"
+
+// MetricNameSeparator joins the measurement and field of a metric name, e.g.
+// cpu.usage_user.
+const MetricNameSeparator = "."
+
+// encodedScope is the InstrumentationScope of all metrics.
+var encodedScope = appendString(nil, scopeName, ScopeName)
+
+// encoder encodes points as OTLP ExportMetricsServiceRequest messages. Each
+// field of a point becomes a gauge metric named after the measurement and the
+// field, with a single data point. Tags become attributes of the resource, or
+// of the data points if they are not resource tags. Fields that are missing or
+// not a number are left out, booleans become 1 or 0.
+type encoder struct {
+	// resourceTags are the keys of the tags that become resource attributes;
+	// every tag does when it is nil
+	resourceTags map[string]bool
+
+	// buffers of the nested messages, reused between points
+	resource        []byte
+	pointAttributes []byte
+	keyValue        []byte
+	anyValue        []byte
+	name            []byte
+	dataPoint       []byte
+	gauge           []byte
+	metric          []byte
+	scopeMetrics    []byte
+	resourceMetrics []byte
+	request         []byte
+}
+
+// setResourceTags makes the tags with the given keys resource attributes, and
+// the other tags data point attributes.
+func (e *encoder) setResourceTags(keys []string) {
+	e.resourceTags = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		e.resourceTags[key] = true
+	}
+}
+
+// encode returns p as an ExportMetricsServiceRequest along with the number of
+// data points in it, which is 0 if p has no number fields. The returned bytes
+// are only valid until the next call.
+func (e *encoder) encode(p *data.Point) ([]byte, int) {
+	e.resource = e.resource[:0]
+	e.pointAttributes = e.pointAttributes[:0]
+	tagValues := p.TagValues()
+	for i, key := range p.TagKeys() {
+		if tagValues[i] == nil {
+			continue
+		}
+		if e.resourceTags == nil || e.resourceTags[string(key)] {
+			e.resource = e.appendKeyValue(e.resource, resourceAttributes, key, tagValues[i])
+		} else {
+			e.pointAttributes = e.appendKeyValue(e.pointAttributes, dataPointAttributes, key, tagValues[i])
+		}
+	}
+
+	e.scopeMetrics = appendMessage(e.scopeMetrics[:0], scopeMetricsScope, encodedScope)
+	ts := uint64(p.Timestamp().UnixNano())
+	fieldValues := p.FieldValues()
+	count := 0
+	for i, key := range p.FieldKeys() {
+		e.dataPoint = append(e.dataPoint[:0], e.pointAttributes...)
+		e.dataPoint = protowire.AppendTag(e.dataPoint, dataPointTimeUnixNano, protowire.Fixed64Type)
+		e.dataPoint = protowire.AppendFixed64(e.dataPoint, ts)
+		var ok bool
+		e.dataPoint, ok = appendNumber(e.dataPoint, fieldValues[i])
+		if !ok {
+			continue
+		}
+		e.gauge = appendMessage(e.gauge[:0], gaugeDataPoints, e.dataPoint)
+
+		e.name = append(e.name[:0], p.MeasurementName()...)
+		e.name = append(e.name, MetricNameSeparator...)
+		e.name = append(e.name, key...)
+		e.metric = appendMessage(e.metric[:0], metricName, e.name)
+		e.metric = appendMessage(e.metric, metricGauge, e.gauge)
+
+		e.scopeMetrics = appendMessage(e.scopeMetrics, scopeMetricsMetrics, e.metric)
+		count++
+	}
+	if count == 0 {
+		return nil, 0
+	}
+
+	e.resourceMetrics = appendMessage(e.resourceMetrics[:0], resourceMetricsResource, e.resource)
+	e.resourceMetrics = appendMessage(e.resourceMetrics, resourceMetricsScopeMetrics, e.scopeMetrics)
+	e.request = appendMessage(e.request[:0], requestResourceMetrics, e.resourceMetrics)
+	return e.request, count
+}
+
+// appendKeyValue appends a KeyValue with the given key and value as field num
+// of b.
+func (e *encoder) appendKeyValue(b []byte, num protowire.Number, key []byte, value interface{}) []byte {
+	e.anyValue = appendAnyValue(e.anyValue[:0], value)
+	e.keyValue = appendMessage(e.keyValue[:0], keyValueKey, key)
+	e.keyValue = appendMessage(e.keyValue, keyValueValue, e.anyValue)
+	return appendMessage(b, num, e.keyValue)
+}
+
+// appendAnyValue appends the fields of an AnyValue holding v to b.
+func appendAnyValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendString(b, anyValueString, v)
+	case []byte:
+		return appendMessage(b, anyValueString, v)
+	case bool:
+		b = protowire.AppendTag(b, anyValueBool, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(v))
+	case int:
+		return appendInt(b, int64(v))
+	case int64:
+		return appendInt(b, v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return appendInt(b, int64(v))
+		}
+		return appendDouble(b, float64(v))
+	case float32:
+		return appendDouble(b, float64(v))
+	case float64:
+		return appendDouble(b, v)
+	default:
+		return appendString(b, anyValueString, fmt.Sprintf("%v", v))
+	}
+}
+
+func appendInt(b []byte, v int64) []byte {
+	b = protowire.AppendTag(b, anyValueInt, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendDouble(b []byte, v float64) []byte {
+	b = protowire.AppendTag(b, anyValueDouble, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+// appendNumber appends the value of a NumberDataPoint holding v to b. It
+// returns false, and b as it is, if v is not a number or a boolean.
+func appendNumber(b []byte, v interface{}) ([]byte, bool) {
+	var asInt int64
+	switch v := v.(type) {
+	case int:
+		asInt = int64(v)
+	case int64:
+		asInt = v
+	case uint64:
+		if v > math.MaxInt64 {
+			return appendAsDouble(b, float64(v)), true
+		}
+		asInt = int64(v)
+	case bool:
+		if v {
+			asInt = 1
+		}
+	case float32:
+		return appendAsDouble(b, float64(v)), true
+	case float64:
+		return appendAsDouble(b, v), true
+	default:
+		return b, false
+	}
+	b = protowire.AppendTag(b, dataPointAsInt, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, uint64(asInt)), true
+}
+
+func appendAsDouble(b []byte, v float64) []byte {
+	b = protowire.AppendTag(b, dataPointAsDouble, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendMessage appends the encoded message, or bytes, m as field num of b.
+func appendMessage(b []byte, num protowire.Number, m []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m)
+}
+
+// resourceOf returns the encoded Resource of the first ResourceMetrics of the
+// given ExportMetricsServiceRequest, which is the only one of a request made
+// by an encoder.
+func resourceOf(request []byte) ([]byte, error) {
+	resourceMetrics, err := consumeMessage(request, requestResourceMetrics)
+	if err != nil {
+		return nil, err
+	}
+	return consumeMessage(resourceMetrics, resourceMetricsResource)
+}
+
+// consumeMessage returns the first field num of the encoded message b, which
+// must be a message or bytes.
+func consumeMessage(b []byte, num protowire.Number) ([]byte, error) {
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return nil, protowire.ParseError(tagLen)
+		}
+		b = b[tagLen:]
+		if n == num && typ == protowire.BytesType {
+			m, mLen := protowire.ConsumeBytes(b)
+			if mLen < 0 {
+				return nil, protowire.ParseError(mLen)
+			}
+			return m, nil
+		}
+		valueLen := protowire.ConsumeFieldValue(n, typ, b)
+		if valueLen < 0 {
+			return nil, protowire.ParseError(valueLen)
+		}
+		b = b[valueLen:]
+	}
+	return nil, nil
+}
@@ -0,0 +1,216 @@
+package otlp
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message is a decoded protobuf message: the values of each of its fields,
+// which are []byte for messages and strings, and uint64 for numbers.
+type message map[protowire.Number][]interface{}
+
+func decode(t *testing.T, b []byte) message {
+	t.Helper()
+	m := make(message)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("cannot decode tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v interface{}
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %d of field %d", typ, num)
+		}
+		if n < 0 {
+			t.Fatalf("cannot decode field %d: %v", num, protowire.ParseError(n))
+		}
+		m[num] = append(m[num], v)
+		b = b[n:]
+	}
+	return m
+}
+
+func (m message) messages(t *testing.T, num protowire.Number) []message {
+	t.Helper()
+	var ret []message
+	for _, v := range m[num] {
+		ret = append(ret, decode(t, v.([]byte)))
+	}
+	return ret
+}
+
+func (m message) message(t *testing.T, num protowire.Number) message {
+	t.Helper()
+	ms := m.messages(t, num)
+	if len(ms) != 1 {
+		t.Fatalf("incorrect number of messages in field %d: got %d want 1", num, len(ms))
+	}
+	return ms[0]
+}
+
+func (m message) str(num protowire.Number) string {
+	if len(m[num]) != 1 {
+		return ""
+	}
+	return string(m[num][0].([]byte))
+}
+
+// attributes returns the values of the KeyValues in field num of m by key.
+func (m message) attributes(t *testing.T, num protowire.Number) map[string]message {
+	t.Helper()
+	ret := make(map[string]message)
+	for _, kv := range m.messages(t, num) {
+		ret[kv.str(keyValueKey)] = kv.message(t, keyValueValue)
+	}
+	return ret
+}
+
+var testTime = time.Unix(1451606400, 0)
+
+func newTestPoint(tags map[string]interface{}, tagOrder []string, fields []string, values ...interface{}) *data.Point {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("disk"))
+	p.SetTimestamp(&testTime)
+	for _, key := range tagOrder {
+		p.AppendTag([]byte(key), tags[key])
+	}
+	for i, key := range fields {
+		p.AppendField([]byte(key), values[i])
+	}
+	return p
+}
+
+func TestEncoderEncode(t *testing.T) {
+	tags := map[string]interface{}{"hostname": "host_0", "region": nil, "path": "/dev/sda"}
+	p := newTestPoint(tags, []string{"hostname", "region", "path"},
+		[]string{"free", "used_percent", "writable", "status", "missing", "total"},
+		int64(5), 12.5, true, "ok", nil, uint64(math.MaxUint64))
+	e := &encoder{}
+	e.setResourceTags([]string{"hostname", "region"})
+	msg, count := e.encode(p)
+	if count != 4 {
+		t.Errorf("incorrect number of data points: got %d want 4", count)
+	}
+
+	rms := decode(t, msg).messages(t, requestResourceMetrics)
+	if len(rms) != 1 {
+		t.Fatalf("incorrect number of resource metrics: got %d want 1", len(rms))
+	}
+	resource := rms[0].message(t, resourceMetricsResource).attributes(t, resourceAttributes)
+	if len(resource) != 1 || resource["hostname"].str(anyValueString) != "host_0" {
+		t.Errorf("incorrect resource attributes: got %v", resource)
+	}
+
+	sm := rms[0].message(t, resourceMetricsScopeMetrics)
+	if got := sm.message(t, scopeMetricsScope).str(scopeName); got != ScopeName {
+		t.Errorf("incorrect scope: got %s want %s", got, ScopeName)
+	}
+	wantValues := []struct {
+		name  string
+		num   protowire.Number
+		value uint64
+	}{
+		{"disk.free", dataPointAsInt, 5},
+		{"disk.used_percent", dataPointAsDouble, math.Float64bits(12.5)},
+		{"disk.writable", dataPointAsInt, 1},
+		{"disk.total", dataPointAsDouble, math.Float64bits(float64(uint64(math.MaxUint64)))},
+	}
+	metrics := sm.messages(t, scopeMetricsMetrics)
+	if len(metrics) != len(wantValues) {
+		t.Fatalf("incorrect number of metrics: got %d want %d", len(metrics), len(wantValues))
+	}
+	for i, want := range wantValues {
+		if got := metrics[i].str(metricName); got != want.name {
+			t.Errorf("metric %d: incorrect name: got %s want %s", i, got, want.name)
+		}
+		dp := metrics[i].message(t, metricGauge).message(t, gaugeDataPoints)
+		if got := dp[want.num]; len(got) != 1 || got[0].(uint64) != want.value {
+			t.Errorf("%s: incorrect value: got %v want %d in field %d", want.name, dp, want.value, want.num)
+		}
+		if got := dp[dataPointTimeUnixNano][0].(uint64); got != uint64(testTime.UnixNano()) {
+			t.Errorf("%s: incorrect time: got %d", want.name, got)
+		}
+		attrs := dp.attributes(t, dataPointAttributes)
+		if len(attrs) != 1 || attrs["path"].str(anyValueString) != "/dev/sda" {
+			t.Errorf("%s: incorrect data point attributes: got %v", want.name, attrs)
+		}
+	}
+}
+
+func TestEncoderAllResourceTags(t *testing.T) {
+	tags := map[string]interface{}{"name": "truck_0", "load_capacity": 1500.0, "fleet": []byte("East"), "model": int64(3)}
+	p := newTestPoint(tags, []string{"name", "load_capacity", "fleet", "model"}, []string{"fuel"}, 2.5)
+	msg, count := (&encoder{}).encode(p)
+	if count != 1 {
+		t.Fatalf("incorrect number of data points: got %d want 1", count)
+	}
+	rm := decode(t, msg).message(t, requestResourceMetrics)
+	attrs := rm.message(t, resourceMetricsResource).attributes(t, resourceAttributes)
+	if got := attrs["name"].str(anyValueString); got != "truck_0" {
+		t.Errorf("incorrect string attribute: got %s", got)
+	}
+	if got := attrs["fleet"].str(anyValueString); got != "East" {
+		t.Errorf("incorrect bytes attribute: got %s", got)
+	}
+	if got := attrs["load_capacity"][anyValueDouble]; len(got) != 1 || got[0].(uint64) != math.Float64bits(1500) {
+		t.Errorf("incorrect double attribute: got %v", got)
+	}
+	if got := attrs["model"][anyValueInt]; len(got) != 1 || got[0].(uint64) != 3 {
+		t.Errorf("incorrect int attribute: got %v", got)
+	}
+
+	dp := rm.message(t, resourceMetricsScopeMetrics).message(t, scopeMetricsMetrics).
+		message(t, metricGauge).message(t, gaugeDataPoints)
+	if got := dp[dataPointAttributes]; len(got) != 0 {
+		t.Errorf("unexpected data point attributes: got %v", got)
+	}
+}
+
+func TestEncoderNoNumbers(t *testing.T) {
+	p := newTestPoint(nil, nil, []string{"status", "missing"}, "ok", nil)
+	msg, count := (&encoder{}).encode(p)
+	if count != 0 || msg != nil {
+		t.Errorf("unexpected request for point without numbers: got %d data points", count)
+	}
+}
+
+func TestResourceOf(t *testing.T) {
+	e := &encoder{}
+	e.setResourceTags([]string{"hostname"})
+	resources := make([][]byte, 0, 3)
+	for _, tags := range []map[string]interface{}{
+		{"hostname": "host_0", "path": "/dev/sda"},
+		{"hostname": "host_0", "path": "/dev/sdb"},
+		{"hostname": "host_1", "path": "/dev/sda"},
+	} {
+		msg, _ := e.encode(newTestPoint(tags, []string{"hostname", "path"}, []string{"free"}, int64(1)))
+		resource, err := resourceOf(msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resources = append(resources, append([]byte(nil), resource...))
+	}
+	if !bytes.Equal(resources[0], resources[1]) {
+		t.Errorf("same host has different resources")
+	}
+	if bytes.Equal(resources[0], resources[2]) {
+		t.Errorf("different hosts have the same resource")
+	}
+
+	if _, err := resourceOf([]byte{0x0a, 0x10}); err == nil {
+		t.Errorf("unexpected lack of error for truncated request")
+	}
+}
@@ -0,0 +1,194 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/grpc"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// exportMethod is the gRPC method of the OTLP metrics service.
+const exportMethod = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+
+// exporter sends ExportMetricsServiceRequest messages to an OTLP receiver.
+type exporter interface {
+	export(request []byte) error
+	close() error
+}
+
+func newExporter(c *SpecificConfig) (exporter, error) {
+	if c.Protocol == ProtocolGRPC {
+		return newGRPCExporter(c)
+	}
+	return &httpExporter{
+		url:    c.Endpoint,
+		gzip:   c.Gzip,
+		client: &http.Client{Timeout: c.Timeout},
+	}, nil
+}
+
+// httpExporter sends requests over OTLP/HTTP in the binary protobuf encoding.
+type httpExporter struct {
+	url    string
+	gzip   bool
+	client *http.Client
+
+	body bytes.Buffer
+	gz   *gzip.Writer
+}
+
+func (e *httpExporter) export(request []byte) error {
+	body := request
+	if e.gzip {
+		e.body.Reset()
+		if e.gz == nil {
+			e.gz = gzip.NewWriter(&e.body)
+		} else {
+			e.gz.Reset(&e.body)
+		}
+		if _, err := e.gz.Write(request); err != nil {
+			return err
+		}
+		if err := e.gz.Close(); err != nil {
+			return err
+		}
+		body = e.body.Bytes()
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if e.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP receiver returned status: %s", resp.Status)
+	}
+	if resp.Header.Get("Content-Type") != "application/x-protobuf" {
+		return nil
+	}
+	return checkResponse(respBody)
+}
+
+func (e *httpExporter) close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// grpcExporter sends requests over OTLP/gRPC. It sends the encoded messages as
+// they are, so it needs no generated code.
+type grpcExporter struct {
+	conn    *grpc.ClientConn
+	options []grpc.CallOption
+	config  *SpecificConfig
+}
+
+func newGRPCExporter(c *SpecificConfig) (*grpcExporter, error) {
+	conn, err := grpc.Dial(c.Endpoint, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	options := []grpc.CallOption{grpc.ForceCodec(rawCodec{})}
+	if c.Gzip {
+		options = append(options, grpc.UseCompressor(grpcgzip.Name))
+	}
+	return &grpcExporter{conn: conn, options: options, config: c}, nil
+}
+
+func (e *grpcExporter) export(request []byte) error {
+	ctx := context.Background()
+	if e.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout)
+		defer cancel()
+	}
+	var resp []byte
+	if err := e.conn.Invoke(ctx, exportMethod, request, &resp, e.options...); err != nil {
+		return err
+	}
+	return checkResponse(resp)
+}
+
+func (e *grpcExporter) close() error {
+	return e.conn.Close()
+}
+
+// rawCodec is a gRPC codec for messages that are already encoded: it sends
+// []byte as it is and receives into *[]byte.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("cannot send %T as raw message", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("cannot receive raw message into %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Name is the name of the codec of the protobuf encoding, so the receiver
+// decodes the messages as usual.
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// checkResponse returns an error if the encoded ExportMetricsServiceResponse
+// reports rejected data points.
+func checkResponse(resp []byte) error {
+	partialSuccess, err := consumeMessage(resp, responsePartialSuccess)
+	if err != nil {
+		return fmt.Errorf("cannot decode OTLP response: %v", err)
+	}
+	var rejected int64
+	var message string
+	for b := partialSuccess; len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("cannot decode OTLP response: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch {
+		case num == partialSuccessRejectedDataPoints && typ == protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
+			rejected, n = int64(v), m
+		case num == partialSuccessErrorMessage && typ == protowire.BytesType:
+			v, m := protowire.ConsumeString(b)
+			message, n = v, m
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("cannot decode OTLP response: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	if rejected > 0 {
+		return fmt.Errorf("OTLP receiver rejected %d data points: %s", rejected, message)
+	}
+	return nil
+}
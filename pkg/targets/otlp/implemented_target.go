@@ -0,0 +1,43 @@
+package otlp
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &otlpTarget{}
+}
+
+type otlpTarget struct {
+}
+
+func (t *otlpTarget) TargetName() string {
+	return constants.FormatOTLP
+}
+
+func (t *otlpTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *otlpTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	otlpSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(otlpSpecificConfig, dataSourceConfig)
+}
+
+func (t *otlpTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"protocol", ProtocolHTTP, "Protocol to send data over: http (OTLP/HTTP) or grpc (OTLP/gRPC)")
+	flagSet.String(flagPrefix+"endpoint", "",
+		"OTLP receiver to send data to (default "+DefaultHTTPEndpoint+" for http, "+DefaultGRPCEndpoint+" for grpc)")
+	flagSet.Duration(flagPrefix+"timeout", 30*time.Second, "Timeout of each export request")
+	flagSet.Bool(flagPrefix+"gzip", false, "Whether to compress export requests with gzip")
+}
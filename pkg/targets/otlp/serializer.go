@@ -0,0 +1,112 @@
+package otlp
+
+// The OTLP serializer writes each point as an ExportMetricsServiceRequest
+// protobuf message in a binary format that looks like:
+// <version><<data point count><message size><message>><<data point count><message size><message>>...
+// where the version, counts and sizes are uvarints. Protobuf messages are not
+// self-delimiting, so they are prefixed by their size, see
+// https://developers.google.com/protocol-buffers/docs/techniques#streaming,
+// and by the number of data points so the loader does not have to decode them.
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const serializerVersion uint64 = 1
+
+// Serializer writes points as OTLP ExportMetricsServiceRequest messages. The
+// tags of the simulator become resource attributes, the tags of single
+// measurements data point attributes.
+type Serializer struct {
+	encoder       encoder
+	headerWritten bool
+	sizeBuf       [2 * binary.MaxVarintLen64]byte
+}
+
+// SetTagKeys makes the tags with the given keys resource attributes. Without
+// them every tag is.
+func (s *Serializer) SetTagKeys(keys []string) {
+	s.encoder.setResourceTags(keys)
+}
+
+// Serialize writes p as an ExportMetricsServiceRequest, unless it has no
+// number fields.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	if !s.headerWritten {
+		n := binary.PutUvarint(s.sizeBuf[:], serializerVersion)
+		if _, err := w.Write(s.sizeBuf[:n]); err != nil {
+			return fmt.Errorf("error writing file header: %v", err)
+		}
+		s.headerWritten = true
+	}
+
+	msg, count := s.encoder.encode(p)
+	if count == 0 {
+		return nil
+	}
+	n := binary.PutUvarint(s.sizeBuf[:], uint64(count))
+	n += binary.PutUvarint(s.sizeBuf[n:], uint64(len(msg)))
+	if _, err := w.Write(s.sizeBuf[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// exportRequest is an ExportMetricsServiceRequest along with the number of
+// data points in it.
+type exportRequest struct {
+	msg        []byte
+	dataPoints uint64
+}
+
+// Encoded returns the encoded request, for the batches of common.ByteBatch.
+func (r *exportRequest) Encoded() []byte {
+	return r.msg
+}
+
+// MetricCount returns the number of data points of the request.
+func (r *exportRequest) MetricCount() uint64 {
+	return r.dataPoints
+}
+
+// reader reads the requests written by a Serializer.
+type reader struct {
+	r *bufio.Reader
+}
+
+// newReader creates a reader and checks the version of the format.
+func newReader(r *bufio.Reader) (*reader, error) {
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file version: %v", err)
+	}
+	if version != serializerVersion {
+		return nil, fmt.Errorf("unsupported version number: %d", version)
+	}
+	return &reader{r: r}, nil
+}
+
+// next returns the next request, or nil at the end of the input.
+func (r *reader) next() (*exportRequest, error) {
+	count, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while reading data point count: %v", err)
+	}
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading message size: %v", err)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r.r, msg); err != nil {
+		return nil, fmt.Errorf("error while reading protobuf message: %v", err)
+	}
+	return &exportRequest{msg: msg, dataPoints: count}, nil
+}
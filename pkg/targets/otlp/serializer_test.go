@@ -0,0 +1,91 @@
+package otlp
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestSerializer(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Serializer{}
+	s.SetTagKeys([]string{"hostname"})
+	inputs := []struct {
+		desc       string
+		dataPoints uint64
+	}{
+		{"default", 1},
+		{"typed fields", 2},
+		{"multi field", 3},
+	}
+	if err := s.Serialize(serialize.TestPointDefault(), &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Serialize(serialize.TestPointTypedFields(), &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// points without number fields are left out
+	if err := s.Serialize(newTestPoint(nil, nil, []string{"status"}, "ok"), &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Serialize(serialize.TestPointMultiField(), &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r, err := newReader(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("unexpected error creating reader: %v", err)
+	}
+	for _, in := range inputs {
+		req, err := r.next()
+		if err != nil {
+			t.Fatalf("%s: unexpected error reading: %v", in.desc, err)
+		}
+		if req == nil {
+			t.Fatalf("%s: request missing", in.desc)
+		}
+		if req.dataPoints != in.dataPoints {
+			t.Errorf("%s: incorrect number of data points: got %d want %d", in.desc, req.dataPoints, in.dataPoints)
+		}
+		rm := decode(t, req.msg).message(t, requestResourceMetrics)
+		attrs := rm.message(t, resourceMetricsResource).attributes(t, resourceAttributes)
+		if got := attrs["hostname"].str(anyValueString); got != "host_0" {
+			t.Errorf("%s: incorrect hostname: got %s", in.desc, got)
+		}
+	}
+	if req, err := r.next(); req != nil || err != nil {
+		t.Errorf("unexpected request or error at the end: %v %v", req, err)
+	}
+}
+
+func TestSerializerErr(t *testing.T) {
+	s := &Serializer{}
+	err := s.Serialize(serialize.TestPointDefault(), &serialize.ErrWriter{})
+	if err == nil {
+		t.Errorf("unexpected lack of error writing header")
+	}
+	err = s.Serialize(serialize.TestPointDefault(), &serialize.ErrWriter{SkipOne: true})
+	if err == nil {
+		t.Errorf("unexpected lack of error writing point")
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	if _, err := newReader(bufio.NewReader(bytes.NewReader(nil))); err == nil {
+		t.Errorf("unexpected lack of error for empty input")
+	}
+	if _, err := newReader(bufio.NewReader(bytes.NewReader([]byte{2}))); err == nil {
+		t.Errorf("unexpected lack of error for unknown version")
+	}
+
+	// a message cut short
+	r, err := newReader(bufio.NewReader(bytes.NewReader([]byte{1, 1, 10, 0x0a})))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.next(); err == nil {
+		t.Errorf("unexpected lack of error for truncated message")
+	}
+}
@@ -0,0 +1,105 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Result types of the Prometheus query API whose series are counted.
+const (
+	resultTypeMatrix = "matrix"
+	resultTypeVector = "vector"
+)
+
+// QueryProcessorOptions are the options of the query processors.
+type QueryProcessorOptions struct {
+	// URLs of the Prometheus compatible query APIs, e.g. of Prometheus or
+	// VictoriaMetrics, used round-robin by worker
+	URLs []string
+	// Debug is the debug level: 1 prints the size of each result, 2 adds
+	// the query description, 3 the request and 4 the response
+	Debug int
+	// PrettyPrintResponses prints every response as indented JSON
+	PrettyPrintResponses bool
+}
+
+// NewQueryProcessorCreate returns a function creating query processors which
+// send the queries round-robin (by worker) to the given Prometheus query APIs.
+func NewQueryProcessorCreate(opts *QueryProcessorOptions) query.ProcessorCreate {
+	return query.NewHTTPProcessorCreate(&query.HTTPProcessorOptions{
+		URLs:                 opts.URLs,
+		Debug:                opts.Debug,
+		PrettyPrintResponses: opts.PrettyPrintResponses,
+		ParseResponse:        describeResponse,
+	})
+}
+
+// queryResult is the size of the result of a query.
+type queryResult struct {
+	series  int
+	samples int
+}
+
+// queryResponse is the part of a query API response needed to tell its
+// status and the size of its result.
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// parseResponse returns the number of series and samples in a response of
+// the query API, or an error if the query failed.
+func parseResponse(body []byte) (queryResult, error) {
+	var res queryResult
+	var resp queryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return res, fmt.Errorf("cannot decode response: %w", err)
+	}
+	if resp.Status != "success" {
+		return res, fmt.Errorf("query failed with status %q: %s: %s", resp.Status, resp.ErrorType, resp.Error)
+	}
+	switch resp.Data.ResultType {
+	case resultTypeMatrix:
+		var series []struct {
+			Values []json.RawMessage `json:"values"`
+		}
+		if err := json.Unmarshal(resp.Data.Result, &series); err != nil {
+			return res, fmt.Errorf("cannot decode %s result: %w", resp.Data.ResultType, err)
+		}
+		res.series = len(series)
+		for _, s := range series {
+			res.samples += len(s.Values)
+		}
+	case resultTypeVector:
+		var series []json.RawMessage
+		if err := json.Unmarshal(resp.Data.Result, &series); err != nil {
+			return res, fmt.Errorf("cannot decode %s result: %w", resp.Data.ResultType, err)
+		}
+		res.series = len(series)
+		res.samples = len(series)
+	default:
+		return res, fmt.Errorf("unexpected result type %q", resp.Data.ResultType)
+	}
+	return res, nil
+}
+
+// describeResponse is the query.HTTPResponseParser of the query API.
+func describeResponse(body []byte) (query.HTTPResult, error) {
+	res, err := parseResponse(body)
+	if err != nil {
+		return query.HTTPResult{}, err
+	}
+	return query.HTTPResult{
+		Description: fmt.Sprintf("%d series, %d samples", res.series, res.samples),
+		HasSize:     true,
+		Series:      int64(res.series),
+		Samples:     int64(res.samples),
+	}, nil
+}
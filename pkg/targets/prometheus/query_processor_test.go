@@ -0,0 +1,97 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	matrixResponse = `{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"__name__":"usage_user"},"values":[[1451606400,"58.1"],[1451606460,"2.6"],[1451606520,"24.9"]]},
+		{"metric":{"__name__":"usage_system"},"values":[[1451606400,"61.5"],[1451606460,"22.9"]]}]}}`
+	vectorResponse = `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"hostname":"host_0"},"value":[1451606400,"1"]},
+		{"metric":{"hostname":"host_1"},"value":[1451606400,"2"]}]}}`
+	errorResponse = `{"status":"error","errorType":"bad_data","error":"parse error"}`
+)
+
+func TestParseResponse(t *testing.T) {
+	cases := []struct {
+		desc string
+		body string
+		want queryResult
+		err  bool
+	}{
+		{desc: "matrix", body: matrixResponse, want: queryResult{series: 2, samples: 5}},
+		{desc: "vector", body: vectorResponse, want: queryResult{series: 2, samples: 2}},
+		{desc: "empty", body: `{"status":"success","data":{"resultType":"matrix","result":[]}}`},
+		{desc: "error", body: errorResponse, err: true},
+		{desc: "scalar", body: `{"status":"success","data":{"resultType":"scalar","result":[1451606400,"1"]}}`, err: true},
+		{desc: "not json", body: "<html>", err: true},
+	}
+	for _, c := range cases {
+		got, err := parseResponse([]byte(c.body))
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: incorrect result: got %+v want %+v", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestQueryProcessorProcessQuery(t *testing.T) {
+	cases := []struct {
+		desc   string
+		status int
+		body   string
+		err    bool
+	}{
+		{desc: "ok", status: http.StatusOK, body: matrixResponse},
+		{desc: "bad request", status: http.StatusBadRequest, body: errorResponse, err: true},
+		{desc: "error status", status: http.StatusOK, body: errorResponse, err: true},
+	}
+	for _, c := range cases {
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.RequestURI()
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+		create := NewQueryProcessorCreate(&QueryProcessorOptions{URLs: []string{"http://localhost:1", server.URL}})
+		p := create()
+		p.Init(1)
+
+		q := query.NewHTTP()
+		q.HumanLabel = []byte("Prometheus query")
+		q.Method = []byte(http.MethodGet)
+		q.Path = []byte("/api/v1/query_range?query=usage_user&start=0&end=60&step=60")
+		stats, err := p.ProcessQuery(q, false)
+		server.Close()
+		if !strings.HasPrefix(path, "/api/v1/query_range?") {
+			t.Errorf("%s: incorrect request path: got %s", c.desc, path)
+		}
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if len(stats) != 1 || string(stats[0].Label()) != "Prometheus query" {
+			t.Errorf("%s: incorrect stats: got %v", c.desc, stats)
+		}
+	}
+}
@@ -51,11 +51,11 @@ func (ps *Serializer) Serialize(p *data.Point, w io.Writer) error {
 		}
 	}
 	series := make([]prompb.TimeSeries, len(p.FieldKeys()))
-	err := convertToPromSeries(p, series)
+	n, err := convertToPromSeries(p, series)
 	if err != nil {
 		return fmt.Errorf("could not serialize point\n%v", err)
 	}
-	for _, ts := range series {
+	for _, ts := range series[:n] {
 		protoBytes, err := proto.Marshal(&ts)
 		if err != nil {
 			return err
@@ -74,12 +74,15 @@ func (ps *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	return nil
 }
 
-// Each point field will become a new TimeSeries with added field key as a label
-func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
+// Each point field will become a new TimeSeries with added field key as a label.
+// Fields with missing or string values are skipped, since a sample always has
+// a number as its value. Booleans become 1 or 0.
+// Returns the number of TimeSeries put in the buffer.
+func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) (int, error) {
 	bufLen := len(buffer)
 	requiredPlaces := len(p.FieldKeys())
 	if requiredPlaces > bufLen {
-		return fmt.Errorf("supplied buffer has insufficient space; need %d; got %d",
+		return 0, fmt.Errorf("supplied buffer has insufficient space; need %d; got %d",
 			requiredPlaces, bufLen,
 		)
 	}
@@ -87,19 +90,22 @@ func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
 	tagValues := p.TagValues()
 	fieldKeys := p.FieldKeys()
 	fieldValues := p.FieldValues()
-	// length is number of tagKeys plus the metric name
-	labels := make([]prompb.Label, len(tagKeys)+1)
+	// at most one label per tag plus the metric name
+	labels := make([]prompb.Label, 0, len(tagKeys)+1)
 	for i := range tagKeys {
+		if tagValues[i] == nil {
+			continue
+		}
 		label := prompb.Label{
 			Name:  string(tagKeys[i]),
 			Value: tagValues[i].(string),
 		}
-		labels[i] = label
+		labels = append(labels, label)
 	}
-	labels[len(labels)-1] = prompb.Label{
+	labels = append(labels, prompb.Label{
 		Name:  model.MetricNameLabel,
 		Value: "",
-	}
+	})
 	sort.Slice(labels, func(i, j int) bool {
 		return labels[i].Name < labels[j].Name
 	})
@@ -108,7 +114,12 @@ func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
 	})
 
 	tsMs := p.TimestampInUnixMs()
+	n := 0
 	for i := range fieldKeys {
+		switch fieldValues[i].(type) {
+		case nil, string, []byte:
+			continue
+		}
 		myLabels := labels
 		if i+1 < len(fieldKeys) {
 			myLabels = make([]prompb.Label, len(labels))
@@ -122,9 +133,10 @@ func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
 			Labels:  myLabels,
 			Samples: []prompb.Sample{{Value: getFloat64(fieldValues[i]), Timestamp: tsMs}},
 		}
-		buffer[i] = ts
+		buffer[n] = ts
+		n++
 	}
-	return nil
+	return n, nil
 }
 
 func getFloat64(fieldValue interface{}) float64 {
@@ -133,8 +145,15 @@ func getFloat64(fieldValue interface{}) float64 {
 		return float64(fieldValue.(int))
 	case int64:
 		return float64(fieldValue.(int64))
+	case uint64:
+		return float64(fieldValue.(uint64))
 	case float64:
 		return fieldValue.(float64)
+	case bool:
+		if fieldValue.(bool) {
+			return 1
+		}
+		return 0
 	default:
 		panic(fmt.Sprintf("unsupported value type: %v", t))
 	}
@@ -106,6 +106,36 @@ func TestConvertToPromSeries(t *testing.T) {
 		Samples: []prompb.Sample{{Value: 2, Timestamp: twoFieldPoint.Timestamp().UnixNano() / 1000000}},
 	}
 
+	sparsePoint := data.NewPoint()
+	sparsePoint.SetTimestamp(&someTimeAgo)
+	sparsePoint.AppendField([]byte("f"), nil)
+	sparsePoint.AppendField([]byte("g"), 2)
+	sparsePoint.AppendTag([]byte("b"), "t1")
+	sparsePoint.AppendTag([]byte("a"), "t2")
+
+	nilTagPoint := data.NewPoint()
+	nilTagPoint.SetTimestamp(&someTimeAgo)
+	nilTagPoint.AppendField([]byte("g"), 2)
+	nilTagPoint.AppendTag([]byte("b"), "t1")
+	nilTagPoint.AppendTag([]byte("c"), nil)
+	nilTagPoint.AppendTag([]byte("a"), "t2")
+
+	typedPoint := data.NewPoint()
+	typedPoint.SetTimestamp(&someTimeAgo)
+	typedPoint.AppendField([]byte("f"), "ok")
+	typedPoint.AppendField([]byte("g"), true)
+	typedPoint.AppendField([]byte("h"), uint64(1)<<63)
+	typedPoint.AppendTag([]byte("b"), "t1")
+	typedPoint.AppendTag([]byte("a"), "t2")
+	tpTS1 := prompb.TimeSeries{
+		Labels:  []prompb.Label{{Name: "__name__", Value: "g"}, {Name: "a", Value: "t2"}, {Name: "b", Value: "t1"}},
+		Samples: []prompb.Sample{{Value: 1, Timestamp: typedPoint.Timestamp().UnixNano() / 1000000}},
+	}
+	tpTS2 := prompb.TimeSeries{
+		Labels:  []prompb.Label{{Name: "__name__", Value: "h"}, {Name: "a", Value: "t2"}, {Name: "b", Value: "t1"}},
+		Samples: []prompb.Sample{{Value: 1 << 63, Timestamp: typedPoint.Timestamp().UnixNano() / 1000000}},
+	}
+
 	testCases := []struct {
 		desc      string
 		expError  bool
@@ -124,12 +154,27 @@ func TestConvertToPromSeries(t *testing.T) {
 			inPoint:   twoFieldPoint,
 			inBuffer:  make([]prompb.TimeSeries, 2),
 			expBuffer: []prompb.TimeSeries{tfTS1, tfTS2},
+		}, {
+			desc:      "Missing value skipped",
+			inPoint:   sparsePoint,
+			inBuffer:  make([]prompb.TimeSeries, 2),
+			expBuffer: []prompb.TimeSeries{tfTS2},
+		}, {
+			desc:      "Nil tag skipped",
+			inPoint:   nilTagPoint,
+			inBuffer:  make([]prompb.TimeSeries, 1),
+			expBuffer: []prompb.TimeSeries{tfTS2},
+		}, {
+			desc:      "String skipped, boolean and unsigned converted",
+			inPoint:   typedPoint,
+			inBuffer:  make([]prompb.TimeSeries, 3),
+			expBuffer: []prompb.TimeSeries{tpTS1, tpTS2},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := convertToPromSeries(tc.inPoint, tc.inBuffer)
+			n, err := convertToPromSeries(tc.inPoint, tc.inBuffer)
 			if tc.expError && err != nil {
 				return
 			} else if tc.expError {
@@ -139,6 +184,9 @@ func TestConvertToPromSeries(t *testing.T) {
 				t.Errorf("unexpected error: %v", err)
 				return
 			}
+			if n != len(tc.expBuffer) {
+				t.Errorf("wrong number of time-series; exp: %d; got %d", len(tc.expBuffer), n)
+			}
 
 			for i, ts := range tc.expBuffer {
 				returnedTS := tc.inBuffer[i]
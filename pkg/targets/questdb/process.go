@@ -1,8 +1,9 @@
-package main
+package questdb
 
 import (
 	"fmt"
 	"net"
+	"sync"
 
 	"github.com/timescale/tsbs/pkg/targets"
 )
@@ -11,17 +12,19 @@ import (
 var printFn = fmt.Printf
 
 type processor struct {
-	ilpConn (*net.TCPConn)
+	ilpBindTo string
+	bufPool   *sync.Pool
+	ilpConn   (*net.TCPConn)
 }
 
 func (p *processor) Init(numWorker int, _, _ bool) {
-	tcpAddr, err := net.ResolveTCPAddr("tcp4", questdbILPBindTo)
+	tcpAddr, err := net.ResolveTCPAddr("tcp4", p.ilpBindTo)
 	if err != nil {
-		fatal("Failed to resolve %s: %s\n", questdbILPBindTo, err.Error())
+		fatal("Failed to resolve %s: %s\n", p.ilpBindTo, err.Error())
 	}
 	p.ilpConn, err = net.DialTCP("tcp", nil, tcpAddr)
 	if err != nil {
-		fatal("Failed connect to %s: %s\n", questdbILPBindTo, err.Error())
+		fatal("Failed connect to %s: %s\n", p.ilpBindTo, err.Error())
 	}
 }
 
@@ -46,6 +49,6 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 
 	// Return the batch buffer to the pool.
 	batch.buf.Reset()
-	bufPool.Put(batch.buf)
+	p.bufPool.Put(batch.buf)
 	return metricCnt, uint64(rowCnt)
 }
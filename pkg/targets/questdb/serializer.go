@@ -4,6 +4,7 @@ import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
 	"io"
+	"strconv"
 )
 
 // Serializer writes a Point in a serialized form for MongoDB
@@ -83,13 +84,37 @@ func appendField(buf, key []byte, v interface{}) []byte {
 	buf = append(buf, key...)
 	buf = append(buf, '=')
 
-	buf = serialize.FastFormatAppend(v, buf)
+	switch v := v.(type) {
+	case string:
+		buf = appendQuoted(buf, []byte(v))
+	case []byte:
+		buf = appendQuoted(buf, v)
+	case uint64:
+		// QuestDB integers are signed, so write unsigned ones as long256
+		buf = append(buf, "0x"...)
+		buf = strconv.AppendUint(buf, v, 16)
+	default:
+		buf = serialize.FastFormatAppend(v, buf)
+	}
 
 	// Influx uses 'i' to indicate integers:
 	switch v.(type) {
-	case int, int64:
+	case int, int64, uint64:
 		buf = append(buf, 'i')
 	}
 
 	return buf
 }
+
+// appendQuoted appends a string field value in double quotes, escaping the
+// quotes and backslashes in it.
+func appendQuoted(buf, v []byte) []byte {
+	buf = append(buf, '"')
+	for _, c := range v {
+		if c == '"' || c == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '"')
+}
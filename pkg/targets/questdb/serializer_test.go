@@ -34,6 +34,10 @@ func TestInfluxSerializerSerialize(t *testing.T) {
 			Desc:       "a Point with a nil field",
 			InputPoint: serialize.TestPointWithNilField(),
 			Output:     "cpu usage_guest_nice=38.24311829 1451606400000000000\n",
+		}, {
+			Desc:       "a Point with string, boolean and unsigned fields",
+			InputPoint: serialize.TestPointTypedFields(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b status=\"ok\",reachable=true,requests_total=0xffffffffffffffffi 1451606400000000000\n",
 		},
 	}
 
@@ -0,0 +1,60 @@
+package questdb
+
+import (
+	"bytes"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator:  sim,
+		headers:    sim.Headers(),
+		serializer: &Serializer{},
+	}
+}
+
+// simulationDataSource generates points with a simulator and converts each
+// one to a line of the InfluxDB line protocol (ILP), the same representation
+// the file data source reads.
+type simulationDataSource struct {
+	simulator  common.Simulator
+	headers    *common.GeneratedDataHeaders
+	serializer *Serializer
+	buf        bytes.Buffer
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return data.LoadedPoint{}
+	}
+
+	d.buf.Reset()
+	if err := d.serializer.Serialize(newSimulatorPoint, &d.buf); err != nil {
+		fatal("could not serialize simulated point: %v", err)
+		return data.LoadedPoint{}
+	}
+	// the batch adds its own line separator
+	line := bytes.TrimSuffix(d.buf.Bytes(), newLine)
+	return data.NewLoadedPoint(append([]byte(nil), line...))
+}
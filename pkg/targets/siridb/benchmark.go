@@ -0,0 +1,82 @@
+package siridb
+
+import (
+	"log"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// allows for testing
+var fatal = log.Fatal
+
+// SpecificConfig holds the SiriDB specific loading options.
+type SpecificConfig struct {
+	DBUser       string   `yaml:"dbuser" mapstructure:"dbuser"`
+	DBPass       string   `yaml:"dbpass" mapstructure:"dbpass"`
+	Hosts        []string `yaml:"hosts" mapstructure:"hosts"`
+	Replica      bool     `yaml:"replica" mapstructure:"replica"`
+	LogBatches   bool     `yaml:"log-batches" mapstructure:"log-batches"`
+	WriteTimeout int      `yaml:"write-timeout" mapstructure:"write-timeout"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+type benchmark struct {
+	opts   *SpecificConfig
+	dbName string
+	ds     targets.DataSource
+}
+
+func NewBenchmark(dbName string, opts *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		ds = &fileDataSource{
+			buf: make([]byte, 0),
+			len: 0,
+			br:  load.GetBufferedReader(dataSourceConfig.File.Location),
+		}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	return &benchmark{
+		opts:   opts,
+		dbName: dbName,
+		ds:     ds,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{opts: b.opts, dbName: b.dbName}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{opts: b.opts}
+}
@@ -1,4 +1,4 @@
-package main
+package siridb
 
 import (
 	"errors"
@@ -19,6 +19,7 @@ const (
 )
 
 type dbCreator struct {
+	opts       *SpecificConfig
 	connection []*siridb.Connection
 	hosts      []string
 	replica    []string
@@ -26,7 +27,7 @@ type dbCreator struct {
 
 // Init should set up any connection or other setup for talking to the DB, but should NOT create any databases
 func (d *dbCreator) Init() {
-	d.hosts = strings.Split(hosts, ",")
+	d.hosts = d.opts.Hosts
 	d.connection = make([]*siridb.Connection, 0)
 	for _, hostport := range d.hosts {
 		x := strings.Split(hostport, ":")
@@ -42,7 +43,7 @@ func (d *dbCreator) Init() {
 // DBExists checks if a database with the given name currently exists.
 func (d *dbCreator) DBExists(dbName string) bool {
 	for _, conn := range d.connection {
-		if err := conn.Connect(dbUser, dbPass, dbName); err == nil {
+		if err := conn.Connect(d.opts.DBUser, d.opts.DBPass, dbName); err == nil {
 			return true
 		}
 	}
@@ -76,13 +77,13 @@ func (d *dbCreator) CreateDB(dbName string) error {
 			fatal(err)
 		}
 
-		if !replica {
+		if !d.opts.Replica {
 			optionsNewPool := make(map[string]interface{})
 			optionsNewPool["dbname"] = dbName
 			optionsNewPool["host"] = host
 			optionsNewPool["port"] = port
-			optionsNewPool["username"] = dbUser
-			optionsNewPool["password"] = dbPass
+			optionsNewPool["username"] = d.opts.DBUser
+			optionsNewPool["password"] = d.opts.DBPass
 
 			if _, err := d.connection[1].Manage(account, password, siridb.AdminNewPool, optionsNewPool); err != nil {
 				return err
@@ -93,8 +94,8 @@ func (d *dbCreator) CreateDB(dbName string) error {
 			optionsNewReplica["dbname"] = dbName
 			optionsNewReplica["host"] = host
 			optionsNewReplica["port"] = port
-			optionsNewReplica["username"] = dbUser
-			optionsNewReplica["password"] = dbPass
+			optionsNewReplica["username"] = d.opts.DBUser
+			optionsNewReplica["password"] = d.opts.DBPass
 			optionsNewReplica["pool"] = 0
 
 			if _, err := d.connection[1].Manage(account, password, siridb.AdminNewReplica, optionsNewReplica); err != nil {
@@ -1,4 +1,4 @@
-package main
+package siridb
 
 import (
 	"fmt"
@@ -13,11 +13,13 @@ import (
 )
 
 type processor struct {
+	opts       *SpecificConfig
+	dbName     string
 	connection *siridb.Connection
 }
 
 func (p *processor) Init(numWorker int, _, _ bool) {
-	hostlist := strings.Split(hosts, ",")
+	hostlist := p.opts.Hosts
 	h := hostlist[numWorker%len(hostlist)]
 	x := strings.Split(h, ":")
 	host := x[0]
@@ -37,7 +39,7 @@ func (p *processor) Close(doLoad bool) {
 func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rows uint64) {
 	batch := b.(*batch)
 	if doLoad {
-		if err := p.connection.Connect(dbUser, dbPass, loader.DatabaseName()); err != nil {
+		if err := p.connection.Connect(p.opts.DBUser, p.opts.DBPass, p.dbName); err != nil {
 			fatal(err)
 		}
 		series := make([]byte, 0)
@@ -51,10 +53,10 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, row
 			series = append(series, v...)
 		}
 		start := time.Now()
-		if _, err := p.connection.InsertBin(series, uint16(writeTimeout)); err != nil {
+		if _, err := p.connection.InsertBin(series, uint16(p.opts.WriteTimeout)); err != nil {
 			fatal(err)
 		}
-		if logBatches {
+		if p.opts.LogBatches {
 			now := time.Now()
 			took := now.Sub(start)
 			batchSize := batch.batchCnt
@@ -35,15 +35,19 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	line = append(line, '|')
 	tagKeys := p.TagKeys()
 	tagValues := p.TagValues()
+	tagged := false
 	for i, v := range tagValues {
-		if i != 0 {
-			line = append(line, ',')
-		}
 		switch t := v.(type) {
 		case string:
+			if tagged {
+				line = append(line, ',')
+			}
+			tagged = true
 			line = append(line, tagKeys[i]...)
 			line = append(line, '=')
 			line = append(line, []byte(t)...)
+		case nil:
+			continue
 		default:
 			panic("Non string tags not supported")
 		}
@@ -57,6 +61,9 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	fieldValues := p.FieldValues()
 	fieldKeys := p.FieldKeys()
 	for i, value := range fieldValues {
+		if value == nil {
+			continue
+		}
 
 		indexLenData := len(line) + 4
 
@@ -69,7 +76,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 
 		preQpack := len(line)
 		ts, _ := strconv.ParseInt(fmt.Sprintf("%d", p.Timestamp().UTC().UnixNano()), 10, 64)
-		err := qpack.PackTo(&line, []interface{}{ts, value}) // packs a byte array in the right format for SiriDB
+		err := qpack.PackTo(&line, []interface{}{ts, seriesValue(value)}) // packs a byte array in the right format for SiriDB
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -85,3 +92,19 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	_, err = w.Write(line)
 	return err
 }
+
+// seriesValue converts a field value to one that SiriDB can store, which are
+// integers, floats and strings. Booleans become 1 or 0, and unsigned integers
+// become floats, since SiriDB integers are signed.
+func seriesValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case bool:
+		if t {
+			return int64(1)
+		}
+		return int64(0)
+	case uint64:
+		return float64(t)
+	}
+	return v
+}
@@ -63,6 +63,30 @@ func TestSiriDBSerializerSerialize(t *testing.T) {
 				value:     [][]interface{}{{1451606400000000000, 38.24311829}},
 			},
 		},
+		{
+			desc:       "a Point with a nil tag",
+			inputPoint: serialize.TestPointWithNilTag(),
+			want: output{
+				seriename: []string{"cpu||usage_guest_nice"},
+				value:     [][]interface{}{{1451606400000000000, 38.24311829}},
+			},
+		},
+		{
+			desc:       "a Point with string, boolean and unsigned fields",
+			inputPoint: serialize.TestPointTypedFields(),
+			want: output{
+				seriename: []string{
+					"cpu|hostname=host_0,region=eu-west-1,datacenter=eu-west-1b|status",
+					"cpu|hostname=host_0,region=eu-west-1,datacenter=eu-west-1b|reachable",
+					"cpu|hostname=host_0,region=eu-west-1,datacenter=eu-west-1b|requests_total",
+				},
+				value: [][]interface{}{
+					{1451606400000000000, "ok"},
+					{1451606400000000000, 1},
+					{1451606400000000000, float64(serialize.TestUint64)},
+				},
+			},
+		},
 	}
 
 	ps := &Serializer{}
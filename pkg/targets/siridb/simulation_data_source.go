@@ -0,0 +1,91 @@
+package siridb
+
+import (
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+	qpack "github.com/transceptor-technology/go-qpack"
+)
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+// simulationDataSource converts simulated points directly into the map of
+// series name to packed (timestamp, value) pairs read from data files.
+type simulationDataSource struct {
+	simulator common.Simulator
+	headers   *common.GeneratedDataHeaders
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return data.LoadedPoint{}
+	}
+
+	return data.NewLoadedPoint(pointToSeries(newSimulatorPoint))
+}
+
+// pointToSeries creates one series per field of p, named
+// <measurement>|<tag1>=<value1>,...|<field key>, the same way the file
+// data source joins the serialized name and field keys. Fields without
+// a value are skipped.
+func pointToSeries(p *data.Point) *point {
+	name := make([]byte, 0, 256)
+	name = append(name, p.MeasurementName()...)
+	name = append(name, '|')
+	tagKeys := p.TagKeys()
+	for i, v := range p.TagValues() {
+		if i != 0 {
+			name = append(name, ',')
+		}
+		switch t := v.(type) {
+		case string:
+			name = append(name, tagKeys[i]...)
+			name = append(name, '=')
+			name = append(name, t...)
+		default:
+			panic("Non string tags not supported")
+		}
+	}
+	name = append(name, '|')
+
+	ts := p.Timestamp().UTC().UnixNano()
+	fieldKeys := p.FieldKeys()
+	series := make(map[string][]byte, len(fieldKeys))
+	for i, value := range p.FieldValues() {
+		if value == nil {
+			continue
+		}
+		packed, err := qpack.Pack([]interface{}{ts, seriesValue(value)}) // packs a byte array in the right format for SiriDB
+		if err != nil {
+			fatal(err)
+		}
+		series[string(name)+string(fieldKeys[i])] = packed
+	}
+	return &point{
+		data:    series,
+		dataCnt: uint64(len(series)),
+	}
+}
@@ -24,6 +24,9 @@ var fatal = log.Fatalf
 
 var tableCols = make(map[string][]string)
 
+// tableColTypes holds the types of the columns in tableCols, if any are typed
+var tableColTypes = make(map[string][]string)
+
 type dbCreator struct {
 	driver  string
 	ds      targets.DataSource
@@ -100,6 +103,7 @@ func (d *dbCreator) PostCreateDB(dbName string) error {
 	for tableName, columns := range headers.FieldKeys {
 		// tableCols is a global map. Globally cache the available columns for the given table
 		tableCols[tableName] = columns
+		tableColTypes[tableName] = headers.FieldTypes[tableName]
 		fieldDefs, indexDefs := d.getFieldAndIndexDefinitions(tableName, columns)
 		if d.opts.CreateMetricsTable {
 			d.createTableAndIndexes(dbBench, tableName, fieldDefs, indexDefs)
@@ -138,11 +142,18 @@ func (d *dbCreator) getFieldAndIndexDefinitions(tableName string, columns []stri
 
 	allCols = append(allCols, columns...)
 	extraCols := 0 // set to 1 when hostname is kept in-table
+	if d.opts.InTableTag {
+		extraCols = 1
+	}
+	colTypes := tableColTypes[tableName]
 	for idx, field := range allCols {
 		if len(field) == 0 {
 			continue
 		}
 		fieldType := "DOUBLE PRECISION"
+		if i := idx - extraCols; i >= 0 && i < len(colTypes) && colTypes[i] != "" {
+			fieldType = serializedTypeToPgType(colTypes[i])
+		}
 		idxType := d.opts.FieldIndex
 		// This condition handles the case where we keep the primary tag key in the table
 		// and partition on it. Since under the current implementation this tag is always
@@ -150,7 +161,6 @@ func (d *dbCreator) getFieldAndIndexDefinitions(tableName string, columns []stri
 		if d.opts.InTableTag && idx == 0 {
 			fieldType = "TEXT"
 			idxType = ""
-			extraCols = 1
 		}
 
 		fieldDefs = append(fieldDefs, fmt.Sprintf("%s %s", field, fieldType))
@@ -321,6 +331,10 @@ func serializedTypeToPgType(serializedType string) string {
 	switch serializedType {
 	case "string":
 		return "TEXT"
+	case "bool":
+		return "BOOLEAN"
+	case "uint64":
+		return "NUMERIC(20)"
 	case "float32":
 		return "FLOAT"
 	case "float64":
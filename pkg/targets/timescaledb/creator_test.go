@@ -129,6 +129,7 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 		desc            string
 		tableName       string
 		columns         []string
+		colTypes        []string
 		fieldIndexCount int
 		inTableTag      bool
 		wantFieldDefs   []string
@@ -179,6 +180,16 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 			wantFieldDefs:   []string{"usage_user DOUBLE PRECISION", "usage_system DOUBLE PRECISION", "usage_idle DOUBLE PRECISION", "usage_nice DOUBLE PRECISION"},
 			wantIndexDefs:   []string{"CREATE INDEX ON cpu (usage_user, time DESC)", "CREATE INDEX ON cpu (usage_system, time DESC)"},
 		},
+		{
+			desc:            "typed fields, in table tag",
+			tableName:       "nginx_status",
+			columns:         []string{"status", "reachable", "requests_total", "bytes_sent"},
+			colTypes:        []string{"string", "bool", "uint64", ""},
+			fieldIndexCount: 0,
+			inTableTag:      true,
+			wantFieldDefs:   []string{"hostname TEXT", "status TEXT", "reachable BOOLEAN", "requests_total NUMERIC(20)", "bytes_sent DOUBLE PRECISION"},
+			wantIndexDefs:   []string{},
+		},
 	}
 
 	for _, c := range cases {
@@ -186,6 +197,7 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 		// Initialize global cache
 		tableCols[tagsKey] = []string{}
 		tableCols[tagsKey] = append(tableCols[tagsKey], "hostname")
+		tableColTypes[c.tableName] = c.colTypes
 		dbc := &dbCreator{opts: &LoadingOptions{
 			InTableTag:      c.inTableTag,
 			FieldIndexCount: c.fieldIndexCount,
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
 
 	"github.com/jackc/pgx/v4"
@@ -108,25 +109,50 @@ func (p *processor) sqlTagsToCacheLine(res *sql.Rows, err error, tagCols []strin
 			panic(err)
 		}
 
-		var key string
+		values := make([]string, len(tagCols))
 		if p.opts.UseJSON {
-			decodedTagset := map[string]string{}
+			decodedTagset := map[string]interface{}{}
 			json.Unmarshal(resVals[1].([]byte), &decodedTagset)
-			key = decodedTagset[tagCols[0]]
+			for i, col := range tagCols {
+				values[i] = tagValueToString(decodedTagset[col])
+			}
 		} else {
-			key = fmt.Sprintf("%v", resVals[1])
+			for i := range tagCols {
+				values[i] = tagValueToString(resVals[i+1])
+			}
 		}
-		ret[key] = resVals[0].(int64)
+		ret[tagsCacheKey(values)] = resVals[0].(int64)
 	}
 	res.Close()
 	return ret
 }
 
+// tagsCacheKey returns the key of the tags cache for the values of the common
+// tags. Every value is part of it, since data combined from several use cases
+// leaves the first tags of some entities empty.
+func tagsCacheKey(values []string) string {
+	return strings.Join(values, ",")
+}
+
+// tagValueToString formats a tag value read from the database the way it is
+// written in the data file, with NULL as an empty value.
+func tagValueToString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 // splitTagsAndMetrics takes an array of insertData (sharded by hypertable) and
 // divides the tags from data into appropriate slices that can then be used in
 // SQL queries to insert into their respective tables. Additionally, it also
 // returns the number of metrics (i.e., non-tag fields) for the data processed.
-func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]string, [][]interface{}, uint64) {
+// Field values are parsed according to fieldTypes, which may be nil if every
+// field is a number.
+func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int, fieldTypes []string) ([][]string, [][]interface{}, uint64) {
 	tagRows := make([][]string, 0, len(rows))
 	dataRows := make([][]interface{}, 0, len(rows))
 	numMetrics := uint64(0)
@@ -162,18 +188,27 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 		if p.opts.InTableTag {
 			r = append(r, tags[0])
 		}
-		for _, v := range metrics[1:] {
+		for i, v := range metrics[1:] {
 			if v == "" {
 				r = append(r, nil)
 				continue
 			}
 
-			num, err := strconv.ParseFloat(v, 64)
+			fieldType := ""
+			if i < len(fieldTypes) {
+				fieldType = fieldTypes[i]
+			}
+			value, err := common.ParseFieldValue(v, fieldType)
 			if err != nil {
 				panic(err)
 			}
+			if fieldType == common.FieldTypeUint64 {
+				// NUMERIC columns take the text, which keeps values above the
+				// int64 limit that database/sql would refuse
+				value = v
+			}
 
-			r = append(r, num)
+			r = append(r, value)
 		}
 
 		dataRows = append(dataRows, r)
@@ -188,13 +223,13 @@ func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 	if p.opts.InTableTag {
 		colLen++
 	}
-	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, colLen)
+	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, colLen, tableColTypes[hypertable])
 
 	// Check if any of these tags has yet to be inserted
 	newTags := make([][]string, 0, len(rows))
 	p._csi.mutex.RLock()
 	for _, cols := range tagRows {
-		if _, ok := p._csi.m[cols[0]]; !ok {
+		if _, ok := p._csi.m[tagsCacheKey(cols)]; !ok {
 			newTags = append(newTags, cols)
 		}
 	}
@@ -210,7 +245,7 @@ func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 
 	p._csi.mutex.RLock()
 	for i := range dataRows {
-		tagKey := tagRows[i][0]
+		tagKey := tagsCacheKey(tagRows[i])
 		dataRows[i][1] = p._csi.m[tagKey]
 	}
 	p._csi.mutex.RUnlock()
@@ -86,6 +86,7 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 		desc        string
 		rows        []*insertData
 		inTableTag  bool
+		fieldTypes  []string
 		wantMetrics uint64
 		wantTags    [][]string
 		wantData    [][]interface{}
@@ -133,6 +134,21 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 				{toTS("200"), nil, map[string]interface{}{"tag3": "BAZ"}, "foofoo", 1.0, 5.0, 45.0},
 			},
 		},
+		{
+			desc: "typed field values",
+			rows: []*insertData{
+				{
+					tags:   "tag1=foo,tag2=bar",
+					fields: "100,ok,true,18446744073709551615",
+				},
+			},
+			fieldTypes:  []string{"string", "bool", "uint64"},
+			wantMetrics: 3,
+			wantTags:    [][]string{{"foo", "bar"}},
+			wantData: [][]interface{}{
+				{toTS("100"), nil, nil, "ok", true, "18446744073709551615"},
+			},
+		},
 		{
 			desc: "invalid timestamp",
 			rows: []*insertData{
@@ -194,13 +210,13 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 					t.Errorf("%s: did not panic when should", c.desc)
 				}
 			}()
-			p.splitTagsAndMetrics(c.rows, numCols+numExtraCols)
+			p.splitTagsAndMetrics(c.rows, numCols+numExtraCols, c.fieldTypes)
 		}
 
 		oldInTableTag := p.opts.InTableTag
 		p.opts.InTableTag = c.inTableTag
 
-		gotTags, gotData, numMetrics := p.splitTagsAndMetrics(c.rows, numCols+numExtraCols)
+		gotTags, gotData, numMetrics := p.splitTagsAndMetrics(c.rows, numCols+numExtraCols, c.fieldTypes)
 		if numMetrics != c.wantMetrics {
 			t.Errorf("%s: number of metrics incorrect: got %d want %d", c.desc, numMetrics, c.wantMetrics)
 		}
@@ -299,3 +315,23 @@ func TestConvertValsToSQLBasedOnType(t *testing.T) {
 		t.Errorf("error converting to sql values\nexpected: %v\ngot: %v", expected, converted)
 	}
 }
+
+func TestTagsCacheKey(t *testing.T) {
+	// entities of other use cases leave the first tags empty, so the key has
+	// to tell them apart by the rest
+	truck1 := tagsCacheKey([]string{"", "", "truck_1", "South"})
+	truck2 := tagsCacheKey([]string{"", "", "truck_2", "South"})
+	if truck1 == truck2 {
+		t.Errorf("tags with the same first value share a key: %s", truck1)
+	}
+
+	// keys of tags read back from the database match the ones of the data file
+	dbValues := []interface{}{nil, []byte("eu-west-1"), int64(7), "x"}
+	values := make([]string, len(dbValues))
+	for i, v := range dbValues {
+		values[i] = tagValueToString(v)
+	}
+	if got, want := tagsCacheKey(values), tagsCacheKey([]string{"", "eu-west-1", "7", "x"}); got != want {
+		t.Errorf("incorrect key of database values: got %s want %s", got, want)
+	}
+}
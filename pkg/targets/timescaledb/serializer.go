@@ -8,7 +8,15 @@ import (
 )
 
 // Serializer writes a Point in a serialized form for TimescaleDB
-type Serializer struct{}
+type Serializer struct {
+	columns map[string][]string
+}
+
+// SetColumns sets the field keys of each measurement, as written in the
+// header, so fields a Point leaves out are written as empty values.
+func (s *Serializer) SetColumns(columns map[string][]string) {
+	s.columns = columns
+}
 
 // Serialize writes Point p to the given Writer w, so it can be
 // loaded by the TimescaleDB loader. The format is CSV with two lines per Point,
@@ -17,6 +25,8 @@ type Serializer struct{}
 // e.g.,
 // tags,<tag1>,<tag2>,<tag3>,...
 // <measurement>,<timestamp>,<field1>,<field2>,<field3>,...
+//
+// Missing field values are written as empty values, which are loaded as NULL.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	// Tag row first, prefixed with name 'tags'
 	buf := make([]byte, 0, 256)
@@ -40,7 +50,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	buf = append(buf, p.MeasurementName()...)
 	buf = append(buf, ',')
 	buf = append(buf, []byte(fmt.Sprintf("%d", p.Timestamp().UTC().UnixNano()))...)
-	fieldValues := p.FieldValues()
+	fieldValues := serialize.ColumnValues(p, s.columns[string(p.MeasurementName())])
 	for _, v := range fieldValues {
 		buf = append(buf, ',')
 		buf = serialize.FastFormatAppend(v, buf)
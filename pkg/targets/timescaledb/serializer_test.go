@@ -27,11 +27,39 @@ func TestTimescaleDBSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointNoTags(),
 			Output:     "tags\ncpu,1451606400000000000,38.24311829\n",
 		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     "tags\ncpu,1451606400000000000,,38.24311829\n",
+		},
 	}
 
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
 
+func TestTimescaleDBSerializerSerializeAbsentFields(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a Point missing leading columns",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,,,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with every column",
+			InputPoint: serialize.TestPointMultiField(),
+			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,5000000000,38,38.24311829\n",
+		},
+	}
+
+	s := &Serializer{}
+	s.SetColumns(map[string][]string{
+		string(serialize.TestMeasurement): {
+			string(serialize.TestColInt64), string(serialize.TestColInt), string(serialize.TestColFloat),
+		},
+	})
+	serialize.SerializerTest(t, cases, s)
+}
+
 func TestTimescaleDBSerializerSerializeErr(t *testing.T) {
 	p := serialize.TestPointMultiField()
 	s := &Serializer{}
@@ -63,7 +63,7 @@ func (d *simulationDataSource) NextItem() data.LoadedPoint {
 	buf = buf[:0]
 	unixNano := newSimulatorPoint.Timestamp().UTC().UnixNano()
 	buf = append(buf, []byte(fmt.Sprintf("%d", unixNano))...)
-	fieldValues := newSimulatorPoint.FieldValues()
+	fieldValues := serialize.ColumnValues(newSimulatorPoint, d.headers.FieldKeys[string(newSimulatorPoint.MeasurementName())])
 	for _, v := range fieldValues {
 		buf = append(buf, ',')
 		buf = serialize.FastFormatAppend(v, buf)
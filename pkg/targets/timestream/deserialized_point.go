@@ -1,5 +1,10 @@
 package timestream
 
+import (
+	"github.com/aws/aws-sdk-go/service/timestreamwrite"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
 // deserializedPoint is a struct used by the Timestream
 // loader to send data to the db. All the fields are strings
 // because the Timestream SDK accepts only string values
@@ -11,3 +16,21 @@ type deserializedPoint struct {
 	tagKeys      []string
 	fields       []*string
 }
+
+// measureValueType returns the Timestream type of the i-th field of a table
+// with the given field types. Timestream has no unsigned integers, so uint64
+// fields are written as doubles like the fields without a type.
+func measureValueType(fieldTypes []string, i int) string {
+	if i >= len(fieldTypes) {
+		return timestreamwrite.MeasureValueTypeDouble
+	}
+	switch fieldTypes[i] {
+	case common.FieldTypeString:
+		return timestreamwrite.MeasureValueTypeVarchar
+	case common.FieldTypeBool:
+		return timestreamwrite.MeasureValueTypeBoolean
+	case common.FieldTypeInt64:
+		return timestreamwrite.MeasureValueTypeBigint
+	}
+	return timestreamwrite.MeasureValueTypeDouble
+}
@@ -73,10 +73,10 @@ func (p *eachValueARecordProcessor) writeBatch(table string, rows []deserialized
 
 func (p *eachValueARecordProcessor) convertToRecords(table string, row deserializedPoint) []*timestreamwrite.Record {
 	dimensions := createDimensions(row.tagKeys, row.tags)
-	return createRecords(&row, p.headers.FieldKeys[table], dimensions, row.timeUnixNano)
+	return createRecords(&row, p.headers.FieldKeys[table], p.headers.FieldTypes[table], dimensions, row.timeUnixNano)
 }
 
-func createRecords(point *deserializedPoint, fieldKeys []string, dimensions []*timestreamwrite.Dimension, ts string) (buffer []*timestreamwrite.Record) {
+func createRecords(point *deserializedPoint, fieldKeys, fieldTypes []string, dimensions []*timestreamwrite.Dimension, ts string) (buffer []*timestreamwrite.Record) {
 	buffer = make([]*timestreamwrite.Record, 0, len(fieldKeys))
 	for i, fieldVal := range point.fields {
 		if fieldVal == nil {
@@ -85,7 +85,7 @@ func createRecords(point *deserializedPoint, fieldKeys []string, dimensions []*t
 		newRecord := &timestreamwrite.Record{}
 		newRecord.SetDimensions(dimensions)
 		newRecord.SetMeasureName(fieldKeys[i])
-		newRecord.SetMeasureValueType(timestreamwrite.MeasureValueTypeDouble)
+		newRecord.SetMeasureValueType(measureValueType(fieldTypes, i))
 		newRecord.SetMeasureValue(*fieldVal)
 		newRecord.SetTime(ts)
 		newRecord.SetTimeUnit(timestreamwrite.TimeUnitNanoseconds)
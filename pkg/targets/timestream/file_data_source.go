@@ -51,6 +51,9 @@ func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
 			if len(line) == 0 {
 				break
 			}
+			if strings.HasPrefix(line, common.HeaderCommentPrefix) {
+				continue
+			}
 			cols = append(cols, line)
 		}
 		i++
@@ -67,16 +70,17 @@ func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
 		return nil
 	}
 	fieldKeys := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	for _, tableDef := range cols {
 		columns := strings.Split(tableDef, ",")
 		tableName := columns[0]
-		colNames := columns[1:]
-		fieldKeys[tableName] = colNames
+		fieldKeys[tableName], fieldTypes[tableName] = common.ParseFieldColumns(columns[1:])
 	}
 	f._headers = &common.GeneratedDataHeaders{
-		TagTypes:  tagTypes,
-		TagKeys:   tagNames,
-		FieldKeys: fieldKeys,
+		TagTypes:   tagTypes,
+		TagKeys:    tagNames,
+		FieldKeys:  fieldKeys,
+		FieldTypes: fieldTypes,
 	}
 	return f._headers
 }
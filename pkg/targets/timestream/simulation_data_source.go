@@ -38,7 +38,7 @@ func (s *simulatorDataSource) NextItem() data.LoadedPoint {
 		table:        string(newSimulatorPoint.MeasurementName()),
 		tags:         tagsToStringArr(newSimulatorPoint.TagValues()),
 		tagKeys:      tagKeysToStringArr(newSimulatorPoint.TagKeys()),
-		fields:       fieldsToStringArr(newSimulatorPoint.FieldValues()),
+		fields:       fieldsToStringArr(serialize.ColumnValues(newSimulatorPoint, s._headers.FieldKeys[string(newSimulatorPoint.MeasurementName())])),
 	})
 }
 
@@ -81,6 +81,9 @@ func tagKeysToStringArr(tagKeys [][]byte) []string {
 func fieldsToStringArr(fieldValues []interface{}) []*string {
 	fieldsAsStr := make([]*string, len(fieldValues))
 	for i, field := range fieldValues {
+		if field == nil {
+			continue
+		}
 		var buf []byte
 		var str string
 		str = string(serialize.FastFormatAppend(field, buf))